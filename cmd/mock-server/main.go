@@ -1,15 +1,24 @@
 package main
 
 import (
+	"os"
+
 	"github.com/Caik/go-mock-server/internal/ci"
 	"github.com/Caik/go-mock-server/internal/config"
 	"github.com/Caik/go-mock-server/internal/server"
 	"github.com/Caik/go-mock-server/internal/server/controller"
 	"github.com/Caik/go-mock-server/internal/service/admin"
+	"github.com/Caik/go-mock-server/internal/service/audit"
+	"github.com/Caik/go-mock-server/internal/service/auth"
 	"github.com/Caik/go-mock-server/internal/service/cache"
+	"github.com/Caik/go-mock-server/internal/service/certificate"
 	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/metrics"
 	"github.com/Caik/go-mock-server/internal/service/mock"
+	"github.com/Caik/go-mock-server/internal/service/scenario"
+	"github.com/Caik/go-mock-server/internal/service/tracing"
 	"github.com/Caik/go-mock-server/internal/service/traffic"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"go.uber.org/dig"
 )
@@ -26,6 +35,24 @@ func main() {
 			Msgf("error while setting up CI config: %v", errs)
 	}
 
+	var appArguments *config.AppArguments
+
+	if err := ci.Get(&appArguments); err != nil {
+		log.Fatal().
+			Msgf("error while reading the arguments: %v", err)
+	}
+
+	// importing a document instead of starting the servers
+	if appArguments.Import != nil {
+		if err := importOpenAPI(appArguments.Import); err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("error while importing the OpenAPI document")
+		}
+
+		return
+	}
+
 	// starting servers
 	if err := startServers(); err != nil {
 		log.Fatal().
@@ -54,6 +81,10 @@ func setupCI() []error {
 		errs = append(errs, err)
 	}
 
+	if err := ci.Add(config.NewHostsConfigWatcher); err != nil {
+		errs = append(errs, err)
+	}
+
 	// servers (mock and admin)
 	if err := ci.Add(server.NewServers); err != nil {
 		errs = append(errs, err)
@@ -76,6 +107,30 @@ func setupCI() []error {
 		errs = append(errs, err)
 	}
 
+	if err := ci.Add(controller.NewRequestsController); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := ci.Add(controller.NewScenariosController); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := ci.Add(controller.NewCacheController); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := ci.Add(controller.NewTLSController); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := ci.Add(controller.NewMetricsController); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := ci.Add(controller.NewAuditController); err != nil {
+		errs = append(errs, err)
+	}
+
 	// admin services
 	if err := ci.Add(admin.NewHostsConfigAdminService); err != nil {
 		errs = append(errs, err)
@@ -105,9 +160,71 @@ func setupCI() []error {
 		errs = append(errs, err)
 	}
 
+	// metrics service
+	if err := ci.Add(metrics.NewMetricsService); err != nil {
+		errs = append(errs, err)
+	}
+
+	// tracing service
+	if err := ci.Add(tracing.NewTracingService); err != nil {
+		errs = append(errs, err)
+	}
+
+	// scenario service
+	if err := ci.Add(scenario.NewScenarioService); err != nil {
+		errs = append(errs, err)
+	}
+
+	// audit service
+	if err := ci.Add(audit.NewAuditService); err != nil {
+		errs = append(errs, err)
+	}
+
+	// auth service
+	if err := ci.Add(auth.NewAuthService); err != nil {
+		errs = append(errs, err)
+	}
+
+	// certificate service
+	if err := ci.Add(certificate.NewCertificateService); err != nil {
+		errs = append(errs, err)
+	}
+
 	return errs
 }
 
+func importOpenAPI(command *config.ImportCommand) error {
+	document, err := os.ReadFile(command.Document)
+
+	if err != nil {
+		return err
+	}
+
+	return ci.Invoke(func(service *admin.MockAdminService) error {
+		result, err := service.ImportOpenAPI(document, command.Host, audit.Caller{UUID: uuid.NewString()})
+
+		if err != nil {
+			return err
+		}
+
+		for _, skipped := range result.Skipped {
+			log.Warn().
+				Str("path", skipped.Path).
+				Str("method", skipped.Method).
+				Str("status", skipped.Status).
+				Msgf("response skipped: %s", skipped.Reason)
+		}
+
+		log.Info().
+			Str("host", result.Host).
+			Int("mocks", len(result.Mocks)).
+			Int("skipped", len(result.Skipped)).
+			Msg("mocks generated from the OpenAPI document")
+
+		return nil
+	})
+}
+
 func startServers() error {
 	return ci.Invoke(server.StartServers)
 }
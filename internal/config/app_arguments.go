@@ -1,14 +1,49 @@
 package config
 
+import "time"
+
 type AppArguments struct {
-	MocksDirectory       string `arg:"required,--mocks-directory" help:"path to the mocks directory"`
-	MocksConfigFile      string `arg:"--mocks-config-file" help:"path to the config file"`
-	DefaultContentType   string `default:"text/plain" arg:"--default-content-type" help:"use default content type when no content type is specified in the request"`
-	ServerPort           int    `default:"8080" arg:"-P,--port" help:"port for mock traffic"`
-	AdminPort            int    `default:"9090" arg:"--admin-port" help:"port for admin API and UI (0 to disable)"`
-	TrafficLogBufferSize int    `default:"1000" arg:"--traffic-log-buffer-size" help:"size of in-memory traffic log buffer (0 to disable traffic logging)"`
-	DisableCache         bool   `arg:"--disable-cache" help:"disable the caching"`
-	DisableLatency       bool   `arg:"--disable-latency" help:"disable latency simulation"`
-	DisableCors          bool   `arg:"--disable-cors" help:"disable CORS headers"`
-	UIDirectory          string `arg:"--ui-dir" help:"path to the web UI directory to serve"`
+	MocksDirectory          string         `arg:"required,--mocks-directory" help:"path to the mocks directory"`
+	MocksConfigFile         string         `arg:"--mocks-config-file" help:"path to the config file"`
+	PersistConfig           bool           `arg:"--persist-config" help:"write the hosts config changes made through the admin API back to the config file"`
+	DefaultContentType      string         `default:"text/plain" arg:"--default-content-type" help:"use default content type when no content type is specified in the request"`
+	ServerPort              int            `default:"8080" arg:"-P,--port" help:"port for mock traffic"`
+	AdminPort               int            `default:"9090" arg:"--admin-port" help:"port for admin API and UI (0 to disable)"`
+	TLS                     bool           `arg:"--tls" help:"serve mock traffic over HTTPS"`
+	AdminTLS                bool           `arg:"--admin-tls" help:"serve the admin API and UI over HTTPS"`
+	TLSCertFile             string         `arg:"--tls-cert" help:"path to the TLS certificate (a local CA signs a certificate per host when not set)"`
+	TLSKeyFile              string         `arg:"--tls-key" help:"path to the TLS private key"`
+	TLSCADirectory          string         `arg:"--tls-ca-dir" help:"directory keeping the local CA across restarts"`
+	AdminTokensFile         string         `arg:"--admin-tokens-file" help:"file of the bearer tokens accepted by the admin API, one name:token per line"`
+	AdminHtpasswdFile       string         `arg:"--admin-htpasswd" help:"htpasswd file of the users accepted by the admin API (bcrypt or SHA1 hashes)"`
+	AdminClientCAFile       string         `arg:"--admin-client-ca" help:"CA certificates signing the client certificates accepted by the admin API (requires --admin-tls)"`
+	AdminWriters            string         `arg:"--admin-writers" help:"comma-separated token names, users and client certificate common names with the read-write role (everyone when empty)"`
+	MaxRequestBodySize      int64          `default:"10485760" arg:"--max-request-body-size" help:"size in bytes above which the requests to the mocks are rejected with a 413 (0 for no limit)"`
+	ForwardProxy            bool           `arg:"--forward-proxy" help:"accept proxy requests and CONNECT tunnels on the mock port, intercepting TLS with the local CA"`
+	TrafficLogBufferSize    int            `default:"1000" arg:"--traffic-log-buffer-size" help:"size of in-memory traffic log buffer (0 to disable traffic logging)"`
+	TrafficLogMaxBodySize   int            `default:"65536" arg:"--traffic-log-max-body-size" help:"size in bytes above which the bodies kept in the traffic log are truncated (0 to not keep them)"`
+	TrafficLogRedactHeaders string         `default:"Authorization,Proxy-Authorization,Cookie,Set-Cookie" arg:"--traffic-log-redact-headers" help:"comma-separated headers whose values are hidden in the traffic log"`
+	TrafficLogDirectory     string         `arg:"--traffic-log-dir" help:"directory keeping the traffic log across restarts, as JSONL segments"`
+	TrafficLogSegmentSize   int64          `default:"10485760" arg:"--traffic-log-segment-size" help:"size in bytes above which a new traffic log segment is started"`
+	TrafficLogMaxSegments   int            `default:"10" arg:"--traffic-log-max-segments" help:"number of traffic log segments kept, the oldest being deleted (0 for no limit)"`
+	AuditLogBufferSize      int            `default:"1000" arg:"--audit-log-buffer-size" help:"number of recent admin changes kept in memory (0 to disable the audit log)"`
+	AuditLogFile            string         `arg:"--audit-log-file" help:"file the admin changes are appended to, as JSONL"`
+	DisableCache            bool           `arg:"--disable-cache" help:"disable the caching"`
+	CacheMaxEntries         int            `default:"1000" arg:"--cache-max-entries" help:"maximum number of responses kept in cache (0 for no limit)"`
+	CacheMaxBytes           int64          `default:"67108864" arg:"--cache-max-bytes" help:"maximum size in bytes of the responses kept in cache (0 for no limit)"`
+	CacheTTL                time.Duration  `default:"10m" arg:"--cache-ttl" help:"how long a response is kept in cache (0 for no expiration)"`
+	DisableLatency          bool           `arg:"--disable-latency" help:"disable latency simulation"`
+	DisableCors             bool           `arg:"--disable-cors" help:"disable CORS headers"`
+	DisableMetrics          bool           `arg:"--disable-metrics" help:"disable the Prometheus metrics endpoint"`
+	TracingExporter         string         `arg:"--tracing-exporter" help:"exporter of the traces of the mock requests: otlp or stdout (tracing disabled if empty)"`
+	TracingEndpoint         string         `arg:"--tracing-endpoint" help:"URL of the OTLP/HTTP traces endpoint (the OTEL_EXPORTER_OTLP_* variables apply if empty)"`
+	TracingServiceName      string         `default:"go-mock-server" arg:"--tracing-service-name" help:"service name of the traces"`
+	UIDirectory             string         `arg:"--ui-dir" help:"path to the web UI directory to serve"`
+	Import                  *ImportCommand `arg:"subcommand:import" help:"generate mocks from an OpenAPI 3 or Swagger 2 document, then exit"`
+}
+
+// ImportCommand generates mocks from an OpenAPI document instead of starting the servers
+type ImportCommand struct {
+	Document string `arg:"positional,required" help:"path to the OpenAPI 3 or Swagger 2 document, in JSON or YAML"`
+	Host     string `arg:"--host" help:"host of the generated mocks (the host the document is served from when not set)"`
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 
@@ -27,11 +28,13 @@ func ParseAppArguments() *AppArguments {
 func NewHostsConfig(appArguments *AppArguments) (*HostsConfig, error) {
 	configFilePath := appArguments.MocksConfigFile
 
+	if appArguments.PersistConfig && len(configFilePath) == 0 {
+		return nil, errors.New("--persist-config requires --mocks-config-file to be set")
+	}
+
 	// if config file is not passed, creating a new empty HostsConfig
 	if len(configFilePath) == 0 {
-		return &HostsConfig{
-			Hosts: make(map[string]HostConfig),
-		}, nil
+		return NewHostsConfigFrom(nil), nil
 	}
 
 	absolutePath, err := filepath.Abs(configFilePath)
@@ -42,23 +45,79 @@ func NewHostsConfig(appArguments *AppArguments) (*HostsConfig, error) {
 
 	data, err := os.ReadFile(absolutePath)
 
+	// when persisting, a missing config file is created on the first change
+	if appArguments.PersistConfig && errors.Is(err, os.ErrNotExist) {
+		return NewHostsConfigFrom(nil), nil
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	var newHostsConfig HostsConfig
+	return parseHostsConfig(data)
+}
 
-	err = json.Unmarshal(data, &newHostsConfig)
+// parseHostsConfig parses and validates the content of a config file
+func parseHostsConfig(data []byte) (*HostsConfig, error) {
+	newHostsConfig := &HostsConfig{}
 
-	if err != nil {
+	if err := json.Unmarshal(data, newHostsConfig); err != nil {
 		return nil, err
 	}
 
-	if err = newHostsConfig.Validate(); err != nil {
+	if err := newHostsConfig.Validate(); err != nil {
 		return nil, err
 	}
 
-	return &newHostsConfig, nil
+	return newHostsConfig, nil
+}
+
+// WriteHostsConfig writes the hosts config to the given file atomically: it is written to a temporary file in the
+// same directory first, which is then renamed over the target, so readers never see a partially written file
+func WriteHostsConfig(path string, hostsConfig *HostsConfig) error {
+	data, err := json.MarshalIndent(hostsConfig, "", "    ")
+
+	if err != nil {
+		return err
+	}
+
+	dir, name := filepath.Split(path)
+	mode := os.FileMode(0644)
+
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tempFile, err := os.CreateTemp(dir, "."+name+".*.tmp")
+
+	if err != nil {
+		return err
+	}
+
+	tempPath := tempFile.Name()
+
+	// the temporary file only remains if something failed before the rename
+	defer os.Remove(tempPath)
+
+	if _, err := tempFile.Write(append(data, '\n')); err != nil {
+		tempFile.Close()
+		return err
+	}
+
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return err
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tempPath, mode); err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, path)
 }
 
 func NewMocksDirectoryConfig(appArguments *AppArguments) (*MocksDirectoryConfig, error) {
@@ -44,12 +44,12 @@ func TestNewHostsConfig_EmptyConfigFile(t *testing.T) {
 		t.Fatal("expected hostsConfig to be non-nil")
 	}
 
-	if hostsConfig.Hosts == nil {
+	if hostsConfig.Snapshot().Hosts == nil {
 		t.Fatal("expected Hosts map to be initialized")
 	}
 
-	if len(hostsConfig.Hosts) != 0 {
-		t.Errorf("expected empty Hosts map, got %d entries", len(hostsConfig.Hosts))
+	if len(hostsConfig.Snapshot().Hosts) != 0 {
+		t.Errorf("expected empty Hosts map, got %d entries", len(hostsConfig.Snapshot().Hosts))
 	}
 }
 
@@ -58,16 +58,14 @@ func TestNewHostsConfig_ValidConfigFile(t *testing.T) {
 	tempDir := t.TempDir()
 	configFile := filepath.Join(tempDir, "test-config.json")
 
-	validConfig := HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				LatencyConfig: &LatencyConfig{
-					Min: intPtr(100),
-					Max: intPtr(200),
-				},
+	validConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			LatencyConfig: &LatencyConfig{
+				Min: intPtr(100),
+				Max: intPtr(200),
 			},
 		},
-	}
+	})
 
 	configData, err := json.Marshal(validConfig)
 	if err != nil {
@@ -93,11 +91,11 @@ func TestNewHostsConfig_ValidConfigFile(t *testing.T) {
 		t.Fatal("expected hostsConfig to be non-nil")
 	}
 
-	if len(hostsConfig.Hosts) != 1 {
-		t.Errorf("expected 1 host, got %d", len(hostsConfig.Hosts))
+	if len(hostsConfig.Snapshot().Hosts) != 1 {
+		t.Errorf("expected 1 host, got %d", len(hostsConfig.Snapshot().Hosts))
 	}
 
-	hostConfig, exists := hostsConfig.Hosts["example.com"]
+	hostConfig, exists := hostsConfig.Snapshot().Hosts["example.com"]
 	if !exists {
 		t.Error("expected example.com host to exist")
 	}
@@ -127,6 +125,92 @@ func TestNewHostsConfig_NonExistentFile(t *testing.T) {
 	}
 }
 
+func TestNewHostsConfig_PersistConfig(t *testing.T) {
+	t.Run("requires a config file", func(t *testing.T) {
+		_, err := NewHostsConfig(&AppArguments{PersistConfig: true})
+
+		if err == nil {
+			t.Fatal("expected error when persisting without a config file")
+		}
+	})
+
+	t.Run("starts empty when the config file doesn't exist yet", func(t *testing.T) {
+		hostsConfig, err := NewHostsConfig(&AppArguments{
+			MocksConfigFile: filepath.Join(t.TempDir(), "config.json"),
+			PersistConfig:   true,
+		})
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if hostsConfig.Snapshot().Hosts == nil || len(hostsConfig.Snapshot().Hosts) != 0 {
+			t.Errorf("expected empty Hosts map, got %v", hostsConfig.Snapshot().Hosts)
+		}
+	})
+}
+
+func TestWriteHostsConfig(t *testing.T) {
+	max := 200
+	min := 100
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {LatencyConfig: &LatencyConfig{Min: &min, Max: &max}},
+	})
+
+	t.Run("writes a config file that loads back", func(t *testing.T) {
+		tempDir := t.TempDir()
+		configFile := filepath.Join(tempDir, "config.json")
+
+		if err := WriteHostsConfig(configFile, hostsConfig); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		loaded, err := NewHostsConfig(&AppArguments{MocksConfigFile: configFile})
+
+		if err != nil {
+			t.Fatalf("expected no error loading the written file, got %v", err)
+		}
+
+		if *loaded.Snapshot().Hosts["example.com"].LatencyConfig.Max != 200 {
+			t.Errorf("unexpected loaded config: %+v", loaded)
+		}
+
+		entries, _ := os.ReadDir(tempDir)
+
+		if len(entries) != 1 {
+			t.Errorf("expected no temporary file left behind, got %d entries", len(entries))
+		}
+	})
+
+	t.Run("replaces the existing file keeping its permissions", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "config.json")
+
+		if err := os.WriteFile(configFile, []byte(`{"hosts":{}}`), 0600); err != nil {
+			t.Fatalf("failed to create config file: %v", err)
+		}
+
+		if err := WriteHostsConfig(configFile, hostsConfig); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		data, _ := os.ReadFile(configFile)
+
+		if !strings.Contains(string(data), "example.com") {
+			t.Errorf("expected config file to be replaced, got %s", data)
+		}
+
+		if info, _ := os.Stat(configFile); info.Mode().Perm() != 0600 {
+			t.Errorf("expected permissions 0600, got %v", info.Mode().Perm())
+		}
+	})
+
+	t.Run("returns an error when the directory doesn't exist", func(t *testing.T) {
+		if err := WriteHostsConfig(filepath.Join(t.TempDir(), "missing", "config.json"), hostsConfig); err == nil {
+			t.Error("expected error for a missing directory")
+		}
+	})
+}
+
 func TestNewHostsConfig_InvalidJSON(t *testing.T) {
 	// Create a temporary file with invalid JSON
 	tempDir := t.TempDir()
@@ -158,16 +242,14 @@ func TestNewHostsConfig_InvalidHostsConfig(t *testing.T) {
 	tempDir := t.TempDir()
 	configFile := filepath.Join(tempDir, "invalid-hosts-config.json")
 
-	invalidConfig := HostsConfig{
-		Hosts: map[string]HostConfig{
-			"invalid-host": { // This doesn't match the host regex pattern
-				LatencyConfig: &LatencyConfig{
-					Min: intPtr(100),
-					Max: intPtr(200),
-				},
+	invalidConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"invalid-host": { // This doesn't match the host regex pattern
+			LatencyConfig: &LatencyConfig{
+				Min: intPtr(100),
+				Max: intPtr(200),
 			},
 		},
-	}
+	})
 
 	configData, err := json.Marshal(invalidConfig)
 	if err != nil {
@@ -284,16 +366,14 @@ func TestNewHostsConfig_InvalidLatencyConfig(t *testing.T) {
 	tempDir := t.TempDir()
 	configFile := filepath.Join(tempDir, "invalid-latency-config.json")
 
-	invalidConfig := HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				LatencyConfig: &LatencyConfig{
-					Min: intPtr(200), // min > max should fail validation
-					Max: intPtr(100),
-				},
+	invalidConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			LatencyConfig: &LatencyConfig{
+				Min: intPtr(200), // min > max should fail validation
+				Max: intPtr(100),
 			},
 		},
-	}
+	})
 
 	configData, err := json.Marshal(invalidConfig)
 	if err != nil {
@@ -325,17 +405,15 @@ func TestNewHostsConfig_InvalidStatusConfig(t *testing.T) {
 	tempDir := t.TempDir()
 	configFile := filepath.Join(tempDir, "invalid-status-config.json")
 
-	invalidConfig := HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				StatusesConfig: map[string]StatusConfig{
-					"500": {
-						Percentage: intPtr(150), // > 100 should fail validation
-					},
+	invalidConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			StatusesConfig: map[string]StatusConfig{
+				"500": {
+					Percentage: intPtr(150), // > 100 should fail validation
 				},
 			},
 		},
-	}
+	})
 
 	configData, err := json.Marshal(invalidConfig)
 	if err != nil {
@@ -396,43 +474,41 @@ func TestNewHostsConfig_ComplexValidConfig(t *testing.T) {
 	tempDir := t.TempDir()
 	configFile := filepath.Join(tempDir, "complex-config.json")
 
-	complexConfig := HostsConfig{
-		Hosts: map[string]HostConfig{
-			"api.example.com": {
-				LatencyConfig: &LatencyConfig{
-					Min: intPtr(50),
-					P95: intPtr(150),
-					P99: intPtr(180),
-					Max: intPtr(200),
-				},
-				StatusesConfig: map[string]StatusConfig{
-					"500": {
-						Percentage: intPtr(10),
-						LatencyConfig: &LatencyConfig{
-							Min: intPtr(100),
-							Max: intPtr(300),
-						},
-					},
-					"503": {
-						Percentage: intPtr(5),
+	complexConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"api.example.com": {
+			LatencyConfig: &LatencyConfig{
+				Min: intPtr(50),
+				P95: intPtr(150),
+				P99: intPtr(180),
+				Max: intPtr(200),
+			},
+			StatusesConfig: map[string]StatusConfig{
+				"500": {
+					Percentage: intPtr(10),
+					LatencyConfig: &LatencyConfig{
+						Min: intPtr(100),
+						Max: intPtr(300),
 					},
 				},
-				UrisConfig: map[string]UriConfig{
-					"/api/v1/users": {
-						LatencyConfig: &LatencyConfig{
-							Min: intPtr(25),
-							Max: intPtr(100),
-						},
-						StatusesConfig: map[string]StatusConfig{
-							"404": {
-								Percentage: intPtr(20),
-							},
+				"503": {
+					Percentage: intPtr(5),
+				},
+			},
+			UrisConfig: map[string]UriConfig{
+				"/api/v1/users": {
+					LatencyConfig: &LatencyConfig{
+						Min: intPtr(25),
+						Max: intPtr(100),
+					},
+					StatusesConfig: map[string]StatusConfig{
+						"404": {
+							Percentage: intPtr(20),
 						},
 					},
 				},
 			},
 		},
-	}
+	})
 
 	configData, err := json.Marshal(complexConfig)
 	if err != nil {
@@ -459,7 +535,7 @@ func TestNewHostsConfig_ComplexValidConfig(t *testing.T) {
 	}
 
 	// Verify the complex configuration was loaded correctly
-	hostConfig, exists := hostsConfig.Hosts["api.example.com"]
+	hostConfig, exists := hostsConfig.Snapshot().Hosts["api.example.com"]
 	if !exists {
 		t.Fatal("expected api.example.com host to exist")
 	}
@@ -507,10 +583,10 @@ func TestNewHostsConfig_EmptyJSONFile(t *testing.T) {
 
 	// Empty JSON object {} will result in nil Hosts map after unmarshaling
 	// This is expected behavior - the validation should handle nil maps gracefully
-	if hostsConfig.Hosts == nil {
+	if hostsConfig.Snapshot().Hosts == nil {
 		t.Log("Hosts map is nil for empty JSON, which is expected behavior")
-	} else if len(hostsConfig.Hosts) != 0 {
-		t.Errorf("expected empty Hosts map, got %d entries", len(hostsConfig.Hosts))
+	} else if len(hostsConfig.Snapshot().Hosts) != 0 {
+		t.Errorf("expected empty Hosts map, got %d entries", len(hostsConfig.Snapshot().Hosts))
 	}
 }
 
@@ -538,12 +614,12 @@ func TestNewHostsConfig_EmptyHostsJSONFile(t *testing.T) {
 		t.Fatal("expected hostsConfig to be non-nil")
 	}
 
-	if hostsConfig.Hosts == nil {
+	if hostsConfig.Snapshot().Hosts == nil {
 		t.Fatal("expected Hosts map to be initialized")
 	}
 
-	if len(hostsConfig.Hosts) != 0 {
-		t.Errorf("expected empty Hosts map, got %d entries", len(hostsConfig.Hosts))
+	if len(hostsConfig.Snapshot().Hosts) != 0 {
+		t.Errorf("expected empty Hosts map, got %d entries", len(hostsConfig.Snapshot().Hosts))
 	}
 }
 
@@ -552,17 +628,15 @@ func TestNewHostsConfig_InvalidErrorCode(t *testing.T) {
 	tempDir := t.TempDir()
 	configFile := filepath.Join(tempDir, "invalid-status-code-config.json")
 
-	invalidConfig := HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				StatusesConfig: map[string]StatusConfig{
-					"600": { // 6xx codes are not allowed
-						Percentage: intPtr(10),
-					},
+	invalidConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			StatusesConfig: map[string]StatusConfig{
+				"600": { // 6xx codes are not allowed
+					Percentage: intPtr(10),
 				},
 			},
 		},
-	}
+	})
 
 	configData, err := json.Marshal(invalidConfig)
 	if err != nil {
@@ -594,20 +668,18 @@ func TestNewHostsConfig_InvalidUriPattern(t *testing.T) {
 	tempDir := t.TempDir()
 	configFile := filepath.Join(tempDir, "invalid-uri-config.json")
 
-	invalidConfig := HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				UrisConfig: map[string]UriConfig{
-					"invalid uri pattern": { // This doesn't match URI regex
-						LatencyConfig: &LatencyConfig{
-							Min: intPtr(100),
-							Max: intPtr(200),
-						},
+	invalidConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			UrisConfig: map[string]UriConfig{
+				"invalid uri pattern": { // This doesn't match URI regex
+					LatencyConfig: &LatencyConfig{
+						Min: intPtr(100),
+						Max: intPtr(200),
 					},
 				},
 			},
 		},
-	}
+	})
 
 	configData, err := json.Marshal(invalidConfig)
 	if err != nil {
@@ -824,16 +896,14 @@ func TestNewHostsConfig_FilePathAbsoluteConversion(t *testing.T) {
 	configFileName := "test-config.json"
 	configFile := filepath.Join(tempDir, configFileName)
 
-	validConfig := HostsConfig{
-		Hosts: map[string]HostConfig{
-			"test.com": {
-				LatencyConfig: &LatencyConfig{
-					Min: intPtr(100),
-					Max: intPtr(200),
-				},
+	validConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"test.com": {
+			LatencyConfig: &LatencyConfig{
+				Min: intPtr(100),
+				Max: intPtr(200),
 			},
 		},
-	}
+	})
 
 	configData, err := json.Marshal(validConfig)
 	if err != nil {
@@ -872,8 +942,8 @@ func TestNewHostsConfig_FilePathAbsoluteConversion(t *testing.T) {
 		t.Fatal("expected hostsConfig to be non-nil")
 	}
 
-	if len(hostsConfig.Hosts) != 1 {
-		t.Errorf("expected 1 host, got %d", len(hostsConfig.Hosts))
+	if len(hostsConfig.Snapshot().Hosts) != 1 {
+		t.Errorf("expected 1 host, got %d", len(hostsConfig.Snapshot().Hosts))
 	}
 }
 
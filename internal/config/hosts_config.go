@@ -1,14 +1,34 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/Caik/go-mock-server/internal/util"
 )
 
+// HostsConfig holds the configuration of all the hosts. Readers always get an immutable snapshot, while every change
+// goes through a single serialized update that publishes a new snapshot (copy-on-write), so no lock is taken on reads.
 type HostsConfig struct {
+	current  atomic.Pointer[HostsSnapshot]
+	updateMu sync.Mutex
+}
+
+// HostsSnapshot is the hosts config at a given version. It is shared by all readers and must not be modified.
+type HostsSnapshot struct {
+	Version uint64                `json:"version"`
+	Hosts   map[string]HostConfig `json:"hosts"`
+}
+
+// hostsConfigFile is the format of the config file
+type hostsConfigFile struct {
 	Hosts map[string]HostConfig `json:"hosts"`
 }
 
@@ -16,11 +36,16 @@ type HostConfig struct {
 	LatencyConfig  *LatencyConfig          `json:"latency"`
 	StatusesConfig map[string]StatusConfig `json:"statuses"`
 	UrisConfig     map[string]UriConfig    `json:"uris"`
+	RecordConfig   *RecordConfig           `json:"record"`
+	ProxyConfig    *ProxyConfig            `json:"proxy"`
+	ContractConfig *ContractConfig         `json:"contract"`
+	FaultsConfig   map[string]FaultConfig  `json:"faults"`
 }
 
 type UriConfig struct {
 	LatencyConfig  *LatencyConfig          `json:"latency"`
 	StatusesConfig map[string]StatusConfig `json:"statuses"`
+	FaultsConfig   map[string]FaultConfig  `json:"faults"`
 }
 
 type LatencyConfig struct {
@@ -30,13 +55,141 @@ type LatencyConfig struct {
 	Max *int `json:"max"`
 }
 
+type RecordConfig struct {
+	Upstream string `json:"upstream"`
+}
+
+type ProxyConfig struct {
+	Upstream string `json:"upstream"`
+}
+
+// ContractConfig attaches an OpenAPI document to a host, checking the requests and the mock responses against it
+type ContractConfig struct {
+	Spec string `json:"spec"`
+	Mode string `json:"mode"`
+}
+
+const (
+	// ContractModeLog reports the contract violations in the traffic log only
+	ContractModeLog = "log"
+	// ContractModeReject rejects the requests violating the contract, and the mock responses violating it
+	ContractModeReject = "reject"
+)
+
 type StatusConfig struct {
 	Percentage    *int           `json:"percentage"`
 	LatencyConfig *LatencyConfig `json:"latency"`
 }
 
+// FaultConfig breaks a percentage of the responses at the connection level, in the way given by its fault type
+type FaultConfig struct {
+	Percentage *int `json:"percentage"`
+	Duration   *int `json:"duration"` // how long a hang lasts, in milliseconds. It lasts until the client gives up if unset
+}
+
+const (
+	// FaultConnectionReset closes the connection abruptly (TCP RST), without sending anything
+	FaultConnectionReset = "connection_reset"
+	// FaultHang sends the headers of the response, then hangs without sending the body
+	FaultHang = "hang"
+	// FaultTruncatedBody sends the headers of the response, then closes the connection halfway through the body
+	FaultTruncatedBody = "truncated_body"
+	// FaultInvalidContentLength sends the response with a Content-Length that can't be parsed
+	FaultInvalidContentLength = "invalid_content_length"
+	// FaultGarbage sends random bytes instead of an HTTP response, then closes the connection
+	FaultGarbage = "garbage"
+)
+
+// faultTypes are the known fault types
+var faultTypes = []string{FaultConnectionReset, FaultHang, FaultTruncatedBody, FaultInvalidContentLength, FaultGarbage}
+
+// NewHostsConfigFrom creates a new HostsConfig with the given hosts, at version 1
+func NewHostsConfigFrom(hosts map[string]HostConfig) *HostsConfig {
+	if hosts == nil {
+		hosts = make(map[string]HostConfig)
+	}
+
+	hostsConfig := &HostsConfig{}
+	hostsConfig.current.Store(&HostsSnapshot{Version: 1, Hosts: hosts})
+
+	return hostsConfig
+}
+
+// Snapshot returns the current hosts config
+func (h *HostsConfig) Snapshot() *HostsSnapshot {
+	if snapshot := h.current.Load(); snapshot != nil {
+		return snapshot
+	}
+
+	return &HostsSnapshot{Hosts: map[string]HostConfig{}}
+}
+
+// Version returns the current version of the hosts config, which increases with every change
+func (h *HostsConfig) Version() uint64 {
+	return h.Snapshot().Version
+}
+
+// Update applies a change to a copy of the hosts and publishes it as a new version. Updates are serialized, and
+// nothing is published when the change returns an error.
+func (h *HostsConfig) Update(change func(hosts map[string]HostConfig) error) error {
+	h.updateMu.Lock()
+	defer h.updateMu.Unlock()
+
+	current := h.Snapshot()
+	hosts := make(map[string]HostConfig, len(current.Hosts))
+
+	for host, hostConfig := range current.Hosts {
+		hosts[host] = hostConfig
+	}
+
+	if err := change(hosts); err != nil {
+		return err
+	}
+
+	h.current.Store(&HostsSnapshot{Version: current.Version + 1, Hosts: hosts})
+
+	return nil
+}
+
+// Rollback publishes the hosts of the given snapshot again, as a new version, unless another change was published
+// after the given version. It returns whether the hosts were rolled back.
+func (h *HostsConfig) Rollback(previous *HostsSnapshot, version uint64) bool {
+	h.updateMu.Lock()
+	defer h.updateMu.Unlock()
+
+	current := h.Snapshot()
+
+	if current.Version != version {
+		return false
+	}
+
+	h.current.Store(&HostsSnapshot{Version: current.Version + 1, Hosts: previous.Hosts})
+
+	return true
+}
+
+func (h *HostsConfig) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hostsConfigFile{Hosts: h.Snapshot().Hosts})
+}
+
+func (h *HostsConfig) UnmarshalJSON(data []byte) error {
+	var file hostsConfigFile
+
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+
+	if file.Hosts == nil {
+		file.Hosts = make(map[string]HostConfig)
+	}
+
+	h.current.Store(&HostsSnapshot{Version: 1, Hosts: file.Hosts})
+
+	return nil
+}
+
 func (h *HostsConfig) Validate() error {
-	for host, hostConfig := range h.Hosts {
+	for host, hostConfig := range h.Snapshot().Hosts {
 		if !util.HostRegex.MatchString(host) {
 			return errors.New("invalid hosts config found: it doesn't a host pattern")
 		}
@@ -49,8 +202,23 @@ func (h *HostsConfig) Validate() error {
 	return nil
 }
 
+// Replace swaps the configuration of all the hosts for the one of the given HostsConfig, as a new version
+func (h *HostsConfig) Replace(newConfig *HostsConfig) {
+	hosts := newConfig.Snapshot().Hosts
+
+	h.Update(func(current map[string]HostConfig) error {
+		clear(current)
+
+		for host, hostConfig := range hosts {
+			current[host] = hostConfig
+		}
+
+		return nil
+	})
+}
+
 func (h *HostsConfig) GetHostConfig(host string) *HostConfig {
-	hostConfig, exists := h.Hosts[host]
+	hostConfig, exists := h.Snapshot().Hosts[host]
 
 	if !exists {
 		return nil
@@ -60,80 +228,111 @@ func (h *HostsConfig) GetHostConfig(host string) *HostConfig {
 }
 
 func (h *HostsConfig) SetHostConfig(host string, newConfig HostConfig) {
-	h.Hosts[host] = newConfig
+	h.Update(func(hosts map[string]HostConfig) error {
+		hosts[host] = newConfig
+		return nil
+	})
 }
 
 func (h *HostsConfig) DeleteHostConfig(host string) {
-	delete(h.Hosts, host)
+	h.Update(func(hosts map[string]HostConfig) error {
+		delete(hosts, host)
+		return nil
+	})
 }
 
-func (h *HostsConfig) UpdateHostLatencyConfig(host string, latencyConfig *LatencyConfig) (*HostConfig, error) {
-	hostConfig, exists := h.Hosts[host]
+// updateHostConfig applies a change to the config of an existing host. It returns nil if the host doesn't exist.
+func (h *HostsConfig) updateHostConfig(host string, change func(hostConfig *HostConfig)) (*HostConfig, error) {
+	var updated *HostConfig
 
-	if !exists {
-		return nil, nil
-	}
+	err := h.Update(func(hosts map[string]HostConfig) error {
+		hostConfig, exists := hosts[host]
+
+		if !exists {
+			return nil
+		}
+
+		change(&hostConfig)
+		hosts[host] = hostConfig
+		updated = &hostConfig
 
-	hostConfig.LatencyConfig = latencyConfig
-	h.Hosts[host] = hostConfig
+		return nil
+	})
 
-	return &hostConfig, nil
+	return updated, err
+}
+
+func (h *HostsConfig) UpdateHostLatencyConfig(host string, latencyConfig *LatencyConfig) (*HostConfig, error) {
+	return h.updateHostConfig(host, func(hostConfig *HostConfig) {
+		hostConfig.LatencyConfig = latencyConfig
+	})
 }
 
 func (h *HostsConfig) DeleteHostLatencyConfig(host string) (*HostConfig, error) {
-	hostConfig, exists := h.Hosts[host]
+	return h.updateHostConfig(host, func(hostConfig *HostConfig) {
+		hostConfig.LatencyConfig = nil
+	})
+}
 
-	if !exists {
-		return nil, nil
-	}
+func (h *HostsConfig) UpdateHostStatusesConfig(host string, statusesConfig map[string]StatusConfig) (*HostConfig, error) {
+	return h.updateHostConfig(host, func(hostConfig *HostConfig) {
+		hostConfig.StatusesConfig = statusesConfig
+	})
+}
+
+func (h *HostsConfig) DeleteHostStatusConfig(host, statusCode string) (*HostConfig, error) {
+	return h.updateHostConfig(host, func(hostConfig *HostConfig) {
+		// the statuses map is shared with the previous snapshots, so it's copied rather than changed
+		statusesConfig := make(map[string]StatusConfig, len(hostConfig.StatusesConfig))
+
+		for code, statusConfig := range hostConfig.StatusesConfig {
+			if code != statusCode {
+				statusesConfig[code] = statusConfig
+			}
+		}
 
-	hostConfig.LatencyConfig = nil
-	h.Hosts[host] = hostConfig
+		hostConfig.StatusesConfig = statusesConfig
+	})
+}
 
-	return &hostConfig, nil
+func (h *HostsConfig) UpdateHostUrisConfig(host string, urisConfig map[string]UriConfig) (*HostConfig, error) {
+	return h.updateHostConfig(host, func(hostConfig *HostConfig) {
+		hostConfig.UrisConfig = urisConfig
+	})
 }
 
-func (h *HostsConfig) UpdateHostStatusesConfig(host string, statusesConfig map[string]StatusConfig) (*HostConfig, error) {
-	hostConfig, exists := h.Hosts[host]
+func (h *HostsConfig) GetHostRecordConfig(host string) *RecordConfig {
+	hostConfig, exists := h.Snapshot().Hosts[host]
 
 	if !exists {
-		return nil, nil
+		return nil
 	}
 
-	hostConfig.StatusesConfig = statusesConfig
-	h.Hosts[host] = hostConfig
-
-	return &hostConfig, nil
+	return hostConfig.RecordConfig
 }
 
-func (h *HostsConfig) DeleteHostStatusConfig(host, statusCode string) (*HostConfig, error) {
-	hostConfig, exists := h.Hosts[host]
+func (h *HostsConfig) GetHostProxyConfig(host string) *ProxyConfig {
+	hostConfig, exists := h.Snapshot().Hosts[host]
 
 	if !exists {
-		return nil, nil
+		return nil
 	}
 
-	delete(hostConfig.StatusesConfig, statusCode)
-	h.Hosts[host] = hostConfig
-
-	return &hostConfig, nil
+	return hostConfig.ProxyConfig
 }
 
-func (h *HostsConfig) UpdateHostUrisConfig(host string, urisConfig map[string]UriConfig) (*HostConfig, error) {
-	hostConfig, exists := h.Hosts[host]
+func (h *HostsConfig) GetHostContractConfig(host string) *ContractConfig {
+	hostConfig, exists := h.Snapshot().Hosts[host]
 
 	if !exists {
-		return nil, nil
+		return nil
 	}
 
-	hostConfig.UrisConfig = urisConfig
-	h.Hosts[host] = hostConfig
-
-	return &hostConfig, nil
+	return hostConfig.ContractConfig
 }
 
 func (h *HostsConfig) GetAppropriateStatusesConfig(host, uri string) (*map[string]StatusConfig, string) {
-	hostConfig, exists := h.Hosts[host]
+	hostConfig, exists := h.Snapshot().Hosts[host]
 
 	if !exists {
 		return nil, ""
@@ -155,8 +354,31 @@ func (h *HostsConfig) GetAppropriateStatusesConfig(host, uri string) (*map[strin
 	return nil, ""
 }
 
+func (h *HostsConfig) GetAppropriateFaultsConfig(host, uri string) (map[string]FaultConfig, string) {
+	hostConfig, exists := h.Snapshot().Hosts[host]
+
+	if !exists {
+		return nil, ""
+	}
+
+	faultsConfig := hostConfig.FaultsConfig
+	scope := "Host Default"
+	uriConfig, exists := hostConfig.UrisConfig[uri]
+
+	if exists && len(uriConfig.FaultsConfig) > 0 {
+		faultsConfig = uriConfig.FaultsConfig
+		scope = "URI Override"
+	}
+
+	if len(faultsConfig) > 0 {
+		return faultsConfig, scope
+	}
+
+	return nil, ""
+}
+
 func (h *HostsConfig) GetAppropriateLatencyConfig(host, uri string) (*LatencyConfig, string) {
-	hostConfig, exists := h.Hosts[host]
+	hostConfig, exists := h.Snapshot().Hosts[host]
 
 	if !exists {
 		return nil, ""
@@ -205,6 +427,10 @@ func (h *HostConfig) Validate() error {
 		return errors.New("invalid host config found: the sum of all percentages should not exceed 100")
 	}
 
+	if err := validateFaults(h.FaultsConfig); err != nil {
+		return fmt.Errorf("invalid host config found: %v", err)
+	}
+
 	for uri, uriConfig := range h.UrisConfig {
 		if !util.UriRegex.MatchString(uri) {
 			return errors.New("invalid host config provided: invalid uri config found: it doesn't match a uri pattern")
@@ -215,6 +441,28 @@ func (h *HostConfig) Validate() error {
 		}
 	}
 
+	if h.RecordConfig != nil && h.ProxyConfig != nil {
+		return errors.New("invalid host config found: record and proxy should not be both set")
+	}
+
+	if h.RecordConfig != nil {
+		if err := h.RecordConfig.validate(); err != nil {
+			return fmt.Errorf("invalid host config found: %v", err)
+		}
+	}
+
+	if h.ProxyConfig != nil {
+		if err := h.ProxyConfig.validate(); err != nil {
+			return fmt.Errorf("invalid host config found: %v", err)
+		}
+	}
+
+	if h.ContractConfig != nil {
+		if err := h.ContractConfig.validate(); err != nil {
+			return fmt.Errorf("invalid host config found: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -243,6 +491,34 @@ func (l *LatencyConfig) validate() error {
 	return nil
 }
 
+func (r *RecordConfig) validate() error {
+	if err := validateUpstream(r.Upstream); err != nil {
+		return fmt.Errorf("invalid record config found: %v", err)
+	}
+
+	return nil
+}
+
+func (p *ProxyConfig) validate() error {
+	if err := validateUpstream(p.Upstream); err != nil {
+		return fmt.Errorf("invalid proxy config found: %v", err)
+	}
+
+	return nil
+}
+
+func (c *ContractConfig) validate() error {
+	if len(c.Spec) == 0 {
+		return errors.New("invalid contract config found: spec should be the path to an OpenAPI document")
+	}
+
+	if c.Mode != "" && c.Mode != ContractModeLog && c.Mode != ContractModeReject {
+		return fmt.Errorf("invalid contract config found: mode should be either %q or %q", ContractModeLog, ContractModeReject)
+	}
+
+	return nil
+}
+
 func (s *StatusConfig) validate() error {
 	if s.Percentage == nil || *s.Percentage <= 0 || *s.Percentage > 100 {
 		return errors.New("invalid status config found: percentage should be greater than 0 and lesser than 100")
@@ -260,8 +536,8 @@ func (s *StatusConfig) validate() error {
 }
 
 func (u *UriConfig) validate() error {
-	if u.StatusesConfig == nil && u.LatencyConfig == nil {
-		return errors.New("invalid uri config found: latency or statuses should not be both null")
+	if u.StatusesConfig == nil && u.LatencyConfig == nil && u.FaultsConfig == nil {
+		return errors.New("invalid uri config found: latency, statuses or faults should not be all null")
 	}
 
 	if u.LatencyConfig != nil {
@@ -294,5 +570,58 @@ func (u *UriConfig) validate() error {
 		return errors.New("invalid uri config found: the sum of all percentages should not exceed 100")
 	}
 
+	if err := validateFaults(u.FaultsConfig); err != nil {
+		return fmt.Errorf("invalid uri config found: %v", err)
+	}
+
+	return nil
+}
+
+func (f *FaultConfig) validate() error {
+	if f.Percentage == nil || *f.Percentage <= 0 || *f.Percentage > 100 {
+		return errors.New("invalid fault config found: percentage should be greater than 0 and lesser than 100")
+	}
+
+	if f.Duration != nil && *f.Duration <= 0 {
+		return errors.New("invalid fault config found: duration should be greater than 0")
+	}
+
+	return nil
+}
+
+// validateFaults checks the fault types and the percentages of the faults, which are drawn apart from the statuses
+func validateFaults(faultsConfig map[string]FaultConfig) error {
+	sumPercentage := 0
+
+	for faultType, faultConfig := range faultsConfig {
+		if !slices.Contains(faultTypes, faultType) {
+			return fmt.Errorf("invalid fault type %q: it should be one of %s", faultType, strings.Join(faultTypes, ", "))
+		}
+
+		if err := faultConfig.validate(); err != nil {
+			return err
+		}
+
+		if faultConfig.Duration != nil && faultType != FaultHang {
+			return fmt.Errorf("invalid fault config found: duration is only supported by the %q fault", FaultHang)
+		}
+
+		sumPercentage += *faultConfig.Percentage
+	}
+
+	if sumPercentage > 100 {
+		return errors.New("the sum of all fault percentages should not exceed 100")
+	}
+
+	return nil
+}
+
+func validateUpstream(upstream string) error {
+	parsedUrl, err := url.Parse(upstream)
+
+	if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || len(parsedUrl.Host) == 0 {
+		return errors.New("upstream should be an absolute http or https url")
+	}
+
 	return nil
 }
@@ -1,6 +1,10 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 )
 
@@ -9,16 +13,14 @@ import (
 // Test HostsConfig methods
 
 func TestHostsConfig_GetHostConfig(t *testing.T) {
-	hostsConfig := &HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				LatencyConfig: &LatencyConfig{
-					Min: intPtr(100),
-					Max: intPtr(200),
-				},
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			LatencyConfig: &LatencyConfig{
+				Min: intPtr(100),
+				Max: intPtr(200),
 			},
 		},
-	}
+	})
 
 	// Test existing host
 	hostConfig := hostsConfig.GetHostConfig("example.com")
@@ -41,11 +43,68 @@ func TestHostsConfig_GetHostConfig(t *testing.T) {
 	}
 }
 
-func TestHostsConfig_SetHostConfig(t *testing.T) {
-	hostsConfig := &HostsConfig{
-		Hosts: make(map[string]HostConfig),
+func TestHostsConfig_GetHostRecordConfig(t *testing.T) {
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			RecordConfig: &RecordConfig{Upstream: "http://localhost:3000"},
+		},
+		"other.com": {},
+	})
+
+	recordConfig := hostsConfig.GetHostRecordConfig("example.com")
+
+	if recordConfig == nil || recordConfig.Upstream != "http://localhost:3000" {
+		t.Errorf("expected record config with upstream, got %v", recordConfig)
+	}
+
+	if hostsConfig.GetHostRecordConfig("other.com") != nil {
+		t.Error("expected nil record config for host without recording")
 	}
 
+	if hostsConfig.GetHostRecordConfig("nonexistent.com") != nil {
+		t.Error("expected nil record config for non-existent host")
+	}
+}
+
+func TestHostsConfig_GetHostProxyConfig(t *testing.T) {
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			ProxyConfig: &ProxyConfig{Upstream: "https://api.example.com"},
+		},
+	})
+
+	proxyConfig := hostsConfig.GetHostProxyConfig("example.com")
+
+	if proxyConfig == nil || proxyConfig.Upstream != "https://api.example.com" {
+		t.Errorf("expected proxy config with upstream, got %v", proxyConfig)
+	}
+
+	if hostsConfig.GetHostProxyConfig("nonexistent.com") != nil {
+		t.Error("expected nil proxy config for non-existent host")
+	}
+}
+
+func TestHostsConfig_GetHostContractConfig(t *testing.T) {
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			ContractConfig: &ContractConfig{Spec: "openapi.yaml", Mode: ContractModeReject},
+		},
+	})
+
+	contractConfig := hostsConfig.GetHostContractConfig("example.com")
+
+	if contractConfig == nil || contractConfig.Spec != "openapi.yaml" {
+		t.Errorf("expected contract config with spec, got %v", contractConfig)
+	}
+
+	if hostsConfig.GetHostContractConfig("nonexistent.com") != nil {
+		t.Error("expected nil contract config for non-existent host")
+	}
+}
+
+func TestHostsConfig_SetHostConfig(t *testing.T) {
+	hostsConfig := NewHostsConfigFrom(make(map[string]HostConfig))
+
 	newConfig := HostConfig{
 		LatencyConfig: &LatencyConfig{
 			Min: intPtr(50),
@@ -84,22 +143,20 @@ func TestHostsConfig_SetHostConfig(t *testing.T) {
 }
 
 func TestHostsConfig_DeleteHostConfig(t *testing.T) {
-	hostsConfig := &HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				LatencyConfig: &LatencyConfig{
-					Min: intPtr(100),
-					Max: intPtr(200),
-				},
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			LatencyConfig: &LatencyConfig{
+				Min: intPtr(100),
+				Max: intPtr(200),
 			},
-			"test.com": {
-				LatencyConfig: &LatencyConfig{
-					Min: intPtr(50),
-					Max: intPtr(150),
-				},
+		},
+		"test.com": {
+			LatencyConfig: &LatencyConfig{
+				Min: intPtr(50),
+				Max: intPtr(150),
 			},
 		},
-	}
+	})
 
 	// Verify host exists before deletion
 	if hostsConfig.GetHostConfig("example.com") == nil {
@@ -124,16 +181,14 @@ func TestHostsConfig_DeleteHostConfig(t *testing.T) {
 }
 
 func TestHostsConfig_UpdateHostLatencyConfig(t *testing.T) {
-	hostsConfig := &HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				LatencyConfig: &LatencyConfig{
-					Min: intPtr(100),
-					Max: intPtr(200),
-				},
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			LatencyConfig: &LatencyConfig{
+				Min: intPtr(100),
+				Max: intPtr(200),
 			},
 		},
-	}
+	})
 
 	// Update existing host
 	newLatencyConfig := &LatencyConfig{
@@ -173,21 +228,19 @@ func TestHostsConfig_UpdateHostLatencyConfig(t *testing.T) {
 }
 
 func TestHostsConfig_DeleteHostLatencyConfig(t *testing.T) {
-	hostsConfig := &HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				LatencyConfig: &LatencyConfig{
-					Min: intPtr(100),
-					Max: intPtr(200),
-				},
-				StatusesConfig: map[string]StatusConfig{
-					"500": {
-						Percentage: intPtr(10),
-					},
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			LatencyConfig: &LatencyConfig{
+				Min: intPtr(100),
+				Max: intPtr(200),
+			},
+			StatusesConfig: map[string]StatusConfig{
+				"500": {
+					Percentage: intPtr(10),
 				},
 			},
 		},
-	}
+	})
 
 	// Delete latency config from existing host
 	updatedConfig, err := hostsConfig.DeleteHostLatencyConfig("example.com")
@@ -222,16 +275,14 @@ func TestHostsConfig_DeleteHostLatencyConfig(t *testing.T) {
 }
 
 func TestHostsConfig_UpdateHostStatusesConfig(t *testing.T) {
-	hostsConfig := &HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				LatencyConfig: &LatencyConfig{
-					Min: intPtr(100),
-					Max: intPtr(200),
-				},
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			LatencyConfig: &LatencyConfig{
+				Min: intPtr(100),
+				Max: intPtr(200),
 			},
 		},
-	}
+	})
 
 	// Update statuses config
 	newStatusesConfig := map[string]StatusConfig{
@@ -274,20 +325,18 @@ func TestHostsConfig_UpdateHostStatusesConfig(t *testing.T) {
 }
 
 func TestHostsConfig_DeleteHostStatusConfig(t *testing.T) {
-	hostsConfig := &HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				StatusesConfig: map[string]StatusConfig{
-					"500": {
-						Percentage: intPtr(20),
-					},
-					"503": {
-						Percentage: intPtr(10),
-					},
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			StatusesConfig: map[string]StatusConfig{
+				"500": {
+					Percentage: intPtr(20),
+				},
+				"503": {
+					Percentage: intPtr(10),
 				},
 			},
 		},
-	}
+	})
 
 	// Delete specific status config
 	updatedConfig, err := hostsConfig.DeleteHostStatusConfig("example.com", "500")
@@ -325,16 +374,14 @@ func TestHostsConfig_DeleteHostStatusConfig(t *testing.T) {
 }
 
 func TestHostsConfig_UpdateHostUrisConfig(t *testing.T) {
-	hostsConfig := &HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				LatencyConfig: &LatencyConfig{
-					Min: intPtr(100),
-					Max: intPtr(200),
-				},
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			LatencyConfig: &LatencyConfig{
+				Min: intPtr(100),
+				Max: intPtr(200),
 			},
 		},
-	}
+	})
 
 	// Update URIs config
 	newUrisConfig := map[string]UriConfig{
@@ -387,32 +434,30 @@ func TestHostsConfig_UpdateHostUrisConfig(t *testing.T) {
 }
 
 func TestHostsConfig_GetAppropriateStatusesConfig(t *testing.T) {
-	hostsConfig := &HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				StatusesConfig: map[string]StatusConfig{
-					"500": {
-						Percentage: intPtr(20),
-					},
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			StatusesConfig: map[string]StatusConfig{
+				"500": {
+					Percentage: intPtr(20),
 				},
-				UrisConfig: map[string]UriConfig{
-					"/api/v1/users": {
-						StatusesConfig: map[string]StatusConfig{
-							"404": {
-								Percentage: intPtr(15),
-							},
+			},
+			UrisConfig: map[string]UriConfig{
+				"/api/v1/users": {
+					StatusesConfig: map[string]StatusConfig{
+						"404": {
+							Percentage: intPtr(15),
 						},
 					},
-					"/api/v1/orders": {
-						LatencyConfig: &LatencyConfig{
-							Min: intPtr(50),
-							Max: intPtr(100),
-						},
+				},
+				"/api/v1/orders": {
+					LatencyConfig: &LatencyConfig{
+						Min: intPtr(50),
+						Max: intPtr(100),
 					},
 				},
 			},
 		},
-	}
+	})
 
 	// Test URI with specific statuses config (should override host statuses)
 	errorsConfig, _ := hostsConfig.GetAppropriateStatusesConfig("example.com", "/api/v1/users")
@@ -460,22 +505,20 @@ func TestHostsConfig_GetAppropriateStatusesConfig(t *testing.T) {
 }
 
 func TestHostsConfig_GetAppropriateStatusesConfig_EmptyConfigs(t *testing.T) {
-	hostsConfig := &HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				// No statuses config at host level
-				UrisConfig: map[string]UriConfig{
-					"/api/v1/users": {
-						// No statuses config at URI level either
-						LatencyConfig: &LatencyConfig{
-							Min: intPtr(50),
-							Max: intPtr(100),
-						},
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			// No statuses config at host level
+			UrisConfig: map[string]UriConfig{
+				"/api/v1/users": {
+					// No statuses config at URI level either
+					LatencyConfig: &LatencyConfig{
+						Min: intPtr(50),
+						Max: intPtr(100),
 					},
 				},
 			},
 		},
-	}
+	})
 
 	// Test when no statuses config exists at any level
 	errorsConfig, _ := hostsConfig.GetAppropriateStatusesConfig("example.com", "/api/v1/users")
@@ -484,32 +527,94 @@ func TestHostsConfig_GetAppropriateStatusesConfig_EmptyConfigs(t *testing.T) {
 	}
 }
 
-func TestHostsConfig_GetAppropriateLatencyConfig(t *testing.T) {
-	hostsConfig := &HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				LatencyConfig: &LatencyConfig{
-					Min: intPtr(100),
-					Max: intPtr(200),
+func TestHostsConfig_GetAppropriateFaultsConfig(t *testing.T) {
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			FaultsConfig: map[string]FaultConfig{
+				FaultConnectionReset: {
+					Percentage: intPtr(10),
 				},
-				UrisConfig: map[string]UriConfig{
-					"/api/v1/users": {
-						LatencyConfig: &LatencyConfig{
-							Min: intPtr(50),
-							Max: intPtr(100),
+			},
+			UrisConfig: map[string]UriConfig{
+				"/api/v1/users": {
+					FaultsConfig: map[string]FaultConfig{
+						FaultHang: {
+							Percentage: intPtr(5),
 						},
 					},
-					"/api/v1/orders": {
-						StatusesConfig: map[string]StatusConfig{
-							"404": {
-								Percentage: intPtr(15),
-							},
-						},
+				},
+				"/api/v1/orders": {
+					LatencyConfig: &LatencyConfig{
+						Min: intPtr(50),
+						Max: intPtr(100),
 					},
 				},
 			},
 		},
+		"other.com": {},
+	})
+
+	tests := []struct {
+		name          string
+		host          string
+		uri           string
+		expectedFault string
+		expectedScope string
+	}{
+		{"URI faults override the host faults", "example.com", "/api/v1/users", FaultHang, "URI Override"},
+		{"URI without faults uses the host faults", "example.com", "/api/v1/orders", FaultConnectionReset, "Host Default"},
+		{"unknown URI uses the host faults", "example.com", "/nonexistent", FaultConnectionReset, "Host Default"},
+		{"host without faults", "other.com", "/api/v1/users", "", ""},
+		{"non-existent host", "nonexistent.com", "/api/v1/users", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faultsConfig, scope := hostsConfig.GetAppropriateFaultsConfig(tt.host, tt.uri)
+
+			if tt.expectedFault == "" {
+				if faultsConfig != nil || scope != "" {
+					t.Errorf("expected no faults config, got %v with scope %q", faultsConfig, scope)
+				}
+
+				return
+			}
+
+			if _, exists := faultsConfig[tt.expectedFault]; !exists || len(faultsConfig) != 1 {
+				t.Errorf("expected only the %s fault, got %v", tt.expectedFault, faultsConfig)
+			}
+
+			if scope != tt.expectedScope {
+				t.Errorf("expected scope %q, got %q", tt.expectedScope, scope)
+			}
+		})
 	}
+}
+
+func TestHostsConfig_GetAppropriateLatencyConfig(t *testing.T) {
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			LatencyConfig: &LatencyConfig{
+				Min: intPtr(100),
+				Max: intPtr(200),
+			},
+			UrisConfig: map[string]UriConfig{
+				"/api/v1/users": {
+					LatencyConfig: &LatencyConfig{
+						Min: intPtr(50),
+						Max: intPtr(100),
+					},
+				},
+				"/api/v1/orders": {
+					StatusesConfig: map[string]StatusConfig{
+						"404": {
+							Percentage: intPtr(15),
+						},
+					},
+				},
+			},
+		},
+	})
 
 	// Test URI with specific latency config (should override host latency)
 	latencyConfig, _ := hostsConfig.GetAppropriateLatencyConfig("example.com", "/api/v1/users")
@@ -561,28 +666,26 @@ func TestHostConfig_StatusesConfig(t *testing.T) {
 }
 
 func TestHostsConfig_GetAppropriateLatencyConfig_NoHostLatency(t *testing.T) {
-	hostsConfig := &HostsConfig{
-		Hosts: map[string]HostConfig{
-			"example.com": {
-				// No latency config at host level
-				UrisConfig: map[string]UriConfig{
-					"/api/v1/users": {
-						LatencyConfig: &LatencyConfig{
-							Min: intPtr(50),
-							Max: intPtr(100),
-						},
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {
+			// No latency config at host level
+			UrisConfig: map[string]UriConfig{
+				"/api/v1/users": {
+					LatencyConfig: &LatencyConfig{
+						Min: intPtr(50),
+						Max: intPtr(100),
 					},
-					"/api/v1/orders": {
-						StatusesConfig: map[string]StatusConfig{
-							"404": {
-								Percentage: intPtr(15),
-							},
+				},
+				"/api/v1/orders": {
+					StatusesConfig: map[string]StatusConfig{
+						"404": {
+							Percentage: intPtr(15),
 						},
 					},
 				},
 			},
 		},
-	}
+	})
 
 	// Test URI with specific latency config
 	latencyConfig, _ := hostsConfig.GetAppropriateLatencyConfig("example.com", "/api/v1/users")
@@ -600,3 +703,173 @@ func TestHostsConfig_GetAppropriateLatencyConfig_NoHostLatency(t *testing.T) {
 		t.Error("expected nil latency config when none exist")
 	}
 }
+
+func TestHostsConfig_Snapshot(t *testing.T) {
+	t.Run("zero value has an empty snapshot", func(t *testing.T) {
+		var hostsConfig HostsConfig
+
+		if snapshot := hostsConfig.Snapshot(); snapshot.Version != 0 || len(snapshot.Hosts) != 0 {
+			t.Errorf("unexpected snapshot: %+v", snapshot)
+		}
+
+		hostsConfig.SetHostConfig("example.com", HostConfig{})
+
+		if hostsConfig.GetHostConfig("example.com") == nil || hostsConfig.Version() != 1 {
+			t.Errorf("expected zero value to be updatable, got %+v", hostsConfig.Snapshot())
+		}
+	})
+
+	t.Run("keeps previous snapshots untouched on updates", func(t *testing.T) {
+		hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+			"example.com": {
+				StatusesConfig: map[string]StatusConfig{
+					"500": {Percentage: intPtr(10)},
+					"503": {Percentage: intPtr(5)},
+				},
+			},
+		})
+
+		before := hostsConfig.Snapshot()
+
+		hostsConfig.DeleteHostStatusConfig("example.com", "500")
+		hostsConfig.SetHostConfig("other.com", HostConfig{})
+
+		if len(before.Hosts) != 1 || len(before.Hosts["example.com"].StatusesConfig) != 2 {
+			t.Errorf("expected previous snapshot to be untouched, got %+v", before.Hosts)
+		}
+
+		after := hostsConfig.Snapshot()
+
+		if len(after.Hosts) != 2 || len(after.Hosts["example.com"].StatusesConfig) != 1 {
+			t.Errorf("unexpected current snapshot: %+v", after.Hosts)
+		}
+	})
+}
+
+func TestHostsConfig_Update(t *testing.T) {
+	t.Run("increments the version on every change", func(t *testing.T) {
+		hostsConfig := NewHostsConfigFrom(nil)
+
+		if hostsConfig.Version() != 1 {
+			t.Fatalf("expected version 1, got %d", hostsConfig.Version())
+		}
+
+		hostsConfig.SetHostConfig("example.com", HostConfig{})
+		hostsConfig.UpdateHostLatencyConfig("example.com", &LatencyConfig{Min: intPtr(1), Max: intPtr(2)})
+		hostsConfig.Replace(NewHostsConfigFrom(nil))
+
+		if hostsConfig.Version() != 4 {
+			t.Errorf("expected version 4, got %d", hostsConfig.Version())
+		}
+	})
+
+	t.Run("doesn't publish a failed change", func(t *testing.T) {
+		hostsConfig := NewHostsConfigFrom(nil)
+
+		err := hostsConfig.Update(func(hosts map[string]HostConfig) error {
+			hosts["example.com"] = HostConfig{}
+			return errors.New("failed")
+		})
+
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		if hostsConfig.Version() != 1 || hostsConfig.GetHostConfig("example.com") != nil {
+			t.Errorf("expected no change, got %+v", hostsConfig.Snapshot())
+		}
+	})
+
+	t.Run("serializes concurrent changes while readers run", func(t *testing.T) {
+		hostsConfig := NewHostsConfigFrom(nil)
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 20; i++ {
+			wg.Add(2)
+
+			go func(i int) {
+				defer wg.Done()
+				hostsConfig.SetHostConfig(fmt.Sprintf("host%d.com", i), HostConfig{LatencyConfig: &LatencyConfig{Min: intPtr(1), Max: intPtr(2)}})
+			}(i)
+
+			go func(i int) {
+				defer wg.Done()
+				hostsConfig.GetAppropriateLatencyConfig(fmt.Sprintf("host%d.com", i), "/")
+				hostsConfig.GetAppropriateStatusesConfig(fmt.Sprintf("host%d.com", i), "/")
+			}(i)
+		}
+
+		wg.Wait()
+
+		if snapshot := hostsConfig.Snapshot(); len(snapshot.Hosts) != 20 || snapshot.Version != 21 {
+			t.Errorf("expected 20 hosts at version 21, got %d at version %d", len(snapshot.Hosts), snapshot.Version)
+		}
+	})
+}
+
+func TestHostsConfig_Rollback(t *testing.T) {
+	t.Run("publishes the previous hosts as a new version", func(t *testing.T) {
+		hostsConfig := NewHostsConfigFrom(nil)
+		previous := hostsConfig.Snapshot()
+
+		hostsConfig.SetHostConfig("example.com", HostConfig{})
+
+		if !hostsConfig.Rollback(previous, 2) {
+			t.Fatal("expected the hosts to be rolled back")
+		}
+
+		if hostsConfig.Version() != 3 || hostsConfig.GetHostConfig("example.com") != nil {
+			t.Errorf("expected no host at version 3, got %+v", hostsConfig.Snapshot())
+		}
+	})
+
+	t.Run("doesn't roll back a later change", func(t *testing.T) {
+		hostsConfig := NewHostsConfigFrom(nil)
+		previous := hostsConfig.Snapshot()
+
+		hostsConfig.SetHostConfig("example.com", HostConfig{})
+		hostsConfig.SetHostConfig("other.com", HostConfig{})
+
+		if hostsConfig.Rollback(previous, 2) {
+			t.Fatal("expected the hosts not to be rolled back")
+		}
+
+		if len(hostsConfig.Snapshot().Hosts) != 2 {
+			t.Errorf("expected the later change to be kept, got %+v", hostsConfig.Snapshot())
+		}
+	})
+}
+
+func TestHostsConfig_JSON(t *testing.T) {
+	hostsConfig := NewHostsConfigFrom(map[string]HostConfig{
+		"example.com": {LatencyConfig: &LatencyConfig{Min: intPtr(1), Max: intPtr(2)}},
+	})
+	hostsConfig.SetHostConfig("other.com", HostConfig{})
+
+	data, err := json.Marshal(hostsConfig)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var file map[string]any
+
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, hasVersion := file["version"]; hasVersion || len(file) != 1 {
+		t.Errorf("expected the config file format, got %s", data)
+	}
+
+	loaded := &HostsConfig{}
+
+	if err := json.Unmarshal(data, loaded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(loaded.Snapshot().Hosts) != 2 || loaded.Version() != 1 {
+		t.Errorf("unexpected loaded config: %+v", loaded.Snapshot())
+	}
+}
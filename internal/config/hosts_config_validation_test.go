@@ -285,6 +285,16 @@ func TestUriConfig_Validate_Valid(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "faults config only",
+			config: UriConfig{
+				FaultsConfig: map[string]FaultConfig{
+					FaultConnectionReset: {
+						Percentage: intPtr(10),
+					},
+				},
+			},
+		},
 		{
 			name: "both latency and statuses config",
 			config: UriConfig{
@@ -320,7 +330,7 @@ func TestUriConfig_Validate_Invalid(t *testing.T) {
 		{
 			name:        "both configs nil",
 			config:      UriConfig{},
-			expectedErr: "latency or statuses should not be both null",
+			expectedErr: "latency, statuses or faults should not be all null",
 		},
 		{
 			name: "invalid latency config",
@@ -357,6 +367,17 @@ func TestUriConfig_Validate_Invalid(t *testing.T) {
 			},
 			expectedErr: "the sum of all percentages should not exceed 100",
 		},
+		{
+			name: "invalid fault",
+			config: UriConfig{
+				FaultsConfig: map[string]FaultConfig{
+					FaultGarbage: {
+						Percentage: intPtr(0),
+					},
+				},
+			},
+			expectedErr: "invalid uri config found: invalid fault config found",
+		},
 	}
 
 	for _, tt := range tests {
@@ -545,7 +566,95 @@ func TestHostConfig_Validate_Invalid(t *testing.T) {
 					},
 				},
 			},
-			expectedErr: "latency or statuses should not be both null",
+			expectedErr: "latency, statuses or faults should not be all null",
+		},
+		{
+			name: "record upstream without scheme",
+			config: HostConfig{
+				RecordConfig: &RecordConfig{
+					Upstream: "localhost:3000",
+				},
+			},
+			expectedErr: "upstream should be an absolute http or https url",
+		},
+		{
+			name: "record upstream with unsupported scheme",
+			config: HostConfig{
+				RecordConfig: &RecordConfig{
+					Upstream: "ftp://example.com",
+				},
+			},
+			expectedErr: "invalid record config found",
+		},
+		{
+			name: "invalid proxy upstream",
+			config: HostConfig{
+				ProxyConfig: &ProxyConfig{
+					Upstream: "not a url",
+				},
+			},
+			expectedErr: "invalid proxy config found",
+		},
+		{
+			name: "record and proxy both set",
+			config: HostConfig{
+				RecordConfig: &RecordConfig{Upstream: "http://localhost:3000"},
+				ProxyConfig:  &ProxyConfig{Upstream: "http://localhost:3000"},
+			},
+			expectedErr: "record and proxy should not be both set",
+		},
+		{
+			name: "unknown fault type",
+			config: HostConfig{
+				FaultsConfig: map[string]FaultConfig{
+					"timeout": {Percentage: intPtr(10)},
+				},
+			},
+			expectedErr: `invalid fault type "timeout"`,
+		},
+		{
+			name: "fault without percentage",
+			config: HostConfig{
+				FaultsConfig: map[string]FaultConfig{
+					FaultHang: {Duration: intPtr(1000)},
+				},
+			},
+			expectedErr: "invalid fault config found: percentage should be greater than 0",
+		},
+		{
+			name: "fault percentage sum over 100",
+			config: HostConfig{
+				FaultsConfig: map[string]FaultConfig{
+					FaultConnectionReset: {Percentage: intPtr(60)},
+					FaultTruncatedBody:   {Percentage: intPtr(50)},
+				},
+			},
+			expectedErr: "the sum of all fault percentages should not exceed 100",
+		},
+		{
+			name: "hang with non-positive duration",
+			config: HostConfig{
+				FaultsConfig: map[string]FaultConfig{
+					FaultHang: {Percentage: intPtr(10), Duration: intPtr(0)},
+				},
+			},
+			expectedErr: "duration should be greater than 0",
+		},
+		{
+			name: "duration on a fault other than hang",
+			config: HostConfig{
+				FaultsConfig: map[string]FaultConfig{
+					FaultGarbage: {Percentage: intPtr(10), Duration: intPtr(1000)},
+				},
+			},
+			expectedErr: `duration is only supported by the "hang" fault`,
+		},
+		{
+			name: "contract without spec",
+			config: HostConfig{
+				ContractConfig: &ContractConfig{Mode: ContractModeLog},
+			},
+			expectedErr: "invalid contract config found",
 		},
 	}
 
@@ -560,3 +669,59 @@ func TestHostConfig_Validate_Invalid(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		upstream string
+		valid    bool
+	}{
+		{name: "http upstream", upstream: "http://localhost:3000", valid: true},
+		{name: "https upstream with base path", upstream: "https://api.example.com/v1", valid: true},
+		{name: "empty upstream", upstream: "", valid: false},
+		{name: "relative upstream", upstream: "/api", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := RecordConfig{Upstream: tt.upstream}
+			err := config.validate()
+
+			if tt.valid && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+
+			if !tt.valid && err == nil {
+				t.Error("expected error for invalid upstream")
+			}
+		})
+	}
+}
+
+func TestContractConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  string
+		valid bool
+	}{
+		{name: "default mode", mode: "", valid: true},
+		{name: "log mode", mode: ContractModeLog, valid: true},
+		{name: "reject mode", mode: ContractModeReject, valid: true},
+		{name: "unknown mode", mode: "block", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := ContractConfig{Spec: "openapi.yaml", Mode: tt.mode}
+			err := config.validate()
+
+			if tt.valid && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+
+			if !tt.valid && err == nil {
+				t.Error("expected error for invalid mode")
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/util"
+	"github.com/fsnotify/fsnotify"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// reloadDelay is how long the watcher waits for the config file to settle before reloading it, editors usually
+// write a file in several steps
+const reloadDelay = 100 * time.Millisecond
+
+type HostsConfigEventType string
+
+const (
+	HostsConfigReloaded     HostsConfigEventType = "reloaded"
+	HostsConfigReloadFailed HostsConfigEventType = "reload_failed"
+)
+
+// HostsConfigEvent tells that the config file changed, and whether the new config is now in use
+type HostsConfigEvent struct {
+	Type      HostsConfigEventType `json:"type"`
+	Path      string               `json:"path"`
+	Error     string               `json:"error,omitempty"`
+	Timestamp time.Time            `json:"timestamp"`
+}
+
+// HostsConfigWatcher watches the config file and reloads the hosts config when it changes. An invalid config file
+// is reported, and the config in use is kept.
+type HostsConfigWatcher struct {
+	hostsConfig *HostsConfig
+	path        string
+	broadcaster *util.Broadcaster[HostsConfigEvent]
+	timerMu     sync.Mutex
+	timer       *time.Timer
+}
+
+// NewHostsConfigWatcher creates a new HostsConfigWatcher and starts watching the config file.
+// Returns nil if no config file is set.
+func NewHostsConfigWatcher(appArguments *AppArguments, hostsConfig *HostsConfig) (*HostsConfigWatcher, error) {
+	if len(appArguments.MocksConfigFile) == 0 {
+		return nil, nil
+	}
+
+	absolutePath, err := filepath.Abs(appArguments.MocksConfigFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	watcher := &HostsConfigWatcher{
+		hostsConfig: hostsConfig,
+		path:        absolutePath,
+		broadcaster: &util.Broadcaster[HostsConfigEvent]{},
+	}
+
+	if err := watcher.start(); err != nil {
+		return nil, err
+	}
+
+	return watcher, nil
+}
+
+// Subscribe returns a channel receiving every reload of the config file
+func (w *HostsConfigWatcher) Subscribe(subscriberId string) <-chan HostsConfigEvent {
+	if w == nil {
+		return nil
+	}
+
+	return w.broadcaster.Subscribe(subscriberId, func(event HostsConfigEvent) bool {
+		return true
+	})
+}
+
+// Unsubscribe removes a subscriber
+func (w *HostsConfigWatcher) Unsubscribe(subscriberId string) {
+	if w == nil {
+		return
+	}
+
+	w.broadcaster.Unsubscribe(subscriberId)
+}
+
+// SubscriberCount returns the number of current subscribers
+func (w *HostsConfigWatcher) SubscriberCount() int {
+	if w == nil {
+		return 0
+	}
+
+	return w.broadcaster.SubscriberCount()
+}
+
+func (w *HostsConfigWatcher) start() error {
+	watcher, err := fsnotify.NewWatcher()
+
+	if err != nil {
+		return err
+	}
+
+	// watching the directory rather than the file, as editors often save by replacing the file, which would end
+	// the watch on the file itself
+	if err := watcher.Add(filepath.Dir(w.path)); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				w.handleFilesystemEvent(event)
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				log.Err(err).
+					Stack().
+					Msg("error received while watching config file")
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (w *HostsConfigWatcher) handleFilesystemEvent(event fsnotify.Event) {
+	if filepath.Clean(event.Name) != w.path {
+		return
+	}
+
+	// a removed file is either gone for good, keeping the config in use, or about to be created again
+	if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) {
+		return
+	}
+
+	w.timerMu.Lock()
+	defer w.timerMu.Unlock()
+
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+
+	w.timer = time.AfterFunc(reloadDelay, w.reload)
+}
+
+func (w *HostsConfigWatcher) reload() {
+	uuid := uuid.NewString()
+
+	data, err := os.ReadFile(w.path)
+
+	if err != nil {
+		log.Warn().
+			Str("uuid", uuid).
+			Str("path", w.path).
+			Msgf("error while reading config file: %v", err)
+
+		return
+	}
+
+	newHostsConfig, err := parseHostsConfig(data)
+
+	if err != nil {
+		log.Error().
+			Str("uuid", uuid).
+			Str("path", w.path).
+			Msgf("invalid config file, keeping the current hosts config: %v", err)
+
+		w.broadcaster.PublishAsync(w.newEvent(HostsConfigReloadFailed, err), uuid)
+
+		return
+	}
+
+	// the file may have been written with the config already in use, e.g. when persisting admin changes
+	if w.isInUse(newHostsConfig) {
+		return
+	}
+
+	w.hostsConfig.Replace(newHostsConfig)
+
+	log.Info().
+		Str("uuid", uuid).
+		Str("path", w.path).
+		Msg("hosts config reloaded")
+
+	w.broadcaster.PublishAsync(w.newEvent(HostsConfigReloaded, nil), uuid)
+}
+
+func (w *HostsConfigWatcher) isInUse(newHostsConfig *HostsConfig) bool {
+	current, err := json.Marshal(w.hostsConfig)
+
+	if err != nil {
+		return false
+	}
+
+	loaded, err := json.Marshal(newHostsConfig)
+
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(current, loaded)
+}
+
+func (w *HostsConfigWatcher) newEvent(eventType HostsConfigEventType, err error) HostsConfigEvent {
+	event := HostsConfigEvent{
+		Type:      eventType,
+		Path:      w.path,
+		Timestamp: time.Now(),
+	}
+
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	return event
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newWatchedConfigFile(t *testing.T, data string) (*HostsConfigWatcher, *HostsConfig, string) {
+	t.Helper()
+
+	configFile := filepath.Join(t.TempDir(), "config.json")
+
+	if err := os.WriteFile(configFile, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	appArguments := &AppArguments{MocksConfigFile: configFile}
+	hostsConfig, err := NewHostsConfig(appArguments)
+
+	if err != nil {
+		t.Fatalf("failed to load config file: %v", err)
+	}
+
+	watcher, err := NewHostsConfigWatcher(appArguments, hostsConfig)
+
+	if err != nil {
+		t.Fatalf("failed to start watcher: %v", err)
+	}
+
+	return watcher, hostsConfig, configFile
+}
+
+func waitHostsConfigEvent(t *testing.T, ch <-chan HostsConfigEvent, timeout time.Duration) (HostsConfigEvent, bool) {
+	t.Helper()
+
+	select {
+	case event := <-ch:
+		return event, true
+	case <-time.After(timeout):
+		return HostsConfigEvent{}, false
+	}
+}
+
+func TestNewHostsConfigWatcher_NoConfigFile(t *testing.T) {
+	watcher, err := NewHostsConfigWatcher(&AppArguments{}, NewHostsConfigFrom(nil))
+
+	if err != nil || watcher != nil {
+		t.Fatalf("expected nil watcher and no error, got %v and %v", watcher, err)
+	}
+
+	// a nil watcher is safe to use
+	if ch := watcher.Subscribe("test"); ch != nil {
+		t.Error("expected nil channel from a nil watcher")
+	}
+
+	watcher.Unsubscribe("test")
+}
+
+func TestHostsConfigWatcher_reload(t *testing.T) {
+	t.Run("swaps the hosts config when the file changes", func(t *testing.T) {
+		watcher, hostsConfig, configFile := newWatchedConfigFile(t, `{"hosts":{}}`)
+		ch := watcher.Subscribe("test")
+		defer watcher.Unsubscribe("test")
+
+		if err := os.WriteFile(configFile, []byte(`{"hosts":{"example.com":{"latency":{"min":10,"max":20}}}}`), 0644); err != nil {
+			t.Fatalf("failed to update config file: %v", err)
+		}
+
+		event, ok := waitHostsConfigEvent(t, ch, 2*time.Second)
+
+		if !ok {
+			t.Fatal("expected a reload event")
+		}
+
+		if event.Type != HostsConfigReloaded || event.Path != configFile {
+			t.Errorf("unexpected event: %+v", event)
+		}
+
+		if hostConfig := hostsConfig.GetHostConfig("example.com"); hostConfig == nil || *hostConfig.LatencyConfig.Max != 20 {
+			t.Errorf("expected the new hosts config to be in use, got %+v", hostsConfig.Snapshot().Hosts)
+		}
+	})
+
+	t.Run("keeps the hosts config when the file is invalid", func(t *testing.T) {
+		watcher, hostsConfig, configFile := newWatchedConfigFile(t, `{"hosts":{"example.com":{"latency":{"min":10,"max":20}}}}`)
+		ch := watcher.Subscribe("test")
+		defer watcher.Unsubscribe("test")
+
+		if err := os.WriteFile(configFile, []byte(`{"hosts":{"example.com":{"latency":{"min":30,"max":20}}}}`), 0644); err != nil {
+			t.Fatalf("failed to update config file: %v", err)
+		}
+
+		event, ok := waitHostsConfigEvent(t, ch, 2*time.Second)
+
+		if !ok {
+			t.Fatal("expected a reload failure event")
+		}
+
+		if event.Type != HostsConfigReloadFailed || len(event.Error) == 0 {
+			t.Errorf("unexpected event: %+v", event)
+		}
+
+		if *hostsConfig.GetHostConfig("example.com").LatencyConfig.Min != 10 {
+			t.Errorf("expected the previous hosts config to be kept, got %+v", hostsConfig.Snapshot().Hosts)
+		}
+	})
+
+	t.Run("ignores writes of the config already in use", func(t *testing.T) {
+		watcher, hostsConfig, configFile := newWatchedConfigFile(t, `{"hosts":{}}`)
+		ch := watcher.Subscribe("test")
+		defer watcher.Unsubscribe("test")
+
+		if err := WriteHostsConfig(configFile, hostsConfig); err != nil {
+			t.Fatalf("failed to write config file: %v", err)
+		}
+
+		if event, ok := waitHostsConfigEvent(t, ch, 500*time.Millisecond); ok {
+			t.Errorf("expected no event, got %+v", event)
+		}
+	})
+
+	t.Run("ignores other files in the directory", func(t *testing.T) {
+		watcher, _, configFile := newWatchedConfigFile(t, `{"hosts":{}}`)
+		ch := watcher.Subscribe("test")
+		defer watcher.Unsubscribe("test")
+
+		if err := os.WriteFile(filepath.Join(filepath.Dir(configFile), "other.json"), []byte(`{`), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		if event, ok := waitHostsConfigEvent(t, ch, 500*time.Millisecond); ok {
+			t.Errorf("expected no event, got %+v", event)
+		}
+	})
+}
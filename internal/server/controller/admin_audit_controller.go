@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/rest"
+	"github.com/Caik/go-mock-server/internal/service/audit"
+	"github.com/Caik/go-mock-server/internal/util"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// AuditController handles the queries and streaming of the audit log of the admin changes
+type AuditController struct {
+	auditService *audit.AuditService
+}
+
+// handleAuditEvents returns the recent audit events matching the filters, from the most recent to the oldest
+func (a *AuditController) handleAuditEvents(c *gin.Context) {
+	if a.auditService == nil {
+		c.JSON(http.StatusServiceUnavailable, rest.Response{
+			Status:  rest.Fail,
+			Message: "audit log is disabled",
+		})
+		return
+	}
+
+	filters, err := a.parseFilters(c)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, rest.Response{
+			Status:  rest.Fail,
+			Message: fmt.Sprintf("invalid filter: %v", err),
+		})
+		return
+	}
+
+	limit := 0
+
+	if limitParam := c.Query("limit"); limitParam != "" {
+		limit, err = strconv.Atoi(limitParam)
+
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, rest.Response{
+				Status:  rest.Fail,
+				Message: "invalid limit: must be a positive number",
+			})
+			return
+		}
+	}
+
+	log.Info().
+		Str("uuid", c.GetString(util.UuidKey)).
+		Msg("getting audit events")
+
+	c.JSON(http.StatusOK, rest.Response{
+		Status:  rest.Success,
+		Message: "audit events retrieved with success",
+		Data:    a.auditService.Query(filters, limit),
+	})
+}
+
+// handleAuditStream streams the new audit events matching the filters, as SSE
+func (a *AuditController) handleAuditStream(c *gin.Context) {
+	uuid := c.GetString(util.UuidKey)
+
+	if a.auditService == nil {
+		c.JSON(http.StatusServiceUnavailable, rest.Response{
+			Status:  rest.Fail,
+			Message: "audit log is disabled",
+		})
+		return
+	}
+
+	filters, err := a.parseFilters(c)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, rest.Response{
+			Status:  rest.Fail,
+			Message: fmt.Sprintf("invalid filter: %v", err),
+		})
+		return
+	}
+
+	log.Info().
+		Str("uuid", uuid).
+		Msg("starting audit stream")
+
+	// subscribing before sending the headers, so no event is missed once the client is connected
+	subscriberID := fmt.Sprintf("audit-%s", uuid)
+	ch := a.auditService.Subscribe(subscriberID, filters)
+	defer a.auditService.Unsubscribe(subscriberID)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable nginx buffering
+	c.Writer.Flush()
+
+	clientGone := c.Request.Context().Done()
+
+	for {
+		select {
+		case <-clientGone:
+			log.Info().
+				Str("uuid", uuid).
+				Msg("client disconnected from audit stream")
+
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			c.SSEvent("message", event)
+			c.Writer.Flush()
+		}
+	}
+}
+
+// parseFilters extracts the filters of the audit events from the query string
+func (a *AuditController) parseFilters(c *gin.Context) (audit.AuditFilters, error) {
+	filters := audit.AuditFilters{
+		Operation: c.Query("operation"),
+		Host:      c.Query("host"),
+		Identity:  c.Query("identity"),
+	}
+
+	if sinceParam := c.Query("since"); sinceParam != "" {
+		since, err := time.Parse(time.RFC3339, sinceParam)
+
+		if err != nil {
+			return filters, fmt.Errorf("invalid since value: %s", sinceParam)
+		}
+
+		filters.Since = &since
+	}
+
+	return filters, nil
+}
+
+// NewAuditController creates a new AuditController
+func NewAuditController(auditService *audit.AuditService) *AuditController {
+	return &AuditController{
+		auditService: auditService,
+	}
+}
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/rest"
+	"github.com/Caik/go-mock-server/internal/service/audit"
+	"github.com/Caik/go-mock-server/internal/service/auth"
+	"github.com/Caik/go-mock-server/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+func newAuditTestRouter(auditService *audit.AuditService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	initAdminAuditController(router.Group("/audit"), NewAuditController(auditService))
+
+	return router
+}
+
+func newTestAuditService(t *testing.T) *audit.AuditService {
+	t.Helper()
+
+	auditService, err := audit.NewAuditService(&config.AppArguments{AuditLogBufferSize: 10})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return auditService
+}
+
+func TestAuditController_handleAuditEvents(t *testing.T) {
+	auditService := newTestAuditService(t)
+	auditService.Record(audit.Caller{UUID: "1", Identity: &auth.Identity{Name: "ci"}}, audit.OperationStatusesUpdate, "example.com", nil, nil)
+	auditService.Record(audit.Caller{UUID: "2"}, audit.OperationMockUpdate, "example.com", nil, nil)
+	auditService.Record(audit.Caller{UUID: "3"}, audit.OperationHostDelete, "other.com", nil, nil)
+
+	router := newAuditTestRouter(auditService)
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedUUIDs []string
+	}{
+		{"all the events, most recent first", "", []string{"3", "2", "1"}},
+		{"operation prefix", "?operation=host", []string{"3", "1"}},
+		{"host", "?host=example.com", []string{"2", "1"}},
+		{"identity", "?identity=ci", []string{"1"}},
+		{"limit", "?limit=2", []string{"3", "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/audit"+tt.query, nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", w.Code)
+			}
+
+			var response struct {
+				Data []audit.AuditEvent `json:"data"`
+			}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var uuids []string
+
+			for _, event := range response.Data {
+				uuids = append(uuids, event.UUID)
+			}
+
+			if strings.Join(uuids, ",") != strings.Join(tt.expectedUUIDs, ",") {
+				t.Errorf("expected events %v, got %v", tt.expectedUUIDs, uuids)
+			}
+		})
+	}
+
+	for _, query := range []string{"?limit=0", "?limit=abc", "?since=yesterday"} {
+		t.Run("rejects "+query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/audit"+query, nil))
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", w.Code)
+			}
+		})
+	}
+
+	t.Run("returns 503 when the audit log is disabled", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newAuditTestRouter(nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/audit", nil))
+
+		var response rest.Response
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+
+		if w.Code != http.StatusServiceUnavailable || response.Message != "audit log is disabled" {
+			t.Errorf("expected status 503, got %d %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestAuditController_handleAuditStream(t *testing.T) {
+	auditService := newTestAuditService(t)
+	server := httptest.NewServer(newAuditTestRouter(auditService))
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/audit/stream?operation=host")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer response.Body.Close()
+
+	if contentType := response.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("unexpected content type: %s", contentType)
+	}
+
+	// the subscription is made before the headers are sent, so the events recorded from now on are streamed
+	auditService.Record(audit.Caller{UUID: "1"}, audit.OperationMockUpdate, "example.com", nil, nil)
+	auditService.Record(audit.Caller{UUID: "2"}, audit.OperationHostUpdate, "example.com", nil, nil)
+
+	lines := make(chan string)
+
+	go func() {
+		scanner := bufio.NewScanner(response.Body)
+
+		for scanner.Scan() {
+			if data, found := strings.CutPrefix(scanner.Text(), "data:"); found {
+				lines <- data
+			}
+		}
+	}()
+
+	select {
+	case data := <-lines:
+		var event audit.AuditEvent
+
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if event.UUID != "2" {
+			t.Errorf("expected the host event, got %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the event")
+	}
+}
+
+func TestCallerOf(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/mocks", nil)
+	c.Request.RemoteAddr = "10.0.0.1:51234"
+	c.Set(util.UuidKey, "test-uuid")
+
+	if caller := callerOf(c); caller.UUID != "test-uuid" || caller.Identity != nil || caller.RemoteAddr != "10.0.0.1" {
+		t.Errorf("unexpected caller without authentication %+v", caller)
+	}
+
+	identity := &auth.Identity{Name: "ci", Method: auth.MethodToken, Role: auth.RoleReadWrite}
+	c.Set(util.IdentityKey, identity)
+
+	if caller := callerOf(c); caller.Identity != identity {
+		t.Errorf("expected the identity of the caller, got %+v", caller.Identity)
+	}
+}
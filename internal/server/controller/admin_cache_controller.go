@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Caik/go-mock-server/internal/rest"
+	"github.com/Caik/go-mock-server/internal/service/cache"
+	"github.com/Caik/go-mock-server/internal/util"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// CacheFlushResult holds how many responses were removed from the cache
+type CacheFlushResult struct {
+	Removed int `json:"removed"`
+}
+
+// CacheController handles the inspection and flush of the responses cache
+type CacheController struct {
+	cacheService cache.CacheService
+}
+
+func (a *CacheController) handleCacheStats(c *gin.Context) {
+	log.Info().
+		Str("uuid", c.GetString(util.UuidKey)).
+		Msg("getting cache stats")
+
+	c.JSON(http.StatusOK, rest.Response{
+		Status:  rest.Success,
+		Message: "cache stats retrieved with success",
+		Data:    a.cacheService.Stats(),
+	})
+}
+
+func (a *CacheController) handleCacheFlush(c *gin.Context) {
+	uuid := c.GetString(util.UuidKey)
+	host := c.Query("host")
+
+	log.Info().
+		Str("uuid", uuid).
+		Str("host", host).
+		Msg("flushing cache")
+
+	var removed int
+
+	if len(host) == 0 {
+		removed = a.cacheService.Clear(uuid)
+	} else {
+		removed = a.cacheService.DeleteFunc(func(cacheKey string) bool {
+			return strings.HasPrefix(cacheKey, host+":")
+		}, uuid)
+	}
+
+	c.JSON(http.StatusOK, rest.Response{
+		Status:  rest.Success,
+		Message: "cache flushed with success",
+		Data:    CacheFlushResult{Removed: removed},
+	})
+}
+
+// NewCacheController creates a new CacheController
+func NewCacheController(cacheService cache.CacheService) *CacheController {
+	return &CacheController{
+		cacheService: cacheService,
+	}
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/rest"
+	"github.com/Caik/go-mock-server/internal/server/middleware"
+	"github.com/Caik/go-mock-server/internal/service/cache"
+	"github.com/Caik/go-mock-server/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+func newCacheTestRouter(cacheService cache.CacheService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(util.UuidKey, "test-uuid")
+	})
+
+	initAdminCacheController(router.Group("/api/v1/cache"), NewCacheController(cacheService), middleware.RequireReadWrite(nil))
+
+	return router
+}
+
+func doCacheRequest(t *testing.T, router *gin.Engine, method, path string) (*httptest.ResponseRecorder, rest.Response) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	var response rest.Response
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("unexpected error decoding response: %v", err)
+	}
+
+	return w, response
+}
+
+func newFilledCacheService() *cache.InMemoryCacheService {
+	cacheService := cache.NewInMemoryCacheService(&config.AppArguments{CacheMaxEntries: 100})
+	data := []byte("data")
+
+	for _, key := range []string{"example.com:GET:/a:200", "example.com:GET:/b:200", "other.com:GET:/a:200"} {
+		cacheService.Set(key, &data, "test-uuid")
+	}
+
+	return cacheService
+}
+
+func TestCacheController_handleCacheStats(t *testing.T) {
+	w, response := doCacheRequest(t, newCacheTestRouter(newFilledCacheService()), http.MethodGet, "/api/v1/cache")
+
+	if w.Code != http.StatusOK || response.Status != rest.Success {
+		t.Fatalf("expected 200 success, got %d %s", w.Code, response.Status)
+	}
+
+	stats, ok := response.Data.(map[string]any)
+
+	if !ok || stats["entries"] != float64(3) || stats["max_entries"] != float64(100) {
+		t.Errorf("unexpected stats: %v", response.Data)
+	}
+}
+
+func TestCacheController_handleCacheFlush(t *testing.T) {
+	t.Run("flushes the whole cache", func(t *testing.T) {
+		cacheService := newFilledCacheService()
+
+		w, response := doCacheRequest(t, newCacheTestRouter(cacheService), http.MethodDelete, "/api/v1/cache")
+
+		if w.Code != http.StatusOK || response.Status != rest.Success {
+			t.Fatalf("expected 200 success, got %d %s", w.Code, response.Status)
+		}
+
+		if result, ok := response.Data.(map[string]any); !ok || result["removed"] != float64(3) {
+			t.Errorf("expected 3 entries removed, got %v", response.Data)
+		}
+
+		if entries := cacheService.Stats().Entries; entries != 0 {
+			t.Errorf("expected empty cache, got %d entries", entries)
+		}
+	})
+
+	t.Run("flushes the cache of a host", func(t *testing.T) {
+		cacheService := newFilledCacheService()
+
+		_, response := doCacheRequest(t, newCacheTestRouter(cacheService), http.MethodDelete, "/api/v1/cache?host=example.com")
+
+		if result, ok := response.Data.(map[string]any); !ok || result["removed"] != float64(2) {
+			t.Errorf("expected 2 entries removed, got %v", response.Data)
+		}
+
+		if _, exists := cacheService.Get("other.com:GET:/a:200", "test-uuid"); !exists {
+			t.Error("expected the cache of other hosts to be kept")
+		}
+	})
+}
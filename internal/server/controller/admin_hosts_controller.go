@@ -16,11 +16,15 @@ import (
 )
 
 type AddDeleteGetHostRequest struct {
-	Host          string                         `json:"host" binding:"required"`
-	LatencyConfig *config.LatencyConfig          `json:"latency"`
-	StatusConfig  map[string]config.StatusConfig `json:"statuses"`
-	UriConfig     map[string]config.UriConfig    `json:"uris"`
-	statusCode    string
+	Host           string                         `json:"host" binding:"required"`
+	LatencyConfig  *config.LatencyConfig          `json:"latency"`
+	StatusConfig   map[string]config.StatusConfig `json:"statuses"`
+	UriConfig      map[string]config.UriConfig    `json:"uris"`
+	RecordConfig   *config.RecordConfig           `json:"record"`
+	ProxyConfig    *config.ProxyConfig            `json:"proxy"`
+	ContractConfig *config.ContractConfig         `json:"contract"`
+	FaultsConfig   map[string]config.FaultConfig  `json:"faults"`
+	statusCode     string
 }
 
 type AdminHostsController struct {
@@ -36,10 +40,20 @@ func (a *AdminHostsController) handleHostsConfigList(c *gin.Context) {
 	c.JSON(http.StatusOK, rest.Response{
 		Status:  rest.Success,
 		Message: "hosts config retrieved with success",
-		Data:    a.hostsConfig,
+		Data:    a.hostsConfig.Snapshot(),
 	})
 }
 
+// handleHostsConfigExport returns the effective hosts config as a file, in the format of the config file
+func (a *AdminHostsController) handleHostsConfigExport(c *gin.Context) {
+	log.Info().
+		Str("uuid", c.GetString(util.UuidKey)).
+		Msg("exporting hosts config")
+
+	c.Header("Content-Disposition", `attachment; filename="hosts-config.json"`)
+	c.IndentedJSON(http.StatusOK, a.service.GetHostsConfig())
+}
+
 func (a *AdminHostsController) handleHostConfigAddUpdate(c *gin.Context) {
 	addReq := AddDeleteGetHostRequest{}
 
@@ -67,11 +81,15 @@ func (a *AdminHostsController) handleHostConfigAddUpdate(c *gin.Context) {
 		Msg("adding/updating host config")
 
 	hostConfig, err := a.service.AddUpdateHost(admin.HostAddDeleteRequest{
-		Host:          addReq.Host,
-		LatencyConfig: addReq.LatencyConfig,
-		StatusConfig:  addReq.StatusConfig,
-		UriConfig:     addReq.UriConfig,
-	})
+		Host:           addReq.Host,
+		LatencyConfig:  addReq.LatencyConfig,
+		StatusConfig:   addReq.StatusConfig,
+		UriConfig:      addReq.UriConfig,
+		RecordConfig:   addReq.RecordConfig,
+		ProxyConfig:    addReq.ProxyConfig,
+		ContractConfig: addReq.ContractConfig,
+		FaultsConfig:   addReq.FaultsConfig,
+	}, callerOf(c))
 
 	if err != nil {
 		msg := fmt.Sprintf("error while adding/updating host config: %v", err)
@@ -152,7 +170,22 @@ func (a *AdminHostsController) handleHostConfigDelete(c *gin.Context) {
 		Str("host", deleteReq.Host).
 		Msg("deleting host config")
 
-	a.service.DeleteHost(deleteReq.Host)
+	if err := a.service.DeleteHost(deleteReq.Host, callerOf(c)); err != nil {
+		msg := fmt.Sprintf("error while deleting host config: %v", err)
+
+		c.JSON(http.StatusInternalServerError, rest.Response{
+			Status:  rest.Error,
+			Message: msg,
+		})
+
+		log.Err(err).
+			Stack().
+			Str("uuid", c.GetString(util.UuidKey)).
+			Str("host", deleteReq.Host).
+			Msg("")
+
+		return
+	}
 
 	c.JSON(http.StatusOK, rest.Response{
 		Status:  rest.Success,
@@ -189,7 +222,7 @@ func (a *AdminHostsController) handleLatencyAddUpdate(c *gin.Context) {
 	hostConfig, err := a.service.AddUpdateHostLatency(admin.HostAddDeleteRequest{
 		Host:          addLatencyReq.Host,
 		LatencyConfig: addLatencyReq.LatencyConfig,
-	})
+	}, callerOf(c))
 
 	if err != nil {
 		msg := fmt.Sprintf("error while adding/updating host latency config: %v", err)
@@ -237,7 +270,7 @@ func (a *AdminHostsController) handleLatencyDelete(c *gin.Context) {
 		return
 	}
 
-	hostConfig, err := a.service.DeleteHostLatency(latencyDeleteReq.Host)
+	hostConfig, err := a.service.DeleteHostLatency(latencyDeleteReq.Host, callerOf(c))
 
 	if err != nil {
 		msg := fmt.Sprintf("error while deleting host latency config: %v", err)
@@ -302,7 +335,7 @@ func (a *AdminHostsController) handleStatusesAddUpdate(c *gin.Context) {
 	hostConfig, err := a.service.AddUpdateHostStatuses(admin.HostAddDeleteRequest{
 		Host:         addStatusesReq.Host,
 		StatusConfig: addStatusesReq.StatusConfig,
-	})
+	}, callerOf(c))
 
 	if err != nil {
 		msg := fmt.Sprintf("error while adding/updating host statuses config: %v", err)
@@ -350,7 +383,7 @@ func (a *AdminHostsController) handleStatusDelete(c *gin.Context) {
 		return
 	}
 
-	hostConfig, err := a.service.DeleteHostStatus(statusDeleteReq.Host, statusDeleteReq.statusCode)
+	hostConfig, err := a.service.DeleteHostStatus(statusDeleteReq.Host, statusDeleteReq.statusCode, callerOf(c))
 
 	if err != nil {
 		msg := fmt.Sprintf("error while deleting host status config: %v", err)
@@ -415,7 +448,7 @@ func (a *AdminHostsController) handleUrisAddUpdate(c *gin.Context) {
 	hostConfig, err := a.service.AddUpdateHostUris(admin.HostAddDeleteRequest{
 		Host:      addErrorsReq.Host,
 		UriConfig: addErrorsReq.UriConfig,
-	})
+	}, callerOf(c))
 
 	if err != nil {
 		msg := fmt.Sprintf("error while adding/updating host uris config: %v", err)
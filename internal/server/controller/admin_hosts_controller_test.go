@@ -22,10 +22,8 @@ func intPtr(i int) *int {
 
 func TestNewAdminHostsController(t *testing.T) {
 	t.Run("creates controller with dependencies", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		controller := NewAdminHostsController(hostsConfig, service)
 
@@ -48,17 +46,15 @@ func TestAdminHostsController_handleHostsConfigList(t *testing.T) {
 
 	t.Run("returns hosts config successfully", func(t *testing.T) {
 		// Create test hosts config
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {
-					LatencyConfig: &config.LatencyConfig{
-						Min: intPtr(100),
-						Max: intPtr(200),
-					},
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				LatencyConfig: &config.LatencyConfig{
+					Min: intPtr(100),
+					Max: intPtr(200),
 				},
 			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create test context
@@ -87,8 +83,54 @@ func TestAdminHostsController_handleHostsConfigList(t *testing.T) {
 			t.Errorf("expected success message, got '%s'", response.Message)
 		}
 
-		if response.Data == nil {
-			t.Error("expected data to be non-nil")
+		data, ok := response.Data.(map[string]any)
+
+		if !ok || data["version"] != float64(1) || data["hosts"] == nil {
+			t.Errorf("expected versioned hosts config, got %v", response.Data)
+		}
+	})
+}
+
+func TestAdminHostsController_handleHostsConfigExport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("returns the hosts config as a file", func(t *testing.T) {
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				LatencyConfig: &config.LatencyConfig{
+					Min: intPtr(100),
+					Max: intPtr(200),
+				},
+			},
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
+		controller := NewAdminHostsController(hostsConfig, service)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set(util.UuidKey, "test-uuid")
+
+		controller.handleHostsConfigExport(c)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+
+		if disposition := w.Header().Get("Content-Disposition"); !strings.Contains(disposition, "hosts-config.json") {
+			t.Errorf("expected attachment disposition, got %q", disposition)
+		}
+
+		var exported config.HostsConfig
+		if err := json.Unmarshal(w.Body.Bytes(), &exported); err != nil {
+			t.Fatalf("failed to unmarshal exported config: %v", err)
+		}
+
+		if err := exported.Validate(); err != nil {
+			t.Errorf("expected exported config to be valid, got %v", err)
+		}
+
+		if exported.GetHostConfig("example.com") == nil || *exported.Snapshot().Hosts["example.com"].LatencyConfig.Max != 200 {
+			t.Errorf("unexpected exported config: %s", w.Body.String())
 		}
 	})
 }
@@ -97,10 +139,8 @@ func TestAdminHostsController_handleHostConfigAddUpdate(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("adds new host config successfully", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request body
@@ -145,11 +185,35 @@ func TestAdminHostsController_handleHostConfigAddUpdate(t *testing.T) {
 		}
 	})
 
-	t.Run("returns error for invalid request", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
+	t.Run("adds host config with faults", func(t *testing.T) {
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
+		controller := NewAdminHostsController(hostsConfig, service)
+
+		jsonBody := []byte(`{"host":"example.com","faults":{"hang":{"percentage":5,"duration":30000}}}`)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/admin/config/hosts", bytes.NewBuffer(jsonBody))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(util.UuidKey, "test-uuid")
+
+		controller.handleHostConfigAddUpdate(c)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
 		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+
+		hostConfig := hostsConfig.GetHostConfig("example.com")
+
+		if hostConfig == nil || *hostConfig.FaultsConfig[config.FaultHang].Duration != 30000 {
+			t.Errorf("expected the faults to be added, got %+v", hostConfig)
+		}
+	})
+
+	t.Run("returns error for invalid request", func(t *testing.T) {
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create invalid request body (missing required host field)
@@ -188,10 +252,8 @@ func TestAdminHostsController_handleHostConfigAddUpdate(t *testing.T) {
 	})
 
 	t.Run("returns error for invalid host config", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with invalid latency config (min > max)
@@ -236,17 +298,15 @@ func TestAdminHostsController_handleHostConfigRetrieve(t *testing.T) {
 
 	t.Run("retrieves existing host config", func(t *testing.T) {
 		// Create hosts config with existing host
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {
-					LatencyConfig: &config.LatencyConfig{
-						Min: intPtr(100),
-						Max: intPtr(200),
-					},
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				LatencyConfig: &config.LatencyConfig{
+					Min: intPtr(100),
+					Max: intPtr(200),
 				},
 			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create test context with host parameter
@@ -278,10 +338,8 @@ func TestAdminHostsController_handleHostConfigRetrieve(t *testing.T) {
 	})
 
 	t.Run("returns not found for non-existent host", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create test context with non-existent host parameter
@@ -314,22 +372,21 @@ func TestAdminHostsController_handleHostConfigDelete(t *testing.T) {
 
 	t.Run("deletes existing host config", func(t *testing.T) {
 		// Create hosts config with existing host
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {
-					LatencyConfig: &config.LatencyConfig{
-						Min: intPtr(100),
-						Max: intPtr(200),
-					},
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				LatencyConfig: &config.LatencyConfig{
+					Min: intPtr(100),
+					Max: intPtr(200),
 				},
 			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create test context with host parameter
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/", nil)
 		c.Params = gin.Params{{Key: "host", Value: "example.com"}}
 		c.Set(util.UuidKey, "test-uuid")
 
@@ -428,10 +485,8 @@ func TestAdminHostsController_ErrorHandling(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("handleHostConfigAddUpdate handles invalid JSON", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with invalid JSON
@@ -455,10 +510,8 @@ func TestAdminHostsController_ErrorHandling(t *testing.T) {
 	})
 
 	t.Run("handleHostConfigAddUpdate handles validation errors", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with invalid host (empty)
@@ -483,10 +536,8 @@ func TestAdminHostsController_ErrorHandling(t *testing.T) {
 	})
 
 	t.Run("handleHostConfigRetrieve handles invalid host parameter", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with invalid host parameter
@@ -503,10 +554,8 @@ func TestAdminHostsController_ErrorHandling(t *testing.T) {
 	})
 
 	t.Run("handleHostConfigRetrieve handles non-existent host", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request for non-existent host
@@ -529,15 +578,14 @@ func TestAdminHostsController_ErrorHandling(t *testing.T) {
 	})
 
 	t.Run("handleHostConfigDelete handles invalid host parameter", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with invalid host parameter
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/", nil)
 		c.Params = gin.Params{{Key: "host", Value: ""}} // Empty host
 		c.Set(util.UuidKey, "test-uuid")
 
@@ -553,12 +601,10 @@ func TestAdminHostsController_LatencyHandling(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("handleLatencyAddUpdate adds latency config successfully", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with latency config
@@ -590,22 +636,21 @@ func TestAdminHostsController_LatencyHandling(t *testing.T) {
 	})
 
 	t.Run("handleLatencyDelete removes latency config successfully", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {
-					LatencyConfig: &config.LatencyConfig{
-						Min: intPtr(100),
-						Max: intPtr(500),
-					},
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				LatencyConfig: &config.LatencyConfig{
+					Min: intPtr(100),
+					Max: intPtr(500),
 				},
 			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create delete request
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/", nil)
 		c.Params = gin.Params{{Key: "host", Value: "example.com"}}
 		c.Set(util.UuidKey, "test-uuid")
 
@@ -623,10 +668,8 @@ func TestAdminHostsController_LatencyHandling(t *testing.T) {
 	})
 
 	t.Run("handleLatencyAddUpdate handles invalid host parameter", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with invalid host parameter
@@ -646,15 +689,14 @@ func TestAdminHostsController_LatencyHandling(t *testing.T) {
 	})
 
 	t.Run("handleLatencyDelete handles invalid host parameter", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with invalid host parameter
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/", nil)
 		c.Params = gin.Params{{Key: "host", Value: ""}} // Empty host
 		c.Set(util.UuidKey, "test-uuid")
 
@@ -670,12 +712,10 @@ func TestAdminHostsController_StatusHandlingEndpoints(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("handleStatusesAddUpdate adds status config successfully", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with status config
@@ -707,22 +747,21 @@ func TestAdminHostsController_StatusHandlingEndpoints(t *testing.T) {
 	})
 
 	t.Run("handleStatusDelete removes specific status successfully", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {
-					StatusesConfig: map[string]config.StatusConfig{
-						"500": {Percentage: intPtr(10)},
-						"404": {Percentage: intPtr(5)},
-					},
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				StatusesConfig: map[string]config.StatusConfig{
+					"500": {Percentage: intPtr(10)},
+					"404": {Percentage: intPtr(5)},
 				},
 			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create delete request for specific status
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/", nil)
 		c.Params = gin.Params{
 			{Key: "host", Value: "example.com"},
 			{Key: "status", Value: "500"},
@@ -743,12 +782,10 @@ func TestAdminHostsController_StatusHandlingEndpoints(t *testing.T) {
 	})
 
 	t.Run("handleUrisAddUpdate adds URI config successfully", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with valid URI config (must have either latency or statuses)
@@ -780,10 +817,8 @@ func TestAdminHostsController_StatusHandlingEndpoints(t *testing.T) {
 	})
 
 	t.Run("status endpoints handle invalid host parameters", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Test handleStatusesAddUpdate with empty host
@@ -822,10 +857,8 @@ func TestAdminHostsController_handleLatencyAddUpdate_NotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("returns 404 when host doesn't exist", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with latency config for non-existent host
@@ -856,12 +889,10 @@ func TestAdminHostsController_handleLatencyAddUpdate_NotFound(t *testing.T) {
 	})
 
 	t.Run("returns 500 when service returns validation error", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with invalid latency config (min > max)
@@ -888,12 +919,10 @@ func TestAdminHostsController_handleLatencyAddUpdate_NotFound(t *testing.T) {
 	})
 
 	t.Run("returns 400 when latency config is missing", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request without latency config
@@ -924,15 +953,14 @@ func TestAdminHostsController_handleLatencyDelete_NotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("returns 404 when host doesn't exist", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create delete request for non-existent host
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/", nil)
 		c.Params = gin.Params{{Key: "host", Value: "nonexistent.com"}}
 		c.Set(util.UuidKey, "test-uuid")
 
@@ -959,10 +987,8 @@ func TestAdminHostsController_handleStatusesAddUpdate_NotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("returns 404 when host doesn't exist", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with status config for non-existent host
@@ -993,12 +1019,10 @@ func TestAdminHostsController_handleStatusesAddUpdate_NotFound(t *testing.T) {
 	})
 
 	t.Run("returns 500 when service returns validation error", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with invalid status config (percentage > 100)
@@ -1025,12 +1049,10 @@ func TestAdminHostsController_handleStatusesAddUpdate_NotFound(t *testing.T) {
 	})
 
 	t.Run("returns 400 when statuses config is missing", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request without statuses config
@@ -1061,15 +1083,14 @@ func TestAdminHostsController_handleStatusDelete_NotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("returns 404 when host doesn't exist", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create delete request for non-existent host
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/", nil)
 		c.Params = gin.Params{
 			{Key: "host", Value: "nonexistent.com"},
 			{Key: "status", Value: "500"},
@@ -1095,21 +1116,20 @@ func TestAdminHostsController_handleStatusDelete_NotFound(t *testing.T) {
 	})
 
 	t.Run("returns 400 when status code parameter is missing", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {
-					StatusesConfig: map[string]config.StatusConfig{
-						"500": {Percentage: intPtr(10)},
-					},
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				StatusesConfig: map[string]config.StatusConfig{
+					"500": {Percentage: intPtr(10)},
 				},
 			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create delete request without status code
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/", nil)
 		c.Params = gin.Params{
 			{Key: "host", Value: "example.com"},
 			{Key: "status", Value: ""},
@@ -1135,10 +1155,8 @@ func TestAdminHostsController_handleUrisAddUpdate_NotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("returns 404 when host doesn't exist", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with uris config for non-existent host
@@ -1169,12 +1187,10 @@ func TestAdminHostsController_handleUrisAddUpdate_NotFound(t *testing.T) {
 	})
 
 	t.Run("returns 500 when service returns validation error", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with invalid uri config (latency min > max)
@@ -1201,12 +1217,10 @@ func TestAdminHostsController_handleUrisAddUpdate_NotFound(t *testing.T) {
 	})
 
 	t.Run("returns 400 when uris request has invalid JSON", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with invalid JSON
@@ -1233,12 +1247,10 @@ func TestAdminHostsController_handleUrisAddUpdate_NotFound(t *testing.T) {
 	})
 
 	t.Run("returns 400 when uris request has invalid host parameter", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
-		service := admin.NewHostsConfigAdminService(hostsConfig)
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
+		service := admin.NewHostsConfigAdminService(hostsConfig, nil, nil)
 		controller := NewAdminHostsController(hostsConfig, service)
 
 		// Create request with empty host
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Caik/go-mock-server/internal/rest"
+	"github.com/Caik/go-mock-server/internal/service/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsController exposes the metrics of the mock server to Prometheus
+type MetricsController struct {
+	metricsService *metrics.MetricsService
+}
+
+func (m *MetricsController) handleMetrics(c *gin.Context) {
+	if m.metricsService == nil {
+		c.JSON(http.StatusServiceUnavailable, rest.Response{
+			Status:  rest.Fail,
+			Message: "metrics are disabled",
+		})
+		return
+	}
+
+	m.metricsService.Handler().ServeHTTP(c.Writer, c.Request)
+}
+
+// NewMetricsController creates a new MetricsController
+func NewMetricsController(metricsService *metrics.MetricsService) *MetricsController {
+	return &MetricsController{
+		metricsService: metricsService,
+	}
+}
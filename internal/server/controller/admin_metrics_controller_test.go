@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+func newMetricsTestRouter(metricsService *metrics.MetricsService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/metrics", NewMetricsController(metricsService).handleMetrics)
+
+	return router
+}
+
+func TestMetricsController_handleMetrics(t *testing.T) {
+	t.Run("returns the metrics in the Prometheus text format", func(t *testing.T) {
+		metricsService := metrics.NewMetricsService(&config.AppArguments{}, nil, nil, nil, config.NewHostsConfigFrom(map[string]config.HostConfig{"example.com": {}}), nil)
+		metricsService.ObserveRequest("example.com", "GET", 200, true, time.Millisecond, 0)
+
+		w := httptest.NewRecorder()
+		newMetricsTestRouter(metricsService).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+
+		if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+			t.Errorf("unexpected content type: %s", contentType)
+		}
+
+		if !strings.Contains(w.Body.String(), `mock_server_requests_total{host="example.com",matched="true",method="GET",status="200"} 1`) {
+			t.Errorf("expected the request to be counted, got\n%s", w.Body.String())
+		}
+	})
+
+	t.Run("returns 503 when metrics are disabled", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newMetricsTestRouter(nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected status 503, got %d", w.Code)
+		}
+	})
+}
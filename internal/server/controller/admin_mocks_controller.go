@@ -167,7 +167,7 @@ func (a *AdminMocksController) handleMockAddUpdate(c *gin.Context) {
 		Method:     req.Method,
 		StatusCode: req.StatusCode,
 		Data:       &data,
-	}, uuid)
+	}, callerOf(c))
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, rest.Response{
@@ -247,7 +247,7 @@ func (a *AdminMocksController) handleMockCreate(c *gin.Context) {
 		Method:     req.Method,
 		StatusCode: req.StatusCode,
 		Data:       &data,
-	}, uuid)
+	}, callerOf(c))
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, rest.Response{
@@ -271,6 +271,134 @@ func (a *AdminMocksController) handleMockCreate(c *gin.Context) {
 	})
 }
 
+// handleMocksImport generates mocks from the OpenAPI 3 or Swagger 2 document sent as body
+func (a *AdminMocksController) handleMocksImport(c *gin.Context) {
+	uuid := c.GetString(util.UuidKey)
+	host := c.GetHeader("x-mock-host")
+
+	data, err := io.ReadAll(c.Request.Body)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, rest.Response{
+			Status:  rest.Error,
+			Message: fmt.Sprintf("error while reading request body: %v", err),
+		})
+
+		return
+	}
+
+	if len(data) == 0 {
+		c.JSON(http.StatusBadRequest, rest.Response{
+			Status:  rest.Fail,
+			Message: "invalid request: request body is empty",
+		})
+
+		return
+	}
+
+	log.Info().
+		Str("uuid", uuid).
+		Str("host", host).
+		Msg("importing OpenAPI document")
+
+	result, err := a.service.ImportOpenAPI(data, host, callerOf(c))
+
+	if errors.Is(err, admin.ErrInvalidImport) {
+		c.JSON(http.StatusBadRequest, rest.Response{
+			Status:  rest.Fail,
+			Message: fmt.Sprintf("invalid request: %v", err),
+		})
+
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, rest.Response{
+			Status:  rest.Error,
+			Message: fmt.Sprintf("error while importing OpenAPI document: %v", err),
+		})
+
+		log.Err(err).
+			Str("uuid", uuid).
+			Str("host", host).
+			Msg("failed to import OpenAPI document")
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, rest.Response{
+		Status:  rest.Success,
+		Message: "OpenAPI document imported successfully",
+		Data:    result,
+	})
+}
+
+// handleMocksImportHAR generates mocks from the HAR file sent as body, optionally only for the hosts given as query
+func (a *AdminMocksController) handleMocksImportHAR(c *gin.Context) {
+	uuid := c.GetString(util.UuidKey)
+
+	var hosts []string
+
+	if hostsParam := c.Query("hosts"); len(hostsParam) > 0 {
+		hosts = strings.Split(hostsParam, ",")
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, rest.Response{
+			Status:  rest.Error,
+			Message: fmt.Sprintf("error while reading request body: %v", err),
+		})
+
+		return
+	}
+
+	if len(data) == 0 {
+		c.JSON(http.StatusBadRequest, rest.Response{
+			Status:  rest.Fail,
+			Message: "invalid request: request body is empty",
+		})
+
+		return
+	}
+
+	log.Info().
+		Str("uuid", uuid).
+		Strs("hosts", hosts).
+		Msg("importing HAR file")
+
+	result, err := a.service.ImportHAR(data, hosts, callerOf(c))
+
+	if errors.Is(err, admin.ErrInvalidImport) {
+		c.JSON(http.StatusBadRequest, rest.Response{
+			Status:  rest.Fail,
+			Message: fmt.Sprintf("invalid request: %v", err),
+		})
+
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, rest.Response{
+			Status:  rest.Error,
+			Message: fmt.Sprintf("error while importing HAR file: %v", err),
+		})
+
+		log.Err(err).
+			Str("uuid", uuid).
+			Msg("failed to import HAR file")
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, rest.Response{
+		Status:  rest.Success,
+		Message: "HAR file imported successfully",
+		Data:    result,
+	})
+}
+
 func (a *AdminMocksController) handleMockUpdate(c *gin.Context) {
 	id := c.Param("id")
 
@@ -334,7 +462,7 @@ func (a *AdminMocksController) handleMockUpdate(c *gin.Context) {
 		Msg("updating mock")
 
 	// Delete the old mock first
-	if err := a.service.DeleteMockByID(id, uuid); err != nil {
+	if err := a.service.DeleteMockByID(id, callerOf(c)); err != nil {
 		if errors.Is(err, admin.ErrInvalidMockID) {
 			c.JSON(http.StatusBadRequest, rest.Response{
 				Status:  rest.Fail,
@@ -358,7 +486,7 @@ func (a *AdminMocksController) handleMockUpdate(c *gin.Context) {
 		Method:     req.Method,
 		StatusCode: req.StatusCode,
 		Data:       &data,
-	}, uuid)
+	}, callerOf(c))
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, rest.Response{
@@ -418,7 +546,7 @@ func (a *AdminMocksController) handleMockDelete(c *gin.Context) {
 		URI:        addReq.Uri,
 		Method:     addReq.Method,
 		StatusCode: addReq.StatusCode,
-	}, uuid)
+	}, callerOf(c))
 
 	if err != nil {
 		msg := fmt.Sprintf("error while deleting mock: %v", err)
@@ -463,7 +591,7 @@ func (a *AddDeleteMockRequest) validate() error {
 		return errors.New("invalid uri provided: it should not be empty")
 	}
 
-	if !util.UriRegex.MatchString(a.Uri) {
+	if !util.UriRegex.MatchString(a.Uri) && !util.UriPatternRegex.MatchString(a.Uri) {
 		return errors.New("invalid uri provided: it doesn't match a uri pattern")
 	}
 
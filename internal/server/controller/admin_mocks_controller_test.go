@@ -14,6 +14,7 @@ import (
 	"github.com/Caik/go-mock-server/internal/rest"
 	"github.com/Caik/go-mock-server/internal/service/admin"
 	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/matcher"
 	"github.com/Caik/go-mock-server/internal/util"
 	"github.com/gin-gonic/gin"
 )
@@ -31,6 +32,13 @@ func (m *mockContentService) SetContent(host, uri, method, uuid string, statusCo
 	return nil
 }
 
+func (m *mockContentService) SetContentMeta(host, uri, method, uuid string, statusCode int, meta content.ContentMeta) error {
+	if m.shouldError {
+		return errors.New(m.errorMsg)
+	}
+	return nil
+}
+
 func (m *mockContentService) GetContent(host, uri, method, uuid string, statusCode int) (*content.ContentResult, error) {
 	if m.shouldError {
 		return nil, errors.New(m.errorMsg)
@@ -43,6 +51,10 @@ func (m *mockContentService) GetContent(host, uri, method, uuid string, statusCo
 	}, nil
 }
 
+func (m *mockContentService) MatchContent(host, uri, method, uuid string, statusCode int, request matcher.Request) (*content.ContentResult, error) {
+	return m.GetContent(host, uri, method, uuid, statusCode)
+}
+
 func (m *mockContentService) DeleteContent(host, uri, method, uuid string, statusCode int) error {
 	if m.shouldError {
 		return errors.New(m.errorMsg)
@@ -79,10 +91,14 @@ func (m *mockContentService) Unsubscribe(subscriberId string) {
 	// Mock implementation
 }
 
+func (m *mockContentService) SubscriberCount() int {
+	return 0
+}
+
 func TestNewAdminMocksController(t *testing.T) {
 	t.Run("creates controller with service", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		if controller == nil {
@@ -100,7 +116,7 @@ func TestAdminMocksController_handleMockAddUpdate(t *testing.T) {
 
 	t.Run("adds mock successfully", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		// Create test request with headers and body
@@ -137,7 +153,7 @@ func TestAdminMocksController_handleMockAddUpdate(t *testing.T) {
 
 	t.Run("returns error for missing headers", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		// Create test request without required headers
@@ -171,7 +187,7 @@ func TestAdminMocksController_handleMockAddUpdate(t *testing.T) {
 
 	t.Run("returns error for invalid host", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		// Create test request with invalid host
@@ -208,7 +224,7 @@ func TestAdminMocksController_handleMockAddUpdate(t *testing.T) {
 
 	t.Run("returns error for empty request body", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		// Create test request with empty body
@@ -251,7 +267,7 @@ func TestAdminMocksController_handleMockAddUpdate(t *testing.T) {
 			shouldError: true,
 			errorMsg:    "service error",
 		}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		// Create test request
@@ -292,7 +308,7 @@ func TestAdminMocksController_handleMockDelete(t *testing.T) {
 
 	t.Run("deletes mock successfully", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		// Create test request with headers
@@ -328,7 +344,7 @@ func TestAdminMocksController_handleMockDelete(t *testing.T) {
 
 	t.Run("returns error for missing headers", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		// Create test request without required headers
@@ -364,7 +380,7 @@ func TestAdminMocksController_handleMockDelete(t *testing.T) {
 			shouldError: true,
 			errorMsg:    "delete error",
 		}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		// Create test request
@@ -416,6 +432,16 @@ func TestAddDeleteMockRequest_validate(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "valid uri pattern",
+			request: AddDeleteMockRequest{
+				Host:       "example.com",
+				Uri:        "/api/users/{id}/*",
+				Method:     "GET",
+				StatusCode: 200,
+			},
+			expectError: false,
+		},
 		{
 			name: "valid IP address",
 			request: AddDeleteMockRequest{
@@ -586,7 +612,7 @@ func TestAdminMocksController_HTTPIntegration(t *testing.T) {
 
 	t.Run("handles different HTTP methods correctly", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		methods := []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"}
@@ -623,7 +649,7 @@ func TestAdminMocksController_HTTPIntegration(t *testing.T) {
 
 	t.Run("handles different content types", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		testCases := []struct {
@@ -684,7 +710,7 @@ func TestAdminMocksController_HTTPIntegration(t *testing.T) {
 
 	t.Run("handles large request bodies", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		// Create a large JSON payload
@@ -721,7 +747,7 @@ func TestAdminMocksController_HTTPIntegration(t *testing.T) {
 
 	t.Run("handles special characters in URIs", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		specialURIs := []string{
@@ -762,7 +788,7 @@ func TestAdminMocksController_handleMockContent(t *testing.T) {
 
 	t.Run("returns content for valid ID", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		// Generate a valid mock ID
@@ -792,7 +818,7 @@ func TestAdminMocksController_handleMockContent(t *testing.T) {
 
 	t.Run("returns 400 for invalid mock ID", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		w := httptest.NewRecorder()
@@ -822,7 +848,7 @@ func TestAdminMocksController_handleMockContent(t *testing.T) {
 			shouldError: true,
 			errorMsg:    "not found",
 		}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		id := generateTestMockID("example.com", "/api/users", "GET")
@@ -855,7 +881,7 @@ func TestAdminMocksController_handleMockUpdate(t *testing.T) {
 
 	t.Run("updates mock successfully", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		id := generateTestMockID("example.com", "/api/users", "GET")
@@ -891,7 +917,7 @@ func TestAdminMocksController_handleMockUpdate(t *testing.T) {
 
 	t.Run("returns 400 for invalid mock ID", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		requestBody := []byte(`{"message": "updated"}`)
@@ -925,7 +951,7 @@ func TestAdminMocksController_handleMockUpdate(t *testing.T) {
 
 	t.Run("returns 400 for missing required headers", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		id := generateTestMockID("example.com", "/api/users", "GET")
@@ -949,7 +975,7 @@ func TestAdminMocksController_handleMockUpdate(t *testing.T) {
 
 	t.Run("returns 400 for empty body", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		id := generateTestMockID("example.com", "/api/users", "GET")
@@ -987,7 +1013,7 @@ func TestAdminMocksController_handleMockUpdate(t *testing.T) {
 			shouldError: true,
 			errorMsg:    "storage error",
 		}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		id := generateTestMockID("example.com", "/api/users", "GET")
@@ -1027,7 +1053,7 @@ func TestAdminMocksController_handleMockCreate(t *testing.T) {
 
 	t.Run("creates mock successfully returns 201", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		requestBody := []byte(`{"message": "created"}`)
@@ -1063,7 +1089,7 @@ func TestAdminMocksController_handleMockCreate(t *testing.T) {
 			shouldError: true,
 			errorMsg:    "create error",
 		}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		requestBody := []byte(`{"message": "created"}`)
@@ -1107,7 +1133,7 @@ func TestAdminMocksController_HandleMocksList(t *testing.T) {
 
 	t.Run("lists mocks successfully", func(t *testing.T) {
 		contentService := &mockContentService{}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/mocks", nil)
@@ -1146,7 +1172,7 @@ func TestAdminMocksController_HandleMocksList(t *testing.T) {
 			shouldError: true,
 			errorMsg:    "service error",
 		}
-		service := admin.NewMockAdminService(contentService)
+		service := admin.NewMockAdminService(contentService, nil)
 		controller := NewAdminMocksController(service)
 
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/mocks", nil)
@@ -1171,3 +1197,154 @@ func TestAdminMocksController_HandleMocksList(t *testing.T) {
 		}
 	})
 }
+
+func TestAdminMocksController_handleMocksImport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	document := `{
+  "openapi": "3.0.0",
+  "servers": [{"url": "https://api.example.com"}],
+  "paths": {"/users/{id}": {"get": {"responses": {"200": {"description": "ok"}}}}}
+}`
+
+	newContext := func(body, host string) (*gin.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/mocks/import", strings.NewReader(body))
+
+		if len(host) > 0 {
+			req.Header.Set("x-mock-host", host)
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Set(util.UuidKey, "test-uuid")
+
+		return c, w
+	}
+
+	t.Run("imports the document and returns 201", func(t *testing.T) {
+		controller := NewAdminMocksController(admin.NewMockAdminService(&mockContentService{}, nil))
+		c, w := newContext(document, "mocks.example.com")
+
+		controller.handleMocksImport(c)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var response struct {
+			Status string             `json:"status"`
+			Data   admin.ImportResult `json:"data"`
+		}
+
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+
+		if response.Data.Host != "mocks.example.com" || len(response.Data.Mocks) != 1 {
+			t.Errorf("unexpected import result %+v", response.Data)
+		}
+
+		if mock := response.Data.Mocks[0]; mock.URI != "/users/{id}" || mock.Method != "GET" || mock.StatusCode != 200 {
+			t.Errorf("unexpected mock %+v", mock)
+		}
+	})
+
+	tests := []struct {
+		name         string
+		body         string
+		host         string
+		shouldError  bool
+		expectedCode int
+	}{
+		{name: "returns 400 for empty body", body: "", expectedCode: http.StatusBadRequest},
+		{name: "returns 400 for invalid document", body: "not a document", expectedCode: http.StatusBadRequest},
+		{name: "returns 400 for invalid host", body: document, host: "invalid host", expectedCode: http.StatusBadRequest},
+		{name: "returns 500 when service fails", body: document, shouldError: true, expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentService := &mockContentService{shouldError: tt.shouldError, errorMsg: "write error"}
+			controller := NewAdminMocksController(admin.NewMockAdminService(contentService, nil))
+			c, w := newContext(tt.body, tt.host)
+
+			controller.handleMocksImport(c)
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestAdminMocksController_handleMocksImportHAR(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	archive := `{"log": {"version": "1.2", "entries": [
+  {"request": {"method": "GET", "url": "https://api.example.com/users/1"}, "response": {"status": 200, "content": {"text": "{}"}}},
+  {"request": {"method": "GET", "url": "https://cdn.example.com/logo.png"}, "response": {"status": 200, "content": {}}}
+]}}`
+
+	newContext := func(body, query string) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/mocks/import/har"+query, strings.NewReader(body))
+		c.Set(util.UuidKey, "test-uuid")
+
+		return c, w
+	}
+
+	t.Run("imports the given hosts and returns 201", func(t *testing.T) {
+		controller := NewAdminMocksController(admin.NewMockAdminService(&mockContentService{}, nil))
+		c, w := newContext(archive, "?hosts=api.example.com")
+
+		controller.handleMocksImportHAR(c)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var response struct {
+			Status string                `json:"status"`
+			Data   admin.HARImportResult `json:"data"`
+		}
+
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+
+		if len(response.Data.Mocks) != 1 {
+			t.Fatalf("unexpected import result %+v", response.Data)
+		}
+
+		if mock := response.Data.Mocks[0]; mock.Host != "api.example.com" || mock.URI != "/users/1" || mock.StatusCode != 200 {
+			t.Errorf("unexpected mock %+v", mock)
+		}
+	})
+
+	tests := []struct {
+		name         string
+		body         string
+		shouldError  bool
+		expectedCode int
+	}{
+		{name: "returns 400 for empty body", body: "", expectedCode: http.StatusBadRequest},
+		{name: "returns 400 for invalid file", body: "not a file", expectedCode: http.StatusBadRequest},
+		{name: "returns 500 when service fails", body: archive, shouldError: true, expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentService := &mockContentService{shouldError: tt.shouldError, errorMsg: "write error"}
+			controller := NewAdminMocksController(admin.NewMockAdminService(contentService, nil))
+			c, w := newContext(tt.body, "")
+
+			controller.handleMocksImportHAR(c)
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/rest"
+	"github.com/Caik/go-mock-server/internal/service/traffic"
+	"github.com/Caik/go-mock-server/internal/util"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultWaitTimeout = 5 * time.Second
+	maxWaitTimeout     = time.Minute
+)
+
+// VerifyRequestsRequest is a pattern along with how many requests matching it are expected
+type VerifyRequestsRequest struct {
+	traffic.RequestPattern
+	Count traffic.CountExpectation `json:"count"`
+}
+
+// WaitRequestsRequest is a pattern along with how many requests matching it to wait for, and for how long
+type WaitRequestsRequest struct {
+	traffic.RequestPattern
+	AtLeast int    `json:"at_least"`
+	Timeout string `json:"timeout"`
+}
+
+// RequestsCountResult holds how many requests matched a pattern
+type RequestsCountResult struct {
+	Count int `json:"count"`
+}
+
+// RequestsController answers the questions tests ask about the requests received, on top of the traffic log
+type RequestsController struct {
+	trafficLogService *traffic.TrafficLogService
+}
+
+func (r *RequestsController) handleRequestsVerify(c *gin.Context) {
+	uuid := c.GetString(util.UuidKey)
+	verifyReq := VerifyRequestsRequest{}
+
+	if !r.bindPattern(c, &verifyReq, &verifyReq.RequestPattern) {
+		return
+	}
+
+	if err := verifyReq.Count.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, rest.Response{
+			Status:  rest.Fail,
+			Message: fmt.Sprintf("invalid request: %v", err),
+		})
+
+		return
+	}
+
+	result := r.trafficLogService.Verify(&verifyReq.RequestPattern, verifyReq.Count)
+
+	log.Info().
+		Str("uuid", uuid).
+		Bool("passed", result.Passed).
+		Int("count", result.Count).
+		Msg("requests verified")
+
+	message := "verification passed"
+
+	if !result.Passed {
+		message = fmt.Sprintf("verification failed: expected %s matching requests, got %d", result.Expected, result.Count)
+	}
+
+	c.JSON(http.StatusOK, rest.Response{
+		Status:  rest.Success,
+		Message: message,
+		Data:    result,
+	})
+}
+
+func (r *RequestsController) handleRequestsCount(c *gin.Context) {
+	pattern := traffic.RequestPattern{}
+
+	if !r.bindPattern(c, &pattern, &pattern) {
+		return
+	}
+
+	c.JSON(http.StatusOK, rest.Response{
+		Status:  rest.Success,
+		Message: "requests counted with success",
+		Data:    RequestsCountResult{Count: r.trafficLogService.Count(&pattern)},
+	})
+}
+
+func (r *RequestsController) handleRequestsWait(c *gin.Context) {
+	uuid := c.GetString(util.UuidKey)
+	waitReq := WaitRequestsRequest{}
+
+	if !r.bindPattern(c, &waitReq, &waitReq.RequestPattern) {
+		return
+	}
+
+	timeout, err := r.parseWaitTimeout(waitReq)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, rest.Response{
+			Status:  rest.Fail,
+			Message: fmt.Sprintf("invalid request: %v", err),
+		})
+
+		return
+	}
+
+	atLeast := max(waitReq.AtLeast, 1)
+	expectation := traffic.CountExpectation{AtLeast: &atLeast}
+
+	log.Info().
+		Str("uuid", uuid).
+		Int("at_least", atLeast).
+		Dur("timeout", timeout).
+		Msg("waiting for requests")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	if _, reached := r.trafficLogService.WaitFor(ctx, &waitReq.RequestPattern, atLeast); !reached {
+		result := r.trafficLogService.Verify(&waitReq.RequestPattern, expectation)
+
+		c.JSON(http.StatusRequestTimeout, rest.Response{
+			Status:  rest.Fail,
+			Message: fmt.Sprintf("timed out after %s: expected %s matching requests, got %d", timeout, result.Expected, result.Count),
+			Data:    result,
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, rest.Response{
+		Status:  rest.Success,
+		Message: "matching requests received",
+		Data:    r.trafficLogService.Verify(&waitReq.RequestPattern, expectation),
+	})
+}
+
+func (r *RequestsController) handleRequestsReset(c *gin.Context) {
+	log.Info().
+		Str("uuid", c.GetString(util.UuidKey)).
+		Msg("resetting requests")
+
+	if r.trafficLogService == nil {
+		r.respondDisabled(c)
+		return
+	}
+
+	r.trafficLogService.Clear()
+
+	c.JSON(http.StatusOK, rest.Response{
+		Status:  rest.Success,
+		Message: "requests reset with success",
+	})
+}
+
+// bindPattern binds the body to the request and compiles its pattern, responding with an error when it can't
+func (r *RequestsController) bindPattern(c *gin.Context, request any, pattern *traffic.RequestPattern) bool {
+	if r.trafficLogService == nil {
+		r.respondDisabled(c)
+		return false
+	}
+
+	// an empty body is an empty pattern, matching every request
+	if err := c.ShouldBindJSON(request); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, rest.Response{
+			Status:  rest.Fail,
+			Message: fmt.Sprintf("invalid request: %v", err),
+		})
+
+		return false
+	}
+
+	if err := r.trafficLogService.CompilePattern(pattern); err != nil {
+		c.JSON(http.StatusBadRequest, rest.Response{
+			Status:  rest.Fail,
+			Message: fmt.Sprintf("invalid request: %v", err),
+		})
+
+		return false
+	}
+
+	return true
+}
+
+func (r *RequestsController) parseWaitTimeout(waitReq WaitRequestsRequest) (time.Duration, error) {
+	if waitReq.AtLeast < 0 {
+		return 0, fmt.Errorf("invalid at_least: must not be negative")
+	}
+
+	if len(waitReq.Timeout) == 0 {
+		return defaultWaitTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(waitReq.Timeout)
+
+	if err != nil || timeout <= 0 || timeout > maxWaitTimeout {
+		return 0, fmt.Errorf("invalid timeout %q: must be a duration up to %s", waitReq.Timeout, maxWaitTimeout)
+	}
+
+	return timeout, nil
+}
+
+func (r *RequestsController) respondDisabled(c *gin.Context) {
+	c.JSON(http.StatusServiceUnavailable, rest.Response{
+		Status:  rest.Fail,
+		Message: "traffic logging is disabled",
+	})
+}
+
+// NewRequestsController creates a new RequestsController
+func NewRequestsController(trafficLogService *traffic.TrafficLogService) *RequestsController {
+	return &RequestsController{
+		trafficLogService: trafficLogService,
+	}
+}
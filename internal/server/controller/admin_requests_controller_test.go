@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/rest"
+	"github.com/Caik/go-mock-server/internal/server/middleware"
+	"github.com/Caik/go-mock-server/internal/service/traffic"
+	"github.com/Caik/go-mock-server/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+func newRequestsTestRouter(trafficLogService *traffic.TrafficLogService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(util.UuidKey, "test-uuid")
+	})
+
+	initAdminRequestsController(router.Group("/api/v1/requests"), NewRequestsController(trafficLogService), middleware.RequireReadWrite(nil))
+
+	return router
+}
+
+func doRequestsRequest(t *testing.T, router *gin.Engine, method, path, body string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Status rest.Status    `json:"status"`
+		Data   map[string]any `json:"data"`
+	}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("unexpected error decoding response: %v", err)
+	}
+
+	return w, response.Data
+}
+
+func newRequestsTestService() *traffic.TrafficLogService {
+	service := newTestTrafficService(10)
+
+	for i, method := range []string{"POST", "POST", "GET"} {
+		entry := traffic.NewTrafficEntry(string(rune('a' + i)))
+		entry.Request = traffic.TrafficRequest{Method: method, Host: "shop.example.com", Path: "/orders", Body: `{"sku":"book"}`}
+		entry.Response = traffic.TrafficResponse{StatusCode: 201}
+		service.Capture(*entry)
+	}
+
+	return service
+}
+
+func TestRequestsController_handleRequestsVerify(t *testing.T) {
+	router := newRequestsTestRouter(newRequestsTestService())
+
+	t.Run("passes when the count meets the expectation", func(t *testing.T) {
+		body := `{"method": "POST", "host": "shop.example.com", "path": "/orders", "body_contains": "book", "count": {"exactly": 2}}`
+		w, data := doRequestsRequest(t, router, http.MethodPost, "/api/v1/requests/verify", body)
+
+		if w.Code != http.StatusOK || data["passed"] != true || data["count"] != float64(2) {
+			t.Errorf("unexpected response %d %v", w.Code, data)
+		}
+	})
+
+	t.Run("fails with the closest mismatches", func(t *testing.T) {
+		body := `{"method": "POST", "path": "/orders", "count": {"exactly": 3}}`
+		w, data := doRequestsRequest(t, router, http.MethodPost, "/api/v1/requests/verify", body)
+
+		if w.Code != http.StatusOK || data["passed"] != false || data["expected"] != "exactly 3" {
+			t.Fatalf("unexpected response %d %v", w.Code, data)
+		}
+
+		nearMisses := data["near_misses"].([]any)
+
+		if len(nearMisses) != 1 {
+			t.Fatalf("expected 1 near miss, got %v", nearMisses)
+		}
+
+		if differences := nearMisses[0].(map[string]any)["differences"].([]any); differences[0] != "method: expected POST, got GET" {
+			t.Errorf("unexpected differences %v", differences)
+		}
+	})
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "invalid json", body: `{"method":`},
+		{name: "invalid regex", body: `{"path_regex": "("}`},
+		{name: "invalid count", body: `{"count": {"exactly": 1, "at_most": 2}}`},
+		{name: "redacted header", body: `{"headers": {"Authorization": "Bearer token"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run("returns 400 for "+tt.name, func(t *testing.T) {
+			if w, _ := doRequestsRequest(t, router, http.MethodPost, "/api/v1/requests/verify", tt.body); w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestRequestsController_handleRequestsCount(t *testing.T) {
+	router := newRequestsTestRouter(newRequestsTestService())
+
+	if w, data := doRequestsRequest(t, router, http.MethodPost, "/api/v1/requests/count", `{"method": "post"}`); w.Code != http.StatusOK || data["count"] != float64(2) {
+		t.Errorf("unexpected response %d %v", w.Code, data)
+	}
+
+	if w, data := doRequestsRequest(t, router, http.MethodPost, "/api/v1/requests/count", ""); w.Code != http.StatusOK || data["count"] != float64(3) {
+		t.Errorf("expected an empty body to count every request, got %d %v", w.Code, data)
+	}
+}
+
+func TestRequestsController_handleRequestsWait(t *testing.T) {
+	t.Run("returns once the requests arrived", func(t *testing.T) {
+		service := newRequestsTestService()
+		router := newRequestsTestRouter(service)
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+
+			entry := traffic.NewTrafficEntry("d")
+			entry.Request = traffic.TrafficRequest{Method: "DELETE", Host: "shop.example.com", Path: "/orders/1"}
+			service.Capture(*entry)
+		}()
+
+		w, data := doRequestsRequest(t, router, http.MethodPost, "/api/v1/requests/wait", `{"method": "DELETE", "timeout": "5s"}`)
+
+		if w.Code != http.StatusOK || data["passed"] != true || data["count"] != float64(1) {
+			t.Errorf("unexpected response %d %v", w.Code, data)
+		}
+	})
+
+	t.Run("returns 408 on timeout", func(t *testing.T) {
+		router := newRequestsTestRouter(newRequestsTestService())
+
+		w, data := doRequestsRequest(t, router, http.MethodPost, "/api/v1/requests/wait", `{"method": "POST", "at_least": 3, "timeout": "50ms"}`)
+
+		if w.Code != http.StatusRequestTimeout || data["count"] != float64(2) || data["near_misses"] == nil {
+			t.Errorf("unexpected response %d %v", w.Code, data)
+		}
+	})
+
+	for _, timeout := range []string{"soon", "-1s", "2h"} {
+		t.Run("returns 400 for timeout "+timeout, func(t *testing.T) {
+			router := newRequestsTestRouter(newRequestsTestService())
+
+			if w, _ := doRequestsRequest(t, router, http.MethodPost, "/api/v1/requests/wait", `{"timeout": "`+timeout+`"}`); w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestRequestsController_handleRequestsReset(t *testing.T) {
+	service := newRequestsTestService()
+	router := newRequestsTestRouter(service)
+
+	if w, _ := doRequestsRequest(t, router, http.MethodDelete, "/api/v1/requests", ""); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	if service.Size() != 0 {
+		t.Errorf("expected the traffic log to be cleared, got %d entries", service.Size())
+	}
+}
+
+func TestRequestsController_disabled(t *testing.T) {
+	router := newRequestsTestRouter(nil)
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/api/v1/requests/verify"},
+		{http.MethodPost, "/api/v1/requests/count"},
+		{http.MethodPost, "/api/v1/requests/wait"},
+		{http.MethodDelete, "/api/v1/requests"},
+	}
+
+	for _, route := range routes {
+		if w, _ := doRequestsRequest(t, router, route.method, route.path, "{}"); w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s %s: expected status 503, got %d", route.method, route.path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Caik/go-mock-server/internal/rest"
+	"github.com/Caik/go-mock-server/internal/service/scenario"
+	"github.com/Caik/go-mock-server/internal/util"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type SetScenarioStateRequest struct {
+	State string `json:"state" binding:"required"`
+}
+
+// ScenariosController handles the inspection and reset of the scenarios state
+type ScenariosController struct {
+	scenarioService *scenario.ScenarioService
+}
+
+func (s *ScenariosController) handleScenariosList(c *gin.Context) {
+	log.Info().
+		Str("uuid", c.GetString(util.UuidKey)).
+		Msg("getting scenarios")
+
+	c.JSON(http.StatusOK, rest.Response{
+		Status:  rest.Success,
+		Message: "scenarios retrieved with success",
+		Data:    s.scenarioService.List(),
+	})
+}
+
+func (s *ScenariosController) handleScenarioRetrieve(c *gin.Context) {
+	name := c.Param("name")
+
+	log.Info().
+		Str("uuid", c.GetString(util.UuidKey)).
+		Str("scenario", name).
+		Msg("getting scenario")
+
+	current, exists := s.scenarioService.Get(name)
+
+	if !exists {
+		c.JSON(http.StatusNotFound, rest.Response{
+			Status:  rest.Fail,
+			Message: "scenario not found",
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, rest.Response{
+		Status:  rest.Success,
+		Message: "scenario retrieved with success",
+		Data:    current,
+	})
+}
+
+func (s *ScenariosController) handleScenarioStateUpdate(c *gin.Context) {
+	name := c.Param("name")
+	updateReq := SetScenarioStateRequest{}
+
+	if err := c.ShouldBind(&updateReq); err != nil {
+		c.JSON(http.StatusBadRequest, rest.Response{
+			Status:  rest.Fail,
+			Message: fmt.Sprintf("invalid request: %v", err),
+		})
+
+		return
+	}
+
+	if len(strings.TrimSpace(updateReq.State)) == 0 {
+		c.JSON(http.StatusBadRequest, rest.Response{
+			Status:  rest.Fail,
+			Message: "invalid request: state should not be empty",
+		})
+
+		return
+	}
+
+	log.Info().
+		Str("uuid", c.GetString(util.UuidKey)).
+		Str("scenario", name).
+		Str("state", updateReq.State).
+		Msg("setting scenario state")
+
+	c.JSON(http.StatusOK, rest.Response{
+		Status:  rest.Success,
+		Message: "scenario state updated with success",
+		Data:    s.scenarioService.SetState(name, updateReq.State),
+	})
+}
+
+func (s *ScenariosController) handleScenarioReset(c *gin.Context) {
+	name := c.Param("name")
+
+	log.Info().
+		Str("uuid", c.GetString(util.UuidKey)).
+		Str("scenario", name).
+		Msg("resetting scenario")
+
+	if !s.scenarioService.Reset(name) {
+		c.JSON(http.StatusNotFound, rest.Response{
+			Status:  rest.Fail,
+			Message: "scenario not found",
+		})
+
+		return
+	}
+
+	current, _ := s.scenarioService.Get(name)
+
+	c.JSON(http.StatusOK, rest.Response{
+		Status:  rest.Success,
+		Message: "scenario reset with success",
+		Data:    current,
+	})
+}
+
+func (s *ScenariosController) handleScenariosReset(c *gin.Context) {
+	log.Info().
+		Str("uuid", c.GetString(util.UuidKey)).
+		Msg("resetting all scenarios")
+
+	s.scenarioService.ResetAll()
+
+	c.JSON(http.StatusOK, rest.Response{
+		Status:  rest.Success,
+		Message: "scenarios reset with success",
+		Data:    s.scenarioService.List(),
+	})
+}
+
+// NewScenariosController creates a new ScenariosController
+func NewScenariosController(scenarioService *scenario.ScenarioService) *ScenariosController {
+	return &ScenariosController{
+		scenarioService: scenarioService,
+	}
+}
@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Caik/go-mock-server/internal/rest"
+	"github.com/Caik/go-mock-server/internal/server/middleware"
+	"github.com/Caik/go-mock-server/internal/service/scenario"
+	"github.com/Caik/go-mock-server/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+func newScenariosTestRouter(scenarioService *scenario.ScenarioService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(util.UuidKey, "test-uuid")
+	})
+
+	initAdminScenariosController(router.Group("/api/v1/scenarios"), NewScenariosController(scenarioService), middleware.RequireReadWrite(nil))
+
+	return router
+}
+
+func doScenariosRequest(t *testing.T, router *gin.Engine, method, path, body string) (*httptest.ResponseRecorder, rest.Response) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	var response rest.Response
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("unexpected error decoding response: %v", err)
+	}
+
+	return w, response
+}
+
+func TestScenariosController_handleScenariosList(t *testing.T) {
+	scenarioService := scenario.NewScenarioService()
+	scenarioService.SetState("order", "shipped")
+
+	w, response := doScenariosRequest(t, newScenariosTestRouter(scenarioService), http.MethodGet, "/api/v1/scenarios", "")
+
+	if w.Code != http.StatusOK || response.Status != rest.Success {
+		t.Fatalf("expected 200 success, got %d %s", w.Code, response.Status)
+	}
+
+	scenarios, ok := response.Data.([]any)
+
+	if !ok || len(scenarios) != 1 {
+		t.Fatalf("expected one scenario, got %v", response.Data)
+	}
+
+	if scenarios[0].(map[string]any)["state"] != "shipped" {
+		t.Errorf("unexpected scenario: %v", scenarios[0])
+	}
+}
+
+func TestScenariosController_handleScenarioRetrieve(t *testing.T) {
+	scenarioService := scenario.NewScenarioService()
+	scenarioService.SetState("order", "shipped")
+	router := newScenariosTestRouter(scenarioService)
+
+	t.Run("returns a known scenario", func(t *testing.T) {
+		w, response := doScenariosRequest(t, router, http.MethodGet, "/api/v1/scenarios/order", "")
+
+		if w.Code != http.StatusOK || response.Data.(map[string]any)["state"] != "shipped" {
+			t.Errorf("unexpected response: %d %v", w.Code, response.Data)
+		}
+	})
+
+	t.Run("returns 404 for an unknown scenario", func(t *testing.T) {
+		w, response := doScenariosRequest(t, router, http.MethodGet, "/api/v1/scenarios/payment", "")
+
+		if w.Code != http.StatusNotFound || response.Status != rest.Fail {
+			t.Errorf("expected 404 fail, got %d %s", w.Code, response.Status)
+		}
+	})
+}
+
+func TestScenariosController_handleScenarioStateUpdate(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+	}{
+		{"sets the state", `{"state":"paid"}`, http.StatusOK},
+		{"rejects a missing state", `{}`, http.StatusBadRequest},
+		{"rejects a blank state", `{"state":"  "}`, http.StatusBadRequest},
+		{"rejects invalid json", `{`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenarioService := scenario.NewScenarioService()
+
+			w, _ := doScenariosRequest(t, newScenariosTestRouter(scenarioService), http.MethodPut, "/api/v1/scenarios/order", tt.body)
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+
+			if tt.expectedCode == http.StatusOK && scenarioService.State("order") != "paid" {
+				t.Errorf("expected state paid, got %q", scenarioService.State("order"))
+			}
+		})
+	}
+}
+
+func TestScenariosController_handleScenarioReset(t *testing.T) {
+	scenarioService := scenario.NewScenarioService()
+	scenarioService.SetState("order", "shipped")
+	scenarioService.SetState("payment", "paid")
+	router := newScenariosTestRouter(scenarioService)
+
+	t.Run("resets a known scenario", func(t *testing.T) {
+		w, _ := doScenariosRequest(t, router, http.MethodDelete, "/api/v1/scenarios/order", "")
+
+		if w.Code != http.StatusOK || scenarioService.State("order") != scenario.StartState {
+			t.Errorf("expected scenario to be reset, got %d %q", w.Code, scenarioService.State("order"))
+		}
+
+		if scenarioService.State("payment") != "paid" {
+			t.Error("expected other scenarios to keep their state")
+		}
+	})
+
+	t.Run("returns 404 for an unknown scenario", func(t *testing.T) {
+		w, _ := doScenariosRequest(t, router, http.MethodDelete, "/api/v1/scenarios/unknown", "")
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("resets all the scenarios", func(t *testing.T) {
+		w, _ := doScenariosRequest(t, router, http.MethodDelete, "/api/v1/scenarios", "")
+
+		if w.Code != http.StatusOK || scenarioService.State("payment") != scenario.StartState {
+			t.Errorf("expected all scenarios to be reset, got %d %q", w.Code, scenarioService.State("payment"))
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Caik/go-mock-server/internal/rest"
+	"github.com/Caik/go-mock-server/internal/service/certificate"
+	"github.com/Caik/go-mock-server/internal/util"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// TLSController handles the export of the local CA, for the test clients to trust it
+type TLSController struct {
+	certificateService *certificate.CertificateService
+}
+
+func (t *TLSController) handleCACertificate(c *gin.Context) {
+	log.Info().
+		Str("uuid", c.GetString(util.UuidKey)).
+		Msg("exporting TLS CA certificate")
+
+	caPEM, exists := t.certificateService.CACertificatePEM()
+
+	if !exists {
+		c.JSON(http.StatusNotFound, rest.Response{
+			Status:  rest.Fail,
+			Message: "no local CA in use, TLS is disabled or uses the supplied certificate",
+		})
+
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="go-mock-server-ca.pem"`)
+	c.Data(http.StatusOK, "application/x-pem-file", caPEM)
+}
+
+// NewTLSController creates a new TLSController
+func NewTLSController(certificateService *certificate.CertificateService) *TLSController {
+	return &TLSController{
+		certificateService: certificateService,
+	}
+}
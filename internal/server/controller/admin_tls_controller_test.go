@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/certificate"
+	"github.com/gin-gonic/gin"
+)
+
+func newTLSTestRouter(certificateService *certificate.CertificateService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/api/v1/tls/ca", NewTLSController(certificateService).handleCACertificate)
+
+	return router
+}
+
+func TestTLSController_handleCACertificate(t *testing.T) {
+	t.Run("returns the local CA", func(t *testing.T) {
+		certificateService, err := certificate.NewCertificateService(&config.AppArguments{TLS: true})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		w := httptest.NewRecorder()
+		newTLSTestRouter(certificateService).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/tls/ca", nil))
+
+		caPEM, _ := certificateService.CACertificatePEM()
+
+		if w.Code != http.StatusOK || w.Body.String() != string(caPEM) {
+			t.Errorf("expected the CA certificate, got %d %s", w.Code, w.Body.String())
+		}
+
+		if contentType := w.Header().Get("Content-Type"); contentType != "application/x-pem-file" {
+			t.Errorf("unexpected content type: %s", contentType)
+		}
+	})
+
+	t.Run("returns 404 without local CA", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newTLSTestRouter(nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/tls/ca", nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", w.Code)
+		}
+	})
+}
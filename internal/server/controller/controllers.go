@@ -4,6 +4,10 @@ import (
 	"net/http"
 
 	"github.com/Caik/go-mock-server/internal/rest"
+	"github.com/Caik/go-mock-server/internal/server/middleware"
+	"github.com/Caik/go-mock-server/internal/service/audit"
+	"github.com/Caik/go-mock-server/internal/service/auth"
+	"github.com/Caik/go-mock-server/internal/util"
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,16 +17,30 @@ func InitMockRoutes(r *gin.Engine, mocksController *MocksController) {
 }
 
 // InitAdminRoutes initializes routes for the admin server
-func InitAdminRoutes(r *gin.Engine, adminMocksController *AdminMocksController, adminHostsController *AdminHostsController, trafficController *TrafficController) {
-	// Health check endpoint
+func InitAdminRoutes(r *gin.Engine, authService *auth.AuthService, adminMocksController *AdminMocksController, adminHostsController *AdminHostsController, trafficController *TrafficController, requestsController *RequestsController, scenariosController *ScenariosController, cacheController *CacheController, tlsController *TLSController, metricsController *MetricsController, auditController *AuditController) {
+	// every caller can read, while only the read-write role can change the state
+	authenticate := middleware.Authenticate(authService)
+	write := middleware.RequireReadWrite(authService)
+
+	// Health check endpoint, left open for the probes
 	r.GET("/health", handleHealthCheck)
 
+	// Prometheus metrics endpoint
+	r.GET("/metrics", authenticate, metricsController.handleMetrics)
+
 	// API v1 routes
-	v1 := r.Group("/api/v1")
+	v1 := r.Group("/api/v1", authenticate)
 	{
-		initAdminMocksController(v1.Group("/mocks"), adminMocksController)
-		initAdminHostsController(v1.Group("/config/hosts"), adminHostsController)
+		v1.GET("/auth/identity", handleIdentity)
+		initAdminMocksController(v1.Group("/mocks"), adminMocksController, write)
+		initAdminHostsController(v1.Group("/config/hosts"), adminHostsController, write)
+		v1.GET("/config/export", adminHostsController.handleHostsConfigExport)
 		initAdminTrafficController(v1.Group("/traffic"), trafficController)
+		initAdminRequestsController(v1.Group("/requests"), requestsController, write)
+		initAdminScenariosController(v1.Group("/scenarios"), scenariosController, write)
+		initAdminCacheController(v1.Group("/cache"), cacheController, write)
+		v1.GET("/tls/ca", tlsController.handleCACertificate)
+		initAdminAuditController(v1.Group("/audit"), auditController)
 	}
 }
 
@@ -33,30 +51,95 @@ func handleHealthCheck(c *gin.Context) {
 	})
 }
 
-func initAdminMocksController(r *gin.RouterGroup, controller *AdminMocksController) {
+// handleIdentity returns the identity of the caller, so the UI can tell whether it may change the state
+func handleIdentity(c *gin.Context) {
+	identity, exists := c.Get(util.IdentityKey)
+
+	if !exists {
+		c.JSON(http.StatusOK, rest.Response{
+			Status:  rest.Success,
+			Message: "authentication is disabled",
+			Data:    auth.Identity{Method: auth.MethodNone, Role: auth.RoleReadWrite},
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, rest.Response{
+		Status:  rest.Success,
+		Message: "identity retrieved with success",
+		Data:    identity,
+	})
+}
+
+// callerOf returns the caller of an admin request, for the audit log
+func callerOf(c *gin.Context) audit.Caller {
+	identity, _ := c.Get(util.IdentityKey)
+	caller, _ := identity.(*auth.Identity)
+
+	return audit.Caller{
+		UUID:       c.GetString(util.UuidKey),
+		Identity:   caller,
+		RemoteAddr: c.ClientIP(),
+	}
+}
+
+func initAdminMocksController(r *gin.RouterGroup, controller *AdminMocksController, write gin.HandlerFunc) {
 	r.GET("", controller.handleMocksList)
 	r.GET("/:id/content", controller.handleMockContent)
-	r.POST("", controller.handleMockCreate)
-	r.PUT("/:id", controller.handleMockUpdate)
-	r.DELETE("", controller.handleMockDelete)
+	r.POST("", write, controller.handleMockCreate)
+	r.POST("/import", write, controller.handleMocksImport)
+	r.POST("/import/har", write, controller.handleMocksImportHAR)
+	r.PUT("/:id", write, controller.handleMockUpdate)
+	r.DELETE("", write, controller.handleMockDelete)
 }
 
-func initAdminHostsController(r *gin.RouterGroup, controller *AdminHostsController) {
+func initAdminHostsController(r *gin.RouterGroup, controller *AdminHostsController, write gin.HandlerFunc) {
 	r.GET("", controller.handleHostsConfigList)
-	r.POST("", controller.handleHostConfigAddUpdate)
+	r.POST("", write, controller.handleHostConfigAddUpdate)
 
 	r.GET("/:host", controller.handleHostConfigRetrieve)
-	r.DELETE("/:host", controller.handleHostConfigDelete)
+	r.DELETE("/:host", write, controller.handleHostConfigDelete)
 
-	r.POST("/:host/latencies", controller.handleLatencyAddUpdate)
-	r.DELETE("/:host/latencies", controller.handleLatencyDelete)
+	r.POST("/:host/latencies", write, controller.handleLatencyAddUpdate)
+	r.DELETE("/:host/latencies", write, controller.handleLatencyDelete)
 
-	r.POST("/:host/statuses", controller.handleStatusesAddUpdate)
-	r.DELETE("/:host/statuses/:status", controller.handleStatusDelete)
+	r.POST("/:host/statuses", write, controller.handleStatusesAddUpdate)
+	r.DELETE("/:host/statuses/:status", write, controller.handleStatusDelete)
 
-	r.POST("/:host/uris", controller.handleUrisAddUpdate)
+	r.POST("/:host/uris", write, controller.handleUrisAddUpdate)
 }
 
 func initAdminTrafficController(r *gin.RouterGroup, controller *TrafficController) {
 	r.GET("", controller.handleTrafficStream)
+	r.GET("/entries", controller.handleTrafficEntries)
+	r.GET("/har", controller.handleTrafficHAR)
+	r.GET("/:uuid", controller.handleTrafficEntry)
+}
+
+func initAdminRequestsController(r *gin.RouterGroup, controller *RequestsController, write gin.HandlerFunc) {
+	// verifying only reads the traffic log, even if the queries are sent as POST
+	r.POST("/verify", controller.handleRequestsVerify)
+	r.POST("/count", controller.handleRequestsCount)
+	r.POST("/wait", controller.handleRequestsWait)
+	r.DELETE("", write, controller.handleRequestsReset)
+}
+
+func initAdminScenariosController(r *gin.RouterGroup, controller *ScenariosController, write gin.HandlerFunc) {
+	r.GET("", controller.handleScenariosList)
+	r.DELETE("", write, controller.handleScenariosReset)
+
+	r.GET("/:name", controller.handleScenarioRetrieve)
+	r.PUT("/:name", write, controller.handleScenarioStateUpdate)
+	r.DELETE("/:name", write, controller.handleScenarioReset)
+}
+
+func initAdminCacheController(r *gin.RouterGroup, controller *CacheController, write gin.HandlerFunc) {
+	r.GET("", controller.handleCacheStats)
+	r.DELETE("", write, controller.handleCacheFlush)
+}
+
+func initAdminAuditController(r *gin.RouterGroup, controller *AuditController) {
+	r.GET("", controller.handleAuditEvents)
+	r.GET("/stream", controller.handleAuditStream)
 }
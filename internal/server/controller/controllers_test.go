@@ -3,8 +3,17 @@ package controller
 import (
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/server/middleware"
+	"github.com/Caik/go-mock-server/internal/service/auth"
+	"github.com/Caik/go-mock-server/internal/service/cache"
+	"github.com/Caik/go-mock-server/internal/service/scenario"
+	"github.com/Caik/go-mock-server/internal/util"
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,10 +27,16 @@ func TestInitAdminRoutes(t *testing.T) {
 		// Create mock controllers (they can be nil for route testing)
 		adminMocksController := &AdminMocksController{}
 		adminHostsController := &AdminHostsController{}
-		trafficController := NewTrafficController(nil)
+		trafficController := NewTrafficController(nil, nil)
+		requestsController := NewRequestsController(nil)
+		scenariosController := NewScenariosController(scenario.NewScenarioService())
+		cacheController := NewCacheController(cache.NewInMemoryCacheService(&config.AppArguments{}))
+		tlsController := NewTLSController(nil)
+		metricsController := NewMetricsController(nil)
+		auditController := NewAuditController(nil)
 
 		// Initialize admin routes
-		InitAdminRoutes(router, adminMocksController, adminHostsController, trafficController)
+		InitAdminRoutes(router, nil, adminMocksController, adminHostsController, trafficController, requestsController, scenariosController, cacheController, tlsController, metricsController, auditController)
 
 		// Test health endpoint
 		t.Run("GET /health", func(t *testing.T) {
@@ -35,6 +50,18 @@ func TestInitAdminRoutes(t *testing.T) {
 			}
 		})
 
+		// Test metrics endpoint, unavailable with the metrics disabled
+		t.Run("GET /metrics", func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusServiceUnavailable {
+				t.Errorf("metrics endpoint should return 503, got %d", w.Code)
+			}
+		})
+
 		// Test admin mocks routes
 		adminMocksRoutes := []struct {
 			method string
@@ -42,6 +69,8 @@ func TestInitAdminRoutes(t *testing.T) {
 		}{
 			{http.MethodPost, "/api/v1/mocks"},
 			{http.MethodDelete, "/api/v1/mocks"},
+			{http.MethodPost, "/api/v1/mocks/import"},
+			{http.MethodPost, "/api/v1/mocks/import/har"},
 		}
 
 		for _, route := range adminMocksRoutes {
@@ -80,6 +109,7 @@ func TestInitAdminRoutes(t *testing.T) {
 			{http.MethodPost, "/api/v1/config/hosts/example.com/statuses"},
 			{http.MethodDelete, "/api/v1/config/hosts/example.com/statuses/500"},
 			{http.MethodPost, "/api/v1/config/hosts/example.com/uris"},
+			{http.MethodGet, "/api/v1/config/export"},
 		}
 
 		for _, route := range adminHostsRoutes {
@@ -104,6 +134,74 @@ func TestInitAdminRoutes(t *testing.T) {
 				}
 			})
 		}
+
+		// Test admin scenarios routes
+		adminScenariosRoutes := []struct {
+			method string
+			path   string
+		}{
+			{http.MethodGet, "/api/v1/scenarios"},
+			{http.MethodDelete, "/api/v1/scenarios"},
+		}
+
+		for _, route := range adminScenariosRoutes {
+			t.Run(route.method+" "+route.path, func(t *testing.T) {
+				req := httptest.NewRequest(route.method, route.path, nil)
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				if w.Code != http.StatusOK {
+					t.Errorf("route %s %s should return 200, got %d", route.method, route.path, w.Code)
+				}
+			})
+		}
+
+		// Test admin cache routes
+		adminCacheRoutes := []struct {
+			method string
+			path   string
+		}{
+			{http.MethodGet, "/api/v1/cache"},
+			{http.MethodDelete, "/api/v1/cache"},
+		}
+
+		for _, route := range adminCacheRoutes {
+			t.Run(route.method+" "+route.path, func(t *testing.T) {
+				req := httptest.NewRequest(route.method, route.path, nil)
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				if w.Code != http.StatusOK {
+					t.Errorf("route %s %s should return 200, got %d", route.method, route.path, w.Code)
+				}
+			})
+		}
+
+		// Test admin requests routes, unavailable with the traffic log disabled
+		adminRequestsRoutes := []struct {
+			method string
+			path   string
+		}{
+			{http.MethodPost, "/api/v1/requests/verify"},
+			{http.MethodPost, "/api/v1/requests/count"},
+			{http.MethodPost, "/api/v1/requests/wait"},
+			{http.MethodDelete, "/api/v1/requests"},
+		}
+
+		for _, route := range adminRequestsRoutes {
+			t.Run(route.method+" "+route.path, func(t *testing.T) {
+				req := httptest.NewRequest(route.method, route.path, nil)
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				if w.Code != http.StatusServiceUnavailable {
+					t.Errorf("route %s %s should return 503, got %d", route.method, route.path, w.Code)
+				}
+			})
+		}
 	})
 }
 
@@ -147,7 +245,7 @@ func TestInitAdminMocksControllerRoutes(t *testing.T) {
 		group := router.Group("/api/v1/mocks")
 		controller := &AdminMocksController{}
 
-		initAdminMocksController(group, controller)
+		initAdminMocksController(group, controller, middleware.RequireReadWrite(nil))
 
 		// Test POST route
 		req := httptest.NewRequest(http.MethodPost, "/api/v1/mocks", nil)
@@ -166,6 +264,24 @@ func TestInitAdminMocksControllerRoutes(t *testing.T) {
 		if w.Code == http.StatusNotFound {
 			t.Error("DELETE /api/v1/mocks route should exist")
 		}
+
+		// Test import route
+		req = httptest.NewRequest(http.MethodPost, "/api/v1/mocks/import", nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code == http.StatusNotFound {
+			t.Error("POST /api/v1/mocks/import route should exist")
+		}
+
+		// Test HAR import route
+		req = httptest.NewRequest(http.MethodPost, "/api/v1/mocks/import/har", nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code == http.StatusNotFound {
+			t.Error("POST /api/v1/mocks/import/har route should exist")
+		}
 	})
 }
 
@@ -175,9 +291,9 @@ func TestInitAdminHostsControllerRoutes(t *testing.T) {
 	t.Run("sets up admin hosts routes", func(t *testing.T) {
 		router := gin.New()
 		group := router.Group("/api/v1/config/hosts")
-		controller := &AdminHostsController{}
+		controller := &AdminHostsController{hostsConfig: config.NewHostsConfigFrom(nil)}
 
-		initAdminHostsController(group, controller)
+		initAdminHostsController(group, controller, middleware.RequireReadWrite(nil))
 
 		// Test base routes
 		baseRoutes := []struct {
@@ -232,9 +348,15 @@ func TestRouteGrouping(t *testing.T) {
 
 		adminMocksController := &AdminMocksController{}
 		adminHostsController := &AdminHostsController{}
-		trafficController := NewTrafficController(nil)
+		trafficController := NewTrafficController(nil, nil)
+		requestsController := NewRequestsController(nil)
+		scenariosController := NewScenariosController(scenario.NewScenarioService())
+		cacheController := NewCacheController(cache.NewInMemoryCacheService(&config.AppArguments{}))
+		tlsController := NewTLSController(nil)
+		metricsController := NewMetricsController(nil)
+		auditController := NewAuditController(nil)
 
-		InitAdminRoutes(router, adminMocksController, adminHostsController, trafficController)
+		InitAdminRoutes(router, nil, adminMocksController, adminHostsController, trafficController, requestsController, scenariosController, cacheController, tlsController, metricsController, auditController)
 
 		// Test that admin routes are under correct paths
 		adminPaths := []string{
@@ -292,3 +414,93 @@ func TestRouteGrouping(t *testing.T) {
 		}
 	})
 }
+
+func TestInitAdminRoutes_auth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tokensFile := filepath.Join(t.TempDir(), "tokens")
+
+	if err := os.WriteFile(tokensFile, []byte("ci:ci-secret\nviewer:viewer-secret\n"), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	authService, err := auth.NewAuthService(&config.AppArguments{AdminTokensFile: tokensFile, AdminWriters: "ci"})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	router := gin.New()
+	InitAdminRoutes(router, authService, &AdminMocksController{}, &AdminHostsController{}, NewTrafficController(nil, nil),
+		NewRequestsController(nil), NewScenariosController(scenario.NewScenarioService()),
+		NewCacheController(cache.NewInMemoryCacheService(&config.AppArguments{})), NewTLSController(nil), NewMetricsController(nil), NewAuditController(nil))
+
+	tests := []struct {
+		method   string
+		path     string
+		token    string
+		expected int
+	}{
+		{http.MethodGet, "/health", "", http.StatusOK},
+		{http.MethodGet, "/metrics", "", http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/cache", "", http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/cache", "wrong", http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/cache", "viewer-secret", http.StatusOK},
+		{http.MethodDelete, "/api/v1/cache", "viewer-secret", http.StatusForbidden},
+		{http.MethodDelete, "/api/v1/cache", "ci-secret", http.StatusOK},
+		{http.MethodPost, "/api/v1/mocks", "viewer-secret", http.StatusForbidden},
+		{http.MethodPost, "/api/v1/config/hosts", "viewer-secret", http.StatusForbidden},
+		{http.MethodPut, "/api/v1/scenarios/checkout", "viewer-secret", http.StatusForbidden},
+		{http.MethodDelete, "/api/v1/requests", "viewer-secret", http.StatusForbidden},
+		// every caller can read the audit log
+		{http.MethodGet, "/api/v1/audit", "viewer-secret", http.StatusServiceUnavailable},
+		// the verification queries only read the traffic log
+		{http.MethodPost, "/api/v1/requests/count", "viewer-secret", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path+" "+tt.token, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+
+			if len(tt.token) > 0 {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expected {
+				t.Errorf("expected status %d, got %d", tt.expected, w.Code)
+			}
+		})
+	}
+}
+
+func TestHandleIdentity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("returns the identity of the caller", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/auth/identity", nil)
+		c.Set(util.IdentityKey, &auth.Identity{Name: "viewer", Method: auth.MethodToken, Role: auth.RoleReadOnly})
+
+		handleIdentity(c)
+
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"role":"read-only"`) || !strings.Contains(w.Body.String(), `"name":"viewer"`) {
+			t.Errorf("unexpected response %d %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("grants the read-write role when authentication is disabled", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/auth/identity", nil)
+
+		handleIdentity(c)
+
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"role":"read-write"`) {
+			t.Errorf("unexpected response %d %s", w.Code, w.Body.String())
+		}
+	})
+}
@@ -1,14 +1,34 @@
 package controller
 
 import (
+	"crypto/rand"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/metrics"
 	"github.com/Caik/go-mock-server/internal/service/mock"
+	"github.com/Caik/go-mock-server/internal/service/tracing"
 	"github.com/Caik/go-mock-server/internal/service/traffic"
 	"github.com/Caik/go-mock-server/internal/util"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	streamBufferSize = 32 * 1024
+	// garbageSize is the number of random bytes sent by the garbage fault
+	garbageSize = 1024
 )
 
 var (
@@ -24,11 +44,32 @@ type MockResponseProvider interface {
 type MocksController struct {
 	factory           MockResponseProvider
 	trafficLogService *traffic.TrafficLogService
+	metricsService    *metrics.MetricsService
+	tracingService    *tracing.TracingService
+	maxBodySize       int64 // size above which the request bodies are rejected, 0 for no limit
 }
 
 func (m *MocksController) handleMockRequest(c *gin.Context) {
 	startTime := time.Now()
-	mockRequest := m.newMockRequest(c)
+	mockRequest, err := m.newMockRequest(c)
+
+	var maxBytesErr *http.MaxBytesError
+
+	if errors.As(err, &maxBytesErr) {
+		log.Warn().
+			Str("uuid", c.GetString(util.UuidKey)).
+			Int64("max_body_size", maxBytesErr.Limit).
+			Msg("request body too large")
+
+		c.Data(http.StatusRequestEntityTooLarge, "text/plain",
+			[]byte(fmt.Sprintf("request body larger than %d bytes", maxBytesErr.Limit)))
+
+		return
+	}
+
+	span := m.startSpan(c, &mockRequest)
+	defer span.End()
+
 	mockResponse := m.factory.GetMockResponse(mockRequest)
 
 	// bad mock server configuration
@@ -48,22 +89,310 @@ func (m *MocksController) handleMockRequest(c *gin.Context) {
 		c.Header(key, value)
 	}
 
-	c.Data(mockResponse.StatusCode, mockResponse.ContentType, *mockResponse.Data)
+	responseBody := *mockResponse.Data
+
+	if mockResponse.Fault != nil && m.canInjectFault(c, mockRequest, mockResponse) {
+		responseBody = m.injectFault(c, mockRequest, mockResponse)
+	} else if mockResponse.Stream != nil {
+		responseBody = m.streamResponse(c, mockRequest, mockResponse)
+	} else {
+		c.Data(mockResponse.StatusCode, mockResponse.ContentType, responseBody)
+	}
 
 	// Capture traffic after response is sent
-	m.captureTraffic(c, mockRequest, mockResponse, startTime)
+	m.captureTraffic(c, mockRequest, mockResponse, responseBody, startTime)
+
+	m.metricsService.ObserveRequest(mockRequest.Host, mockRequest.Method, mockResponse.StatusCode,
+		mockResponse.Metadata[mock.MetadataMatched] == "true", time.Since(startTime), mockResponse.SimulatedLatency())
+
+	span.SetAttributes(attribute.Int("http.response.status_code", mockResponse.StatusCode))
+
+	if mockResponse.StatusCode >= 500 {
+		span.SetStatus(codes.Error, http.StatusText(mockResponse.StatusCode))
+	}
+}
+
+// startSpan starts the span of the request, continuing the trace of the client if it sent one, and hands it
+// over to the mock chain through the context of the mock request
+func (m *MocksController) startSpan(c *gin.Context, mockRequest *mock.MockRequest) trace.Span {
+	ctx := m.tracingService.Extract(c.Request.Context(), c.Request.Header)
+
+	ctx, span := m.tracingService.Start(ctx, mockRequest.Method+" "+mockRequest.Host,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("http.request.method", mockRequest.Method),
+			attribute.String("server.address", mockRequest.Host),
+			attribute.String("url.path", c.Request.URL.Path),
+			attribute.String("url.query", c.Request.URL.RawQuery),
+			attribute.String("mock.request.uuid", mockRequest.Uuid),
+		),
+	)
+
+	mockRequest.Context = ctx
+
+	return span
 }
 
-func (m *MocksController) newMockRequest(c *gin.Context) mock.MockRequest {
+// newMockRequest builds the mock request from the request of the client. It fails only when the body is larger than
+// the maximum size, with an *http.MaxBytesError.
+func (m *MocksController) newMockRequest(c *gin.Context) (mock.MockRequest, error) {
+	uuid := c.GetString(util.UuidKey)
+	var body []byte
+
+	if c.Request.Body != nil {
+		reader := c.Request.Body
+
+		// the body is kept in memory, so it has to be bounded
+		if m.maxBodySize > 0 {
+			reader = http.MaxBytesReader(c.Writer, c.Request.Body, m.maxBodySize)
+		}
+
+		data, err := io.ReadAll(reader)
+
+		var maxBytesErr *http.MaxBytesError
+
+		if errors.As(err, &maxBytesErr) {
+			return mock.MockRequest{}, err
+		}
+
+		if err != nil {
+			log.Warn().
+				Str("uuid", uuid).
+				Msgf("error while reading request body: %v", err)
+		}
+
+		body = data
+	}
+
 	return mock.MockRequest{
-		Host:   m.sanitizeHost(c.Request.Host),
-		URI:    c.Request.RequestURI,
-		Method: c.Request.Method,
-		Accept: c.GetHeader("accept"),
-		Uuid:   c.GetString(util.UuidKey),
+		Host:    m.sanitizeHost(m.requestHost(c.Request)),
+		URI:     m.requestURI(c.Request),
+		Method:  c.Request.Method,
+		Accept:  c.GetHeader("accept"),
+		Uuid:    uuid,
+		Headers: c.Request.Header.Clone(),
+		Body:    body,
+	}, nil
+}
+
+// streamResponse relays a streamed body to the client, flushing every chunk as soon as it is received. It returns the
+// beginning of the body, up to the size kept in the traffic log.
+func (m *MocksController) streamResponse(c *gin.Context, mockRequest mock.MockRequest, mockResponse *mock.MockResponse) []byte {
+	defer mockResponse.Stream.Close()
+
+	if len(mockResponse.ContentType) > 0 {
+		c.Header("Content-Type", mockResponse.ContentType)
+	}
+
+	c.Status(mockResponse.StatusCode)
+	c.Writer.WriteHeaderNow()
+
+	buffer := make([]byte, streamBufferSize)
+	maxBodySize := m.trafficLogService.MaxBodySize()
+	var captured []byte
+
+	for {
+		n, err := mockResponse.Stream.Read(buffer)
+
+		if n > 0 {
+			if m.trafficLogService != nil && len(captured) <= maxBodySize {
+				captured = append(captured, buffer[:min(n, maxBodySize+1-len(captured))]...)
+			}
+
+			if _, writeErr := c.Writer.Write(buffer[:n]); writeErr != nil {
+				log.Warn().
+					Str("uuid", mockRequest.Uuid).
+					Msgf("error while writing streamed response: %v", writeErr)
+
+				return captured
+			}
+
+			c.Writer.Flush()
+		}
+
+		if err == io.EOF {
+			return captured
+		}
+
+		if err != nil {
+			log.Warn().
+				Str("uuid", mockRequest.Uuid).
+				Msgf("error while reading streamed response: %v", err)
+
+			return captured
+		}
 	}
 }
 
+// canInjectFault tells if the fault of the response can be injected, as it takes over the connection, which is only
+// possible with HTTP/1.x. Otherwise, the response is served as usual.
+func (m *MocksController) canInjectFault(c *gin.Context, mockRequest mock.MockRequest, mockResponse *mock.MockResponse) bool {
+	if c.Request.ProtoMajor == 1 {
+		return true
+	}
+
+	log.Warn().
+		Str("uuid", mockRequest.Uuid).
+		Str("fault", mockResponse.Fault.Type).
+		Msgf("faults can't be injected over %s, serving the response as usual", c.Request.Proto)
+
+	return false
+}
+
+// injectFault takes over the connection and breaks the response as its fault says. It returns the part of the body
+// that was sent.
+func (m *MocksController) injectFault(c *gin.Context, mockRequest mock.MockRequest, mockResponse *mock.MockResponse) []byte {
+	body := m.faultBody(mockRequest, mockResponse)
+
+	if len(mockResponse.ContentType) > 0 {
+		c.Header("Content-Type", mockResponse.ContentType)
+	}
+
+	// only recorded for the logs, as nothing is written through the response writer
+	c.Status(mockResponse.StatusCode)
+
+	conn, buf, err := c.Writer.Hijack()
+
+	if err != nil {
+		log.Warn().
+			Str("uuid", mockRequest.Uuid).
+			Msgf("error while taking over the connection to inject a fault, serving the response as usual: %v", err)
+
+		c.Data(mockResponse.StatusCode, mockResponse.ContentType, body)
+
+		return body
+	}
+
+	// the deadlines of the server no longer apply, as the connection was taken over
+	_ = conn.SetDeadline(time.Time{})
+
+	var sent []byte
+
+	switch mockResponse.Fault.Type {
+	case config.FaultConnectionReset:
+		resetConnection(conn)
+		return nil
+	case config.FaultHang:
+		writeHead(buf.Writer, mockResponse.StatusCode, c.Writer.Header(), strconv.Itoa(len(body)))
+		_ = buf.Flush()
+		hang(buf.Reader, mockResponse.Fault.Duration)
+	case config.FaultTruncatedBody:
+		// an empty body still announces a byte, so there is something missing
+		sent = body[:len(body)/2]
+		writeHead(buf.Writer, mockResponse.StatusCode, c.Writer.Header(), strconv.Itoa(max(len(body), 1)))
+		_, _ = buf.Write(sent)
+		_ = buf.Flush()
+	case config.FaultInvalidContentLength:
+		sent = body
+		writeHead(buf.Writer, mockResponse.StatusCode, c.Writer.Header(), "-1")
+		_, _ = buf.Write(sent)
+		_ = buf.Flush()
+	case config.FaultGarbage:
+		garbage := make([]byte, garbageSize)
+		_, _ = rand.Read(garbage)
+		_, _ = buf.Write(garbage)
+		_ = buf.Flush()
+	}
+
+	if err := conn.Close(); err != nil {
+		log.Warn().
+			Str("uuid", mockRequest.Uuid).
+			Msgf("error while closing the connection after injecting a fault: %v", err)
+	}
+
+	return sent
+}
+
+// faultBody returns the body of the response, reading a streamed body whole as most of the faults need its size
+func (m *MocksController) faultBody(mockRequest mock.MockRequest, mockResponse *mock.MockResponse) []byte {
+	if mockResponse.Stream == nil {
+		return *mockResponse.Data
+	}
+
+	defer mockResponse.Stream.Close()
+
+	body, err := io.ReadAll(mockResponse.Stream)
+
+	if err != nil {
+		log.Warn().
+			Str("uuid", mockRequest.Uuid).
+			Msgf("error while reading streamed response: %v", err)
+	}
+
+	mockResponse.Data = &body
+	mockResponse.Stream = nil
+
+	return body
+}
+
+// writeHead writes the status line and the headers of a response, with the given Content-Length
+func writeHead(w io.Writer, statusCode int, header http.Header, contentLength string) {
+	header = header.Clone()
+	header.Set("Content-Length", contentLength)
+	header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+
+	_, _ = fmt.Fprintf(w, "HTTP/1.1 %03d %s\r\n", statusCode, http.StatusText(statusCode))
+	_ = header.Write(w)
+	_, _ = io.WriteString(w, "\r\n")
+}
+
+// hang holds the connection until the client closes it, or for the given duration if set
+func hang(reader io.Reader, duration time.Duration) {
+	clientGone := make(chan struct{})
+
+	go func() {
+		// nothing else is expected from the client, so the reading only ends when the connection is closed
+		_, _ = io.Copy(io.Discard, reader)
+		close(clientGone)
+	}()
+
+	var timeout <-chan time.Time
+
+	if duration > 0 {
+		timer := time.NewTimer(duration)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	select {
+	case <-clientGone:
+	case <-timeout:
+	}
+}
+
+// resetConnection closes the connection abruptly, so the client gets a TCP RST rather than a graceful close
+func resetConnection(conn net.Conn) {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		_ = tcpConn.SetLinger(0)
+	}
+
+	_ = conn.Close()
+}
+
+// requestHost returns the host of the request, falling back to the SNI host when the Host header is missing
+func (m *MocksController) requestHost(request *http.Request) string {
+	if len(request.Host) == 0 && request.TLS != nil {
+		return request.TLS.ServerName
+	}
+
+	return request.Host
+}
+
+// requestURI returns the uri of the request, in origin form even for the proxy requests sent in absolute form
+// (e.g. GET http://api.example.com/users)
+func (m *MocksController) requestURI(request *http.Request) string {
+	if strings.HasPrefix(request.RequestURI, "/") || request.URL == nil || !request.URL.IsAbs() {
+		return request.RequestURI
+	}
+
+	return request.URL.RequestURI()
+}
+
 func (m *MocksController) sanitizeHost(host string) string {
 	index := strings.Index(host, ":")
 
@@ -74,39 +403,62 @@ func (m *MocksController) sanitizeHost(host string) string {
 	return strings.ToLower(host[0:index])
 }
 
-func NewMocksController(factory MockResponseProvider, trafficLogService *traffic.TrafficLogService) *MocksController {
+func NewMocksController(factory MockResponseProvider, trafficLogService *traffic.TrafficLogService, metricsService *metrics.MetricsService,
+	tracingService *tracing.TracingService, args *config.AppArguments) *MocksController {
 	controller := MocksController{
 		factory:           factory,
 		trafficLogService: trafficLogService,
+		metricsService:    metricsService,
+		tracingService:    tracingService,
+		maxBodySize:       args.MaxRequestBodySize,
 	}
 
 	return &controller
 }
 
 // captureTraffic logs the request/response traffic for debugging
-func (m *MocksController) captureTraffic(c *gin.Context, mockRequest mock.MockRequest, mockResponse *mock.MockResponse, startTime time.Time) {
+func (m *MocksController) captureTraffic(c *gin.Context, mockRequest mock.MockRequest, mockResponse *mock.MockResponse, responseBody []byte, startTime time.Time) {
 	if m.trafficLogService == nil {
 		return
 	}
 
+	scheme := "http"
+
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+
+	bodySize := len(responseBody)
+
+	// streamed bodies are not kept in memory, so their size is taken from what was written
+	if mockResponse.Stream != nil {
+		bodySize = max(c.Writer.Size(), 0)
+	}
+
 	// Build TrafficEntry from request and response
 	entry := traffic.TrafficEntry{
 		UUID:      mockRequest.Uuid,
 		Timestamp: startTime,
 		Request: traffic.TrafficRequest{
-			Method: mockRequest.Method,
-			Host:   mockRequest.Host,
-			Path:   c.Request.URL.Path,
-			Query:  c.Request.URL.RawQuery,
+			Method:  mockRequest.Method,
+			Scheme:  scheme,
+			Host:    mockRequest.Host,
+			Path:    c.Request.URL.Path,
+			Query:   c.Request.URL.RawQuery,
+			Headers: traffic.FlattenHeaders(mockRequest.Headers),
 		},
 		Response: traffic.TrafficResponse{
 			StatusCode:  mockResponse.StatusCode,
 			ContentType: mockResponse.ContentType,
-			BodySize:    len(*mockResponse.Data),
+			Headers:     traffic.FlattenHeaders(c.Writer.Header()),
 			LatencyMs:   time.Since(startTime).Milliseconds(),
 		},
 		Metadata: mockResponse.Metadata,
 	}
 
+	maxBodySize := m.trafficLogService.MaxBodySize()
+	entry.Request.SetBody(mockRequest.Body, maxBodySize)
+	entry.Response.SetBody(responseBody, bodySize, maxBodySize)
+
 	m.trafficLogService.Capture(entry)
 }
@@ -1,29 +1,41 @@
 package controller
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/metrics"
 	"github.com/Caik/go-mock-server/internal/service/mock"
+	"github.com/Caik/go-mock-server/internal/service/tracing"
 	"github.com/Caik/go-mock-server/internal/service/traffic"
 	"github.com/Caik/go-mock-server/internal/util"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func newTestTrafficLogService(bufferSize int) *traffic.TrafficLogService {
 	return traffic.NewTrafficLogService(&config.AppArguments{
-		TrafficLogBufferSize: bufferSize,
+		TrafficLogBufferSize:    bufferSize,
+		TrafficLogMaxBodySize:   1024,
+		TrafficLogRedactHeaders: "Authorization",
 	})
 }
 
 // mockResponseProvider is a test implementation of MockResponseProvider
 type mockResponseProvider struct {
-	response *mock.MockResponse
+	response    *mock.MockResponse
+	lastRequest mock.MockRequest
 }
 
 func (m *mockResponseProvider) GetMockResponse(mockRequest mock.MockRequest) *mock.MockResponse {
+	m.lastRequest = mockRequest
 	return m.response
 }
 
@@ -31,7 +43,7 @@ func TestNewMocksController(t *testing.T) {
 	t.Run("creates controller with factory", func(t *testing.T) {
 		// We can't easily mock MockServiceFactory since it's a concrete struct
 		// So we'll test with a nil factory and verify the controller is created
-		controller := NewMocksController(nil, nil)
+		controller := NewMocksController(nil, nil, nil, nil, &config.AppArguments{})
 
 		if controller == nil {
 			t.Fatal("NewMocksController should return non-nil controller")
@@ -129,7 +141,7 @@ func TestMocksController_newMockRequest(t *testing.T) {
 		c.Set(util.UuidKey, "test-uuid-123")
 
 		// Create mock request
-		mockRequest := controller.newMockRequest(c)
+		mockRequest, _ := controller.newMockRequest(c)
 
 		// Verify the mock request
 		if mockRequest.Host != "api.example.com" {
@@ -153,6 +165,73 @@ func TestMocksController_newMockRequest(t *testing.T) {
 		}
 	})
 
+	t.Run("uses the SNI host when the Host header is missing", func(t *testing.T) {
+		controller := &MocksController{}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		req := httptest.NewRequest(http.MethodGet, "https://api.example.com/api/users", nil)
+		req.Host = ""
+		c.Request = req
+
+		mockRequest, _ := controller.newMockRequest(c)
+
+		if mockRequest.Host != "api.example.com" {
+			t.Errorf("expected host 'api.example.com', got '%s'", mockRequest.Host)
+		}
+
+		req.TLS.ServerName = "other.example.com"
+		req.Host = "api.example.com"
+
+		if mockRequest, _ := controller.newMockRequest(c); mockRequest.Host != "api.example.com" {
+			t.Errorf("expected the Host header to take precedence, got '%s'", mockRequest.Host)
+		}
+	})
+
+	t.Run("uses the origin form of proxy requests", func(t *testing.T) {
+		controller := &MocksController{}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		req := httptest.NewRequest(http.MethodGet, "http://api.partner.com/api/users?id=123", nil)
+		req.RequestURI = "http://api.partner.com/api/users?id=123"
+		c.Request = req
+
+		mockRequest, _ := controller.newMockRequest(c)
+
+		if mockRequest.Host != "api.partner.com" {
+			t.Errorf("expected host 'api.partner.com', got '%s'", mockRequest.Host)
+		}
+
+		if mockRequest.URI != "/api/users?id=123" {
+			t.Errorf("expected URI '/api/users?id=123', got '%s'", mockRequest.URI)
+		}
+	})
+
+	t.Run("captures request headers and body", func(t *testing.T) {
+		controller := &MocksController{}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(`{"name":"alice"}`))
+		req.Host = "example.com"
+		req.Header.Set("X-Custom", "value")
+		c.Request = req
+
+		mockRequest, _ := controller.newMockRequest(c)
+
+		if string(mockRequest.Body) != `{"name":"alice"}` {
+			t.Errorf("expected request body to be captured, got '%s'", string(mockRequest.Body))
+		}
+
+		if mockRequest.Headers.Get("X-Custom") != "value" {
+			t.Errorf("expected request headers to be captured, got %v", mockRequest.Headers)
+		}
+	})
+
 	t.Run("handles missing UUID", func(t *testing.T) {
 		controller := &MocksController{}
 
@@ -165,7 +244,7 @@ func TestMocksController_newMockRequest(t *testing.T) {
 
 		// Don't set UUID in context
 
-		mockRequest := controller.newMockRequest(c)
+		mockRequest, _ := controller.newMockRequest(c)
 
 		if mockRequest.Uuid != "" {
 			t.Errorf("expected empty UUID, got '%s'", mockRequest.Uuid)
@@ -182,7 +261,7 @@ func TestMocksController_newMockRequest(t *testing.T) {
 		req.Host = "example.com"
 		c.Request = req
 
-		mockRequest := controller.newMockRequest(c)
+		mockRequest, _ := controller.newMockRequest(c)
 
 		if mockRequest.Accept != "" {
 			t.Errorf("expected empty Accept, got '%s'", mockRequest.Accept)
@@ -195,7 +274,7 @@ func TestMocksController_handleMockRequest(t *testing.T) {
 
 	t.Run("returns 500 when factory returns nil response", func(t *testing.T) {
 		mockProvider := &mockResponseProvider{response: nil}
-		controller := NewMocksController(mockProvider, nil)
+		controller := NewMocksController(mockProvider, nil, nil, nil, &config.AppArguments{})
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -232,7 +311,7 @@ func TestMocksController_handleMockRequest(t *testing.T) {
 				Headers:     headers,
 			},
 		}
-		controller := NewMocksController(mockProvider, nil)
+		controller := NewMocksController(mockProvider, nil, nil, nil, &config.AppArguments{})
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -279,7 +358,7 @@ func TestMocksController_handleMockRequest(t *testing.T) {
 				Headers:     nil,
 			},
 		}
-		controller := NewMocksController(mockProvider, nil)
+		controller := NewMocksController(mockProvider, nil, nil, nil, &config.AppArguments{})
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -317,7 +396,7 @@ func TestMocksController_handleMockRequest(t *testing.T) {
 				Data:        &data,
 			},
 		}
-		controller := NewMocksController(mockProvider, nil)
+		controller := NewMocksController(mockProvider, nil, nil, nil, &config.AppArguments{})
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -343,7 +422,7 @@ func TestMocksController_handleMockRequest(t *testing.T) {
 				Data:        &data,
 			},
 		}
-		controller := NewMocksController(mockProvider, nil)
+		controller := NewMocksController(mockProvider, nil, nil, nil, &config.AppArguments{})
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -373,7 +452,7 @@ func TestMocksController_handleMockRequest(t *testing.T) {
 				Data:        &data,
 			},
 		}
-		controller := NewMocksController(mockProvider, nil)
+		controller := NewMocksController(mockProvider, nil, nil, nil, &config.AppArguments{})
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -399,7 +478,7 @@ func TestMocksController_handleMockRequest(t *testing.T) {
 				Data:        &emptyData,
 			},
 		}
-		controller := NewMocksController(mockProvider, nil)
+		controller := NewMocksController(mockProvider, nil, nil, nil, &config.AppArguments{})
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -429,7 +508,7 @@ func TestMocksController_handleMockRequest(t *testing.T) {
 				Data:        &data,
 			},
 		}
-		controller := NewMocksController(mockProvider, nil)
+		controller := NewMocksController(mockProvider, nil, nil, nil, &config.AppArguments{})
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -467,7 +546,7 @@ func TestMocksController_handleMockRequest(t *testing.T) {
 				Headers:     headers,
 			},
 		}
-		controller := NewMocksController(mockProvider, nil)
+		controller := NewMocksController(mockProvider, nil, nil, nil, &config.AppArguments{})
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -493,7 +572,7 @@ func TestMocksController_handleMockRequest(t *testing.T) {
 				Data:        &data,
 			},
 		}
-		controller := NewMocksController(mockProvider, nil)
+		controller := NewMocksController(mockProvider, nil, nil, nil, &config.AppArguments{})
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -521,7 +600,7 @@ func TestMocksController_handleMockRequest(t *testing.T) {
 				Headers:     headers,
 			},
 		}
-		controller := NewMocksController(mockProvider, nil)
+		controller := NewMocksController(mockProvider, nil, nil, nil, &config.AppArguments{})
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -541,6 +620,49 @@ func TestMocksController_handleMockRequest(t *testing.T) {
 			t.Errorf("expected status ok message, got %s", w.Body.String())
 		}
 	})
+
+	t.Run("relays streamed response body", func(t *testing.T) {
+		empty := []byte("")
+		mockProvider := &mockResponseProvider{
+			response: &mock.MockResponse{
+				StatusCode:  202,
+				ContentType: "text/plain",
+				Data:        &empty,
+				Headers:     map[string]string{"X-Upstream": "yes"},
+				Stream:      io.NopCloser(strings.NewReader("streamed body")),
+			},
+		}
+		trafficLogService := newTestTrafficLogService(10)
+		controller := NewMocksController(mockProvider, trafficLogService, nil, nil, &config.AppArguments{})
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/stream", nil)
+		req.Host = "api.example.com"
+		c.Request = req
+		c.Set(util.UuidKey, "test-uuid")
+
+		controller.handleMockRequest(c)
+
+		if w.Code != 202 {
+			t.Errorf("expected 202 status code, got %d", w.Code)
+		}
+
+		if w.Body.String() != "streamed body" {
+			t.Errorf("expected streamed body, got %s", w.Body.String())
+		}
+
+		if w.Header().Get("Content-Type") != "text/plain" || w.Header().Get("X-Upstream") != "yes" {
+			t.Errorf("expected streamed response headers, got %v", w.Header())
+		}
+
+		entries := trafficLogService.GetAll()
+
+		if len(entries) != 1 || entries[0].Response.BodySize != len("streamed body") {
+			t.Errorf("expected traffic entry with streamed body size, got %+v", entries)
+		}
+	})
 }
 
 func TestMocksController_RequestParsing(t *testing.T) {
@@ -561,7 +683,7 @@ func TestMocksController_RequestParsing(t *testing.T) {
 				c.Request = req
 				c.Set(util.UuidKey, "test-uuid")
 
-				mockRequest := controller.newMockRequest(c)
+				mockRequest, _ := controller.newMockRequest(c)
 
 				if mockRequest.Method != method {
 					t.Errorf("expected method %s, got %s", method, mockRequest.Method)
@@ -604,7 +726,7 @@ func TestMocksController_RequestParsing(t *testing.T) {
 				c.Request = req
 				c.Set(util.UuidKey, "test-uuid")
 
-				mockRequest := controller.newMockRequest(c)
+				mockRequest, _ := controller.newMockRequest(c)
 
 				if mockRequest.Accept != acceptHeader {
 					t.Errorf("expected Accept '%s', got '%s'", acceptHeader, mockRequest.Accept)
@@ -635,7 +757,7 @@ func TestMocksController_RequestParsing(t *testing.T) {
 				c.Request = req
 				c.Set(util.UuidKey, "test-uuid")
 
-				mockRequest := controller.newMockRequest(c)
+				mockRequest, _ := controller.newMockRequest(c)
 
 				if mockRequest.URI != uri {
 					t.Errorf("expected URI '%s', got '%s'", uri, mockRequest.URI)
@@ -660,7 +782,7 @@ func TestMocksController_captureTraffic(t *testing.T) {
 		}
 
 		trafficLogService := newTestTrafficLogService(10)
-		controller := NewMocksController(mockProvider, trafficLogService)
+		controller := NewMocksController(mockProvider, trafficLogService, nil, nil, &config.AppArguments{})
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -702,9 +824,376 @@ func TestMocksController_captureTraffic(t *testing.T) {
 			t.Errorf("expected Metadata[Matched]=true, got %q", entry.Metadata["Matched"])
 		}
 	})
+
+	t.Run("captures headers and bodies", func(t *testing.T) {
+		data := []byte(`{"id":1}`)
+		mockProvider := &mockResponseProvider{
+			response: &mock.MockResponse{
+				StatusCode:  201,
+				ContentType: "application/json",
+				Data:        &data,
+			},
+		}
+
+		trafficLogService := newTestTrafficLogService(10)
+		controller := NewMocksController(mockProvider, trafficLogService, nil, nil, &config.AppArguments{})
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		req := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(`{"name":"john"}`))
+		req.Host = "example.com"
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Add("Accept", "text/plain")
+		req.Header.Add("Accept", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+		c.Request = req
+		c.Set(util.UuidKey, "test-capture-uuid")
+
+		controller.handleMockRequest(c)
+
+		entry := trafficLogService.GetAll()[0]
+
+		if entry.Request.Scheme != "http" || entry.Request.Body != `{"name":"john"}` || entry.Request.BodySize != 15 {
+			t.Errorf("unexpected request %+v", entry.Request)
+		}
+
+		if entry.Request.Headers["Accept"] != "text/plain, application/json" {
+			t.Errorf("expected the repeated headers joined, got %q", entry.Request.Headers["Accept"])
+		}
+
+		if entry.Request.Headers["Authorization"] != "[REDACTED]" {
+			t.Errorf("expected the Authorization header redacted, got %q", entry.Request.Headers["Authorization"])
+		}
+
+		if entry.Response.Body != `{"id":1}` || entry.Response.BodySize != 8 || entry.Response.BodyTruncated {
+			t.Errorf("unexpected response %+v", entry.Response)
+		}
+
+		if !strings.HasPrefix(entry.Response.Headers["Content-Type"], "application/json") {
+			t.Errorf("expected the response headers, got %v", entry.Response.Headers)
+		}
+	})
 }
 
 // Helper function
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestMocksController_maxBodySize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	data := []byte("hello")
+	serve := func(body string) (*httptest.ResponseRecorder, *mockResponseProvider) {
+		mockProvider := &mockResponseProvider{response: &mock.MockResponse{StatusCode: 200, Data: &data}}
+		controller := NewMocksController(mockProvider, nil, nil, nil, &config.AppArguments{MaxRequestBodySize: 8})
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(body))
+		c.Request.Host = "example.com"
+		c.Set(util.UuidKey, "test-uuid")
+
+		controller.handleMockRequest(c)
+
+		return w, mockProvider
+	}
+
+	t.Run("serves the bodies up to the maximum size", func(t *testing.T) {
+		w, mockProvider := serve("12345678")
+
+		if w.Code != http.StatusOK || string(mockProvider.lastRequest.Body) != "12345678" {
+			t.Errorf("expected the request to be served, got %d with body %q", w.Code, mockProvider.lastRequest.Body)
+		}
+	})
+
+	t.Run("rejects the bodies larger than the maximum size", func(t *testing.T) {
+		w, mockProvider := serve("123456789")
+
+		if w.Code != http.StatusRequestEntityTooLarge || w.Body.String() != "request body larger than 8 bytes" {
+			t.Errorf("expected a 413, got %d: %s", w.Code, w.Body.String())
+		}
+
+		if mockProvider.lastRequest.Method != "" {
+			t.Error("expected the request not to reach the mocks")
+		}
+	})
+}
+
+func TestMocksController_metrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	data := []byte("hello")
+	mockProvider := &mockResponseProvider{
+		response: &mock.MockResponse{
+			StatusCode: 404,
+			Data:       &data,
+			Metadata:   map[string]string{mock.MetadataMatched: "false"},
+		},
+	}
+
+	metricsService := metrics.NewMetricsService(&config.AppArguments{}, nil, nil, nil, config.NewHostsConfigFrom(map[string]config.HostConfig{"example.com": {}}), nil)
+	controller := NewMocksController(mockProvider, nil, metricsService, nil, &config.AppArguments{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	req.Host = "example.com"
+	c.Request = req
+	c.Set(util.UuidKey, "test-uuid")
+
+	controller.handleMockRequest(c)
+
+	var output strings.Builder
+	metricsService.Write(&output)
+
+	if !strings.Contains(output.String(), `mock_server_requests_total{host="example.com",matched="false",method="POST",status="404"} 1`) {
+		t.Errorf("expected the request to be counted, got\n%s", output.String())
+	}
+}
+
+func TestMocksController_tracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recorder := tracetest.NewSpanRecorder()
+	tracingService, err := tracing.NewTracingServiceFrom(recorder, "test")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := []byte("unavailable")
+	mockProvider := &mockResponseProvider{
+		response: &mock.MockResponse{StatusCode: 503, Data: &data},
+	}
+
+	controller := NewMocksController(mockProvider, nil, nil, tracingService, &config.AppArguments{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	req := httptest.NewRequest(http.MethodGet, "/users?page=2", nil)
+	req.Host = "example.com"
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	c.Request = req
+	c.Set(util.UuidKey, "test-uuid")
+
+	controller.handleMockRequest(c)
+
+	spans := recorder.Ended()
+
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	span := spans[0]
+
+	if span.Name() != "GET example.com" || span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("unexpected span %s of kind %s", span.Name(), span.SpanKind())
+	}
+
+	if got := span.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected the trace of the client, got %s", got)
+	}
+
+	if got := span.Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("expected the span of the client as parent, got %s", got)
+	}
+
+	if span.Status().Code != codes.Error {
+		t.Errorf("expected an error status for a 503, got %v", span.Status().Code)
+	}
+
+	attributes := make(map[string]string)
+
+	for _, kv := range span.Attributes() {
+		attributes[string(kv.Key)] = kv.Value.Emit()
+	}
+
+	for key, expected := range map[string]string{
+		"http.request.method":       "GET",
+		"server.address":            "example.com",
+		"url.path":                  "/users",
+		"url.query":                 "page=2",
+		"http.response.status_code": "503",
+	} {
+		if attributes[key] != expected {
+			t.Errorf("expected %s=%s, got %q", key, expected, attributes[key])
+		}
+	}
+
+	// the chain continues the span of the request
+	if got := trace.SpanFromContext(mockProvider.lastRequest.Context).SpanContext().SpanID(); got != span.SpanContext().SpanID() {
+		t.Errorf("expected the span of the request in the context of the mock request, got %s", got)
+	}
+}
+
+func TestMocksController_faults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// newFaultServer serves a response with the given fault through a real connection, as the faults take it over
+	newFaultServer := func(t *testing.T, fault *mock.Fault, trafficLogService *traffic.TrafficLogService) *httptest.Server {
+		t.Helper()
+
+		data := []byte(`{"message":"hello world"}`)
+		controller := NewMocksController(&mockResponseProvider{
+			response: &mock.MockResponse{
+				StatusCode:  200,
+				ContentType: "application/json",
+				Data:        &data,
+				Fault:       fault,
+			},
+		}, trafficLogService, nil, nil, &config.AppArguments{})
+
+		router := gin.New()
+		router.NoRoute(controller.handleMockRequest)
+
+		server := httptest.NewServer(router)
+		t.Cleanup(server.Close)
+
+		return server
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{DisableKeepAlives: true},
+		Timeout:   2 * time.Second,
+	}
+
+	t.Run("connection reset", func(t *testing.T) {
+		server := newFaultServer(t, &mock.Fault{Type: config.FaultConnectionReset}, nil)
+
+		if response, err := client.Get(server.URL + "/api/test"); err == nil {
+			response.Body.Close()
+			t.Fatalf("expected a connection error, got status %d", response.StatusCode)
+		}
+	})
+
+	t.Run("hang for the given duration", func(t *testing.T) {
+		server := newFaultServer(t, &mock.Fault{Type: config.FaultHang, Duration: 100 * time.Millisecond}, nil)
+		startTime := time.Now()
+
+		response, err := client.Get(server.URL + "/api/test")
+
+		if err != nil {
+			t.Fatalf("expected the headers to be received, got %v", err)
+		}
+
+		defer response.Body.Close()
+
+		if response.StatusCode != 200 || response.ContentLength != 25 {
+			t.Errorf("unexpected headers: status %d, content length %d", response.StatusCode, response.ContentLength)
+		}
+
+		if _, err := io.ReadAll(response.Body); err != io.ErrUnexpectedEOF {
+			t.Errorf("expected an unexpected EOF, got %v", err)
+		}
+
+		if elapsed := time.Since(startTime); elapsed < 100*time.Millisecond {
+			t.Errorf("expected the connection to hang for 100ms, it lasted %v", elapsed)
+		}
+	})
+
+	t.Run("hang until the client gives up", func(t *testing.T) {
+		server := newFaultServer(t, &mock.Fault{Type: config.FaultHang}, nil)
+		impatientClient := &http.Client{
+			Transport: &http.Transport{DisableKeepAlives: true},
+			Timeout:   200 * time.Millisecond,
+		}
+
+		response, err := impatientClient.Get(server.URL + "/api/test")
+
+		if err != nil {
+			t.Fatalf("expected the headers to be received, got %v", err)
+		}
+
+		defer response.Body.Close()
+
+		if _, err := io.ReadAll(response.Body); err == nil || !strings.Contains(err.Error(), "Client.Timeout") {
+			t.Errorf("expected the client to time out, got %v", err)
+		}
+	})
+
+	t.Run("truncated body", func(t *testing.T) {
+		trafficLogService := newTestTrafficLogService(10)
+		server := newFaultServer(t, &mock.Fault{Type: config.FaultTruncatedBody}, trafficLogService)
+
+		response, err := client.Get(server.URL + "/api/test")
+
+		if err != nil {
+			t.Fatalf("expected the headers to be received, got %v", err)
+		}
+
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+
+		if err != io.ErrUnexpectedEOF {
+			t.Errorf("expected an unexpected EOF, got %v", err)
+		}
+
+		if string(body) != `{"message":"` {
+			t.Errorf("expected half of the body, got %s", body)
+		}
+
+		// the traffic log keeps what was actually sent, once the handler is done after closing the connection
+		for deadline := time.Now().Add(time.Second); trafficLogService.Size() == 0 && time.Now().Before(deadline); {
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		entries := trafficLogService.GetAll()
+
+		if len(entries) != 1 || entries[0].Response.Body != `{"message":"` {
+			t.Errorf("unexpected traffic entries %+v", entries)
+		}
+	})
+
+	t.Run("invalid content length", func(t *testing.T) {
+		server := newFaultServer(t, &mock.Fault{Type: config.FaultInvalidContentLength}, nil)
+
+		response, err := client.Get(server.URL + "/api/test")
+
+		if err == nil {
+			response.Body.Close()
+			t.Fatalf("expected a parsing error, got status %d", response.StatusCode)
+		}
+
+		if !strings.Contains(err.Error(), "Content-Length") {
+			t.Errorf("expected a Content-Length error, got %v", err)
+		}
+	})
+
+	t.Run("garbage", func(t *testing.T) {
+		server := newFaultServer(t, &mock.Fault{Type: config.FaultGarbage}, nil)
+
+		if response, err := client.Get(server.URL + "/api/test"); err == nil {
+			response.Body.Close()
+			t.Fatalf("expected a malformed response error, got status %d", response.StatusCode)
+		}
+	})
+
+	t.Run("serves the response as usual over HTTP/2", func(t *testing.T) {
+		data := []byte("hello")
+		controller := NewMocksController(&mockResponseProvider{
+			response: &mock.MockResponse{
+				StatusCode: 200,
+				Data:       &data,
+				Fault:      &mock.Fault{Type: config.FaultConnectionReset},
+			},
+		}, nil, nil, nil, &config.AppArguments{})
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+		req.Proto, req.ProtoMajor, req.ProtoMinor = "HTTP/2.0", 2, 0
+		c.Request = req
+		c.Set(util.UuidKey, "test-uuid")
+
+		controller.handleMockRequest(c)
+
+		if w.Code != 200 || w.Body.String() != "hello" {
+			t.Errorf("expected the response as usual, got %d %s", w.Code, w.Body.String())
+		}
+	})
+}
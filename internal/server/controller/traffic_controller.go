@@ -2,21 +2,30 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/Caik/go-mock-server/internal/config"
 	"github.com/Caik/go-mock-server/internal/rest"
+	"github.com/Caik/go-mock-server/internal/service/har"
 	"github.com/Caik/go-mock-server/internal/service/traffic"
 	"github.com/Caik/go-mock-server/internal/util"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 )
 
+// hostsConfigSSEEvent is the SSE event type of the hosts config reloads sent along the traffic stream
+const hostsConfigSSEEvent = "hosts-config"
+
 // TrafficController handles traffic log streaming and queries
 type TrafficController struct {
-	trafficLogService *traffic.TrafficLogService
+	trafficLogService  *traffic.TrafficLogService
+	hostsConfigWatcher *config.HostsConfigWatcher
 }
 
 // handleTrafficStream handles SSE streaming of traffic logs
@@ -55,6 +64,10 @@ func (t *TrafficController) handleTrafficStream(c *gin.Context) {
 	ch := t.trafficLogService.Subscribe(subscriberID, filters)
 	defer t.trafficLogService.Unsubscribe(subscriberID)
 
+	// Subscribe to hosts config reloads, so clients can tell when the rules changed
+	configCh := t.hostsConfigWatcher.Subscribe(subscriberID)
+	defer t.hostsConfigWatcher.Unsubscribe(subscriberID)
+
 	// Send catch-up entries first
 	catchUp := t.trafficLogService.GetFiltered(filters)
 
@@ -86,8 +99,153 @@ func (t *TrafficController) handleTrafficStream(c *gin.Context) {
 			if err := t.writeSSEEvent(c, entry, uuid); err != nil {
 				return
 			}
+		case event, ok := <-configCh:
+			if !ok {
+				configCh = nil
+				continue
+			}
+			if err := t.writeSSENamedEvent(c, hostsConfigSSEEvent, event, uuid); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handleTrafficEntry returns a single traffic log entry, with its headers and bodies
+func (t *TrafficController) handleTrafficEntry(c *gin.Context) {
+	if t.trafficLogService == nil {
+		c.JSON(http.StatusServiceUnavailable, rest.Response{
+			Status:  rest.Fail,
+			Message: "traffic logging is disabled",
+		})
+		return
+	}
+
+	entryUUID := c.Param("uuid")
+
+	log.Info().
+		Str("uuid", c.GetString(util.UuidKey)).
+		Str("entry", entryUUID).
+		Msg("getting traffic entry")
+
+	entry, exists := t.trafficLogService.Get(entryUUID)
+
+	if !exists {
+		c.JSON(http.StatusNotFound, rest.Response{
+			Status:  rest.Fail,
+			Message: "traffic entry not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, rest.Response{
+		Status:  rest.Success,
+		Message: "traffic entry retrieved with success",
+		Data:    entry,
+	})
+}
+
+// handleTrafficEntries returns a page of the traffic log entries matching the filters, from the most recent to the
+// oldest. With a persistent traffic log, the entries go beyond the buffer and the last restart.
+func (t *TrafficController) handleTrafficEntries(c *gin.Context) {
+	uuid := c.GetString(util.UuidKey)
+
+	if t.trafficLogService == nil {
+		c.JSON(http.StatusServiceUnavailable, rest.Response{
+			Status:  rest.Fail,
+			Message: "traffic logging is disabled",
+		})
+		return
+	}
+
+	filters, err := t.parseFilters(c)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, rest.Response{
+			Status:  rest.Fail,
+			Message: fmt.Sprintf("invalid filter: %v", err),
+		})
+		return
+	}
+
+	limit := 0
+
+	if limitParam := c.Query("limit"); limitParam != "" {
+		limit, err = strconv.Atoi(limitParam)
+
+		if err != nil || limit <= 0 || limit > traffic.MaxQueryLimit {
+			c.JSON(http.StatusBadRequest, rest.Response{
+				Status:  rest.Fail,
+				Message: fmt.Sprintf("invalid limit: must be between 1 and %d", traffic.MaxQueryLimit),
+			})
+			return
 		}
 	}
+
+	page, err := t.trafficLogService.Query(traffic.TrafficQuery{
+		Filters: filters,
+		Before:  c.Query("before"),
+		Limit:   limit,
+	})
+
+	if errors.Is(err, traffic.ErrUnknownCursor) {
+		c.JSON(http.StatusBadRequest, rest.Response{
+			Status:  rest.Fail,
+			Message: "invalid before: the entry is not in the traffic log anymore",
+		})
+		return
+	}
+
+	if err != nil {
+		log.Err(err).
+			Str("uuid", uuid).
+			Msg("failed to query the traffic log")
+
+		c.JSON(http.StatusInternalServerError, rest.Response{
+			Status:  rest.Error,
+			Message: fmt.Sprintf("error while querying the traffic log: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, rest.Response{
+		Status:  rest.Success,
+		Message: "traffic entries retrieved with success",
+		Data:    page,
+	})
+}
+
+// handleTrafficHAR exports the traffic log entries matching the filters as a HAR file
+func (t *TrafficController) handleTrafficHAR(c *gin.Context) {
+	uuid := c.GetString(util.UuidKey)
+
+	if t.trafficLogService == nil {
+		c.JSON(http.StatusServiceUnavailable, rest.Response{
+			Status:  rest.Fail,
+			Message: "traffic logging is disabled",
+		})
+		return
+	}
+
+	filters, err := t.parseFilters(c)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, rest.Response{
+			Status:  rest.Fail,
+			Message: fmt.Sprintf("invalid filter: %v", err),
+		})
+		return
+	}
+
+	entries := t.trafficLogService.GetFiltered(filters)
+
+	log.Info().
+		Str("uuid", uuid).
+		Int("entries", len(entries)).
+		Msg("exporting traffic as HAR")
+
+	c.Header("Content-Disposition", `attachment; filename="traffic.har"`)
+	c.JSON(http.StatusOK, har.FromTraffic(entries, config.GetVersion()))
 }
 
 func (t *TrafficController) addSSEHeaders(c *gin.Context) {
@@ -103,9 +261,22 @@ func (t *TrafficController) parseFilters(c *gin.Context) (*traffic.TrafficFilter
 	hostsParam := c.Query("hosts")
 	statusParam := c.Query("status")
 	matchedParam := c.Query("matched")
+	methodsParam := c.Query("methods")
+	pathPrefixParam := c.Query("path_prefix")
+	pathParam := c.Query("path")
+	pathRegexParam := c.Query("path_regex")
+	queryParam := c.Query("query")
+	minLatencyParam := c.Query("min_latency")
+	maxLatencyParam := c.Query("max_latency")
+	sinceParam := c.Query("since")
+	untilParam := c.Query("until")
+	metadataParams := c.QueryArray("metadata")
+	textParam := c.Query("q")
 
 	// Return nil if no filters provided
-	if hostsParam == "" && statusParam == "" && matchedParam == "" {
+	if hostsParam == "" && statusParam == "" && matchedParam == "" && methodsParam == "" && pathPrefixParam == "" &&
+		pathParam == "" && pathRegexParam == "" && queryParam == "" && minLatencyParam == "" &&
+		maxLatencyParam == "" && sinceParam == "" && untilParam == "" && len(metadataParams) == 0 && textParam == "" {
 		return nil, nil
 	}
 
@@ -146,11 +317,85 @@ func (t *TrafficController) parseFilters(c *gin.Context) (*traffic.TrafficFilter
 		matched = &matchedBool
 	}
 
+	// Parse methods (comma-separated)
+	var methods []string
+
+	if methodsParam != "" {
+		methods = strings.Split(methodsParam, ",")
+	}
+
+	// Parse path regular expression
+	var pathRegex *regexp.Regexp
+
+	if pathRegexParam != "" {
+		compiled, err := regexp.Compile(pathRegexParam)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid path_regex value: %v", err)
+		}
+
+		pathRegex = compiled
+	}
+
+	// Parse latency range (durations such as 250ms)
+	minLatency, err := t.parseDuration("min_latency", minLatencyParam)
+
+	if err != nil {
+		return nil, err
+	}
+
+	maxLatency, err := t.parseDuration("max_latency", maxLatencyParam)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse time range (RFC 3339)
+	since, err := t.parseTime("since", sinceParam)
+
+	if err != nil {
+		return nil, err
+	}
+
+	until, err := t.parseTime("until", untilParam)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse metadata (repeated key=value)
+	var metadata map[string]string
+
+	for _, param := range metadataParams {
+		key, value, found := strings.Cut(param, "=")
+
+		if !found {
+			return nil, fmt.Errorf("invalid metadata value: %s, expected key=value", param)
+		}
+
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+
+		metadata[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
 	// Build and validate filters
 	filters := &traffic.TrafficFilters{
-		Hosts:       hosts,
-		StatusCodes: statusCodes,
-		Matched:     matched,
+		Hosts:         hosts,
+		StatusCodes:   statusCodes,
+		Matched:       matched,
+		Methods:       methods,
+		PathPrefix:    pathPrefixParam,
+		PathGlob:      pathParam,
+		PathRegex:     pathRegex,
+		QueryContains: queryParam,
+		MinLatency:    minLatency,
+		MaxLatency:    maxLatency,
+		Since:         since,
+		Until:         until,
+		Metadata:      metadata,
+		Text:          textParam,
 	}
 
 	if err := filters.Validate(); err != nil {
@@ -160,10 +405,46 @@ func (t *TrafficController) parseFilters(c *gin.Context) (*traffic.TrafficFilter
 	return filters, nil
 }
 
+// parseDuration parses a duration such as 250ms, returning nil when empty
+func (t *TrafficController) parseDuration(name, value string) (*time.Duration, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value: %s", name, value)
+	}
+
+	return &parsed, nil
+}
+
+// parseTime parses an RFC 3339 time, returning nil when empty
+func (t *TrafficController) parseTime(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value: %s", name, value)
+	}
+
+	return &parsed, nil
+}
+
 // writeSSEEvent marshals and writes a traffic entry as an SSE event.
 // Returns nil on marshal errors (continue streaming), error on write errors (stop streaming).
 func (t *TrafficController) writeSSEEvent(c *gin.Context, entry traffic.TrafficEntry, uuid string) error {
-	data, err := json.Marshal(entry)
+	return t.writeSSENamedEvent(c, "", entry, uuid)
+}
+
+// writeSSENamedEvent marshals and writes a value as an SSE event of the given type, the default message type when
+// empty. Clients only listening to messages ignore the other types.
+func (t *TrafficController) writeSSENamedEvent(c *gin.Context, eventType string, value any, uuid string) error {
+	data, err := json.Marshal(value)
 
 	if err != nil {
 		log.Warn().
@@ -175,6 +456,17 @@ func (t *TrafficController) writeSSEEvent(c *gin.Context, entry traffic.TrafficE
 		return nil
 	}
 
+	if len(eventType) > 0 {
+		if _, err := fmt.Fprintf(c.Writer, "event: %s\n", eventType); err != nil {
+			log.Warn().
+				Err(err).
+				Str("uuid", uuid).
+				Msg("failed to write SSE event")
+
+			return err
+		}
+	}
+
 	if _, err := fmt.Fprintf(c.Writer, "data: %s\n\n", data); err != nil {
 		log.Warn().
 			Err(err).
@@ -190,8 +482,9 @@ func (t *TrafficController) writeSSEEvent(c *gin.Context, entry traffic.TrafficE
 }
 
 // NewTrafficController creates a new TrafficController
-func NewTrafficController(trafficLogService *traffic.TrafficLogService) *TrafficController {
+func NewTrafficController(trafficLogService *traffic.TrafficLogService, hostsConfigWatcher *config.HostsConfigWatcher) *TrafficController {
 	return &TrafficController{
-		trafficLogService: trafficLogService,
+		trafficLogService:  trafficLogService,
+		hostsConfigWatcher: hostsConfigWatcher,
 	}
 }
@@ -4,13 +4,17 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/har"
 	"github.com/Caik/go-mock-server/internal/service/traffic"
 	"github.com/Caik/go-mock-server/internal/util"
 	"github.com/gin-gonic/gin"
@@ -18,14 +22,16 @@ import (
 
 func newTestTrafficService(bufferSize int) *traffic.TrafficLogService {
 	return traffic.NewTrafficLogService(&config.AppArguments{
-		TrafficLogBufferSize: bufferSize,
+		TrafficLogBufferSize:    bufferSize,
+		TrafficLogMaxBodySize:   1024,
+		TrafficLogRedactHeaders: "Authorization",
 	})
 }
 
 func TestNewTrafficController(t *testing.T) {
 	t.Run("creates controller with service", func(t *testing.T) {
 		service := newTestTrafficService(10)
-		controller := NewTrafficController(service)
+		controller := NewTrafficController(service, nil)
 
 		if controller == nil {
 			t.Fatal("expected non-nil controller")
@@ -33,7 +39,7 @@ func TestNewTrafficController(t *testing.T) {
 	})
 
 	t.Run("creates controller with nil service", func(t *testing.T) {
-		controller := NewTrafficController(nil)
+		controller := NewTrafficController(nil, nil)
 
 		if controller == nil {
 			t.Fatal("expected non-nil controller even with nil service")
@@ -45,7 +51,7 @@ func TestTrafficController_handleTrafficStream(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("returns 503 when traffic logging is disabled", func(t *testing.T) {
-		controller := NewTrafficController(nil)
+		controller := NewTrafficController(nil, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -61,7 +67,7 @@ func TestTrafficController_handleTrafficStream(t *testing.T) {
 
 	t.Run("returns 400 for invalid status code filter", func(t *testing.T) {
 		service := newTestTrafficService(10)
-		controller := NewTrafficController(service)
+		controller := NewTrafficController(service, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -77,7 +83,7 @@ func TestTrafficController_handleTrafficStream(t *testing.T) {
 
 	t.Run("returns 400 for invalid matched filter", func(t *testing.T) {
 		service := newTestTrafficService(10)
-		controller := NewTrafficController(service)
+		controller := NewTrafficController(service, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -93,7 +99,7 @@ func TestTrafficController_handleTrafficStream(t *testing.T) {
 
 	t.Run("returns 400 for invalid status code range", func(t *testing.T) {
 		service := newTestTrafficService(10)
-		controller := NewTrafficController(service)
+		controller := NewTrafficController(service, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -108,11 +114,272 @@ func TestTrafficController_handleTrafficStream(t *testing.T) {
 	})
 }
 
+func TestTrafficController_handleTrafficEntry(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(service *traffic.TrafficLogService) *gin.Engine {
+		router := gin.New()
+		initAdminTrafficController(router.Group("/api/v1/traffic"), NewTrafficController(service, nil))
+
+		return router
+	}
+
+	t.Run("returns 503 when traffic logging is disabled", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newRouter(nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/traffic/some-uuid", nil))
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected status 503, got %d", w.Code)
+		}
+	})
+
+	service := newTestTrafficService(10)
+	entry := traffic.NewTrafficEntry("entry-uuid")
+	entry.Request = traffic.TrafficRequest{Method: "POST", Host: "example.com", Path: "/users", Body: `{"name":"john"}`}
+	entry.Response = traffic.TrafficResponse{StatusCode: 201, Headers: map[string]string{"Content-Type": "application/json"}}
+	service.Capture(*entry)
+
+	router := newRouter(service)
+
+	t.Run("returns the entry with its bodies", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/traffic/entry-uuid", nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+
+		var response struct {
+			Data traffic.TrafficEntry `json:"data"`
+		}
+
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+
+		if response.Data.UUID != "entry-uuid" || response.Data.Request.Body != `{"name":"john"}` {
+			t.Errorf("unexpected entry %+v", response.Data)
+		}
+
+		if response.Data.Response.Headers["Content-Type"] != "application/json" {
+			t.Errorf("expected the response headers, got %v", response.Data.Response.Headers)
+		}
+	})
+
+	t.Run("returns 404 for an unknown entry", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/traffic/unknown", nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", w.Code)
+		}
+	})
+}
+
+func TestTrafficController_handleTrafficEntries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(service *traffic.TrafficLogService) *gin.Engine {
+		router := gin.New()
+		initAdminTrafficController(router.Group("/api/v1/traffic"), NewTrafficController(service, nil))
+
+		return router
+	}
+
+	t.Run("returns 503 when traffic logging is disabled", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newRouter(nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/traffic/entries", nil))
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected status 503, got %d", w.Code)
+		}
+	})
+
+	service := newTestTrafficService(10)
+
+	for i, method := range []string{"GET", "POST", "GET", "POST"} {
+		entry := traffic.NewTrafficEntry(fmt.Sprintf("entry-%d", i+1))
+		entry.Request = traffic.TrafficRequest{Method: method, Host: "example.com", Path: "/users"}
+		entry.Response = traffic.TrafficResponse{StatusCode: 200}
+		service.Capture(*entry)
+	}
+
+	router := newRouter(service)
+
+	getPage := func(t *testing.T, query string) traffic.TrafficPage {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/traffic/entries"+query, nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+
+		var response struct {
+			Data traffic.TrafficPage `json:"data"`
+		}
+
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+
+		return response.Data
+	}
+
+	t.Run("returns the entries matching the free text", func(t *testing.T) {
+		page := getPage(t, "?q=post")
+
+		if len(page.Entries) != 2 || page.Entries[0].UUID != "entry-4" || page.Entries[1].UUID != "entry-2" {
+			t.Errorf("unexpected page %+v", page)
+		}
+	})
+
+	t.Run("pages through the filtered entries", func(t *testing.T) {
+		page := getPage(t, "?methods=post&limit=1")
+
+		if len(page.Entries) != 1 || page.Entries[0].UUID != "entry-4" || page.Next != "entry-4" {
+			t.Fatalf("unexpected page %+v", page)
+		}
+
+		page = getPage(t, "?methods=post&limit=1&before="+page.Next)
+
+		if len(page.Entries) != 1 || page.Entries[0].UUID != "entry-2" || page.Next != "" {
+			t.Errorf("unexpected page %+v", page)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "invalid limit", query: "?limit=abc"},
+		{name: "zero limit", query: "?limit=0"},
+		{name: "limit above the maximum", query: "?limit=1001"},
+		{name: "unknown cursor", query: "?before=unknown"},
+		{name: "invalid since", query: "?since=yesterday"},
+	}
+
+	for _, tt := range tests {
+		t.Run("returns 400 for "+tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/traffic/entries"+tt.query, nil))
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestTrafficController_handleTrafficHAR(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newContext := func(query string) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/traffic/har"+query, nil)
+		c.Set(util.UuidKey, "test-uuid")
+
+		return c, w
+	}
+
+	t.Run("returns 503 when traffic logging is disabled", func(t *testing.T) {
+		controller := NewTrafficController(nil, nil)
+		c, w := newContext("")
+
+		controller.handleTrafficHAR(c)
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected status 503, got %d", w.Code)
+		}
+	})
+
+	t.Run("returns 400 for invalid filters", func(t *testing.T) {
+		controller := NewTrafficController(newTestTrafficService(10), nil)
+		c, w := newContext("?status=abc")
+
+		controller.handleTrafficHAR(c)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("exports the filtered entries", func(t *testing.T) {
+		service := newTestTrafficService(10)
+
+		for i, host := range []string{"api.example.com", "cdn.example.com"} {
+			entry := traffic.NewTrafficEntry(fmt.Sprintf("uuid-%d", i))
+			entry.Request = traffic.TrafficRequest{Method: "GET", Scheme: "https", Host: host, Path: "/users"}
+			entry.Response = traffic.TrafficResponse{StatusCode: 200, Body: `{"name": "john"}`, BodySize: 16}
+			service.Capture(*entry)
+		}
+
+		controller := NewTrafficController(service, nil)
+		c, w := newContext("?hosts=api.example.com")
+
+		controller.handleTrafficHAR(c)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+
+		if disposition := w.Header().Get("Content-Disposition"); disposition != `attachment; filename="traffic.har"` {
+			t.Errorf("unexpected Content-Disposition %q", disposition)
+		}
+
+		archive, err := har.Parse(w.Body.Bytes())
+
+		if err != nil {
+			t.Fatalf("expected a valid HAR file, got %v", err)
+		}
+
+		if len(archive.Log.Entries) != 1 {
+			t.Fatalf("expected 1 entry, got %d", len(archive.Log.Entries))
+		}
+
+		if entry := archive.Log.Entries[0]; entry.Request.URL != "https://api.example.com/users" || entry.Response.Content.Text != `{"name": "john"}` {
+			t.Errorf("unexpected entry %+v", entry)
+		}
+	})
+
+	t.Run("hides the values of the redacted headers", func(t *testing.T) {
+		service := newTestTrafficService(10)
+
+		entry := traffic.NewTrafficEntry("uuid-1")
+		entry.Request = traffic.TrafficRequest{Method: "GET", Host: "api.example.com", Path: "/users", Headers: map[string]string{"authorization": "Bearer secret", "Accept": "*/*"}}
+		entry.Response = traffic.TrafficResponse{StatusCode: 200}
+		service.Capture(*entry)
+
+		controller := NewTrafficController(service, nil)
+		c, w := newContext("")
+
+		controller.handleTrafficHAR(c)
+
+		if strings.Contains(w.Body.String(), "secret") {
+			t.Fatalf("expected the Authorization header to be redacted, got %s", w.Body.String())
+		}
+
+		archive, err := har.Parse(w.Body.Bytes())
+
+		if err != nil {
+			t.Fatalf("expected a valid HAR file, got %v", err)
+		}
+
+		for _, header := range archive.Log.Entries[0].Request.Headers {
+			if header.Name == "Accept" && header.Value != "*/*" {
+				t.Errorf("expected the other headers to be kept, got %+v", header)
+			}
+		}
+	})
+}
+
 func TestTrafficController_parseFilters(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("parses hosts filter", func(t *testing.T) {
-		controller := NewTrafficController(nil)
+		controller := NewTrafficController(nil, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -129,7 +396,7 @@ func TestTrafficController_parseFilters(t *testing.T) {
 	})
 
 	t.Run("parses status codes filter", func(t *testing.T) {
-		controller := NewTrafficController(nil)
+		controller := NewTrafficController(nil, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -146,11 +413,95 @@ func TestTrafficController_parseFilters(t *testing.T) {
 	})
 }
 
+func TestTrafficController_parseFilters_request(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("parses methods, path prefix and time range filters", func(t *testing.T) {
+		controller := NewTrafficController(nil, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/traffic?methods=GET,POST&path_prefix=/api&since=2024-01-01T00:00:00Z&until=2024-01-02T00:00:00Z", nil)
+
+		filters, err := controller.parseFilters(c)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(filters.Methods) != 2 || filters.PathPrefix != "/api" {
+			t.Errorf("unexpected filters %+v", filters)
+		}
+		if filters.Since == nil || filters.Until == nil || filters.Until.Sub(*filters.Since) != 24*time.Hour {
+			t.Errorf("unexpected time range %v - %v", filters.Since, filters.Until)
+		}
+	})
+
+	t.Run("parses path, query, latency, metadata and text filters", func(t *testing.T) {
+		controller := NewTrafficController(nil, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/traffic?path=/users/*&path_regex=^/users/[0-9]%2B$&query=page%3D2&min_latency=100ms&max_latency=2s&metadata=Simulated%20Status%3Dtrue&metadata=Matched%3Dtrue&q=john", nil)
+
+		filters, err := controller.parseFilters(c)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if filters.PathGlob != "/users/*" || filters.PathRegex == nil || filters.PathRegex.String() != "^/users/[0-9]+$" || filters.QueryContains != "page=2" {
+			t.Errorf("unexpected path and query filters %+v", filters)
+		}
+		if filters.MinLatency == nil || *filters.MinLatency != 100*time.Millisecond || filters.MaxLatency == nil || *filters.MaxLatency != 2*time.Second {
+			t.Errorf("unexpected latency range %v - %v", filters.MinLatency, filters.MaxLatency)
+		}
+		if len(filters.Metadata) != 2 || filters.Metadata["Simulated Status"] != "true" || filters.Text != "john" {
+			t.Errorf("unexpected metadata and text filters %+v", filters)
+		}
+	})
+
+	invalid := []struct {
+		name  string
+		query string
+	}{
+		{name: "path regex", query: "path_regex=("},
+		{name: "path glob", query: "path=/users/["},
+		{name: "latency", query: "min_latency=fast"},
+		{name: "latency range", query: "min_latency=2s&max_latency=1s"},
+		{name: "metadata", query: "metadata=Matched"},
+	}
+
+	for _, tt := range invalid {
+		t.Run("returns error for invalid "+tt.name, func(t *testing.T) {
+			controller := NewTrafficController(nil, nil)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/traffic?"+tt.query, nil)
+
+			if _, err := controller.parseFilters(c); err == nil {
+				t.Errorf("expected error for %s", tt.query)
+			}
+		})
+	}
+
+	t.Run("returns error for inverted time range", func(t *testing.T) {
+		controller := NewTrafficController(nil, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/traffic?since=2024-01-02T00:00:00Z&until=2024-01-01T00:00:00Z", nil)
+
+		if _, err := controller.parseFilters(c); err == nil {
+			t.Error("expected error for inverted time range")
+		}
+	})
+}
+
 func TestTrafficController_parseFilters_matched(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	t.Run("parses matched=true filter", func(t *testing.T) {
-		controller := NewTrafficController(nil)
+		controller := NewTrafficController(nil, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -167,7 +518,7 @@ func TestTrafficController_parseFilters_matched(t *testing.T) {
 	})
 
 	t.Run("parses matched=false filter", func(t *testing.T) {
-		controller := NewTrafficController(nil)
+		controller := NewTrafficController(nil, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -184,7 +535,7 @@ func TestTrafficController_parseFilters_matched(t *testing.T) {
 	})
 
 	t.Run("returns nil filters when no params", func(t *testing.T) {
-		controller := NewTrafficController(nil)
+		controller := NewTrafficController(nil, nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -206,7 +557,7 @@ func TestTrafficController_SSEStreaming(t *testing.T) {
 
 	t.Run("streams catch-up entries", func(t *testing.T) {
 		service := newTestTrafficService(10)
-		controller := NewTrafficController(service)
+		controller := NewTrafficController(service, nil)
 
 		// Add some entries before streaming
 		service.Capture(traffic.TrafficEntry{UUID: "entry-1", Request: traffic.TrafficRequest{Host: "test.com"}})
@@ -245,7 +596,7 @@ func TestTrafficController_SSEStreaming(t *testing.T) {
 
 	t.Run("sets SSE headers", func(t *testing.T) {
 		service := newTestTrafficService(10)
-		controller := NewTrafficController(service)
+		controller := NewTrafficController(service, nil)
 
 		router := gin.New()
 		router.GET("/api/v1/traffic", func(c *gin.Context) {
@@ -274,7 +625,7 @@ func TestTrafficController_SSEStreaming(t *testing.T) {
 
 	t.Run("filters catch-up entries", func(t *testing.T) {
 		service := newTestTrafficService(10)
-		controller := NewTrafficController(service)
+		controller := NewTrafficController(service, nil)
 
 		// Add entries with different hosts
 		service.Capture(traffic.TrafficEntry{UUID: "match", Request: traffic.TrafficRequest{Host: "example.com"}})
@@ -315,7 +666,7 @@ func TestTrafficController_SSEFormat(t *testing.T) {
 
 	t.Run("sends data in SSE format", func(t *testing.T) {
 		service := newTestTrafficService(10)
-		controller := NewTrafficController(service)
+		controller := NewTrafficController(service, nil)
 
 		service.Capture(traffic.TrafficEntry{UUID: "test-entry"})
 
@@ -361,3 +712,56 @@ func TestTrafficController_SSEFormat(t *testing.T) {
 		}
 	})
 }
+
+func TestTrafficController_SSEHostsConfigEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("sends hosts config reloads as named events", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "config.json")
+
+		if err := os.WriteFile(configFile, []byte(`{"hosts":{}}`), 0644); err != nil {
+			t.Fatalf("failed to write config file: %v", err)
+		}
+
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		watcher, err := config.NewHostsConfigWatcher(&config.AppArguments{MocksConfigFile: configFile}, hostsConfig)
+
+		if err != nil {
+			t.Fatalf("unexpected error starting watcher: %v", err)
+		}
+
+		controller := NewTrafficController(newTestTrafficService(10), watcher)
+
+		router := gin.New()
+		router.GET("/api/v1/traffic", func(c *gin.Context) {
+			c.Set(util.UuidKey, "test-uuid")
+			controller.handleTrafficStream(c)
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/traffic", nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		done := make(chan bool)
+		go func() {
+			router.ServeHTTP(w, req)
+			done <- true
+		}()
+
+		time.Sleep(50 * time.Millisecond)
+
+		if err := os.WriteFile(configFile, []byte(`{"hosts":{"example.com":{"latency":{"min":1,"max":2}}}}`), 0644); err != nil {
+			t.Fatalf("failed to update config file: %v", err)
+		}
+
+		time.Sleep(500 * time.Millisecond)
+		cancel()
+		<-done
+
+		result := w.Body.String()
+
+		if !strings.Contains(result, "event: hosts-config\ndata: {\"type\":\"reloaded\"") {
+			t.Errorf("expected a hosts config event, got %q", result)
+		}
+	})
+}
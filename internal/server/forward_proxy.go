@@ -0,0 +1,195 @@
+package server
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/Caik/go-mock-server/internal/service/certificate"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// tlsRecordTypeHandshake is the first byte sent by a client starting a TLS handshake
+const tlsRecordTypeHandshake = 0x16
+
+// forwardProxyHandler lets the mock port be used as an HTTP proxy. Proxy requests in absolute form are served as
+// usual, while CONNECT tunnels are terminated here, intercepting TLS with the certificates of the certificate
+// service, and the requests sent through them are served by the mock engine.
+type forwardProxyHandler struct {
+	next               http.Handler
+	certificateService *certificate.CertificateService
+}
+
+func (f *forwardProxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		f.next.ServeHTTP(w, r)
+		return
+	}
+
+	uuid := uuid.NewString()
+	hijacker, ok := w.(http.Hijacker)
+
+	if !ok {
+		http.Error(w, "CONNECT is only supported over HTTP/1.1", http.StatusHTTPVersionNotSupported)
+		return
+	}
+
+	conn, buffer, err := hijacker.Hijack()
+
+	if err != nil {
+		log.Err(err).
+			Stack().
+			Str("uuid", uuid).
+			Str("target", r.Host).
+			Msg("error while hijacking the CONNECT connection")
+
+		return
+	}
+
+	if _, err := conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		conn.Close()
+		return
+	}
+
+	log.Info().
+		Str("uuid", uuid).
+		Str("target", r.Host).
+		Msg("tunnel established")
+
+	f.serveTunnel(&bufferedConn{Conn: conn, reader: buffer.Reader}, r.Host, uuid)
+}
+
+// serveTunnel serves the requests sent through the tunnel, over TLS if that's what the client starts with
+func (f *forwardProxyHandler) serveTunnel(conn *bufferedConn, target, uuid string) {
+	targetHost, _, err := net.SplitHostPort(target)
+
+	if err != nil {
+		targetHost = target
+	}
+
+	var tunnelConn net.Conn = conn
+	firstByte, err := conn.reader.Peek(1)
+
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	if firstByte[0] == tlsRecordTypeHandshake {
+		if f.certificateService == nil {
+			log.Warn().
+				Str("uuid", uuid).
+				Str("target", target).
+				Msg("no certificate available to intercept the tunnel, closing it")
+
+			conn.Close()
+
+			return
+		}
+
+		tunnelConn = tls.Server(conn, f.tunnelTLSConfig(targetHost))
+	}
+
+	listener := newSingleConnListener(tunnelConn)
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the tunnel target is the host the client asked for, when the request doesn't tell it
+			if len(r.Host) == 0 {
+				r.Host = targetHost
+			}
+
+			f.next.ServeHTTP(w, r)
+		}),
+		ConnState: func(conn net.Conn, state http.ConnState) {
+			if state == http.StateClosed || state == http.StateHijacked {
+				listener.Close()
+			}
+		},
+	}
+
+	server.Serve(listener)
+
+	log.Info().
+		Str("uuid", uuid).
+		Str("target", target).
+		Msg("tunnel closed")
+}
+
+// tunnelTLSConfig returns the TLS config of the tunnel, signing a certificate for the tunnel target when the client
+// doesn't send SNI
+func (f *forwardProxyHandler) tunnelTLSConfig(targetHost string) *tls.Config {
+	tlsConfig := f.certificateService.TLSConfig()
+	tlsConfig.NextProtos = []string{"http/1.1"}
+	tlsConfig.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if len(hello.ServerName) == 0 {
+			hello.ServerName = targetHost
+		}
+
+		return f.certificateService.GetCertificate(hello)
+	}
+
+	return tlsConfig
+}
+
+func newForwardProxyHandler(next http.Handler, certificateService *certificate.CertificateService) http.Handler {
+	return &forwardProxyHandler{
+		next:               next,
+		certificateService: certificateService,
+	}
+}
+
+// bufferedConn is a connection whose first bytes were already read into a buffer
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (b *bufferedConn) Read(p []byte) (int, error) {
+	return b.reader.Read(p)
+}
+
+// singleConnListener is a listener accepting a single connection, so an http.Server can serve it
+type singleConnListener struct {
+	conn      net.Conn
+	connMu    sync.Mutex
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newSingleConnListener(conn net.Conn) *singleConnListener {
+	return &singleConnListener{
+		conn:   conn,
+		closed: make(chan struct{}),
+	}
+}
+
+func (s *singleConnListener) Accept() (net.Conn, error) {
+	s.connMu.Lock()
+	conn := s.conn
+	s.conn = nil
+	s.connMu.Unlock()
+
+	if conn != nil {
+		return conn, nil
+	}
+
+	// waiting for the connection to be done with before telling the server there is nothing left to accept
+	<-s.closed
+
+	return nil, net.ErrClosed
+}
+
+func (s *singleConnListener) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+	})
+
+	return nil
+}
+
+func (s *singleConnListener) Addr() net.Addr {
+	return &net.TCPAddr{}
+}
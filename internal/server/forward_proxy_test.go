@@ -0,0 +1,149 @@
+package server
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/certificate"
+)
+
+// echoRequestHandler answers with what the mock engine would see of the request
+var echoRequestHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, "%s %s %s tls=%t", r.Method, r.Host, r.RequestURI, r.TLS != nil)
+})
+
+func newProxyClient(t *testing.T, proxyURL string, certificateService *certificate.CertificateService) *http.Client {
+	t.Helper()
+
+	parsedURL, err := url.Parse(proxyURL)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+
+	if caPEM, exists := certificateService.CACertificatePEM(); exists {
+		pool.AppendCertsFromPEM(caPEM)
+	}
+
+	return &http.Client{Transport: &http.Transport{
+		Proxy:           http.ProxyURL(parsedURL),
+		TLSClientConfig: &tls.Config{RootCAs: pool},
+	}}
+}
+
+func readBody(t *testing.T, response *http.Response) string {
+	t.Helper()
+
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return string(body)
+}
+
+func TestForwardProxyHandler(t *testing.T) {
+	certificateService, err := certificate.NewCertificateService(&config.AppArguments{ForwardProxy: true})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	proxy := httptest.NewServer(newForwardProxyHandler(echoRequestHandler, certificateService))
+	defer proxy.Close()
+
+	client := newProxyClient(t, proxy.URL, certificateService)
+
+	t.Run("serves proxy requests in absolute form", func(t *testing.T) {
+		response, err := client.Get("http://api.partner.com/users?page=2")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if body := readBody(t, response); body != "GET api.partner.com http://api.partner.com/users?page=2 tls=false" {
+			t.Errorf("unexpected request: %s", body)
+		}
+	})
+
+	t.Run("intercepts TLS through CONNECT tunnels", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			response, err := client.Get("https://api.partner.com/users?page=2")
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if body := readBody(t, response); body != "GET api.partner.com /users?page=2 tls=true" {
+				t.Errorf("unexpected request: %s", body)
+			}
+		}
+
+		response, err := client.Post("https://other.partner.com:8443/orders", "application/json", strings.NewReader("{}"))
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if body := readBody(t, response); body != "POST other.partner.com:8443 /orders tls=true" {
+			t.Errorf("unexpected request: %s", body)
+		}
+	})
+
+	t.Run("serves plain HTTP through CONNECT tunnels", func(t *testing.T) {
+		conn, err := net.Dial("tcp", strings.TrimPrefix(proxy.URL, "http://"))
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		fmt.Fprint(conn, "CONNECT api.partner.com:80 HTTP/1.1\r\nHost: api.partner.com:80\r\n\r\n")
+
+		response, err := http.ReadResponse(reader, nil)
+
+		if err != nil || response.StatusCode != http.StatusOK {
+			t.Fatalf("expected the tunnel to be established, got %v", err)
+		}
+
+		fmt.Fprint(conn, "GET /users HTTP/1.1\r\nHost: api.partner.com\r\n\r\n")
+
+		response, err = http.ReadResponse(reader, nil)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if body := readBody(t, response); body != "GET api.partner.com /users tls=false" {
+			t.Errorf("unexpected request: %s", body)
+		}
+	})
+}
+
+func TestForwardProxyHandler_withoutCertificate(t *testing.T) {
+	proxy := httptest.NewServer(newForwardProxyHandler(echoRequestHandler, nil))
+	defer proxy.Close()
+
+	client := newProxyClient(t, proxy.URL, nil)
+
+	if _, err := client.Get("https://api.partner.com/users"); err == nil {
+		t.Error("expected the tunnel to be closed without certificate to intercept TLS")
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Caik/go-mock-server/internal/rest"
+	"github.com/Caik/go-mock-server/internal/service/auth"
+	"github.com/Caik/go-mock-server/internal/util"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// Authenticate rejects the admin requests without valid credentials, and keeps the identity of the caller in the
+// context. Every request goes through when authentication is disabled.
+func Authenticate(authService *auth.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if authService == nil {
+			c.Next()
+			return
+		}
+
+		identity := authService.Authenticate(c.Request)
+
+		if identity == nil {
+			log.Warn().
+				Str("uuid", c.GetString(util.UuidKey)).
+				Msg("admin request without valid credentials")
+
+			c.Header("WWW-Authenticate", authService.Challenge())
+			c.AbortWithStatusJSON(http.StatusUnauthorized, rest.Response{
+				Status:  rest.Fail,
+				Message: "authentication required",
+			})
+
+			return
+		}
+
+		c.Set(util.IdentityKey, identity)
+		c.Next()
+	}
+}
+
+// RequireReadWrite rejects the requests of the callers without the read-write role. It must come after
+// Authenticate, and lets every request through when authentication is disabled.
+func RequireReadWrite(authService *auth.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if authService == nil {
+			c.Next()
+			return
+		}
+
+		identity, _ := c.Get(util.IdentityKey)
+
+		if caller, _ := identity.(*auth.Identity); !caller.CanWrite() {
+			log.Warn().
+				Str("uuid", c.GetString(util.UuidKey)).
+				Msg("admin request without the read-write role")
+
+			c.AbortWithStatusJSON(http.StatusForbidden, rest.Response{
+				Status:  rest.Fail,
+				Message: "the read-write role is required",
+			})
+
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/auth"
+	"github.com/Caik/go-mock-server/internal/util"
+	"github.com/gin-gonic/gin"
+)
+
+func newTestAuthService(t *testing.T) *auth.AuthService {
+	t.Helper()
+
+	tokensFile := filepath.Join(t.TempDir(), "tokens")
+
+	if err := os.WriteFile(tokensFile, []byte("ci:ci-secret\nviewer:viewer-secret\n"), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	authService, err := auth.NewAuthService(&config.AppArguments{AdminTokensFile: tokensFile, AdminWriters: "ci"})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return authService
+}
+
+func newAuthRouter(authService *auth.AuthService) *gin.Engine {
+	router := gin.New()
+	router.Use(Authenticate(authService))
+
+	router.GET("/read", func(c *gin.Context) {
+		identity, _ := c.Get(util.IdentityKey)
+
+		if caller, ok := identity.(*auth.Identity); ok {
+			c.String(http.StatusOK, caller.Name)
+			return
+		}
+
+		c.String(http.StatusOK, "anonymous")
+	})
+
+	router.POST("/write", RequireReadWrite(authService), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	return router
+}
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthRouter(newTestAuthService(t))
+
+	t.Run("rejects the requests without credentials", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/read", nil))
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected status 401, got %d", w.Code)
+		}
+
+		if w.Header().Get("WWW-Authenticate") == "" {
+			t.Error("expected a WWW-Authenticate header")
+		}
+	})
+
+	t.Run("keeps the identity of the caller", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/read", nil)
+		req.Header.Set("Authorization", "Bearer viewer-secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK || w.Body.String() != "viewer" {
+			t.Errorf("expected status 200 for viewer, got %d %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("lets every request through when disabled", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newAuthRouter(nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/read", nil))
+
+		if w.Code != http.StatusOK || w.Body.String() != "anonymous" {
+			t.Errorf("expected status 200 for anonymous, got %d %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestRequireReadWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newAuthRouter(newTestAuthService(t))
+
+	tests := []struct {
+		token    string
+		expected int
+	}{
+		{"ci-secret", http.StatusOK},
+		{"viewer-secret", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/write", nil)
+		req.Header.Set("Authorization", "Bearer "+tt.token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.expected {
+			t.Errorf("%s: expected status %d, got %d", tt.token, tt.expected, w.Code)
+		}
+	}
+
+	t.Run("lets every request through when disabled", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newAuthRouter(nil).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/write", nil))
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
+}
@@ -2,18 +2,22 @@ package middleware
 
 import (
 	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/Caik/go-mock-server/internal/service/auth"
 	"github.com/Caik/go-mock-server/internal/util"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 )
 
 func Logger(ctx *gin.Context) {
+	uri := loggedURI(ctx.Request)
+
 	log.Info().
 		Str("uuid", ctx.GetString(util.UuidKey)).
 		Str("host", ctx.Request.Host).
-		Str("uri", ctx.Request.RequestURI).
+		Str("uri", uri).
 		Str("method", ctx.Request.Method).
 		Msg("request received")
 
@@ -26,9 +30,22 @@ func Logger(ctx *gin.Context) {
 	log.Info().
 		Str("uuid", ctx.GetString(util.UuidKey)).
 		Str("host", ctx.Request.Host).
-		Str("uri", ctx.Request.RequestURI).
+		Str("uri", uri).
 		Str("method", ctx.Request.Method).
 		Int("status_code", ctx.Writer.Status()).
 		Str("latency", fmt.Sprintf("%v", end.Sub(start))).
 		Msg("request finished")
 }
+
+// loggedURI returns the uri of the request, hiding the access token sent by the admin UI as a query parameter
+func loggedURI(request *http.Request) string {
+	query := request.URL.Query()
+
+	if !query.Has(auth.AccessTokenParam) {
+		return request.RequestURI
+	}
+
+	query.Set(auth.AccessTokenParam, "[REDACTED]")
+
+	return request.URL.Path + "?" + query.Encode()
+}
@@ -300,3 +300,28 @@ func TestLogger(t *testing.T) {
 		log.Logger = zerolog.New(os.Stderr).With().Timestamp().Logger()
 	})
 }
+
+func TestLogger_accessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	log.Logger = zerolog.New(&buf)
+	defer func() { log.Logger = zerolog.New(os.Stderr) }()
+
+	router := gin.New()
+	router.Use(Logger)
+	router.GET("/api/v1/traffic", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/traffic?access_token=secret-token&methods=GET", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if strings.Contains(buf.String(), "secret-token") {
+		t.Errorf("expected the access token to be hidden, got %s", buf.String())
+	}
+
+	if !strings.Contains(buf.String(), "methods=GET") {
+		t.Errorf("expected the rest of the query to be logged, got %s", buf.String())
+	}
+}
@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net/http"
@@ -12,6 +14,11 @@ import (
 
 	"github.com/Caik/go-mock-server/internal/config"
 	"github.com/Caik/go-mock-server/internal/server/middleware"
+	"github.com/Caik/go-mock-server/internal/service/audit"
+	"github.com/Caik/go-mock-server/internal/service/auth"
+	"github.com/Caik/go-mock-server/internal/service/certificate"
+	"github.com/Caik/go-mock-server/internal/service/tracing"
+	"github.com/Caik/go-mock-server/internal/service/traffic"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 )
@@ -29,7 +36,18 @@ type StartServerParams struct {
 	AdminMocksController *controller.AdminMocksController
 	AdminHostsController *controller.AdminHostsController
 	TrafficController    *controller.TrafficController
+	RequestsController   *controller.RequestsController
+	ScenariosController  *controller.ScenariosController
+	CacheController      *controller.CacheController
+	TLSController        *controller.TLSController
+	MetricsController    *controller.MetricsController
+	AuditController      *controller.AuditController
 	MocksController      *controller.MocksController
+	CertificateService   *certificate.CertificateService
+	TracingService       *tracing.TracingService
+	AuthService          *auth.AuthService
+	AuditService         *audit.AuditService
+	TrafficLogService    *traffic.TrafficLogService
 }
 
 var once sync.Once
@@ -74,13 +92,31 @@ func StartServers(params StartServerParams) error {
 	controller.InitMockRoutes(params.Servers.MockEngine, params.MocksController)
 
 	// Initialize admin routes on admin engine
-	controller.InitAdminRoutes(params.Servers.AdminEngine, params.AdminMocksController, params.AdminHostsController, params.TrafficController)
+	controller.InitAdminRoutes(params.Servers.AdminEngine, params.AuthService, params.AdminMocksController, params.AdminHostsController, params.TrafficController, params.RequestsController, params.ScenariosController, params.CacheController, params.TLSController, params.MetricsController, params.AuditController)
 
 	// Initialize UI routes if a UI directory is configured
 	if params.AppArguments.UIDirectory != "" {
 		controller.InitUIRoutes(params.Servers.AdminEngine, params.AppArguments.UIDirectory)
 	}
 
+	// exporting the spans not exported yet and writing the traffic entries still queued once the servers stop
+	defer func() {
+		if err := params.TracingService.Shutdown(context.Background()); err != nil {
+			log.Err(err).
+				Msg("error while shutting down tracing")
+		}
+
+		if err := params.AuditService.Close(); err != nil {
+			log.Err(err).
+				Msg("error while closing the audit log file")
+		}
+
+		if err := params.TrafficLogService.Close(); err != nil {
+			log.Err(err).
+				Msg("error while closing the traffic log store")
+		}
+	}()
+
 	// Channel to capture errors from goroutines
 	errChan := make(chan error, 2)
 
@@ -92,6 +128,7 @@ func StartServers(params StartServerParams) error {
 	if params.AppArguments.AdminPort > 0 {
 		go func() {
 			log.Info().
+				Bool("tls", params.AppArguments.AdminTLS).
 				Msgf("starting admin server on port %d", params.AppArguments.AdminPort)
 
 			server := &http.Server{
@@ -110,7 +147,7 @@ func StartServers(params StartServerParams) error {
 				}
 			}()
 
-			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			if err := listenAndServe(server, params.AppArguments.AdminTLS, params.CertificateService, params.AuthService.ClientCAs()); err != nil && !errors.Is(err, http.ErrServerClosed) {
 				errChan <- fmt.Errorf("admin server error: %w", err)
 				cancel()
 			}
@@ -119,11 +156,19 @@ func StartServers(params StartServerParams) error {
 
 	// Start mock server
 	log.Info().
+		Bool("tls", params.AppArguments.TLS).
+		Bool("forward_proxy", params.AppArguments.ForwardProxy).
 		Msgf("starting mock server on port %d", params.AppArguments.ServerPort)
 
+	var handler http.Handler = params.Servers.MockEngine
+
+	if params.AppArguments.ForwardProxy {
+		handler = newForwardProxyHandler(handler, params.CertificateService)
+	}
+
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", params.AppArguments.ServerPort),
-		Handler: params.Servers.MockEngine,
+		Handler: handler,
 	}
 
 	go func() {
@@ -137,7 +182,7 @@ func StartServers(params StartServerParams) error {
 		}
 	}()
 
-	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+	if err := listenAndServe(server, params.AppArguments.TLS, params.CertificateService, nil); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("mock server error: %w", err)
 	}
 
@@ -149,3 +194,25 @@ func StartServers(params StartServerParams) error {
 		return nil
 	}
 }
+
+// listenAndServe starts the server, over TLS with the certificates of the certificate service if enabled. When
+// clientCAs is set, the client certificates signed by them are verified, while the clients without one still connect.
+func listenAndServe(server *http.Server, enableTLS bool, certificateService *certificate.CertificateService, clientCAs *x509.CertPool) error {
+	if !enableTLS {
+		return server.ListenAndServe()
+	}
+
+	if certificateService == nil {
+		return errors.New("TLS is enabled but no certificate is available")
+	}
+
+	server.TLSConfig = certificateService.TLSConfig()
+
+	if clientCAs != nil {
+		server.TLSConfig.ClientCAs = clientCAs
+		server.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	// the certificates come from the TLS config
+	return server.ListenAndServeTLS("", "")
+}
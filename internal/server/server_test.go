@@ -2,8 +2,15 @@ package server
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
 	"io"
+	"math/big"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -13,7 +20,11 @@ import (
 	"github.com/Caik/go-mock-server/internal/config"
 	"github.com/Caik/go-mock-server/internal/server/controller"
 	"github.com/Caik/go-mock-server/internal/service/admin"
+	"github.com/Caik/go-mock-server/internal/service/cache"
+	"github.com/Caik/go-mock-server/internal/service/certificate"
 	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/matcher"
+	"github.com/Caik/go-mock-server/internal/service/scenario"
 	"github.com/Caik/go-mock-server/internal/util"
 	"github.com/gin-gonic/gin"
 )
@@ -25,10 +36,18 @@ func (m *mockContentService) GetContent(host, uri, method, uuid string, statusCo
 	return nil, nil
 }
 
+func (m *mockContentService) MatchContent(host, uri, method, uuid string, statusCode int, request matcher.Request) (*content.ContentResult, error) {
+	return m.GetContent(host, uri, method, uuid, statusCode)
+}
+
 func (m *mockContentService) SetContent(host, uri, method, uuid string, statusCode int, data *[]byte) error {
 	return nil
 }
 
+func (m *mockContentService) SetContentMeta(host, uri, method, uuid string, statusCode int, meta content.ContentMeta) error {
+	return nil
+}
+
 func (m *mockContentService) DeleteContent(host, uri, method, uuid string, statusCode int) error {
 	return nil
 }
@@ -48,6 +67,10 @@ func (m *mockContentService) Subscribe(subscriberId string, eventTypes ...conten
 func (m *mockContentService) Unsubscribe(subscriberId string) {
 }
 
+func (m *mockContentService) SubscriberCount() int {
+	return 0
+}
+
 // Ensure mockContentService implements content.ContentService
 var _ content.ContentService = (*mockContentService)(nil)
 
@@ -226,12 +249,18 @@ func TestStartServer(t *testing.T) {
 		// Create admin controllers
 		adminMocksController := &controller.AdminMocksController{}
 		adminHostsController := &controller.AdminHostsController{}
-		trafficController := controller.NewTrafficController(nil)
+		trafficController := controller.NewTrafficController(nil, nil)
+		requestsController := controller.NewRequestsController(nil)
+		scenariosController := controller.NewScenariosController(scenario.NewScenarioService())
+		cacheController := controller.NewCacheController(cache.NewInMemoryCacheService(&config.AppArguments{}))
+		tlsController := controller.NewTLSController(nil)
+		metricsController := controller.NewMetricsController(nil)
+		auditController := controller.NewAuditController(nil)
 
 		servers := NewServers()
 
 		// Initialize admin routes
-		controller.InitAdminRoutes(servers.AdminEngine, adminMocksController, adminHostsController, trafficController)
+		controller.InitAdminRoutes(servers.AdminEngine, nil, adminMocksController, adminHostsController, trafficController, requestsController, scenariosController, cacheController, tlsController, metricsController, auditController)
 
 		// Test that admin routes are accessible (they should return some response, even if it's an error)
 		testRoutes := []struct {
@@ -239,6 +268,7 @@ func TestStartServer(t *testing.T) {
 			path   string
 		}{
 			{http.MethodGet, "/health"},
+			{http.MethodGet, "/metrics"},
 			{http.MethodGet, "/api/v1/config/hosts"},
 			{http.MethodPost, "/api/v1/mocks"},
 			{http.MethodDelete, "/api/v1/mocks"},
@@ -264,9 +294,15 @@ func TestStartServer(t *testing.T) {
 		servers := NewServers()
 		adminMocksController := &controller.AdminMocksController{}
 		adminHostsController := &controller.AdminHostsController{}
-		trafficController := controller.NewTrafficController(nil)
+		trafficController := controller.NewTrafficController(nil, nil)
+		requestsController := controller.NewRequestsController(nil)
+		scenariosController := controller.NewScenariosController(scenario.NewScenarioService())
+		cacheController := controller.NewCacheController(cache.NewInMemoryCacheService(&config.AppArguments{}))
+		tlsController := controller.NewTLSController(nil)
+		metricsController := controller.NewMetricsController(nil)
+		auditController := controller.NewAuditController(nil)
 
-		controller.InitAdminRoutes(servers.AdminEngine, adminMocksController, adminHostsController, trafficController)
+		controller.InitAdminRoutes(servers.AdminEngine, nil, adminMocksController, adminHostsController, trafficController, requestsController, scenariosController, cacheController, tlsController, metricsController, auditController)
 
 		req := httptest.NewRequest(http.MethodGet, "/health", nil)
 		w := httptest.NewRecorder()
@@ -376,17 +412,23 @@ func TestStartServersIntegration(t *testing.T) {
 		}
 
 		servers := NewServers()
-		hostsConfig := &config.HostsConfig{}
+		hostsConfig := config.NewHostsConfigFrom(nil)
 		contentSvc := &mockContentService{}
 
 		// Use nil for MocksController to avoid complex dependency chain
 		// We'll just test that admin routes work
-		adminMocksController := controller.NewAdminMocksController(admin.NewMockAdminService(contentSvc))
-		adminHostsController := controller.NewAdminHostsController(hostsConfig, admin.NewHostsConfigAdminService(hostsConfig))
-		trafficController := controller.NewTrafficController(nil)
+		adminMocksController := controller.NewAdminMocksController(admin.NewMockAdminService(contentSvc, nil))
+		adminHostsController := controller.NewAdminHostsController(hostsConfig, admin.NewHostsConfigAdminService(hostsConfig, nil, nil))
+		trafficController := controller.NewTrafficController(nil, nil)
+		requestsController := controller.NewRequestsController(nil)
+		scenariosController := controller.NewScenariosController(scenario.NewScenarioService())
+		cacheController := controller.NewCacheController(cache.NewInMemoryCacheService(&config.AppArguments{}))
+		tlsController := controller.NewTLSController(nil)
+		metricsController := controller.NewMetricsController(nil)
+		auditController := controller.NewAuditController(nil)
 
 		// Initialize admin routes manually for testing
-		controller.InitAdminRoutes(servers.AdminEngine, adminMocksController, adminHostsController, trafficController)
+		controller.InitAdminRoutes(servers.AdminEngine, nil, adminMocksController, adminHostsController, trafficController, requestsController, scenariosController, cacheController, tlsController, metricsController, auditController)
 
 		// Add a simple mock route for testing
 		servers.MockEngine.GET("/test", func(c *gin.Context) {
@@ -474,11 +516,11 @@ func TestStartServersIntegration(t *testing.T) {
 
 	t.Run("StartServerParams struct is properly configured", func(t *testing.T) {
 		servers := NewServers()
-		hostsConfig := &config.HostsConfig{}
+		hostsConfig := config.NewHostsConfigFrom(nil)
 		contentSvc := &mockContentService{}
 
-		adminMocksController := controller.NewAdminMocksController(admin.NewMockAdminService(contentSvc))
-		adminHostsController := controller.NewAdminHostsController(hostsConfig, admin.NewHostsConfigAdminService(hostsConfig))
+		adminMocksController := controller.NewAdminMocksController(admin.NewMockAdminService(contentSvc, nil))
+		adminHostsController := controller.NewAdminHostsController(hostsConfig, admin.NewHostsConfigAdminService(hostsConfig, nil, nil))
 
 		params := StartServerParams{
 			Servers: servers,
@@ -522,3 +564,198 @@ func TestStartServersIntegration(t *testing.T) {
 		}
 	})
 }
+
+func TestListenAndServe(t *testing.T) {
+	t.Run("fails when TLS is enabled without certificate", func(t *testing.T) {
+		server := &http.Server{Addr: "127.0.0.1:0"}
+
+		if err := listenAndServe(server, true, nil, nil); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("serves over TLS with the certificate service", func(t *testing.T) {
+		certificateService, err := certificate.NewCertificateService(&config.AppArguments{TLS: true})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		addr := listener.Addr().String()
+		listener.Close()
+
+		server := &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, r.Host)
+		})}
+
+		go listenAndServe(server, true, certificateService, nil)
+		defer server.Close()
+
+		caPEM, _ := certificateService.CACertificatePEM()
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(caPEM)
+
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool, ServerName: "api.partner.com"},
+		}}
+
+		var response *http.Response
+
+		// waiting for the server to be listening
+		for i := 0; i < 50; i++ {
+			if response, err = client.Get("https://" + addr); err == nil {
+				break
+			}
+
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		defer response.Body.Close()
+
+		if response.TLS == nil || response.TLS.PeerCertificates[0].DNSNames[0] != "api.partner.com" {
+			t.Error("expected a certificate signed for api.partner.com")
+		}
+	})
+	t.Run("verifies the client certificates signed by the client CAs", func(t *testing.T) {
+		certificateService, err := certificate.NewCertificateService(&config.AppArguments{AdminTLS: true})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		clientCA, clientCAKey := newTestCA(t)
+		clientCAs := x509.NewCertPool()
+		clientCAs.AddCert(clientCA)
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		addr := listener.Addr().String()
+		listener.Close()
+
+		server := &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(r.TLS.VerifiedChains) == 0 {
+				io.WriteString(w, "anonymous")
+				return
+			}
+
+			io.WriteString(w, r.TLS.VerifiedChains[0][0].Subject.CommonName)
+		})}
+
+		go listenAndServe(server, true, certificateService, clientCAs)
+		defer server.Close()
+
+		caPEM, _ := certificateService.CACertificatePEM()
+		rootCAs := x509.NewCertPool()
+		rootCAs.AppendCertsFromPEM(caPEM)
+
+		get := func(certificates []tls.Certificate) string {
+			client := &http.Client{Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: rootCAs, ServerName: "localhost", Certificates: certificates},
+			}}
+
+			var response *http.Response
+
+			// waiting for the server to be listening
+			for i := 0; i < 50; i++ {
+				if response, err = client.Get("https://" + addr); err == nil {
+					break
+				}
+
+				time.Sleep(20 * time.Millisecond)
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			defer response.Body.Close()
+			body, _ := io.ReadAll(response.Body)
+
+			return string(body)
+		}
+
+		if name := get([]tls.Certificate{newTestClientCertificate(t, clientCA, clientCAKey, "ci")}); name != "ci" {
+			t.Errorf("expected the client certificate of ci to be verified, got %s", name)
+		}
+
+		// the clients without a certificate still connect, to authenticate otherwise
+		if name := get(nil); name != "anonymous" {
+			t.Errorf("expected no verified certificate, got %s", name)
+		}
+	})
+}
+
+func newTestCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "client CA"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ca, err := x509.ParseCertificate(der)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return ca, key
+}
+
+func newTestClientCertificate(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, commonName string) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
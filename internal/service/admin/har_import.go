@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"fmt"
+
+	"github.com/Caik/go-mock-server/internal/service/audit"
+	"github.com/Caik/go-mock-server/internal/service/har"
+	"github.com/rs/zerolog/log"
+)
+
+// HARImportResult lists the mocks generated from a HAR file, and the entries skipped
+type HARImportResult struct {
+	Mocks   []MockListItem     `json:"mocks"`
+	Skipped []har.SkippedEntry `json:"skipped"`
+}
+
+// ImportHAR generates a mock for every response recorded in a HAR file, under the host each request was sent to.
+// When hosts are given, only the requests sent to them are imported.
+func (m *MockAdminService) ImportHAR(data []byte, hosts []string, caller audit.Caller) (*HARImportResult, error) {
+	archive, err := har.Parse(data)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+
+	mocks, skipped := archive.Mocks(hosts)
+	result := &HARImportResult{
+		Mocks:   make([]MockListItem, 0, len(mocks)),
+		Skipped: skipped,
+	}
+
+	m.changeMu.Lock()
+	defer m.changeMu.Unlock()
+
+	var audited importAudit
+	var writeErr error
+
+	for _, mock := range mocks {
+		if err := m.importMock(&audited, mock.Host, mock.URI, mock.Method, caller.UUID, mock.StatusCode, mock.Data, contentMetaOf(mock.ContentType, mock.Headers)); err != nil {
+			// a mock that can't be written doesn't stop the import halfway, it's reported along with the entries skipped
+			writeErr = fmt.Errorf("error while creating mock %s %s%s %d: %v", mock.Method, mock.Host, mock.URI, mock.StatusCode, err)
+			result.Skipped = append(result.Skipped, har.SkippedEntry{
+				Method: mock.Method,
+				URL:    mock.URL,
+				Status: mock.StatusCode,
+				Reason: fmt.Sprintf("error while creating the mock: %v", err),
+			})
+
+			continue
+		}
+
+		result.Mocks = append(result.Mocks, MockListItem{
+			ID:         generateMockID(mock.Host, mock.URI, mock.Method, mock.StatusCode),
+			Host:       mock.Host,
+			URI:        mock.URI,
+			Method:     mock.Method,
+			StatusCode: mock.StatusCode,
+		})
+	}
+
+	// the import is audited as a whole, listing the mocks it wrote and the ones they replaced
+	if writeErr == nil || len(audited.after) > 0 {
+		m.auditService.Record(caller, audit.OperationHARImport, "", audited.before, audited.after)
+	}
+
+	// when no mock could be written at all, the mocks directory is what fails rather than the entries
+	if writeErr != nil && len(result.Mocks) == 0 {
+		return nil, writeErr
+	}
+
+	log.Info().
+		Str("uuid", caller.UUID).
+		Int("mocks", len(result.Mocks)).
+		Int("skipped", len(result.Skipped)).
+		Msg("HAR file imported")
+
+	return result, nil
+}
@@ -0,0 +1,202 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/audit"
+	"github.com/Caik/go-mock-server/internal/service/content"
+)
+
+const importHAR = `{
+  "log": {
+    "version": "1.2",
+    "creator": {"name": "browser", "version": "1"},
+    "entries": [
+      {
+        "request": {"method": "GET", "url": "https://api.example.com/users/1?fields=name"},
+        "response": {
+          "status": 200,
+          "headers": [{"name": "ETag", "value": "\"v1\""}],
+          "content": {"mimeType": "application/json", "text": "{\"name\": \"john\"}"}
+        }
+      },
+      {
+        "request": {"method": "GET", "url": "https://cdn.example.com/logo"},
+        "response": {"status": 200, "content": {"mimeType": "image/png", "text": "iVBORw==", "encoding": "base64"}}
+      },
+      {
+        "request": {"method": "GET", "url": "https://api.example.com/aborted"},
+        "response": {"status": 0, "content": {}}
+      }
+    ]
+  }
+}`
+
+// failingContentService fails to write any mock once the given number of mocks is written
+type failingContentService struct {
+	*mockContentService
+	writes int
+}
+
+func (f *failingContentService) SetContent(host, uri, method, uuid string, statusCode int, data *[]byte) error {
+	if len(f.contents) >= f.writes {
+		return errors.New("disk full")
+	}
+
+	return f.mockContentService.SetContent(host, uri, method, uuid, statusCode, data)
+}
+
+func TestMockAdminService_ImportHAR(t *testing.T) {
+	newService := func() (*MockAdminService, *mockContentService) {
+		contentService := &mockContentService{
+			contents: make(map[string][]byte),
+			events:   make(chan content.ContentEvent),
+		}
+
+		return NewMockAdminService(contentService, nil), contentService
+	}
+
+	t.Run("creates the mocks under the hosts of the requests", func(t *testing.T) {
+		service, contentService := newService()
+
+		result, err := service.ImportHAR([]byte(importHAR), nil, audit.Caller{UUID: "test-uuid"})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(result.Mocks) != 2 || len(result.Skipped) != 1 {
+			t.Fatalf("unexpected result %+v", result)
+		}
+
+		if result.Mocks[0].ID != generateMockID("api.example.com", "/users/1?fields=name", "GET", 200) {
+			t.Errorf("unexpected mock ID %s", result.Mocks[0].ID)
+		}
+
+		if data := contentService.contents["api.example.com:/users/1?fields=name:GET:200"]; string(data) != `{"name": "john"}` {
+			t.Errorf("expected the text mock to be written, got %q", data)
+		}
+
+		if data := contentService.contents["cdn.example.com:/logo:GET:200"]; string(data) != "\x89PNG" {
+			t.Errorf("expected the decoded binary mock to be written, got %q", data)
+		}
+
+		meta := contentService.metas["api.example.com:/users/1?fields=name:GET:200"]
+
+		if meta.Headers["Content-Type"] != "application/json" || meta.Headers["Etag"] != `"v1"` {
+			t.Errorf("expected the media type and headers to be written, got %+v", meta)
+		}
+
+		if meta := contentService.metas["cdn.example.com:/logo:GET:200"]; meta.Headers["Content-Type"] != "image/png" {
+			t.Errorf("expected the media type of the binary mock to be written, got %+v", meta)
+		}
+	})
+
+	t.Run("imports only the given hosts", func(t *testing.T) {
+		service, contentService := newService()
+
+		result, err := service.ImportHAR([]byte(importHAR), []string{"cdn.example.com"}, audit.Caller{UUID: "test-uuid"})
+
+		if err != nil || len(result.Mocks) != 1 || len(contentService.contents) != 1 {
+			t.Fatalf("unexpected result %+v, %v", result, err)
+		}
+
+		if result.Mocks[0].Host != "cdn.example.com" {
+			t.Errorf("unexpected mock %+v", result.Mocks[0])
+		}
+	})
+
+	t.Run("rejects invalid files", func(t *testing.T) {
+		service, _ := newService()
+
+		if _, err := service.ImportHAR([]byte("not a file"), nil, audit.Caller{UUID: "test-uuid"}); !errors.Is(err, ErrInvalidImport) {
+			t.Errorf("expected ErrInvalidImport, got %v", err)
+		}
+	})
+
+	t.Run("returns error when content service fails", func(t *testing.T) {
+		service, contentService := newService()
+		contentService.shouldError = true
+		contentService.errorMsg = "write error"
+
+		_, err := service.ImportHAR([]byte(importHAR), nil, audit.Caller{UUID: "test-uuid"})
+
+		if err == nil || errors.Is(err, ErrInvalidImport) {
+			t.Errorf("expected a write error, got %v", err)
+		}
+	})
+
+	t.Run("skips the mocks that can't be written and imports the others", func(t *testing.T) {
+		service, contentService := newService()
+		archive := `{"log": {"version": "1.2", "entries": [
+		  {"request": {"method": "GET", "url": "https://app.example.com/api/users"}, "response": {"status": 200, "content": {"text": "[]"}}},
+		  {"request": {"method": "GET", "url": "https://app.example.com/static/main.js"}, "response": {"status": 200, "content": {"text": "main()"}}},
+		  {"request": {"method": "GET", "url": "https://app.example.com/favicon.ico"}, "response": {"status": 200, "content": {}}},
+		  {"request": {"method": "GET", "url": "https://app.example.com/v1.0/status"}, "response": {"status": 200, "content": {}}},
+		  {"request": {"method": "GET", "url": "https://app.example.com/api/orders"}, "response": {"status": 200, "content": {"text": "[]"}}}
+		]}}`
+
+		result, err := service.ImportHAR([]byte(archive), nil, audit.Caller{UUID: "test-uuid"})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(result.Mocks) != 2 || result.Mocks[0].URI != "/api/users" || result.Mocks[1].URI != "/api/orders" || len(contentService.contents) != 2 {
+			t.Errorf("expected the api mocks to be imported, got %+v", result.Mocks)
+		}
+
+		wantSkipped := []string{"https://app.example.com/static/main.js", "https://app.example.com/favicon.ico", "https://app.example.com/v1.0/status"}
+
+		if len(result.Skipped) != len(wantSkipped) {
+			t.Fatalf("unexpected skipped entries %+v", result.Skipped)
+		}
+
+		for i, url := range wantSkipped {
+			if result.Skipped[i].URL != url || len(result.Skipped[i].Reason) == 0 {
+				t.Errorf("skipped %d: expected %s with a reason, got %+v", i, url, result.Skipped[i])
+			}
+		}
+	})
+
+	t.Run("records the mocks written before a failure", func(t *testing.T) {
+		auditService, err := audit.NewAuditService(&config.AppArguments{AuditLogBufferSize: 10})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, contentService := newService()
+		service := NewMockAdminService(&failingContentService{mockContentService: contentService, writes: 1}, auditService)
+
+		result, err := service.ImportHAR([]byte(importHAR), nil, audit.Caller{UUID: "test-uuid"})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// the mock that can't be written is skipped, along with the entry without response
+		if len(result.Mocks) != 1 || len(result.Skipped) != 2 || result.Skipped[1].URL != "https://cdn.example.com/logo" {
+			t.Fatalf("unexpected result %+v", result)
+		}
+
+		events := auditService.Query(audit.AuditFilters{}, 0)
+
+		if len(events) != 1 || events[0].Operation != audit.OperationHARImport {
+			t.Fatalf("unexpected events %+v", events)
+		}
+
+		var written []MockSnapshot
+
+		if err := json.Unmarshal(events[0].After, &written); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(written) != 1 || written[0].Host != "api.example.com" {
+			t.Errorf("expected the mock written to be recorded, got %+v", written)
+		}
+	})
+}
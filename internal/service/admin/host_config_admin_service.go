@@ -2,19 +2,31 @@ package admin
 
 import (
 	"fmt"
+	"path/filepath"
+	"sync"
 
 	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/audit"
+	"github.com/rs/zerolog/log"
 )
 
 type HostAddDeleteRequest struct {
-	Host          string
-	LatencyConfig *config.LatencyConfig
-	StatusConfig  map[string]config.StatusConfig
-	UriConfig     map[string]config.UriConfig
+	Host           string
+	LatencyConfig  *config.LatencyConfig
+	StatusConfig   map[string]config.StatusConfig
+	UriConfig      map[string]config.UriConfig
+	RecordConfig   *config.RecordConfig
+	ProxyConfig    *config.ProxyConfig
+	ContractConfig *config.ContractConfig
+	FaultsConfig   map[string]config.FaultConfig
 }
 
 type HostsConfigAdminService struct {
-	hostsConfig *config.HostsConfig
+	hostsConfig  *config.HostsConfig
+	auditService *audit.AuditService
+	configFile   string // where changes are written back to, empty when persisting is disabled
+	persistMu    sync.Mutex
+	changeMu     sync.Mutex
 }
 
 func (h *HostsConfigAdminService) GetHostsConfig() *config.HostsConfig {
@@ -25,27 +37,43 @@ func (h *HostsConfigAdminService) GetHostConfig(host string) *config.HostConfig
 	return h.hostsConfig.GetHostConfig(host)
 }
 
-func (h *HostsConfigAdminService) AddUpdateHost(addRequest HostAddDeleteRequest) (*config.HostConfig, error) {
+func (h *HostsConfigAdminService) AddUpdateHost(addRequest HostAddDeleteRequest, caller audit.Caller) (*config.HostConfig, error) {
 	hostConfig := config.HostConfig{
 		LatencyConfig:  addRequest.LatencyConfig,
 		StatusesConfig: addRequest.StatusConfig,
 		UrisConfig:     addRequest.UriConfig,
+		RecordConfig:   addRequest.RecordConfig,
+		ProxyConfig:    addRequest.ProxyConfig,
+		ContractConfig: addRequest.ContractConfig,
+		FaultsConfig:   addRequest.FaultsConfig,
 	}
 
 	if err := hostConfig.Validate(); err != nil {
 		return nil, fmt.Errorf("error while validating host config: %v", err)
 	}
 
-	h.hostsConfig.SetHostConfig(addRequest.Host, hostConfig)
+	_, err := h.change(caller, audit.OperationHostUpdate, addRequest.Host, func() (*config.HostConfig, error) {
+		h.hostsConfig.SetHostConfig(addRequest.Host, hostConfig)
+		return &hostConfig, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
 
 	return &hostConfig, nil
 }
 
-func (h *HostsConfigAdminService) DeleteHost(host string) {
-	h.hostsConfig.DeleteHostConfig(host)
+func (h *HostsConfigAdminService) DeleteHost(host string, caller audit.Caller) error {
+	_, err := h.change(caller, audit.OperationHostDelete, host, func() (*config.HostConfig, error) {
+		h.hostsConfig.DeleteHostConfig(host)
+		return nil, nil
+	})
+
+	return err
 }
 
-func (h *HostsConfigAdminService) AddUpdateHostLatency(addLatencyRequest HostAddDeleteRequest) (*config.HostConfig, error) {
+func (h *HostsConfigAdminService) AddUpdateHostLatency(addLatencyRequest HostAddDeleteRequest, caller audit.Caller) (*config.HostConfig, error) {
 	newHostConfig := config.HostConfig{
 		LatencyConfig: addLatencyRequest.LatencyConfig,
 	}
@@ -54,7 +82,9 @@ func (h *HostsConfigAdminService) AddUpdateHostLatency(addLatencyRequest HostAdd
 		return nil, fmt.Errorf("error while validating host config: %v", err)
 	}
 
-	hostConfig, err := h.hostsConfig.UpdateHostLatencyConfig(addLatencyRequest.Host, addLatencyRequest.LatencyConfig)
+	hostConfig, err := h.change(caller, audit.OperationLatencyUpdate, addLatencyRequest.Host, func() (*config.HostConfig, error) {
+		return h.hostsConfig.UpdateHostLatencyConfig(addLatencyRequest.Host, addLatencyRequest.LatencyConfig)
+	})
 
 	if err != nil {
 		return nil, fmt.Errorf("error while updating host latency config: %v", err)
@@ -63,8 +93,10 @@ func (h *HostsConfigAdminService) AddUpdateHostLatency(addLatencyRequest HostAdd
 	return hostConfig, nil
 }
 
-func (h *HostsConfigAdminService) DeleteHostLatency(host string) (*config.HostConfig, error) {
-	hostConfig, err := h.hostsConfig.DeleteHostLatencyConfig(host)
+func (h *HostsConfigAdminService) DeleteHostLatency(host string, caller audit.Caller) (*config.HostConfig, error) {
+	hostConfig, err := h.change(caller, audit.OperationLatencyDelete, host, func() (*config.HostConfig, error) {
+		return h.hostsConfig.DeleteHostLatencyConfig(host)
+	})
 
 	if err != nil {
 		return nil, fmt.Errorf("error while deleting host latency config: %v", err)
@@ -73,7 +105,7 @@ func (h *HostsConfigAdminService) DeleteHostLatency(host string) (*config.HostCo
 	return hostConfig, nil
 }
 
-func (h *HostsConfigAdminService) AddUpdateHostStatuses(addStatusesRequest HostAddDeleteRequest) (*config.HostConfig, error) {
+func (h *HostsConfigAdminService) AddUpdateHostStatuses(addStatusesRequest HostAddDeleteRequest, caller audit.Caller) (*config.HostConfig, error) {
 	newHostConfig := config.HostConfig{
 		StatusesConfig: addStatusesRequest.StatusConfig,
 	}
@@ -82,7 +114,9 @@ func (h *HostsConfigAdminService) AddUpdateHostStatuses(addStatusesRequest HostA
 		return nil, fmt.Errorf("error while validating host statuses config: %v", err)
 	}
 
-	hostConfig, err := h.hostsConfig.UpdateHostStatusesConfig(addStatusesRequest.Host, newHostConfig.StatusesConfig)
+	hostConfig, err := h.change(caller, audit.OperationStatusesUpdate, addStatusesRequest.Host, func() (*config.HostConfig, error) {
+		return h.hostsConfig.UpdateHostStatusesConfig(addStatusesRequest.Host, newHostConfig.StatusesConfig)
+	})
 
 	if err != nil {
 		return nil, fmt.Errorf("error updating host statuses config: %v", err)
@@ -91,8 +125,10 @@ func (h *HostsConfigAdminService) AddUpdateHostStatuses(addStatusesRequest HostA
 	return hostConfig, nil
 }
 
-func (h *HostsConfigAdminService) DeleteHostStatus(host, statusCode string) (*config.HostConfig, error) {
-	hostConfig, err := h.hostsConfig.DeleteHostStatusConfig(host, statusCode)
+func (h *HostsConfigAdminService) DeleteHostStatus(host, statusCode string, caller audit.Caller) (*config.HostConfig, error) {
+	hostConfig, err := h.change(caller, audit.OperationStatusDelete, host, func() (*config.HostConfig, error) {
+		return h.hostsConfig.DeleteHostStatusConfig(host, statusCode)
+	})
 
 	if err != nil {
 		return nil, fmt.Errorf("error deleting host status config: %v", err)
@@ -101,7 +137,7 @@ func (h *HostsConfigAdminService) DeleteHostStatus(host, statusCode string) (*co
 	return hostConfig, nil
 }
 
-func (h *HostsConfigAdminService) AddUpdateHostUris(addUrisRequest HostAddDeleteRequest) (*config.HostConfig, error) {
+func (h *HostsConfigAdminService) AddUpdateHostUris(addUrisRequest HostAddDeleteRequest, caller audit.Caller) (*config.HostConfig, error) {
 	newHostConfig := config.HostConfig{
 		UrisConfig: addUrisRequest.UriConfig,
 	}
@@ -110,7 +146,9 @@ func (h *HostsConfigAdminService) AddUpdateHostUris(addUrisRequest HostAddDelete
 		return nil, fmt.Errorf("error while validating host config: %v", err)
 	}
 
-	hostConfig, err := h.hostsConfig.UpdateHostUrisConfig(addUrisRequest.Host, addUrisRequest.UriConfig)
+	hostConfig, err := h.change(caller, audit.OperationUrisUpdate, addUrisRequest.Host, func() (*config.HostConfig, error) {
+		return h.hostsConfig.UpdateHostUrisConfig(addUrisRequest.Host, addUrisRequest.UriConfig)
+	})
 
 	if err != nil {
 		return nil, fmt.Errorf("error while updating host uris config: %v", err)
@@ -119,8 +157,69 @@ func (h *HostsConfigAdminService) AddUpdateHostUris(addUrisRequest HostAddDelete
 	return hostConfig, nil
 }
 
-func NewHostsConfigAdminService(hostsConfig *config.HostsConfig) *HostsConfigAdminService {
-	return &HostsConfigAdminService{
-		hostsConfig: hostsConfig,
+// change applies a change to the config of a host, persists it and records it in the audit log along with the config
+// of the host before and after it. Changes are applied one at a time, so the config before a change is the one it
+// replaced. A change that can't be persisted is rolled back, so the config in use never drifts from the config file.
+// Nothing is persisted nor recorded when the host config doesn't exist and nothing changed.
+func (h *HostsConfigAdminService) change(caller audit.Caller, operation, host string, apply func() (*config.HostConfig, error)) (*config.HostConfig, error) {
+	h.changeMu.Lock()
+	defer h.changeMu.Unlock()
+
+	previous := h.hostsConfig.Snapshot()
+	before := h.hostsConfig.GetHostConfig(host)
+	after, err := apply()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if before == nil && after == nil {
+		return nil, nil
+	}
+
+	applied := h.hostsConfig.Version()
+
+	if err := h.persist(); err != nil {
+		h.hostsConfig.Rollback(previous, applied)
+		return nil, err
+	}
+
+	h.auditService.Record(caller, operation, host, before, after)
+
+	return after, nil
+}
+
+// persist writes the hosts config back to the config file, when persisting is enabled
+func (h *HostsConfigAdminService) persist() error {
+	if len(h.configFile) == 0 {
+		return nil
+	}
+
+	h.persistMu.Lock()
+	defer h.persistMu.Unlock()
+
+	if err := config.WriteHostsConfig(h.configFile, h.hostsConfig); err != nil {
+		return fmt.Errorf("error while persisting hosts config: %v", err)
+	}
+
+	log.Info().
+		Str("path", h.configFile).
+		Msg("hosts config persisted")
+
+	return nil
+}
+
+func NewHostsConfigAdminService(hostsConfig *config.HostsConfig, appArguments *config.AppArguments, auditService *audit.AuditService) *HostsConfigAdminService {
+	service := HostsConfigAdminService{
+		hostsConfig:  hostsConfig,
+		auditService: auditService,
 	}
+
+	if appArguments != nil && appArguments.PersistConfig && len(appArguments.MocksConfigFile) > 0 {
+		if absolutePath, err := filepath.Abs(appArguments.MocksConfigFile); err == nil {
+			service.configFile = absolutePath
+		}
+	}
+
+	return &service
 }
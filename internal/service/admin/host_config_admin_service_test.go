@@ -1,10 +1,14 @@
 package admin
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/audit"
 )
 
 // Helper function to create int pointers
@@ -14,11 +18,9 @@ func intPtr(i int) *int {
 
 func TestNewHostsConfigAdminService(t *testing.T) {
 	t.Run("creates service with hosts config", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		if service == nil {
 			t.Fatal("NewHostsConfigAdminService should return non-nil service")
@@ -30,7 +32,7 @@ func TestNewHostsConfigAdminService(t *testing.T) {
 	})
 
 	t.Run("handles nil hosts config", func(t *testing.T) {
-		service := NewHostsConfigAdminService(nil)
+		service := NewHostsConfigAdminService(nil, nil, nil)
 
 		if service == nil {
 			t.Fatal("NewHostsConfigAdminService should return non-nil service even with nil config")
@@ -44,18 +46,16 @@ func TestNewHostsConfigAdminService(t *testing.T) {
 
 func TestHostsConfigAdminService_GetHostsConfig(t *testing.T) {
 	t.Run("returns stored hosts config", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {
-					LatencyConfig: &config.LatencyConfig{
-						Min: intPtr(10),
-						Max: intPtr(20),
-					},
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				LatencyConfig: &config.LatencyConfig{
+					Min: intPtr(10),
+					Max: intPtr(20),
 				},
 			},
-		}
+		})
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 		result := service.GetHostsConfig()
 
 		if result != hostsConfig {
@@ -64,7 +64,7 @@ func TestHostsConfigAdminService_GetHostsConfig(t *testing.T) {
 	})
 
 	t.Run("returns nil when hosts config is nil", func(t *testing.T) {
-		service := NewHostsConfigAdminService(nil)
+		service := NewHostsConfigAdminService(nil, nil, nil)
 		result := service.GetHostsConfig()
 
 		if result != nil {
@@ -82,13 +82,11 @@ func TestHostsConfigAdminService_GetHostConfig(t *testing.T) {
 			},
 		}
 
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": expectedConfig,
-			},
-		}
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": expectedConfig,
+		})
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 		result := service.GetHostConfig("example.com")
 
 		if result == nil {
@@ -105,11 +103,9 @@ func TestHostsConfigAdminService_GetHostConfig(t *testing.T) {
 	})
 
 	t.Run("returns nil for non-existent host", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 		result := service.GetHostConfig("non-existent.com")
 
 		if result != nil {
@@ -120,11 +116,9 @@ func TestHostsConfigAdminService_GetHostConfig(t *testing.T) {
 
 func TestHostsConfigAdminService_AddUpdateHost(t *testing.T) {
 	t.Run("adds new host successfully", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		request := HostAddDeleteRequest{
 			Host: "example.com",
@@ -134,7 +128,7 @@ func TestHostsConfigAdminService_AddUpdateHost(t *testing.T) {
 			},
 		}
 
-		result, err := service.AddUpdateHost(request)
+		result, err := service.AddUpdateHost(request, audit.Caller{})
 
 		if err != nil {
 			t.Fatalf("AddUpdateHost should not return error: %v", err)
@@ -152,18 +146,16 @@ func TestHostsConfigAdminService_AddUpdateHost(t *testing.T) {
 	})
 
 	t.Run("updates existing host successfully", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {
-					LatencyConfig: &config.LatencyConfig{
-						Min: intPtr(5),
-						Max: intPtr(10),
-					},
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				LatencyConfig: &config.LatencyConfig{
+					Min: intPtr(5),
+					Max: intPtr(10),
 				},
 			},
-		}
+		})
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		request := HostAddDeleteRequest{
 			Host: "example.com",
@@ -173,7 +165,7 @@ func TestHostsConfigAdminService_AddUpdateHost(t *testing.T) {
 			},
 		}
 
-		result, err := service.AddUpdateHost(request)
+		result, err := service.AddUpdateHost(request, audit.Caller{})
 
 		if err != nil {
 			t.Fatalf("AddUpdateHost should not return error: %v", err)
@@ -195,11 +187,9 @@ func TestHostsConfigAdminService_AddUpdateHost(t *testing.T) {
 	})
 
 	t.Run("returns error for invalid host config", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		request := HostAddDeleteRequest{
 			Host: "example.com",
@@ -209,7 +199,7 @@ func TestHostsConfigAdminService_AddUpdateHost(t *testing.T) {
 			},
 		}
 
-		result, err := service.AddUpdateHost(request)
+		result, err := service.AddUpdateHost(request, audit.Caller{})
 
 		if err == nil {
 			t.Error("AddUpdateHost should return error for invalid config")
@@ -227,11 +217,9 @@ func TestHostsConfigAdminService_AddUpdateHost(t *testing.T) {
 	})
 
 	t.Run("handles complex host config", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		request := HostAddDeleteRequest{
 			Host: "api.example.com",
@@ -260,7 +248,7 @@ func TestHostsConfigAdminService_AddUpdateHost(t *testing.T) {
 			},
 		}
 
-		result, err := service.AddUpdateHost(request)
+		result, err := service.AddUpdateHost(request, audit.Caller{})
 
 		if err != nil {
 			t.Fatalf("AddUpdateHost should not return error for valid complex config: %v", err)
@@ -288,25 +276,23 @@ func TestHostsConfigAdminService_AddUpdateHost(t *testing.T) {
 
 func TestHostsConfigAdminService_DeleteHost(t *testing.T) {
 	t.Run("deletes existing host", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {
-					LatencyConfig: &config.LatencyConfig{
-						Min: intPtr(10),
-						Max: intPtr(20),
-					},
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				LatencyConfig: &config.LatencyConfig{
+					Min: intPtr(10),
+					Max: intPtr(20),
 				},
 			},
-		}
+		})
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		// Verify host exists before deletion
 		if hostsConfig.GetHostConfig("example.com") == nil {
 			t.Fatal("host should exist before deletion")
 		}
 
-		service.DeleteHost("example.com")
+		service.DeleteHost("example.com", audit.Caller{})
 
 		// Verify host was deleted
 		if hostsConfig.GetHostConfig("example.com") != nil {
@@ -315,17 +301,15 @@ func TestHostsConfigAdminService_DeleteHost(t *testing.T) {
 	})
 
 	t.Run("handles deletion of non-existent host", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		// Should not panic or error
-		service.DeleteHost("non-existent.com")
+		service.DeleteHost("non-existent.com", audit.Caller{})
 
 		// Verify hosts config is still empty
-		if len(hostsConfig.Hosts) != 0 {
+		if len(hostsConfig.Snapshot().Hosts) != 0 {
 			t.Error("hosts config should remain empty")
 		}
 	})
@@ -333,13 +317,11 @@ func TestHostsConfigAdminService_DeleteHost(t *testing.T) {
 
 func TestHostsConfigAdminService_AddUpdateHostLatency(t *testing.T) {
 	t.Run("adds latency config to existing host", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {}, // Host exists but no latency config
-			},
-		}
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {}, // Host exists but no latency config
+		})
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		request := HostAddDeleteRequest{
 			Host: "example.com",
@@ -349,7 +331,7 @@ func TestHostsConfigAdminService_AddUpdateHostLatency(t *testing.T) {
 			},
 		}
 
-		result, err := service.AddUpdateHostLatency(request)
+		result, err := service.AddUpdateHostLatency(request, audit.Caller{})
 
 		if err != nil {
 			t.Fatalf("AddUpdateHostLatency should not return error: %v", err)
@@ -375,13 +357,11 @@ func TestHostsConfigAdminService_AddUpdateHostLatency(t *testing.T) {
 	})
 
 	t.Run("returns error for invalid latency config", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		request := HostAddDeleteRequest{
 			Host: "example.com",
@@ -391,7 +371,7 @@ func TestHostsConfigAdminService_AddUpdateHostLatency(t *testing.T) {
 			},
 		}
 
-		result, err := service.AddUpdateHostLatency(request)
+		result, err := service.AddUpdateHostLatency(request, audit.Caller{})
 
 		if err == nil {
 			t.Error("AddUpdateHostLatency should return error for invalid config")
@@ -405,20 +385,18 @@ func TestHostsConfigAdminService_AddUpdateHostLatency(t *testing.T) {
 
 func TestHostsConfigAdminService_DeleteHostLatency(t *testing.T) {
 	t.Run("deletes latency config from existing host", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {
-					LatencyConfig: &config.LatencyConfig{
-						Min: intPtr(10),
-						Max: intPtr(20),
-					},
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				LatencyConfig: &config.LatencyConfig{
+					Min: intPtr(10),
+					Max: intPtr(20),
 				},
 			},
-		}
+		})
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
-		result, err := service.DeleteHostLatency("example.com")
+		result, err := service.DeleteHostLatency("example.com", audit.Caller{})
 
 		if err != nil {
 			t.Fatalf("DeleteHostLatency should not return error: %v", err)
@@ -440,13 +418,11 @@ func TestHostsConfigAdminService_DeleteHostLatency(t *testing.T) {
 	})
 
 	t.Run("handles non-existent host gracefully", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
-		result, err := service.DeleteHostLatency("non-existent.com")
+		result, err := service.DeleteHostLatency("non-existent.com", audit.Caller{})
 
 		if err != nil {
 			t.Errorf("DeleteHostLatency should not return error for non-existent host: %v", err)
@@ -460,11 +436,9 @@ func TestHostsConfigAdminService_DeleteHostLatency(t *testing.T) {
 
 func TestHostsConfigAdminService_ErrorScenarios(t *testing.T) {
 	t.Run("AddUpdateHost handles validation errors", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		// Test with invalid latency config (min > max)
 		request := HostAddDeleteRequest{
@@ -475,7 +449,7 @@ func TestHostsConfigAdminService_ErrorScenarios(t *testing.T) {
 			},
 		}
 
-		result, err := service.AddUpdateHost(request)
+		result, err := service.AddUpdateHost(request, audit.Caller{})
 
 		if err == nil {
 			t.Error("AddUpdateHost should return error for invalid latency config")
@@ -491,13 +465,11 @@ func TestHostsConfigAdminService_ErrorScenarios(t *testing.T) {
 	})
 
 	t.Run("AddUpdateHostLatency handles validation errors", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		// Test with invalid latency config
 		request := HostAddDeleteRequest{
@@ -508,7 +480,7 @@ func TestHostsConfigAdminService_ErrorScenarios(t *testing.T) {
 			},
 		}
 
-		result, err := service.AddUpdateHostLatency(request)
+		result, err := service.AddUpdateHostLatency(request, audit.Caller{})
 
 		if err == nil {
 			t.Error("AddUpdateHostLatency should return error for invalid config")
@@ -520,13 +492,11 @@ func TestHostsConfigAdminService_ErrorScenarios(t *testing.T) {
 	})
 
 	t.Run("AddUpdateHostStatuses handles validation errors", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		// Test with invalid status config (percentage > 100)
 		request := HostAddDeleteRequest{
@@ -538,7 +508,7 @@ func TestHostsConfigAdminService_ErrorScenarios(t *testing.T) {
 			},
 		}
 
-		result, err := service.AddUpdateHostStatuses(request)
+		result, err := service.AddUpdateHostStatuses(request, audit.Caller{})
 
 		if err == nil {
 			t.Error("AddUpdateHostStatuses should return error for invalid config")
@@ -550,13 +520,11 @@ func TestHostsConfigAdminService_ErrorScenarios(t *testing.T) {
 	})
 
 	t.Run("AddUpdateHostUris handles validation errors", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		// Test with invalid URI config (invalid status percentage)
 		request := HostAddDeleteRequest{
@@ -572,7 +540,7 @@ func TestHostsConfigAdminService_ErrorScenarios(t *testing.T) {
 			},
 		}
 
-		result, err := service.AddUpdateHostUris(request)
+		result, err := service.AddUpdateHostUris(request, audit.Caller{})
 
 		if err == nil {
 			t.Error("AddUpdateHostUris should return error for invalid URI config")
@@ -586,11 +554,9 @@ func TestHostsConfigAdminService_ErrorScenarios(t *testing.T) {
 
 func TestHostsConfigAdminService_EdgeCases(t *testing.T) {
 	t.Run("handles empty host name", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		request := HostAddDeleteRequest{
 			Host: "", // Empty host name
@@ -600,7 +566,7 @@ func TestHostsConfigAdminService_EdgeCases(t *testing.T) {
 			},
 		}
 
-		result, err := service.AddUpdateHost(request)
+		result, err := service.AddUpdateHost(request, audit.Caller{})
 
 		// This should work - empty string is a valid key
 		if err != nil {
@@ -613,18 +579,16 @@ func TestHostsConfigAdminService_EdgeCases(t *testing.T) {
 	})
 
 	t.Run("handles nil latency config", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		request := HostAddDeleteRequest{
 			Host:          "example.com",
 			LatencyConfig: nil, // Nil latency config
 		}
 
-		result, err := service.AddUpdateHost(request)
+		result, err := service.AddUpdateHost(request, audit.Caller{})
 
 		if err != nil {
 			t.Errorf("AddUpdateHost should handle nil latency config: %v", err)
@@ -636,18 +600,16 @@ func TestHostsConfigAdminService_EdgeCases(t *testing.T) {
 	})
 
 	t.Run("handles nil status config", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		request := HostAddDeleteRequest{
 			Host:         "example.com",
 			StatusConfig: nil, // Nil status config
 		}
 
-		result, err := service.AddUpdateHost(request)
+		result, err := service.AddUpdateHost(request, audit.Caller{})
 
 		if err != nil {
 			t.Errorf("AddUpdateHost should handle nil status config: %v", err)
@@ -659,18 +621,16 @@ func TestHostsConfigAdminService_EdgeCases(t *testing.T) {
 	})
 
 	t.Run("handles nil URI config", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		request := HostAddDeleteRequest{
 			Host:      "example.com",
 			UriConfig: nil, // Nil URI config
 		}
 
-		result, err := service.AddUpdateHost(request)
+		result, err := service.AddUpdateHost(request, audit.Caller{})
 
 		if err != nil {
 			t.Errorf("AddUpdateHost should handle nil URI config: %v", err)
@@ -684,13 +644,11 @@ func TestHostsConfigAdminService_EdgeCases(t *testing.T) {
 
 func TestHostsConfigAdminService_AddUpdateHostStatuses(t *testing.T) {
 	t.Run("adds status config to existing host", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		request := HostAddDeleteRequest{
 			Host: "example.com",
@@ -700,7 +658,7 @@ func TestHostsConfigAdminService_AddUpdateHostStatuses(t *testing.T) {
 			},
 		}
 
-		result, err := service.AddUpdateHostStatuses(request)
+		result, err := service.AddUpdateHostStatuses(request, audit.Caller{})
 
 		if err != nil {
 			t.Fatalf("AddUpdateHostStatuses should not return error: %v", err)
@@ -722,11 +680,9 @@ func TestHostsConfigAdminService_AddUpdateHostStatuses(t *testing.T) {
 	})
 
 	t.Run("handles non-existent host by returning nil", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		request := HostAddDeleteRequest{
 			Host: "non-existent.com",
@@ -735,7 +691,7 @@ func TestHostsConfigAdminService_AddUpdateHostStatuses(t *testing.T) {
 			},
 		}
 
-		result, err := service.AddUpdateHostStatuses(request)
+		result, err := service.AddUpdateHostStatuses(request, audit.Caller{})
 
 		if err != nil {
 			t.Errorf("AddUpdateHostStatuses should not return error for non-existent host: %v", err)
@@ -749,20 +705,18 @@ func TestHostsConfigAdminService_AddUpdateHostStatuses(t *testing.T) {
 
 func TestHostsConfigAdminService_DeleteHostStatus(t *testing.T) {
 	t.Run("deletes specific status from host", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {
-					StatusesConfig: map[string]config.StatusConfig{
-						"500": {Percentage: intPtr(10)},
-						"404": {Percentage: intPtr(5)},
-					},
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				StatusesConfig: map[string]config.StatusConfig{
+					"500": {Percentage: intPtr(10)},
+					"404": {Percentage: intPtr(5)},
 				},
 			},
-		}
+		})
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
-		result, err := service.DeleteHostStatus("example.com", "500")
+		result, err := service.DeleteHostStatus("example.com", "500", audit.Caller{})
 
 		if err != nil {
 			t.Fatalf("DeleteHostStatus should not return error: %v", err)
@@ -792,13 +746,11 @@ func TestHostsConfigAdminService_DeleteHostStatus(t *testing.T) {
 	})
 
 	t.Run("handles non-existent host by returning nil", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
-		result, err := service.DeleteHostStatus("non-existent.com", "500")
+		result, err := service.DeleteHostStatus("non-existent.com", "500", audit.Caller{})
 
 		if err != nil {
 			t.Errorf("DeleteHostStatus should not return error for non-existent host: %v", err)
@@ -812,13 +764,11 @@ func TestHostsConfigAdminService_DeleteHostStatus(t *testing.T) {
 
 func TestHostsConfigAdminService_AddUpdateHostUris(t *testing.T) {
 	t.Run("adds URI config to existing host", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {},
-			},
-		}
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {},
+		})
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		request := HostAddDeleteRequest{
 			Host: "example.com",
@@ -837,7 +787,7 @@ func TestHostsConfigAdminService_AddUpdateHostUris(t *testing.T) {
 			},
 		}
 
-		result, err := service.AddUpdateHostUris(request)
+		result, err := service.AddUpdateHostUris(request, audit.Caller{})
 
 		if err != nil {
 			t.Fatalf("AddUpdateHostUris should not return error: %v", err)
@@ -859,11 +809,9 @@ func TestHostsConfigAdminService_AddUpdateHostUris(t *testing.T) {
 	})
 
 	t.Run("handles non-existent host by returning nil", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := NewHostsConfigAdminService(hostsConfig)
+		service := NewHostsConfigAdminService(hostsConfig, nil, nil)
 
 		request := HostAddDeleteRequest{
 			Host: "non-existent.com",
@@ -876,7 +824,7 @@ func TestHostsConfigAdminService_AddUpdateHostUris(t *testing.T) {
 			},
 		}
 
-		result, err := service.AddUpdateHostUris(request)
+		result, err := service.AddUpdateHostUris(request, audit.Caller{})
 
 		if err != nil {
 			t.Errorf("AddUpdateHostUris should not return error for non-existent host: %v", err)
@@ -887,3 +835,219 @@ func TestHostsConfigAdminService_AddUpdateHostUris(t *testing.T) {
 		}
 	})
 }
+
+func TestHostsConfigAdminService_persist(t *testing.T) {
+	newService := func(t *testing.T, persist bool) (*HostsConfigAdminService, string) {
+		configFile := filepath.Join(t.TempDir(), "config.json")
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+
+		return NewHostsConfigAdminService(hostsConfig, &config.AppArguments{
+			MocksConfigFile: configFile,
+			PersistConfig:   persist,
+		}, nil), configFile
+	}
+
+	readConfigFile := func(t *testing.T, configFile string) *config.HostsConfig {
+		t.Helper()
+
+		data, err := os.ReadFile(configFile)
+
+		if err != nil {
+			t.Fatalf("failed to read config file: %v", err)
+		}
+
+		hostsConfig := &config.HostsConfig{}
+
+		if err := json.Unmarshal(data, hostsConfig); err != nil {
+			t.Fatalf("failed to unmarshal config file: %v", err)
+		}
+
+		return hostsConfig
+	}
+
+	t.Run("writes every change back to the config file", func(t *testing.T) {
+		service, configFile := newService(t, true)
+
+		if _, err := service.AddUpdateHost(HostAddDeleteRequest{Host: "example.com"}, audit.Caller{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := service.AddUpdateHostLatency(HostAddDeleteRequest{
+			Host:          "example.com",
+			LatencyConfig: &config.LatencyConfig{Min: intPtr(10), Max: intPtr(20)},
+		}, audit.Caller{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		persisted := readConfigFile(t, configFile)
+
+		if persisted.GetHostConfig("example.com") == nil || *persisted.Snapshot().Hosts["example.com"].LatencyConfig.Max != 20 {
+			t.Errorf("expected latency to be persisted, got %+v", persisted.Snapshot())
+		}
+
+		if err := service.DeleteHost("example.com", audit.Caller{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if persisted := readConfigFile(t, configFile); len(persisted.Snapshot().Hosts) != 0 {
+			t.Errorf("expected host deletion to be persisted, got %+v", persisted.Snapshot())
+		}
+	})
+
+	t.Run("doesn't write when nothing changed", func(t *testing.T) {
+		service, configFile := newService(t, true)
+
+		hostConfig, err := service.DeleteHostLatency("missing.com", audit.Caller{})
+
+		if err != nil || hostConfig != nil {
+			t.Fatalf("expected no host config and no error, got %v and %v", hostConfig, err)
+		}
+
+		if _, err := os.Stat(configFile); !os.IsNotExist(err) {
+			t.Error("expected config file not to be written")
+		}
+	})
+
+	t.Run("doesn't write when persisting is disabled", func(t *testing.T) {
+		service, configFile := newService(t, false)
+
+		if _, err := service.AddUpdateHost(HostAddDeleteRequest{Host: "example.com"}, audit.Caller{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := os.Stat(configFile); !os.IsNotExist(err) {
+			t.Error("expected config file not to be written")
+		}
+	})
+
+	t.Run("returns an error when the config file can't be written", func(t *testing.T) {
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+		service := NewHostsConfigAdminService(hostsConfig, &config.AppArguments{
+			MocksConfigFile: filepath.Join(t.TempDir(), "missing", "config.json"),
+			PersistConfig:   true,
+		}, nil)
+
+		_, err := service.AddUpdateHost(HostAddDeleteRequest{Host: "example.com"}, audit.Caller{})
+
+		if err == nil || !strings.Contains(err.Error(), "error while persisting hosts config") {
+			t.Errorf("expected persisting error, got %v", err)
+		}
+	})
+
+	t.Run("rolls back a change that can't be persisted", func(t *testing.T) {
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {LatencyConfig: &config.LatencyConfig{Min: intPtr(10), Max: intPtr(20)}},
+		})
+		auditService, _ := audit.NewAuditService(&config.AppArguments{AuditLogBufferSize: 10})
+		service := NewHostsConfigAdminService(hostsConfig, &config.AppArguments{
+			MocksConfigFile: filepath.Join(t.TempDir(), "missing", "config.json"),
+			PersistConfig:   true,
+		}, auditService)
+
+		if _, err := service.DeleteHostLatency("example.com", audit.Caller{}); err == nil {
+			t.Fatal("expected persisting error")
+		}
+
+		if err := service.DeleteHost("example.com", audit.Caller{}); err == nil {
+			t.Fatal("expected persisting error")
+		}
+
+		hostConfig := hostsConfig.GetHostConfig("example.com")
+
+		if hostConfig == nil || hostConfig.LatencyConfig == nil || *hostConfig.LatencyConfig.Min != 10 {
+			t.Errorf("expected the host config to be rolled back, got %+v", hostConfig)
+		}
+
+		if events := auditService.Query(audit.AuditFilters{}, 0); len(events) != 0 {
+			t.Errorf("expected no audit events, got %d", len(events))
+		}
+	})
+}
+
+func TestHostsConfigAdminService_audit(t *testing.T) {
+	newService := func(t *testing.T) (*HostsConfigAdminService, *audit.AuditService) {
+		auditService, err := audit.NewAuditService(&config.AppArguments{AuditLogBufferSize: 10})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {StatusesConfig: map[string]config.StatusConfig{"500": {Percentage: intPtr(10)}}},
+		})
+
+		return NewHostsConfigAdminService(hostsConfig, nil, auditService), auditService
+	}
+
+	t.Run("records the host config before and after a change", func(t *testing.T) {
+		service, auditService := newService(t)
+		caller := audit.Caller{UUID: "test-uuid", RemoteAddr: "10.0.0.1"}
+
+		_, err := service.AddUpdateHostStatuses(HostAddDeleteRequest{
+			Host:         "example.com",
+			StatusConfig: map[string]config.StatusConfig{"500": {Percentage: intPtr(100)}},
+		}, caller)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		events := auditService.Query(audit.AuditFilters{}, 0)
+
+		if len(events) != 1 {
+			t.Fatalf("expected 1 event, got %d", len(events))
+		}
+
+		event := events[0]
+
+		if event.Operation != audit.OperationStatusesUpdate || event.Host != "example.com" || event.UUID != "test-uuid" ||
+			event.RemoteAddr != "10.0.0.1" {
+			t.Errorf("unexpected event %+v", event)
+		}
+
+		var before, after config.HostConfig
+
+		if err := json.Unmarshal(event.Before, &before); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := json.Unmarshal(event.After, &after); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if *before.StatusesConfig["500"].Percentage != 10 || *after.StatusesConfig["500"].Percentage != 100 {
+			t.Errorf("unexpected snapshots %s and %s", event.Before, event.After)
+		}
+	})
+
+	t.Run("records a deletion without state after", func(t *testing.T) {
+		service, auditService := newService(t)
+
+		if err := service.DeleteHost("example.com", audit.Caller{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		events := auditService.Query(audit.AuditFilters{}, 0)
+
+		if len(events) != 1 || events[0].Operation != audit.OperationHostDelete || events[0].Before == nil ||
+			events[0].After != nil {
+			t.Errorf("unexpected events %+v", events)
+		}
+	})
+
+	t.Run("doesn't record when nothing changed", func(t *testing.T) {
+		service, auditService := newService(t)
+
+		if _, err := service.DeleteHostLatency("missing.com", audit.Caller{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := service.DeleteHost("missing.com", audit.Caller{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if events := auditService.Query(audit.AuditFilters{}, 0); len(events) != 0 {
+			t.Errorf("expected no events, got %+v", events)
+		}
+	})
+}
@@ -1,12 +1,17 @@
 package admin
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/Caik/go-mock-server/internal/service/audit"
 	"github.com/Caik/go-mock-server/internal/service/content"
 )
 
@@ -34,26 +39,103 @@ type MockListItem struct {
 	StatusCode int    `json:"status_code"`
 }
 
+// MockSnapshot describes a mock in the audit log. The content is told by its size and digest rather than kept, as
+// mocks may be big or binary.
+type MockSnapshot struct {
+	MockListItem
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// importAudit collects the mocks written by an import, to audit it as a single change
+type importAudit struct {
+	before []*MockSnapshot // the mocks replaced, as they were
+	after  []*MockSnapshot // the mocks written
+}
+
 type MockAdminService struct {
 	contentService content.ContentService
+	auditService   *audit.AuditService
+	changeMu       sync.Mutex
 }
 
-func (m *MockAdminService) AddUpdateMock(addRequest MockAddDeleteRequest, uuid string) error {
-	return m.contentService.SetContent(addRequest.Host, addRequest.URI, addRequest.Method, uuid, addRequest.StatusCode, addRequest.Data)
+func (m *MockAdminService) AddUpdateMock(addRequest MockAddDeleteRequest, caller audit.Caller) error {
+	m.changeMu.Lock()
+	defer m.changeMu.Unlock()
+
+	before := m.snapshot(addRequest.Host, addRequest.URI, addRequest.Method, caller.UUID, addRequest.StatusCode)
+
+	if err := m.contentService.SetContent(addRequest.Host, addRequest.URI, addRequest.Method, caller.UUID, addRequest.StatusCode, addRequest.Data); err != nil {
+		return err
+	}
+
+	after := newMockSnapshot(addRequest.Host, addRequest.URI, addRequest.Method, addRequest.StatusCode, addRequest.Data)
+	m.auditService.Record(caller, audit.OperationMockUpdate, addRequest.Host, before, after)
+
+	return nil
 }
 
-func (m *MockAdminService) DeleteMock(addRequest MockAddDeleteRequest, uuid string) error {
-	return m.contentService.DeleteContent(addRequest.Host, addRequest.URI, addRequest.Method, uuid, addRequest.StatusCode)
+func (m *MockAdminService) DeleteMock(addRequest MockAddDeleteRequest, caller audit.Caller) error {
+	return m.deleteMock(addRequest.Host, addRequest.URI, addRequest.Method, addRequest.StatusCode, caller)
 }
 
-func (m *MockAdminService) DeleteMockByID(id, uuid string) error {
+func (m *MockAdminService) DeleteMockByID(id string, caller audit.Caller) error {
 	host, uri, method, statusCode, err := decodeMockID(id)
 
 	if err != nil {
 		return fmt.Errorf("%w: %v", ErrInvalidMockID, err)
 	}
 
-	return m.contentService.DeleteContent(host, uri, method, uuid, statusCode)
+	return m.deleteMock(host, uri, method, statusCode, caller)
+}
+
+func (m *MockAdminService) deleteMock(host, uri, method string, statusCode int, caller audit.Caller) error {
+	m.changeMu.Lock()
+	defer m.changeMu.Unlock()
+
+	before := m.snapshot(host, uri, method, caller.UUID, statusCode)
+
+	if err := m.contentService.DeleteContent(host, uri, method, caller.UUID, statusCode); err != nil {
+		return err
+	}
+
+	m.auditService.Record(caller, audit.OperationMockDelete, host, before, nil)
+
+	return nil
+}
+
+// importMock writes a mock generated by an import along with its meta, and adds it to the audit of the import.
+// The caller must hold changeMu.
+func (m *MockAdminService) importMock(audited *importAudit, host, uri, method, uuid string, statusCode int, data []byte, meta content.ContentMeta) error {
+	before := m.snapshot(host, uri, method, uuid, statusCode)
+
+	if err := m.contentService.SetContent(host, uri, method, uuid, statusCode, &data); err != nil {
+		return err
+	}
+
+	if before != nil {
+		audited.before = append(audited.before, before)
+	}
+
+	audited.after = append(audited.after, newMockSnapshot(host, uri, method, statusCode, &data))
+
+	return m.contentService.SetContentMeta(host, uri, method, uuid, statusCode, meta)
+}
+
+// snapshot returns the snapshot of the mock for the audit log, nil when it doesn't exist
+func (m *MockAdminService) snapshot(host, uri, method, uuid string, statusCode int) *MockSnapshot {
+	result, err := m.contentService.GetContent(host, uri, method, uuid, statusCode)
+
+	if err != nil || result == nil || result.Data == nil {
+		return nil
+	}
+
+	// for a missing mock, the content service serves the _default fallback of the host, or an empty body without a path
+	if len(result.Path) == 0 || (uri != content.DefaultUri && strings.HasPrefix(filepath.Base(result.Path), "_default.")) {
+		return nil
+	}
+
+	return newMockSnapshot(host, uri, method, statusCode, result.Data)
 }
 
 func (m *MockAdminService) GetMockContent(id, uuid string) ([]byte, error) {
@@ -130,6 +212,28 @@ func (m *MockAdminService) ListMocks(uuid string) ([]MockListItem, error) {
 	return mocks, nil
 }
 
+func newMockSnapshot(host, uri, method string, statusCode int, data *[]byte) *MockSnapshot {
+	var content []byte
+
+	if data != nil {
+		content = *data
+	}
+
+	digest := sha256.Sum256(content)
+
+	return &MockSnapshot{
+		MockListItem: MockListItem{
+			ID:         generateMockID(host, uri, method, statusCode),
+			Host:       host,
+			URI:        uri,
+			Method:     method,
+			StatusCode: statusCode,
+		},
+		Size:   len(content),
+		SHA256: hex.EncodeToString(digest[:]),
+	}
+}
+
 // generateMockID creates a unique identifier for a mock based on its host, uri, method, and statusCode.
 func generateMockID(host, uri, method string, statusCode int) string {
 	data := fmt.Sprintf("%s%s%s%s%s%s%d", host, mockIDSeparator, uri, mockIDSeparator, method, mockIDSeparator, statusCode)
@@ -159,8 +263,9 @@ func decodeMockID(id string) (host, uri, method string, statusCode int, err erro
 	return parts[0], parts[1], parts[2], sc, nil
 }
 
-func NewMockAdminService(contentService content.ContentService) *MockAdminService {
+func NewMockAdminService(contentService content.ContentService, auditService *audit.AuditService) *MockAdminService {
 	return &MockAdminService{
 		contentService: contentService,
+		auditService:   auditService,
 	}
 }
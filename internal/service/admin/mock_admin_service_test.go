@@ -1,16 +1,21 @@
 package admin
 
 import (
+	"encoding/json"
 	"errors"
 	"strconv"
 	"testing"
 
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/audit"
 	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/matcher"
 )
 
 // Mock content service for testing
 type mockContentService struct {
 	contents    map[string][]byte
+	metas       map[string]content.ContentMeta
 	events      chan content.ContentEvent
 	shouldError bool
 	errorMsg    string
@@ -32,6 +37,10 @@ func (m *mockContentService) GetContent(host, uri, method, uuid string, statusCo
 	return nil, errors.New("not found")
 }
 
+func (m *mockContentService) MatchContent(host, uri, method, uuid string, statusCode int, request matcher.Request) (*content.ContentResult, error) {
+	return m.GetContent(host, uri, method, uuid, statusCode)
+}
+
 func (m *mockContentService) SetContent(host, uri, method, uuid string, statusCode int, data *[]byte) error {
 	if m.shouldError {
 		return errors.New(m.errorMsg)
@@ -46,6 +55,19 @@ func (m *mockContentService) SetContent(host, uri, method, uuid string, statusCo
 	return nil
 }
 
+func (m *mockContentService) SetContentMeta(host, uri, method, uuid string, statusCode int, meta content.ContentMeta) error {
+	if m.shouldError {
+		return errors.New(m.errorMsg)
+	}
+
+	if m.metas == nil {
+		m.metas = make(map[string]content.ContentMeta)
+	}
+
+	m.metas[host+":"+uri+":"+method+":"+strconv.Itoa(statusCode)] = meta
+	return nil
+}
+
 func (m *mockContentService) DeleteContent(host, uri, method, uuid string, statusCode int) error {
 	if m.shouldError {
 		return errors.New(m.errorMsg)
@@ -97,6 +119,10 @@ func (m *mockContentService) Unsubscribe(subscriberId string) {
 	// Mock implementation
 }
 
+func (m *mockContentService) SubscriberCount() int {
+	return 0
+}
+
 func TestNewMockAdminService(t *testing.T) {
 	t.Run("creates service with content service", func(t *testing.T) {
 		contentService := &mockContentService{
@@ -104,7 +130,7 @@ func TestNewMockAdminService(t *testing.T) {
 			events:   make(chan content.ContentEvent),
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		if service == nil {
 			t.Fatal("NewMockAdminService should return non-nil service")
@@ -116,7 +142,7 @@ func TestNewMockAdminService(t *testing.T) {
 	})
 
 	t.Run("handles nil content service", func(t *testing.T) {
-		service := NewMockAdminService(nil)
+		service := NewMockAdminService(nil, nil)
 
 		if service == nil {
 			t.Fatal("NewMockAdminService should return non-nil service even with nil content service")
@@ -135,7 +161,7 @@ func TestMockAdminService_AddUpdateMock(t *testing.T) {
 			events:   make(chan content.ContentEvent),
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		testData := []byte(`{"message": "test response"}`)
 		request := MockAddDeleteRequest{
@@ -146,7 +172,7 @@ func TestMockAdminService_AddUpdateMock(t *testing.T) {
 			Data:       &testData,
 		}
 
-		err := service.AddUpdateMock(request, "test-uuid")
+		err := service.AddUpdateMock(request, audit.Caller{UUID: "test-uuid"})
 
 		if err != nil {
 			t.Fatalf("AddUpdateMock should not return error: %v", err)
@@ -172,7 +198,7 @@ func TestMockAdminService_AddUpdateMock(t *testing.T) {
 			events: make(chan content.ContentEvent),
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		newTestData := []byte(`{"message": "new response"}`)
 		request := MockAddDeleteRequest{
@@ -183,7 +209,7 @@ func TestMockAdminService_AddUpdateMock(t *testing.T) {
 			Data:       &newTestData,
 		}
 
-		err := service.AddUpdateMock(request, "test-uuid")
+		err := service.AddUpdateMock(request, audit.Caller{UUID: "test-uuid"})
 
 		if err != nil {
 			t.Fatalf("AddUpdateMock should not return error: %v", err)
@@ -207,7 +233,7 @@ func TestMockAdminService_AddUpdateMock(t *testing.T) {
 			events:   make(chan content.ContentEvent),
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		request := MockAddDeleteRequest{
 			Host:       "example.com",
@@ -217,7 +243,7 @@ func TestMockAdminService_AddUpdateMock(t *testing.T) {
 			Data:       nil, // Nil data
 		}
 
-		err := service.AddUpdateMock(request, "test-uuid")
+		err := service.AddUpdateMock(request, audit.Caller{UUID: "test-uuid"})
 
 		if err != nil {
 			t.Fatalf("AddUpdateMock should not return error for nil data: %v", err)
@@ -237,7 +263,7 @@ func TestMockAdminService_AddUpdateMock(t *testing.T) {
 			events:   make(chan content.ContentEvent),
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		emptyData := []byte{}
 		request := MockAddDeleteRequest{
@@ -248,7 +274,7 @@ func TestMockAdminService_AddUpdateMock(t *testing.T) {
 			Data:       &emptyData,
 		}
 
-		err := service.AddUpdateMock(request, "test-uuid")
+		err := service.AddUpdateMock(request, audit.Caller{UUID: "test-uuid"})
 
 		if err != nil {
 			t.Fatalf("AddUpdateMock should not return error for empty data: %v", err)
@@ -274,7 +300,7 @@ func TestMockAdminService_AddUpdateMock(t *testing.T) {
 			errorMsg:    "content service error",
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		testData := []byte(`{"message": "test response"}`)
 		request := MockAddDeleteRequest{
@@ -285,7 +311,7 @@ func TestMockAdminService_AddUpdateMock(t *testing.T) {
 			Data:       &testData,
 		}
 
-		err := service.AddUpdateMock(request, "test-uuid")
+		err := service.AddUpdateMock(request, audit.Caller{UUID: "test-uuid"})
 
 		if err == nil {
 			t.Error("AddUpdateMock should return error when content service fails")
@@ -302,7 +328,7 @@ func TestMockAdminService_AddUpdateMock(t *testing.T) {
 			events:   make(chan content.ContentEvent),
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		methods := []string{"GET", "POST", "PUT", "DELETE", "PATCH"}
 
@@ -316,7 +342,7 @@ func TestMockAdminService_AddUpdateMock(t *testing.T) {
 				Data:       &testData,
 			}
 
-			err := service.AddUpdateMock(request, "test-uuid")
+			err := service.AddUpdateMock(request, audit.Caller{UUID: "test-uuid"})
 
 			if err != nil {
 				t.Fatalf("AddUpdateMock should not return error for method %s: %v", method, err)
@@ -346,7 +372,7 @@ func TestMockAdminService_DeleteMock(t *testing.T) {
 			events: make(chan content.ContentEvent),
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		request := MockAddDeleteRequest{
 			Host:       "example.com",
@@ -355,7 +381,7 @@ func TestMockAdminService_DeleteMock(t *testing.T) {
 			StatusCode: 200,
 		}
 
-		err := service.DeleteMock(request, "test-uuid")
+		err := service.DeleteMock(request, audit.Caller{UUID: "test-uuid"})
 
 		if err != nil {
 			t.Fatalf("DeleteMock should not return error: %v", err)
@@ -375,7 +401,7 @@ func TestMockAdminService_DeleteMock(t *testing.T) {
 			events:   make(chan content.ContentEvent),
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		request := MockAddDeleteRequest{
 			Host:       "example.com",
@@ -384,7 +410,7 @@ func TestMockAdminService_DeleteMock(t *testing.T) {
 			StatusCode: 200,
 		}
 
-		err := service.DeleteMock(request, "test-uuid")
+		err := service.DeleteMock(request, audit.Caller{UUID: "test-uuid"})
 
 		if err != nil {
 			t.Fatalf("DeleteMock should not return error for non-existent mock: %v", err)
@@ -404,7 +430,7 @@ func TestMockAdminService_DeleteMock(t *testing.T) {
 			errorMsg:    "delete error",
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		request := MockAddDeleteRequest{
 			Host:       "example.com",
@@ -413,7 +439,7 @@ func TestMockAdminService_DeleteMock(t *testing.T) {
 			StatusCode: 200,
 		}
 
-		err := service.DeleteMock(request, "test-uuid")
+		err := service.DeleteMock(request, audit.Caller{UUID: "test-uuid"})
 
 		if err == nil {
 			t.Error("DeleteMock should return error when content service fails")
@@ -434,7 +460,7 @@ func TestMockAdminService_DeleteMock(t *testing.T) {
 			events: make(chan content.ContentEvent),
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		// Delete one mock
 		request := MockAddDeleteRequest{
@@ -444,7 +470,7 @@ func TestMockAdminService_DeleteMock(t *testing.T) {
 			StatusCode: 200,
 		}
 
-		err := service.DeleteMock(request, "test-uuid")
+		err := service.DeleteMock(request, audit.Caller{UUID: "test-uuid"})
 
 		if err != nil {
 			t.Fatalf("DeleteMock should not return error: %v", err)
@@ -484,7 +510,7 @@ func TestMockAdminService_ListMocks(t *testing.T) {
 			events: make(chan content.ContentEvent),
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 		mocks, err := service.ListMocks("test-uuid")
 
 		if err != nil {
@@ -502,7 +528,7 @@ func TestMockAdminService_ListMocks(t *testing.T) {
 			events:   make(chan content.ContentEvent),
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 		mocks, err := service.ListMocks("test-uuid")
 
 		if err != nil {
@@ -522,7 +548,7 @@ func TestMockAdminService_ListMocks(t *testing.T) {
 			errorMsg:    "content service error",
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 		mocks, err := service.ListMocks("test-uuid")
 
 		if err == nil {
@@ -542,7 +568,7 @@ func TestMockAdminService_ListMocks(t *testing.T) {
 			events: make(chan content.ContentEvent),
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 		mocks, err := service.ListMocks("test-uuid")
 
 		if err != nil {
@@ -598,7 +624,7 @@ func TestMockAdminService_ListDefaultMocks(t *testing.T) {
 			events: make(chan content.ContentEvent),
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 		mocks, err := service.ListDefaultMocks("test-uuid")
 
 		if err != nil {
@@ -633,7 +659,7 @@ func TestMockAdminService_ListDefaultMocks(t *testing.T) {
 			events:   make(chan content.ContentEvent),
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 		mocks, err := service.ListDefaultMocks("test-uuid")
 
 		if err != nil {
@@ -653,7 +679,7 @@ func TestMockAdminService_ListDefaultMocks(t *testing.T) {
 			errorMsg:    "content service error",
 		}
 
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 		mocks, err := service.ListDefaultMocks("test-uuid")
 
 		if err == nil {
@@ -666,7 +692,7 @@ func TestMockAdminService_ListDefaultMocks(t *testing.T) {
 	})
 
 	t.Run("handles nil contents from service", func(t *testing.T) {
-		service := NewMockAdminService(&nilDataContentService{})
+		service := NewMockAdminService(&nilDataContentService{}, nil)
 		mocks, err := service.ListDefaultMocks("test-uuid")
 
 		if err != nil {
@@ -730,10 +756,10 @@ func TestMockAdminService_DeleteMockByID(t *testing.T) {
 			},
 			events: make(chan content.ContentEvent),
 		}
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		id := generateMockID("example.com", "/api/users", "GET", 200)
-		err := service.DeleteMockByID(id, "test-uuid")
+		err := service.DeleteMockByID(id, audit.Caller{UUID: "test-uuid"})
 
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
@@ -745,9 +771,9 @@ func TestMockAdminService_DeleteMockByID(t *testing.T) {
 			contents: make(map[string][]byte),
 			events:   make(chan content.ContentEvent),
 		}
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
-		err := service.DeleteMockByID("not-valid-base64!!!", "test-uuid")
+		err := service.DeleteMockByID("not-valid-base64!!!", audit.Caller{UUID: "test-uuid"})
 
 		if err == nil {
 			t.Fatal("expected error")
@@ -763,10 +789,10 @@ func TestMockAdminService_DeleteMockByID(t *testing.T) {
 			contents: make(map[string][]byte),
 			events:   make(chan content.ContentEvent),
 		}
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		// Valid base64 but missing separator
-		err := service.DeleteMockByID("aW52YWxpZA==", "test-uuid")
+		err := service.DeleteMockByID("aW52YWxpZA==", audit.Caller{UUID: "test-uuid"})
 
 		if err == nil {
 			t.Fatal("expected error")
@@ -787,7 +813,7 @@ func TestMockAdminService_GetMockContent(t *testing.T) {
 			},
 			events: make(chan content.ContentEvent),
 		}
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		id := generateMockID("example.com", "/api/users", "GET", 200)
 		result, err := service.GetMockContent(id, "test-uuid")
@@ -806,7 +832,7 @@ func TestMockAdminService_GetMockContent(t *testing.T) {
 			contents: make(map[string][]byte),
 			events:   make(chan content.ContentEvent),
 		}
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		_, err := service.GetMockContent("not-valid!!!", "test-uuid")
 
@@ -826,7 +852,7 @@ func TestMockAdminService_GetMockContent(t *testing.T) {
 			shouldError: true,
 			errorMsg:    "not found",
 		}
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		id := generateMockID("example.com", "/api/users", "GET", 200)
 		_, err := service.GetMockContent(id, "test-uuid")
@@ -842,7 +868,7 @@ func TestMockAdminService_GetMockContent(t *testing.T) {
 
 	t.Run("returns ErrMockNotFound when result data is nil", func(t *testing.T) {
 		contentService := &nilDataContentService{}
-		service := NewMockAdminService(contentService)
+		service := NewMockAdminService(contentService, nil)
 
 		id := generateMockID("example.com", "/api/users", "GET", 200)
 		_, err := service.GetMockContent(id, "test-uuid")
@@ -864,10 +890,18 @@ func (n *nilDataContentService) GetContent(host, uri, method, uuid string, statu
 	return &content.ContentResult{Data: nil}, nil
 }
 
+func (n *nilDataContentService) MatchContent(host, uri, method, uuid string, statusCode int, request matcher.Request) (*content.ContentResult, error) {
+	return n.GetContent(host, uri, method, uuid, statusCode)
+}
+
 func (n *nilDataContentService) SetContent(host, uri, method, uuid string, statusCode int, data *[]byte) error {
 	return nil
 }
 
+func (n *nilDataContentService) SetContentMeta(host, uri, method, uuid string, statusCode int, meta content.ContentMeta) error {
+	return nil
+}
+
 func (n *nilDataContentService) DeleteContent(host, uri, method, uuid string, statusCode int) error {
 	return nil
 }
@@ -886,6 +920,8 @@ func (n *nilDataContentService) Subscribe(subscriberId string, eventTypes ...con
 
 func (n *nilDataContentService) Unsubscribe(subscriberId string) {}
 
+func (n *nilDataContentService) SubscriberCount() int { return 0 }
+
 func TestDecodeMockID(t *testing.T) {
 	t.Run("decodes valid ID", func(t *testing.T) {
 		id := generateMockID("example.com", "/api/users", "GET", 200)
@@ -946,3 +982,102 @@ func TestDecodeMockID(t *testing.T) {
 		}
 	})
 }
+
+func TestMockAdminService_audit(t *testing.T) {
+	auditService, err := audit.NewAuditService(&config.AppArguments{AuditLogBufferSize: 10})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	contentService := &mockContentService{contents: make(map[string][]byte)}
+	service := NewMockAdminService(contentService, auditService)
+	caller := audit.Caller{UUID: "test-uuid"}
+	data := []byte(`{"ok": true}`)
+	request := MockAddDeleteRequest{Host: "example.com", URI: "/api/test", Method: "GET", StatusCode: 200, Data: &data}
+
+	if err := service.AddUpdateMock(request, caller); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := service.DeleteMock(request, caller); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := auditService.Query(audit.AuditFilters{}, 0)
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	deleted, created := events[0], events[1]
+
+	if created.Operation != audit.OperationMockUpdate || created.Before != nil || created.After == nil {
+		t.Errorf("unexpected creation event %+v", created)
+	}
+
+	if deleted.Operation != audit.OperationMockDelete || deleted.After != nil || string(deleted.Before) != string(created.After) {
+		t.Errorf("expected the deleted mock to be the created one, got %+v", deleted)
+	}
+
+	var snapshot MockSnapshot
+
+	if err := json.Unmarshal(created.After, &snapshot); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the content is told by its size and digest
+	if snapshot.URI != "/api/test" || snapshot.Size != len(data) || len(snapshot.SHA256) != 64 {
+		t.Errorf("unexpected snapshot %+v", snapshot)
+	}
+
+	t.Run("doesn't record the failed changes", func(t *testing.T) {
+		contentService.shouldError = true
+		defer func() { contentService.shouldError = false }()
+
+		if err := service.AddUpdateMock(request, caller); err == nil {
+			t.Fatal("expected an error")
+		}
+
+		if events := auditService.Query(audit.AuditFilters{}, 0); len(events) != 2 {
+			t.Errorf("expected 2 events, got %d", len(events))
+		}
+	})
+
+	t.Run("doesn't take the fallback of a host for the previous mock", func(t *testing.T) {
+		auditService, err := audit.NewAuditService(&config.AppArguments{AuditLogBufferSize: 10})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		contentService := content.NewFilesystemContentService(&config.MocksDirectoryConfig{Path: t.TempDir()})
+		service := NewMockAdminService(contentService, auditService)
+		fallback := []byte("fallback")
+
+		if err := contentService.SetContent("example.com", content.DefaultUri, "GET", "test-uuid", 200, &fallback); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// a host with a _default mock, then a host without any mock
+		for _, host := range []string{"example.com", "other.example.com"} {
+			request := MockAddDeleteRequest{Host: host, URI: "/api/test", Method: "GET", StatusCode: 200, Data: &data}
+
+			if err := service.AddUpdateMock(request, caller); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if events := auditService.Query(audit.AuditFilters{Host: host}, 0); len(events) != 1 || events[0].Before != nil {
+				t.Errorf("expected the mock of %s to be recorded as created, got %+v", host, events)
+			}
+		}
+
+		if err := service.AddUpdateMock(request, caller); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if events := auditService.Query(audit.AuditFilters{Host: "example.com"}, 0); len(events) != 2 || events[0].Before == nil {
+			t.Errorf("expected the mock to be recorded as replaced, got %+v", events)
+		}
+	})
+}
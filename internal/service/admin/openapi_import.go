@@ -0,0 +1,99 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Caik/go-mock-server/internal/service/audit"
+	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/openapi"
+	"github.com/Caik/go-mock-server/internal/util"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrInvalidImport tells that the mocks can't be generated from the document
+var ErrInvalidImport = errors.New("invalid import")
+
+// ImportResult lists the mocks generated from an OpenAPI document, and the responses skipped
+type ImportResult struct {
+	Host    string                    `json:"host"`
+	Mocks   []MockListItem            `json:"mocks"`
+	Skipped []openapi.SkippedResponse `json:"skipped"`
+}
+
+// ImportOpenAPI generates a mock for every response of an OpenAPI 3 or Swagger 2 document. The mocks are created
+// under the given host, or under the host the document is served from when empty.
+func (m *MockAdminService) ImportOpenAPI(document []byte, host string, caller audit.Caller) (*ImportResult, error) {
+	parsed, err := openapi.Parse(document)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+
+	if len(host) == 0 {
+		host = parsed.Host()
+	}
+
+	if !util.HostRegex.MatchString(host) {
+		return nil, fmt.Errorf("%w: invalid host %q", ErrInvalidImport, host)
+	}
+
+	mocks, skipped := parsed.Mocks()
+	result := &ImportResult{
+		Host:    host,
+		Mocks:   make([]MockListItem, 0, len(mocks)),
+		Skipped: skipped,
+	}
+
+	m.changeMu.Lock()
+	defer m.changeMu.Unlock()
+
+	var audited importAudit
+
+	for _, mock := range mocks {
+		if err := m.importMock(&audited, host, mock.URI, mock.Method, caller.UUID, mock.StatusCode, mock.Data, contentMetaOf(mock.ContentType, nil)); err != nil {
+			// the mocks written before the failure stay, so they are audited all the same
+			if len(audited.after) > 0 {
+				m.auditService.Record(caller, audit.OperationOpenAPIImport, host, audited.before, audited.after)
+			}
+
+			return nil, fmt.Errorf("error while creating mock %s %s %d: %v", mock.Method, mock.URI, mock.StatusCode, err)
+		}
+
+		result.Mocks = append(result.Mocks, MockListItem{
+			ID:         generateMockID(host, mock.URI, mock.Method, mock.StatusCode),
+			Host:       host,
+			URI:        mock.URI,
+			Method:     mock.Method,
+			StatusCode: mock.StatusCode,
+		})
+	}
+
+	// the import is audited as a whole, listing the mocks it wrote and the ones they replaced
+	m.auditService.Record(caller, audit.OperationOpenAPIImport, host, audited.before, audited.after)
+
+	log.Info().
+		Str("uuid", caller.UUID).
+		Str("host", host).
+		Int("mocks", len(result.Mocks)).
+		Int("skipped", len(result.Skipped)).
+		Msg("OpenAPI document imported")
+
+	return result, nil
+}
+
+// contentMetaOf returns the meta of an imported mock, declaring the media type of its body if it has one along with
+// the given headers
+func contentMetaOf(contentType string, headers map[string]string) content.ContentMeta {
+	meta := content.ContentMeta{Headers: make(map[string]string, len(headers)+1)}
+
+	for key, value := range headers {
+		meta.Headers[key] = value
+	}
+
+	if len(contentType) > 0 {
+		meta.Headers["Content-Type"] = contentType
+	}
+
+	return meta
+}
@@ -0,0 +1,212 @@
+package admin
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/audit"
+	"github.com/Caik/go-mock-server/internal/service/cache"
+	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/mock"
+)
+
+const importDocument = `{
+  "openapi": "3.0.0",
+  "servers": [{"url": "https://api.example.com"}],
+  "paths": {
+    "/users/{id}": {
+      "get": {
+        "responses": {
+          "200": {"content": {"application/json": {"example": {"name": "john"}}}},
+          "default": {"description": "error"}
+        }
+      }
+    }
+  }
+}`
+
+const contractDocument = `{
+  "openapi": "3.0.0",
+  "paths": {
+    "/pets": {
+      "get": {
+        "responses": {
+          "200": {"content": {"application/vnd.pets+json": {"schema": {"type": "array", "items": {"type": "string"}}}}}
+        }
+      }
+    },
+    "/pets/{id}": {
+      "get": {
+        "responses": {
+          "200": {"content": {"text/plain": {"example": "rex"}}}
+        }
+      }
+    }
+  }
+}`
+
+func TestMockAdminService_ImportOpenAPI(t *testing.T) {
+	newService := func() (*MockAdminService, *mockContentService) {
+		contentService := &mockContentService{
+			contents: make(map[string][]byte),
+			events:   make(chan content.ContentEvent),
+		}
+
+		return NewMockAdminService(contentService, nil), contentService
+	}
+
+	t.Run("creates the mocks under the host of the document", func(t *testing.T) {
+		service, contentService := newService()
+
+		result, err := service.ImportOpenAPI([]byte(importDocument), "", audit.Caller{UUID: "test-uuid"})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.Host != "api.example.com" || len(result.Mocks) != 1 || len(result.Skipped) != 1 {
+			t.Fatalf("unexpected result %+v", result)
+		}
+
+		if result.Mocks[0].ID != generateMockID("api.example.com", "/users/{id}", "GET", 200) {
+			t.Errorf("unexpected mock ID %s", result.Mocks[0].ID)
+		}
+
+		data, exists := contentService.contents["api.example.com:/users/{id}:GET:200"]
+
+		if !exists || string(data) != "{\n  \"name\": \"john\"\n}" {
+			t.Errorf("expected the mock to be written, got %q", data)
+		}
+	})
+
+	t.Run("declares the media type of the mocks", func(t *testing.T) {
+		service, contentService := newService()
+
+		if _, err := service.ImportOpenAPI([]byte(importDocument), "", audit.Caller{UUID: "test-uuid"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		meta := contentService.metas["api.example.com:/users/{id}:GET:200"]
+
+		if meta.Headers["Content-Type"] != "application/json" {
+			t.Errorf("expected the media type to be declared, got %+v", meta)
+		}
+	})
+
+	t.Run("serves the mocks as declared by the document", func(t *testing.T) {
+		specPath := filepath.Join(t.TempDir(), "openapi.json")
+
+		if err := os.WriteFile(specPath, []byte(contractDocument), 0644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		contentService := content.NewFilesystemContentService(&config.MocksDirectoryConfig{Path: t.TempDir()})
+		service := NewMockAdminService(contentService, nil)
+
+		if _, err := service.ImportOpenAPI([]byte(contractDocument), "api.example.com", audit.Caller{UUID: "test-uuid"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"api.example.com": {ContractConfig: &config.ContractConfig{Spec: specPath, Mode: config.ContractModeReject}},
+		})
+		arguments := &config.AppArguments{DisableLatency: true, DisableCache: true, DefaultContentType: "application/json"}
+		factory := mock.NewMockServiceFactory(contentService, cache.NewInMemoryCacheService(arguments), nil, nil, nil, arguments, hostsConfig)
+
+		for _, uri := range []string{"/pets", "/pets/1"} {
+			response := factory.GetMockResponse(mock.MockRequest{
+				Host:       "api.example.com",
+				Method:     "GET",
+				URI:        uri,
+				Uuid:       "test-uuid",
+				StatusCode: 200,
+			})
+
+			if response == nil || response.StatusCode != 200 || response.Metadata[mock.MetadataMatched] != "true" {
+				t.Errorf("expected the mock of %s to comply with the contract, got %+v", uri, response)
+			}
+		}
+	})
+
+	t.Run("creates the mocks under the given host", func(t *testing.T) {
+		service, contentService := newService()
+
+		result, err := service.ImportOpenAPI([]byte(importDocument), "mocks.example.com", audit.Caller{UUID: "test-uuid"})
+
+		if err != nil || result.Host != "mocks.example.com" {
+			t.Fatalf("unexpected result %+v, %v", result, err)
+		}
+
+		if _, exists := contentService.contents["mocks.example.com:/users/{id}:GET:200"]; !exists {
+			t.Error("expected the mock to be written under the given host")
+		}
+	})
+
+	t.Run("rejects invalid documents", func(t *testing.T) {
+		service, _ := newService()
+
+		if _, err := service.ImportOpenAPI([]byte("not a document"), "", audit.Caller{UUID: "test-uuid"}); !errors.Is(err, ErrInvalidImport) {
+			t.Errorf("expected ErrInvalidImport, got %v", err)
+		}
+	})
+
+	t.Run("requires a host", func(t *testing.T) {
+		service, _ := newService()
+		document := `{"openapi": "3.0.0", "paths": {}}`
+
+		if _, err := service.ImportOpenAPI([]byte(document), "", audit.Caller{UUID: "test-uuid"}); !errors.Is(err, ErrInvalidImport) {
+			t.Errorf("expected ErrInvalidImport, got %v", err)
+		}
+	})
+
+	t.Run("returns error when content service fails", func(t *testing.T) {
+		service, contentService := newService()
+		contentService.shouldError = true
+		contentService.errorMsg = "write error"
+
+		_, err := service.ImportOpenAPI([]byte(importDocument), "", audit.Caller{UUID: "test-uuid"})
+
+		if err == nil || errors.Is(err, ErrInvalidImport) {
+			t.Errorf("expected a write error, got %v", err)
+		}
+	})
+
+	t.Run("records the import as a single audit event", func(t *testing.T) {
+		auditService, err := audit.NewAuditService(&config.AppArguments{AuditLogBufferSize: 10})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, contentService := newService()
+		service := NewMockAdminService(contentService, auditService)
+
+		if _, err := service.ImportOpenAPI([]byte(importDocument), "", audit.Caller{UUID: "test-uuid"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		events := auditService.Query(audit.AuditFilters{}, 0)
+
+		if len(events) != 1 || events[0].Operation != audit.OperationOpenAPIImport || events[0].Host != "api.example.com" {
+			t.Fatalf("unexpected events %+v", events)
+		}
+
+		if events[0].Before != nil || events[0].After == nil {
+			t.Errorf("expected the mocks to be recorded as created, got %+v", events[0])
+		}
+
+		// importing again replaces the same mocks
+		if _, err := service.ImportOpenAPI([]byte(importDocument), "", audit.Caller{UUID: "test-uuid"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		events = auditService.Query(audit.AuditFilters{}, 0)
+
+		if len(events) != 2 || string(events[0].Before) != string(events[1].After) {
+			t.Errorf("expected the replaced mocks to be recorded, got %+v", events)
+		}
+	})
+}
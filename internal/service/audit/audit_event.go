@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/service/auth"
+)
+
+// Operations of the admin API recorded in the audit log. They are dot separated, so filtering on a prefix such as
+// "host" matches all the changes of the hosts config.
+const (
+	OperationHostUpdate     = "host.update"
+	OperationHostDelete     = "host.delete"
+	OperationLatencyUpdate  = "host.latency.update"
+	OperationLatencyDelete  = "host.latency.delete"
+	OperationStatusesUpdate = "host.statuses.update"
+	OperationStatusDelete   = "host.status.delete"
+	OperationUrisUpdate     = "host.uris.update"
+	OperationMockUpdate     = "mock.update"
+	OperationMockDelete     = "mock.delete"
+	OperationOpenAPIImport  = "mock.import.openapi"
+	OperationHARImport      = "mock.import.har"
+)
+
+// Caller tells who made a change: the request it came with, the identity of the caller when authentication is
+// enabled, and the address the request came from
+type Caller struct {
+	UUID       string
+	Identity   *auth.Identity
+	RemoteAddr string
+}
+
+// AuditEvent records a change made through the admin API, with the state of what changed before and after it
+type AuditEvent struct {
+	Timestamp  time.Time       `json:"timestamp"`
+	UUID       string          `json:"uuid"`
+	Identity   *auth.Identity  `json:"identity,omitempty"`
+	RemoteAddr string          `json:"remote_addr,omitempty"`
+	Operation  string          `json:"operation"`
+	Host       string          `json:"host,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditFilters contains optional filters for querying audit events.
+type AuditFilters struct {
+	Operation string     // Match this operation, or the operations it is a prefix of (e.g. host for host.update)
+	Host      string     // Match the changes of this host (case-insensitive)
+	Identity  string     // Match the changes made by this identity
+	Since     *time.Time // Match events recorded at or after this time
+}
+
+// Matches returns true if the event matches all non-empty filters.
+func (f AuditFilters) Matches(event AuditEvent) bool {
+	if f.Operation != "" && event.Operation != f.Operation && !strings.HasPrefix(event.Operation, f.Operation+".") {
+		return false
+	}
+
+	if f.Host != "" && !strings.EqualFold(event.Host, f.Host) {
+		return false
+	}
+
+	if f.Identity != "" && (event.Identity == nil || event.Identity.Name != f.Identity) {
+		return false
+	}
+
+	return f.Since == nil || !event.Timestamp.Before(*f.Since)
+}
@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/service/auth"
+)
+
+func TestAuditFilters_Matches(t *testing.T) {
+	now := time.Now()
+	event := AuditEvent{
+		Timestamp: now,
+		Identity:  &auth.Identity{Name: "ci"},
+		Operation: OperationStatusesUpdate,
+		Host:      "example.com",
+	}
+
+	since := now.Add(-time.Minute)
+	later := now.Add(time.Minute)
+
+	tests := []struct {
+		name     string
+		filters  AuditFilters
+		expected bool
+	}{
+		{"no filters", AuditFilters{}, true},
+		{"same operation", AuditFilters{Operation: OperationStatusesUpdate}, true},
+		{"operation prefix", AuditFilters{Operation: "host"}, true},
+		{"partial operation name", AuditFilters{Operation: "host.status"}, false},
+		{"other operation", AuditFilters{Operation: OperationMockUpdate}, false},
+		{"same host, other case", AuditFilters{Host: "EXAMPLE.com"}, true},
+		{"other host", AuditFilters{Host: "other.com"}, false},
+		{"same identity", AuditFilters{Identity: "ci"}, true},
+		{"other identity", AuditFilters{Identity: "viewer"}, false},
+		{"recorded since", AuditFilters{Since: &since}, true},
+		{"recorded before since", AuditFilters{Since: &later}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if matches := tt.filters.Matches(event); matches != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, matches)
+			}
+		})
+	}
+
+	t.Run("an identity filter doesn't match the events without identity", func(t *testing.T) {
+		if (AuditFilters{Identity: "ci"}).Matches(AuditEvent{Operation: OperationHostUpdate}) {
+			t.Error("expected no match")
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/util"
+	"github.com/rs/zerolog/log"
+)
+
+// AuditService records the changes made through the admin API in an in-memory ring buffer and, optionally, a JSONL
+// file. New events are broadcast to subscribers.
+type AuditService struct {
+	ringBuffer  *util.RingBuffer[AuditEvent]
+	broadcaster *util.Broadcaster[AuditEvent]
+	file        *os.File
+	fileMu      sync.Mutex
+}
+
+// NewAuditService creates a new AuditService from AppArguments.
+// Returns nil if the audit log is disabled (bufferSize <= 0).
+func NewAuditService(args *config.AppArguments) (*AuditService, error) {
+	if args.AuditLogBufferSize <= 0 {
+		return nil, nil
+	}
+
+	ringBuffer, err := util.NewRingBuffer[AuditEvent](args.AuditLogBufferSize)
+
+	if err != nil {
+		return nil, fmt.Errorf("error while creating the audit log buffer: %v", err)
+	}
+
+	service := &AuditService{
+		ringBuffer:  ringBuffer,
+		broadcaster: &util.Broadcaster[AuditEvent]{},
+	}
+
+	// unlike the traffic log, a file that can't be opened stops the server, as the changes would go unaccounted for
+	if len(args.AuditLogFile) > 0 {
+		file, err := os.OpenFile(args.AuditLogFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+
+		if err != nil {
+			return nil, fmt.Errorf("error while opening the audit log file: %v", err)
+		}
+
+		service.file = file
+	}
+
+	return service, nil
+}
+
+// Record adds an event for the operation made by the caller to the log, writes it to the file, and broadcasts it to
+// subscribers. The states before and after the change are kept as JSON, so later changes don't alter them.
+func (a *AuditService) Record(caller Caller, operation, host string, before, after any) {
+	if a == nil {
+		return
+	}
+
+	event := AuditEvent{
+		Timestamp:  time.Now(),
+		UUID:       caller.UUID,
+		Identity:   caller.Identity,
+		RemoteAddr: caller.RemoteAddr,
+		Operation:  operation,
+		Host:       host,
+		Before:     snapshot(before, caller.UUID),
+		After:      snapshot(after, caller.UUID),
+	}
+
+	a.ringBuffer.Add(event)
+	a.persist(event)
+	a.broadcaster.PublishAsync(event, event.UUID)
+
+	logEvent := log.Info().
+		Str("uuid", event.UUID).
+		Str("operation", operation).
+		Str("host", host)
+
+	if event.Identity != nil {
+		logEvent = logEvent.Str("identity", event.Identity.Name)
+	}
+
+	logEvent.Msg("admin change audited")
+}
+
+// persist appends the event to the file, if any
+func (a *AuditService) persist(event AuditEvent) {
+	if a.file == nil {
+		return
+	}
+
+	data, err := json.Marshal(event)
+
+	if err == nil {
+		a.fileMu.Lock()
+		_, err = a.file.Write(append(data, '\n'))
+		a.fileMu.Unlock()
+	}
+
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("uuid", event.UUID).
+			Str("path", a.file.Name()).
+			Msg("error while writing the audit event to the file")
+	}
+}
+
+// Query returns the most recent events matching the filters, from the most recent to the oldest. A limit of 0
+// returns all of them.
+func (a *AuditService) Query(filters AuditFilters, limit int) []AuditEvent {
+	if a == nil {
+		return []AuditEvent{}
+	}
+
+	all := a.ringBuffer.GetAll()
+	events := make([]AuditEvent, 0)
+
+	for i := len(all) - 1; i >= 0 && (limit <= 0 || len(events) < limit); i-- {
+		if filters.Matches(all[i]) {
+			events = append(events, all[i])
+		}
+	}
+
+	return events
+}
+
+// Subscribe returns a channel that receives the new events matching the filters.
+// Returns nil if the service is nil.
+func (a *AuditService) Subscribe(subscriberID string, filters AuditFilters) <-chan AuditEvent {
+	if a == nil {
+		return nil
+	}
+
+	return a.broadcaster.Subscribe(subscriberID, filters.Matches)
+}
+
+// Unsubscribe removes a subscriber.
+func (a *AuditService) Unsubscribe(subscriberID string) {
+	if a == nil {
+		return
+	}
+
+	a.broadcaster.Unsubscribe(subscriberID)
+}
+
+// SubscriberCount returns the number of current subscribers
+func (a *AuditService) SubscriberCount() int {
+	if a == nil {
+		return 0
+	}
+
+	return a.broadcaster.SubscriberCount()
+}
+
+// Close closes the audit log file, if any.
+func (a *AuditService) Close() error {
+	if a == nil || a.file == nil {
+		return nil
+	}
+
+	a.fileMu.Lock()
+	defer a.fileMu.Unlock()
+
+	return a.file.Close()
+}
+
+// snapshot returns the given state as JSON, nil when there is no state, as for a deleted host
+func snapshot(state any, uuid string) json.RawMessage {
+	if state == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(state)
+
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("uuid", uuid).
+			Msg("error while marshalling the audited state")
+
+		return nil
+	}
+
+	// a nil pointer isn't a nil interface, but is still no state
+	if string(data) == "null" {
+		return nil
+	}
+
+	return data
+}
@@ -0,0 +1,184 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/auth"
+)
+
+type hostState struct {
+	Status int `json:"status"`
+}
+
+func TestNewAuditService(t *testing.T) {
+	t.Run("returns nil when disabled", func(t *testing.T) {
+		service, err := NewAuditService(&config.AppArguments{AuditLogBufferSize: 0})
+
+		if err != nil || service != nil {
+			t.Errorf("expected nil service and error, got %v and %v", service, err)
+		}
+	})
+
+	t.Run("returns an error when the file can't be opened", func(t *testing.T) {
+		_, err := NewAuditService(&config.AppArguments{
+			AuditLogBufferSize: 10,
+			AuditLogFile:       filepath.Join(t.TempDir(), "missing", "audit.jsonl"),
+		})
+
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestAuditService_Record(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "audit.jsonl")
+	service, err := NewAuditService(&config.AppArguments{AuditLogBufferSize: 10, AuditLogFile: file})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() { _ = service.Close() })
+
+	caller := Caller{UUID: "uuid-1", Identity: &auth.Identity{Name: "ci"}, RemoteAddr: "10.0.0.1"}
+	state := &hostState{Status: 200}
+
+	service.Record(caller, OperationStatusesUpdate, "example.com", state, &hostState{Status: 500})
+
+	// the snapshot is taken when recorded, so later changes don't alter it
+	state.Status = 404
+
+	events := service.Query(AuditFilters{}, 0)
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+
+	event := events[0]
+
+	if event.UUID != "uuid-1" || event.Identity.Name != "ci" || event.RemoteAddr != "10.0.0.1" ||
+		event.Operation != OperationStatusesUpdate || event.Host != "example.com" {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	if string(event.Before) != `{"status":200}` || string(event.After) != `{"status":500}` {
+		t.Errorf("unexpected snapshots %s and %s", event.Before, event.After)
+	}
+
+	t.Run("omits the missing states", func(t *testing.T) {
+		var deleted *hostState
+
+		service.Record(caller, OperationHostDelete, "example.com", state, deleted)
+
+		if after := service.Query(AuditFilters{}, 1)[0].After; after != nil {
+			t.Errorf("expected no state after, got %s", after)
+		}
+	})
+
+	t.Run("appends the events to the file", func(t *testing.T) {
+		f, err := os.Open(file)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		defer f.Close()
+
+		var operations []string
+		scanner := bufio.NewScanner(f)
+
+		for scanner.Scan() {
+			var persisted AuditEvent
+
+			if err := json.Unmarshal(scanner.Bytes(), &persisted); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			operations = append(operations, persisted.Operation)
+		}
+
+		if len(operations) != 2 || operations[0] != OperationStatusesUpdate || operations[1] != OperationHostDelete {
+			t.Errorf("unexpected persisted operations %v", operations)
+		}
+	})
+}
+
+func TestAuditService_Query(t *testing.T) {
+	service, err := NewAuditService(&config.AppArguments{AuditLogBufferSize: 2})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	service.Record(Caller{UUID: "1"}, OperationHostUpdate, "a.com", nil, nil)
+	service.Record(Caller{UUID: "2"}, OperationMockUpdate, "b.com", nil, nil)
+	service.Record(Caller{UUID: "3"}, OperationHostDelete, "a.com", nil, nil)
+
+	t.Run("returns the most recent events first, within the buffer", func(t *testing.T) {
+		events := service.Query(AuditFilters{}, 0)
+
+		if len(events) != 2 || events[0].UUID != "3" || events[1].UUID != "2" {
+			t.Errorf("unexpected events %+v", events)
+		}
+	})
+
+	t.Run("filters and limits the events", func(t *testing.T) {
+		if events := service.Query(AuditFilters{Host: "a.com"}, 0); len(events) != 1 || events[0].UUID != "3" {
+			t.Errorf("unexpected events %+v", events)
+		}
+
+		if events := service.Query(AuditFilters{}, 1); len(events) != 1 || events[0].UUID != "3" {
+			t.Errorf("unexpected events %+v", events)
+		}
+	})
+}
+
+func TestAuditService_Subscribe(t *testing.T) {
+	service, err := NewAuditService(&config.AppArguments{AuditLogBufferSize: 10})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ch := service.Subscribe("test", AuditFilters{Operation: "mock"})
+	defer service.Unsubscribe("test")
+
+	service.Record(Caller{UUID: "1"}, OperationHostUpdate, "a.com", nil, nil)
+	service.Record(Caller{UUID: "2"}, OperationMockDelete, "a.com", nil, nil)
+
+	select {
+	case event := <-ch:
+		if event.UUID != "2" {
+			t.Errorf("expected the mock event, got %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the event")
+	}
+}
+
+func TestAuditService_nil(t *testing.T) {
+	var service *AuditService
+
+	service.Record(Caller{}, OperationHostUpdate, "a.com", nil, nil)
+
+	if events := service.Query(AuditFilters{}, 0); len(events) != 0 {
+		t.Errorf("expected no events, got %+v", events)
+	}
+
+	if ch := service.Subscribe("test", AuditFilters{}); ch != nil {
+		t.Error("expected a nil channel")
+	}
+
+	service.Unsubscribe("test")
+
+	if err := service.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
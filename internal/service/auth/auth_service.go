@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/rs/zerolog/log"
+)
+
+// AccessTokenParam is the query parameter carrying the bearer token of the clients that can't send headers,
+// such as the browsers' EventSource
+const AccessTokenParam = "access_token"
+
+// Role tells what an identity is allowed to do on the admin API
+type Role string
+
+const (
+	// RoleReadOnly can only read, e.g. browse the traffic log and the mocks
+	RoleReadOnly Role = "read-only"
+	// RoleReadWrite can also change the mocks, the hosts config and the rest of the state
+	RoleReadWrite Role = "read-write"
+)
+
+// methods an identity was authenticated with
+const (
+	MethodNone              = "none"
+	MethodToken             = "token"
+	MethodBasic             = "basic"
+	MethodClientCertificate = "client-certificate"
+)
+
+// Identity is a caller of the admin API, authenticated by one of the enabled methods
+type Identity struct {
+	Name   string `json:"name"`
+	Method string `json:"method"`
+	Role   Role   `json:"role"`
+}
+
+// CanWrite tells whether the identity can change the state of the mock server
+func (i *Identity) CanWrite() bool {
+	return i != nil && i.Role == RoleReadWrite
+}
+
+// AuthService authenticates the callers of the admin API with bearer tokens, htpasswd users or client certificates.
+// A nil AuthService means authentication is disabled.
+type AuthService struct {
+	tokens    []token
+	users     map[string]string
+	clientCAs *x509.CertPool
+	writers   map[string]bool
+}
+
+// NewAuthService creates a new AuthService from AppArguments.
+// Returns nil if no authentication method is configured.
+func NewAuthService(args *config.AppArguments) (*AuthService, error) {
+	if len(args.AdminTokensFile) == 0 && len(args.AdminHtpasswdFile) == 0 && len(args.AdminClientCAFile) == 0 {
+		if len(args.AdminWriters) > 0 {
+			return nil, errors.New("--admin-writers requires --admin-tokens-file, --admin-htpasswd or --admin-client-ca")
+		}
+
+		return nil, nil
+	}
+
+	service := &AuthService{
+		writers: make(map[string]bool),
+	}
+
+	if len(args.AdminTokensFile) > 0 {
+		tokens, err := loadTokens(args.AdminTokensFile)
+
+		if err != nil {
+			return nil, err
+		}
+
+		service.tokens = tokens
+	}
+
+	if len(args.AdminHtpasswdFile) > 0 {
+		users, err := loadHtpasswd(args.AdminHtpasswdFile)
+
+		if err != nil {
+			return nil, err
+		}
+
+		service.users = users
+	}
+
+	if len(args.AdminClientCAFile) > 0 {
+		if !args.AdminTLS {
+			return nil, errors.New("--admin-client-ca requires --admin-tls")
+		}
+
+		clientCAs, err := loadClientCAs(args.AdminClientCAFile)
+
+		if err != nil {
+			return nil, err
+		}
+
+		service.clientCAs = clientCAs
+	}
+
+	for _, writer := range strings.Split(args.AdminWriters, ",") {
+		if writer = strings.TrimSpace(writer); len(writer) > 0 {
+			service.writers[writer] = true
+		}
+	}
+
+	log.Info().
+		Int("tokens", len(service.tokens)).
+		Int("users", len(service.users)).
+		Bool("client_certificates", service.clientCAs != nil).
+		Int("writers", len(service.writers)).
+		Msg("admin authentication enabled")
+
+	return service, nil
+}
+
+// Authenticate returns the identity of the caller, or nil if it didn't send valid credentials.
+// A verified client certificate comes first, then the Authorization header, then the access token parameter.
+func (a *AuthService) Authenticate(request *http.Request) *Identity {
+	if a == nil {
+		return nil
+	}
+
+	if a.clientCAs != nil && request.TLS != nil && len(request.TLS.VerifiedChains) > 0 {
+		return a.newIdentity(request.TLS.VerifiedChains[0][0].Subject.CommonName, MethodClientCertificate)
+	}
+
+	if user, password, ok := request.BasicAuth(); ok {
+		return a.authenticateUser(user, password)
+	}
+
+	if value, found := strings.CutPrefix(request.Header.Get("Authorization"), "Bearer "); found {
+		return a.authenticateToken(strings.TrimSpace(value))
+	}
+
+	if value := request.URL.Query().Get(AccessTokenParam); len(value) > 0 {
+		return a.authenticateToken(value)
+	}
+
+	return nil
+}
+
+// ClientCAs returns the CAs the client certificates are checked against, nil if they aren't accepted
+func (a *AuthService) ClientCAs() *x509.CertPool {
+	if a == nil {
+		return nil
+	}
+
+	return a.clientCAs
+}
+
+// Challenge returns the WWW-Authenticate header sent along with the 401 responses. Basic is preferred when
+// enabled, so browsers prompt for the user and password.
+func (a *AuthService) Challenge() string {
+	if a != nil && a.users != nil {
+		return `Basic realm="go-mock-server admin", charset="UTF-8"`
+	}
+
+	return `Bearer realm="go-mock-server admin"`
+}
+
+func (a *AuthService) authenticateUser(user, password string) *Identity {
+	hash, exists := a.users[user]
+
+	if !exists || !verifyPassword(hash, password) {
+		return nil
+	}
+
+	return a.newIdentity(user, MethodBasic)
+}
+
+func (a *AuthService) authenticateToken(value string) *Identity {
+	var name string
+
+	// comparing every token, so the time taken doesn't tell which one is close
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare(t.value, []byte(value)) == 1 {
+			name = t.name
+		}
+	}
+
+	if len(name) == 0 {
+		return nil
+	}
+
+	return a.newIdentity(name, MethodToken)
+}
+
+// newIdentity gives the read-write role to the writers, or to everyone when no writer is configured
+func (a *AuthService) newIdentity(name, method string) *Identity {
+	role := RoleReadOnly
+
+	if len(a.writers) == 0 || a.writers[name] {
+		role = RoleReadWrite
+	}
+
+	return &Identity{
+		Name:   name,
+		Method: method,
+		Role:   role,
+	}
+}
@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
+)
+
+// caPEM returns a self-signed CA certificate, PEM encoded
+func caPEM(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func newTestAuthService(t *testing.T, writers string) *AuthService {
+	t.Helper()
+
+	service, err := NewAuthService(&config.AppArguments{
+		AdminTokensFile:   writeFile(t, "tokens", "ci:ci-secret", "viewer:viewer-secret"),
+		AdminHtpasswdFile: writeFile(t, "htpasswd", "alice:"+bcryptHash(t, "wonderland")),
+		AdminWriters:      writers,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return service
+}
+
+func TestNewAuthService(t *testing.T) {
+	t.Run("returns nil when no method is configured", func(t *testing.T) {
+		service, err := NewAuthService(&config.AppArguments{})
+
+		if err != nil || service != nil {
+			t.Errorf("expected nil service and error, got %v and %v", service, err)
+		}
+	})
+
+	t.Run("rejects the writers without any method", func(t *testing.T) {
+		if _, err := NewAuthService(&config.AppArguments{AdminWriters: "ci"}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("requires the admin TLS for the client certificates", func(t *testing.T) {
+		caFile := writeFile(t, "ca.pem", caPEM(t))
+
+		if _, err := NewAuthService(&config.AppArguments{AdminClientCAFile: caFile}); err == nil {
+			t.Error("expected an error without --admin-tls")
+		}
+
+		service, err := NewAuthService(&config.AppArguments{AdminClientCAFile: caFile, AdminTLS: true})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if service.ClientCAs() == nil {
+			t.Error("expected the client CAs to be loaded")
+		}
+	})
+}
+
+func TestAuthService_Authenticate(t *testing.T) {
+	service := newTestAuthService(t, "ci")
+
+	tests := []struct {
+		name         string
+		prepare      func(request *http.Request)
+		expectedName string
+		expectedRole Role
+	}{
+		{
+			name:         "bearer token of a writer",
+			prepare:      func(r *http.Request) { r.Header.Set("Authorization", "Bearer ci-secret") },
+			expectedName: "ci",
+			expectedRole: RoleReadWrite,
+		},
+		{
+			name:         "bearer token of a reader",
+			prepare:      func(r *http.Request) { r.Header.Set("Authorization", "Bearer viewer-secret") },
+			expectedName: "viewer",
+			expectedRole: RoleReadOnly,
+		},
+		{
+			name:         "access token parameter",
+			prepare:      func(r *http.Request) { r.URL.RawQuery = AccessTokenParam + "=viewer-secret" },
+			expectedName: "viewer",
+			expectedRole: RoleReadOnly,
+		},
+		{
+			name:         "htpasswd user",
+			prepare:      func(r *http.Request) { r.SetBasicAuth("alice", "wonderland") },
+			expectedName: "alice",
+			expectedRole: RoleReadOnly,
+		},
+		{
+			name:    "unknown token",
+			prepare: func(r *http.Request) { r.Header.Set("Authorization", "Bearer unknown") },
+		},
+		{
+			name:    "wrong password",
+			prepare: func(r *http.Request) { r.SetBasicAuth("alice", "wrong") },
+		},
+		{
+			name:    "no credentials",
+			prepare: func(r *http.Request) {},
+		},
+		{
+			name: "client certificate without client CAs",
+			prepare: func(r *http.Request) {
+				r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "ci"}}}}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/api/v1/mocks", nil)
+			tt.prepare(request)
+
+			identity := service.Authenticate(request)
+
+			if len(tt.expectedName) == 0 {
+				if identity != nil {
+					t.Errorf("expected no identity, got %+v", identity)
+				}
+
+				return
+			}
+
+			if identity == nil {
+				t.Fatal("expected an identity")
+			}
+
+			if identity.Name != tt.expectedName || identity.Role != tt.expectedRole {
+				t.Errorf("expected %s with the %s role, got %+v", tt.expectedName, tt.expectedRole, identity)
+			}
+		})
+	}
+}
+
+func TestAuthService_Authenticate_clientCertificate(t *testing.T) {
+	service, err := NewAuthService(&config.AppArguments{
+		AdminClientCAFile: writeFile(t, "ca.pem", caPEM(t)),
+		AdminTLS:          true,
+		AdminWriters:      "ci",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/mocks", nil)
+	request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "ci"}}}}}
+
+	identity := service.Authenticate(request)
+
+	if identity == nil || identity.Name != "ci" || identity.Method != MethodClientCertificate || !identity.CanWrite() {
+		t.Errorf("expected ci with the read-write role, got %+v", identity)
+	}
+
+	// an unverified certificate doesn't authenticate
+	request.TLS = &tls.ConnectionState{}
+
+	if identity := service.Authenticate(request); identity != nil {
+		t.Errorf("expected no identity, got %+v", identity)
+	}
+}
+
+func TestAuthService_roles(t *testing.T) {
+	t.Run("everyone can write without writers", func(t *testing.T) {
+		service := newTestAuthService(t, "")
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/mocks", nil)
+		request.Header.Set("Authorization", "Bearer viewer-secret")
+
+		if identity := service.Authenticate(request); !identity.CanWrite() {
+			t.Errorf("expected the read-write role, got %+v", identity)
+		}
+	})
+
+	t.Run("nil identity can't write", func(t *testing.T) {
+		var identity *Identity
+
+		if identity.CanWrite() {
+			t.Error("expected a nil identity not to write")
+		}
+	})
+}
+
+func TestAuthService_Challenge(t *testing.T) {
+	if challenge := newTestAuthService(t, "").Challenge(); !strings.HasPrefix(challenge, "Basic ") {
+		t.Errorf("expected a Basic challenge with htpasswd users, got %s", challenge)
+	}
+
+	service, err := NewAuthService(&config.AppArguments{AdminTokensFile: writeFile(t, "tokens", "ci:ci-secret")})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if challenge := service.Challenge(); !strings.HasPrefix(challenge, "Bearer ") {
+		t.Errorf("expected a Bearer challenge with tokens only, got %s", challenge)
+	}
+}
@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const shaPrefix = "{SHA}"
+
+// token is a bearer token accepted by the admin API, along with the name it authenticates as
+type token struct {
+	name  string
+	value []byte
+}
+
+// loadTokens reads the tokens file, made of name:token lines. Empty lines and lines starting with # are ignored.
+func loadTokens(path string) ([]token, error) {
+	tokens := make([]token, 0)
+	names := make(map[string]bool)
+
+	err := readCredentialLines(path, func(lineNumber int, name, value string) error {
+		if names[name] {
+			return fmt.Errorf("line %d: duplicate token name %q", lineNumber, name)
+		}
+
+		names[name] = true
+		tokens = append(tokens, token{name: name, value: []byte(value)})
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error while reading the tokens file: %v", err)
+	}
+
+	return tokens, nil
+}
+
+// loadHtpasswd reads the htpasswd file, made of user:hash lines. Only the bcrypt (htpasswd -B) and SHA1 (htpasswd -s)
+// hashes are supported.
+func loadHtpasswd(path string) (map[string]string, error) {
+	users := make(map[string]string)
+
+	err := readCredentialLines(path, func(lineNumber int, user, hash string) error {
+		if _, exists := users[user]; exists {
+			return fmt.Errorf("line %d: duplicate user %q", lineNumber, user)
+		}
+
+		if !isBcryptHash(hash) && !strings.HasPrefix(hash, shaPrefix) {
+			return fmt.Errorf("line %d: unsupported hash for user %q, use bcrypt (htpasswd -B) or SHA1 (htpasswd -s)", lineNumber, user)
+		}
+
+		users[user] = hash
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error while reading the htpasswd file: %v", err)
+	}
+
+	return users, nil
+}
+
+// loadClientCAs reads the PEM encoded CA certificates the client certificates are checked against
+func loadClientCAs(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("error while reading the client CA file: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificate found in the client CA file %s", path)
+	}
+
+	return pool, nil
+}
+
+// readCredentialLines calls fn with both parts of every key:value line of the file
+func readCredentialLines(path string, fn func(lineNumber int, key, value string) error) error {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+
+		if !found || len(key) == 0 || len(value) == 0 {
+			return fmt.Errorf("line %d: expected key:value", lineNumber)
+		}
+
+		if err := fn(lineNumber, key, value); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// verifyPassword tells whether the password matches the htpasswd hash
+func verifyPassword(hash, password string) bool {
+	if isBcryptHash(hash) {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	}
+
+	if encoded, found := strings.CutPrefix(hash, shaPrefix); found {
+		sum := sha1.Sum([]byte(password))
+		expected := base64.StdEncoding.EncodeToString(sum[:])
+
+		return subtle.ConstantTimeCompare([]byte(encoded), []byte(expected)) == 1
+	}
+
+	return false
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
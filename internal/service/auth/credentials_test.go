@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// writeFile writes the lines to a file of the test directory, and returns its path
+func writeFile(t *testing.T, name string, lines ...string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return path
+}
+
+func bcryptHash(t *testing.T, password string) string {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return string(hash)
+}
+
+func TestLoadTokens(t *testing.T) {
+	t.Run("reads the tokens, skipping comments and empty lines", func(t *testing.T) {
+		tokens, err := loadTokens(writeFile(t, "tokens", "# shared with CI", "ci:ci-secret", "", "viewer:viewer:secret"))
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(tokens) != 2 {
+			t.Fatalf("expected 2 tokens, got %d", len(tokens))
+		}
+
+		if tokens[0].name != "ci" || string(tokens[0].value) != "ci-secret" {
+			t.Errorf("unexpected token %+v", tokens[0])
+		}
+
+		// only the first colon separates the name from the token
+		if tokens[1].name != "viewer" || string(tokens[1].value) != "viewer:secret" {
+			t.Errorf("unexpected token %+v", tokens[1])
+		}
+	})
+
+	t.Run("rejects invalid files", func(t *testing.T) {
+		for name, lines := range map[string][]string{
+			"missing token":   {"ci:"},
+			"missing colon":   {"ci-secret"},
+			"duplicate names": {"ci:a", "ci:b"},
+		} {
+			if _, err := loadTokens(writeFile(t, "tokens", lines...)); err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+
+		if _, err := loadTokens(filepath.Join(t.TempDir(), "missing")); err == nil {
+			t.Error("expected an error for a missing file")
+		}
+	})
+}
+
+func TestLoadHtpasswd(t *testing.T) {
+	t.Run("reads the bcrypt and SHA1 users", func(t *testing.T) {
+		users, err := loadHtpasswd(writeFile(t, "htpasswd",
+			"alice:"+bcryptHash(t, "wonderland"),
+			// htpasswd -nbs bob builder
+			"bob:{SHA}9SMYoF5RilWWASry7TjeaKwmpGg=",
+		))
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !verifyPassword(users["alice"], "wonderland") || verifyPassword(users["alice"], "wrong") {
+			t.Error("expected only the password of alice to match")
+		}
+
+		if !verifyPassword(users["bob"], "builder") || verifyPassword(users["bob"], "wrong") {
+			t.Error("expected only the password of bob to match")
+		}
+	})
+
+	t.Run("rejects the unsupported hashes", func(t *testing.T) {
+		_, err := loadHtpasswd(writeFile(t, "htpasswd", "carol:$apr1$salt$hash"))
+
+		if err == nil || !strings.Contains(err.Error(), "unsupported hash") {
+			t.Errorf("expected an unsupported hash error, got %v", err)
+		}
+	})
+}
+
+func TestLoadClientCAs(t *testing.T) {
+	if _, err := loadClientCAs(writeFile(t, "ca.pem", "not a certificate")); err == nil {
+		t.Error("expected an error for a file without certificates")
+	}
+}
@@ -3,4 +3,20 @@ package cache
 type CacheService interface {
 	Get(cacheKey, uuid string) (*[]byte, bool)
 	Set(cacheKey string, data *[]byte, uuid string)
+	Delete(cacheKey, uuid string) bool
+	DeleteFunc(match func(cacheKey string) bool, uuid string) int
+	Clear(uuid string) int
+	Stats() CacheStats
+}
+
+// CacheStats holds the usage of a cache
+type CacheStats struct {
+	Entries    int   `json:"entries"`
+	Bytes      int64 `json:"bytes"`
+	Hits       int64 `json:"hits"`
+	Misses     int64 `json:"misses"`
+	Evictions  int64 `json:"evictions"`
+	MaxEntries int   `json:"max_entries"`
+	MaxBytes   int64 `json:"max_bytes"`
+	TTLSeconds int64 `json:"ttl_seconds"`
 }
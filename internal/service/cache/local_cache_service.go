@@ -1,41 +1,246 @@
 package cache
 
 import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
 	"github.com/rs/zerolog/log"
 )
 
+// InMemoryCacheService is a least recently used cache, bounded by its number of entries and their size. Entries
+// also expire once older than the TTL. A zero limit means no limit.
 type InMemoryCacheService struct {
-	cache map[string]*[]byte
+	mu         sync.Mutex
+	cache      map[string]*list.Element
+	recency    *list.List // most recently used first
+	bytes      int64
+	maxEntries int
+	maxBytes   int64
+	ttl        time.Duration
+	hits       int64
+	misses     int64
+	evictions  int64
+	now        func() time.Time
+}
+
+type cacheEntry struct {
+	key      string
+	data     *[]byte
+	storedAt time.Time
+}
+
+func (e *cacheEntry) size() int64 {
+	if e.data == nil {
+		return 0
+	}
+
+	return int64(len(*e.data))
 }
 
 func (l *InMemoryCacheService) Get(cacheKey, uuid string) (*[]byte, bool) {
-	data, exists := l.cache[cacheKey]
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, exists := l.cache[cacheKey]
+
+	if !exists {
+		l.misses++
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+
+	if l.isExpired(entry) {
+		l.remove(element)
+		l.misses++
 
-	if exists {
 		log.Info().
 			Str("uuid", uuid).
 			Str("cache_key", cacheKey).
-			Msg("data retrieved from cache")
+			Msg("cache data expired")
+
+		return nil, false
 	}
 
-	return data, exists
+	l.recency.MoveToFront(element)
+	l.hits++
+
+	log.Info().
+		Str("uuid", uuid).
+		Str("cache_key", cacheKey).
+		Msg("data retrieved from cache")
+
+	return entry.data, true
 }
 
 func (l *InMemoryCacheService) Set(cacheKey string, data *[]byte, uuid string) {
-	if l.cache == nil {
-		l.cache = make(map[string]*[]byte)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.ensureInit()
+
+	if element, exists := l.cache[cacheKey]; exists {
+		l.remove(element)
 	}
 
-	l.cache[cacheKey] = data
+	entry := &cacheEntry{key: cacheKey, data: data, storedAt: l.now()}
+
+	// an entry bigger than the whole cache would only evict everything else before being evicted itself
+	if l.maxBytes > 0 && entry.size() > l.maxBytes {
+		log.Info().
+			Str("uuid", uuid).
+			Str("cache_key", cacheKey).
+			Int64("size", entry.size()).
+			Msg("data too big to be stored in cache")
+
+		return
+	}
+
+	l.cache[cacheKey] = l.recency.PushFront(entry)
+	l.bytes += entry.size()
+
+	evicted := l.evict()
 
 	log.Info().
 		Str("uuid", uuid).
 		Str("cache_key", cacheKey).
+		Int("evicted", evicted).
 		Msg("data stored in cache")
 }
 
-func NewInMemoryCacheService() *InMemoryCacheService {
+// Delete removes an entry, returning whether it was cached
+func (l *InMemoryCacheService) Delete(cacheKey, uuid string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, exists := l.cache[cacheKey]
+
+	if !exists {
+		return false
+	}
+
+	l.remove(element)
+
+	log.Info().
+		Str("uuid", uuid).
+		Str("cache_key", cacheKey).
+		Msg("data removed from cache")
+
+	return true
+}
+
+// DeleteFunc removes every entry whose key matches, returning how many were removed
+func (l *InMemoryCacheService) DeleteFunc(match func(cacheKey string) bool, uuid string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	removed := 0
+
+	for cacheKey, element := range l.cache {
+		if !match(cacheKey) {
+			continue
+		}
+
+		l.remove(element)
+		removed++
+
+		log.Info().
+			Str("uuid", uuid).
+			Str("cache_key", cacheKey).
+			Msg("data removed from cache")
+	}
+
+	return removed
+}
+
+// Clear removes every entry, returning how many were removed
+func (l *InMemoryCacheService) Clear(uuid string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	removed := len(l.cache)
+
+	l.cache = make(map[string]*list.Element)
+	l.recency = list.New()
+	l.bytes = 0
+
+	log.Info().
+		Str("uuid", uuid).
+		Int("removed", removed).
+		Msg("cache cleared")
+
+	return removed
+}
+
+func (l *InMemoryCacheService) Stats() CacheStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return CacheStats{
+		Entries:    len(l.cache),
+		Bytes:      l.bytes,
+		Hits:       l.hits,
+		Misses:     l.misses,
+		Evictions:  l.evictions,
+		MaxEntries: l.maxEntries,
+		MaxBytes:   l.maxBytes,
+		TTLSeconds: int64(l.ttl.Seconds()),
+	}
+}
+
+func (l *InMemoryCacheService) ensureInit() {
+	if l.cache == nil {
+		l.cache = make(map[string]*list.Element)
+	}
+
+	if l.recency == nil {
+		l.recency = list.New()
+	}
+
+	if l.now == nil {
+		l.now = time.Now
+	}
+}
+
+func (l *InMemoryCacheService) isExpired(entry *cacheEntry) bool {
+	return l.ttl > 0 && l.now().Sub(entry.storedAt) >= l.ttl
+}
+
+// evict removes the least recently used entries until the cache is within its limits, returning how many were
+// removed
+func (l *InMemoryCacheService) evict() int {
+	evicted := 0
+
+	for l.isOverLimits() {
+		l.remove(l.recency.Back())
+		l.evictions++
+		evicted++
+	}
+
+	return evicted
+}
+
+func (l *InMemoryCacheService) isOverLimits() bool {
+	return (l.maxEntries > 0 && len(l.cache) > l.maxEntries) || (l.maxBytes > 0 && l.bytes > l.maxBytes)
+}
+
+func (l *InMemoryCacheService) remove(element *list.Element) {
+	entry := element.Value.(*cacheEntry)
+
+	l.recency.Remove(element)
+	delete(l.cache, entry.key)
+	l.bytes -= entry.size()
+}
+
+func NewInMemoryCacheService(appArguments *config.AppArguments) *InMemoryCacheService {
 	return &InMemoryCacheService{
-		cache: map[string]*[]byte{},
+		cache:      map[string]*list.Element{},
+		recency:    list.New(),
+		maxEntries: appArguments.CacheMaxEntries,
+		maxBytes:   appArguments.CacheMaxBytes,
+		ttl:        appArguments.CacheTTL,
+		now:        time.Now,
 	}
 }
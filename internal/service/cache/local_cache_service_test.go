@@ -1,12 +1,17 @@
 package cache
 
 import (
+	"fmt"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
 )
 
 func TestNewInMemoryCacheService(t *testing.T) {
 	t.Run("creates new cache service with initialized map", func(t *testing.T) {
-		service := NewInMemoryCacheService()
+		service := NewInMemoryCacheService(&config.AppArguments{})
 
 		if service == nil {
 			t.Fatal("NewInMemoryCacheService should return non-nil service")
@@ -24,7 +29,7 @@ func TestNewInMemoryCacheService(t *testing.T) {
 
 func TestInMemoryCacheService_Set(t *testing.T) {
 	t.Run("stores data in cache", func(t *testing.T) {
-		service := NewInMemoryCacheService()
+		service := NewInMemoryCacheService(&config.AppArguments{})
 		testData := []byte("test data")
 		cacheKey := "test-key"
 		uuid := "test-uuid"
@@ -36,7 +41,7 @@ func TestInMemoryCacheService_Set(t *testing.T) {
 			t.Errorf("expected cache to have 1 item, got %d", len(service.cache))
 		}
 
-		storedData, exists := service.cache[cacheKey]
+		storedData, exists := service.Get(cacheKey, uuid)
 		if !exists {
 			t.Error("data should be stored in cache")
 		}
@@ -67,7 +72,7 @@ func TestInMemoryCacheService_Set(t *testing.T) {
 		}
 
 		// Verify data was stored
-		storedData, exists := service.cache[cacheKey]
+		storedData, exists := service.Get(cacheKey, uuid)
 		if !exists {
 			t.Error("data should be stored in cache")
 		}
@@ -78,7 +83,7 @@ func TestInMemoryCacheService_Set(t *testing.T) {
 	})
 
 	t.Run("overwrites existing data", func(t *testing.T) {
-		service := NewInMemoryCacheService()
+		service := NewInMemoryCacheService(&config.AppArguments{})
 		cacheKey := "test-key"
 		uuid := "test-uuid"
 
@@ -91,7 +96,7 @@ func TestInMemoryCacheService_Set(t *testing.T) {
 		service.Set(cacheKey, &newData, uuid)
 
 		// Verify new data overwrote old data
-		storedData, exists := service.cache[cacheKey]
+		storedData, exists := service.Get(cacheKey, uuid)
 		if !exists {
 			t.Error("data should exist in cache")
 		}
@@ -107,7 +112,7 @@ func TestInMemoryCacheService_Set(t *testing.T) {
 	})
 
 	t.Run("handles multiple cache entries", func(t *testing.T) {
-		service := NewInMemoryCacheService()
+		service := NewInMemoryCacheService(&config.AppArguments{})
 		uuid := "test-uuid"
 
 		// Store multiple entries
@@ -128,7 +133,7 @@ func TestInMemoryCacheService_Set(t *testing.T) {
 		}
 
 		for key, expectedData := range entries {
-			storedData, exists := service.cache[key]
+			storedData, exists := service.Get(key, uuid)
 			if !exists {
 				t.Errorf("key '%s' should exist in cache", key)
 				continue
@@ -142,14 +147,14 @@ func TestInMemoryCacheService_Set(t *testing.T) {
 	})
 
 	t.Run("handles nil data pointer", func(t *testing.T) {
-		service := NewInMemoryCacheService()
+		service := NewInMemoryCacheService(&config.AppArguments{})
 		cacheKey := "test-key"
 		uuid := "test-uuid"
 
 		service.Set(cacheKey, nil, uuid)
 
 		// Verify nil was stored
-		storedData, exists := service.cache[cacheKey]
+		storedData, exists := service.Get(cacheKey, uuid)
 		if !exists {
 			t.Error("nil data should be stored in cache")
 		}
@@ -160,7 +165,7 @@ func TestInMemoryCacheService_Set(t *testing.T) {
 	})
 
 	t.Run("handles empty data", func(t *testing.T) {
-		service := NewInMemoryCacheService()
+		service := NewInMemoryCacheService(&config.AppArguments{})
 		cacheKey := "test-key"
 		uuid := "test-uuid"
 		emptyData := []byte{}
@@ -168,7 +173,7 @@ func TestInMemoryCacheService_Set(t *testing.T) {
 		service.Set(cacheKey, &emptyData, uuid)
 
 		// Verify empty data was stored
-		storedData, exists := service.cache[cacheKey]
+		storedData, exists := service.Get(cacheKey, uuid)
 		if !exists {
 			t.Error("empty data should be stored in cache")
 		}
@@ -185,7 +190,7 @@ func TestInMemoryCacheService_Set(t *testing.T) {
 
 func TestInMemoryCacheService_Get(t *testing.T) {
 	t.Run("retrieves existing data", func(t *testing.T) {
-		service := NewInMemoryCacheService()
+		service := NewInMemoryCacheService(&config.AppArguments{})
 		testData := []byte("test data")
 		cacheKey := "test-key"
 		uuid := "test-uuid"
@@ -210,7 +215,7 @@ func TestInMemoryCacheService_Get(t *testing.T) {
 	})
 
 	t.Run("returns false for non-existent key", func(t *testing.T) {
-		service := NewInMemoryCacheService()
+		service := NewInMemoryCacheService(&config.AppArguments{})
 		uuid := "test-uuid"
 
 		retrievedData, exists := service.Get("non-existent-key", uuid)
@@ -225,7 +230,7 @@ func TestInMemoryCacheService_Get(t *testing.T) {
 	})
 
 	t.Run("handles empty cache", func(t *testing.T) {
-		service := NewInMemoryCacheService()
+		service := NewInMemoryCacheService(&config.AppArguments{})
 		uuid := "test-uuid"
 
 		retrievedData, exists := service.Get("any-key", uuid)
@@ -240,7 +245,7 @@ func TestInMemoryCacheService_Get(t *testing.T) {
 	})
 
 	t.Run("retrieves nil data correctly", func(t *testing.T) {
-		service := NewInMemoryCacheService()
+		service := NewInMemoryCacheService(&config.AppArguments{})
 		cacheKey := "test-key"
 		uuid := "test-uuid"
 
@@ -260,7 +265,7 @@ func TestInMemoryCacheService_Get(t *testing.T) {
 	})
 
 	t.Run("retrieves empty data correctly", func(t *testing.T) {
-		service := NewInMemoryCacheService()
+		service := NewInMemoryCacheService(&config.AppArguments{})
 		cacheKey := "test-key"
 		uuid := "test-uuid"
 		emptyData := []byte{}
@@ -285,7 +290,7 @@ func TestInMemoryCacheService_Get(t *testing.T) {
 	})
 
 	t.Run("handles multiple concurrent gets", func(t *testing.T) {
-		service := NewInMemoryCacheService()
+		service := NewInMemoryCacheService(&config.AppArguments{})
 		uuid := "test-uuid"
 
 		// Store multiple entries
@@ -321,3 +326,183 @@ func TestInMemoryCacheService_Get(t *testing.T) {
 		}
 	})
 }
+
+func TestInMemoryCacheService_Limits(t *testing.T) {
+	t.Run("evicts the least recently used entry when over the max entries", func(t *testing.T) {
+		service := NewInMemoryCacheService(&config.AppArguments{CacheMaxEntries: 2})
+		data := []byte("data")
+
+		service.Set("key1", &data, "test-uuid")
+		service.Set("key2", &data, "test-uuid")
+
+		// using key1, so key2 becomes the least recently used
+		service.Get("key1", "test-uuid")
+		service.Set("key3", &data, "test-uuid")
+
+		if _, exists := service.Get("key2", "test-uuid"); exists {
+			t.Error("expected key2 to be evicted")
+		}
+
+		for _, key := range []string{"key1", "key3"} {
+			if _, exists := service.Get(key, "test-uuid"); !exists {
+				t.Errorf("expected %s to be kept", key)
+			}
+		}
+
+		if stats := service.Stats(); stats.Entries != 2 || stats.Evictions != 1 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("evicts entries when over the max bytes", func(t *testing.T) {
+		service := NewInMemoryCacheService(&config.AppArguments{CacheMaxBytes: 10})
+		first := []byte("123456")
+		second := []byte("abcdef")
+
+		service.Set("key1", &first, "test-uuid")
+		service.Set("key2", &second, "test-uuid")
+
+		if _, exists := service.Get("key1", "test-uuid"); exists {
+			t.Error("expected key1 to be evicted")
+		}
+
+		if stats := service.Stats(); stats.Entries != 1 || stats.Bytes != 6 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("doesn't store data bigger than the max bytes", func(t *testing.T) {
+		service := NewInMemoryCacheService(&config.AppArguments{CacheMaxBytes: 4})
+		small := []byte("123")
+		big := []byte("123456")
+
+		service.Set("small", &small, "test-uuid")
+		service.Set("big", &big, "test-uuid")
+
+		if _, exists := service.Get("big", "test-uuid"); exists {
+			t.Error("expected big data not to be stored")
+		}
+
+		if _, exists := service.Get("small", "test-uuid"); !exists {
+			t.Error("expected small data to be kept")
+		}
+	})
+
+	t.Run("expires entries older than the TTL", func(t *testing.T) {
+		now := time.Now()
+		service := NewInMemoryCacheService(&config.AppArguments{CacheTTL: time.Minute})
+		service.now = func() time.Time { return now }
+		data := []byte("data")
+
+		service.Set("key", &data, "test-uuid")
+
+		now = now.Add(59 * time.Second)
+
+		if _, exists := service.Get("key", "test-uuid"); !exists {
+			t.Fatal("expected data to be cached before the TTL")
+		}
+
+		now = now.Add(time.Second)
+
+		if _, exists := service.Get("key", "test-uuid"); exists {
+			t.Error("expected data to be expired after the TTL")
+		}
+
+		if stats := service.Stats(); stats.Entries != 0 || stats.Hits != 1 || stats.Misses != 1 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+}
+
+func TestInMemoryCacheService_Delete(t *testing.T) {
+	service := NewInMemoryCacheService(&config.AppArguments{})
+	data := []byte("data")
+
+	service.Set("key", &data, "test-uuid")
+
+	if !service.Delete("key", "test-uuid") {
+		t.Error("expected existing key to be deleted")
+	}
+
+	if service.Delete("key", "test-uuid") {
+		t.Error("expected missing key not to be deleted")
+	}
+
+	if stats := service.Stats(); stats.Entries != 0 || stats.Bytes != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestInMemoryCacheService_DeleteFunc(t *testing.T) {
+	service := NewInMemoryCacheService(&config.AppArguments{})
+	data := []byte("data")
+
+	for _, key := range []string{"a:1", "a:2", "b:1"} {
+		service.Set(key, &data, "test-uuid")
+	}
+
+	removed := service.DeleteFunc(func(cacheKey string) bool {
+		return cacheKey[0] == 'a'
+	}, "test-uuid")
+
+	if removed != 2 {
+		t.Errorf("expected 2 entries removed, got %d", removed)
+	}
+
+	if _, exists := service.Get("b:1", "test-uuid"); !exists {
+		t.Error("expected non matching entry to be kept")
+	}
+}
+
+func TestInMemoryCacheService_Clear(t *testing.T) {
+	service := NewInMemoryCacheService(&config.AppArguments{})
+	data := []byte("data")
+
+	service.Set("key1", &data, "test-uuid")
+	service.Set("key2", &data, "test-uuid")
+
+	if removed := service.Clear("test-uuid"); removed != 2 {
+		t.Errorf("expected 2 entries removed, got %d", removed)
+	}
+
+	if stats := service.Stats(); stats.Entries != 0 || stats.Bytes != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	service.Set("key1", &data, "test-uuid")
+
+	if _, exists := service.Get("key1", "test-uuid"); !exists {
+		t.Error("expected cache to be usable after being cleared")
+	}
+}
+
+func TestInMemoryCacheService_Concurrency(t *testing.T) {
+	service := NewInMemoryCacheService(&config.AppArguments{CacheMaxEntries: 10})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("key%d", i%20)
+			data := []byte(key)
+
+			service.Set(key, &data, "test-uuid")
+			service.Get(key, "test-uuid")
+			service.Stats()
+
+			if i%10 == 0 {
+				service.Delete(key, "test-uuid")
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if stats := service.Stats(); stats.Entries > 10 {
+		t.Errorf("expected at most 10 entries, got %d", stats.Entries)
+	}
+}
@@ -0,0 +1,356 @@
+package certificate
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	caCertFileName = "ca.pem"
+	caKeyFileName  = "ca-key.pem"
+
+	caValidity   = 10 * 365 * 24 * time.Hour
+	leafValidity = 365 * 24 * time.Hour
+
+	// maxLeafCertificates bounds the leaf certificates kept in memory, as the SNI host comes from the clients
+	maxLeafCertificates = 1000
+
+	// defaultServerName is used for the clients not sending SNI, e.g. when connecting through an ip address
+	defaultServerName = "localhost"
+)
+
+// CertificateService provides the certificates of the TLS listeners. It either serves a user supplied certificate,
+// or signs a leaf certificate for every SNI host on the fly with a local CA.
+type CertificateService struct {
+	certificate *tls.Certificate
+	ca          *x509.Certificate
+	caKey       crypto.Signer
+	caPEM       []byte
+	leavesMu    sync.Mutex
+	leaves      map[string]*tls.Certificate
+}
+
+// NewCertificateService creates a new CertificateService.
+// Returns nil if neither the listeners nor the forward proxy are using TLS.
+func NewCertificateService(appArguments *config.AppArguments) (*CertificateService, error) {
+	if !appArguments.TLS && !appArguments.AdminTLS && !appArguments.ForwardProxy {
+		return nil, nil
+	}
+
+	if len(appArguments.TLSCertFile) > 0 || len(appArguments.TLSKeyFile) > 0 {
+		if len(appArguments.TLSCertFile) == 0 || len(appArguments.TLSKeyFile) == 0 {
+			return nil, errors.New("both --tls-cert and --tls-key should be set")
+		}
+
+		certificate, err := tls.LoadX509KeyPair(appArguments.TLSCertFile, appArguments.TLSKeyFile)
+
+		if err != nil {
+			return nil, fmt.Errorf("error while loading the TLS certificate: %v", err)
+		}
+
+		log.Info().
+			Str("cert_file", appArguments.TLSCertFile).
+			Msg("using the supplied TLS certificate")
+
+		return &CertificateService{certificate: &certificate}, nil
+	}
+
+	service := &CertificateService{
+		leaves: make(map[string]*tls.Certificate),
+	}
+
+	if err := service.initCA(appArguments.TLSCADirectory); err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
+
+// TLSConfig returns the TLS config of the listeners
+func (c *CertificateService) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: c.GetCertificate,
+	}
+}
+
+// GetCertificate returns the certificate for the host the client is connecting to
+func (c *CertificateService) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if c.certificate != nil {
+		return c.certificate, nil
+	}
+
+	serverName := strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+
+	if len(serverName) == 0 {
+		serverName = c.localAddress(hello)
+	}
+
+	return c.leafCertificate(serverName)
+}
+
+// CACertificatePEM returns the certificate of the local CA, PEM encoded, so clients can trust it.
+// Returns false when no CA is in use.
+func (c *CertificateService) CACertificatePEM() ([]byte, bool) {
+	if c == nil || c.caPEM == nil {
+		return nil, false
+	}
+
+	return c.caPEM, true
+}
+
+func (c *CertificateService) leafCertificate(serverName string) (*tls.Certificate, error) {
+	c.leavesMu.Lock()
+	defer c.leavesMu.Unlock()
+
+	if leaf, exists := c.leaves[serverName]; exists && time.Now().Before(leaf.Leaf.NotAfter) {
+		return leaf, nil
+	}
+
+	leaf, err := c.signLeafCertificate(serverName)
+
+	if err != nil {
+		log.Err(err).
+			Stack().
+			Str("server_name", serverName).
+			Msg("error while signing the TLS certificate")
+
+		return nil, err
+	}
+
+	if len(c.leaves) >= maxLeafCertificates {
+		c.leaves = make(map[string]*tls.Certificate)
+	}
+
+	c.leaves[serverName] = leaf
+
+	log.Info().
+		Str("server_name", serverName).
+		Msg("TLS certificate signed")
+
+	return leaf, nil
+}
+
+func (c *CertificateService) signLeafCertificate(serverName string) (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := newCertificateTemplate(serverName, leafValidity)
+
+	if err != nil {
+		return nil, err
+	}
+
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+
+	if ip := net.ParseIP(serverName); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{serverName}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, c.ca, key.Public(), c.caKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{der, c.ca.Raw},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, nil
+}
+
+// initCA loads the CA kept in the directory, generating it on the first run. Without a directory, a new CA is
+// generated on every start.
+func (c *CertificateService) initCA(directory string) error {
+	if len(directory) > 0 {
+		loaded, err := c.loadCA(directory)
+
+		if err != nil {
+			return err
+		}
+
+		if loaded {
+			return nil
+		}
+	}
+
+	if err := c.generateCA(); err != nil {
+		return fmt.Errorf("error while generating the TLS CA: %v", err)
+	}
+
+	if len(directory) == 0 {
+		log.Info().
+			Msg("TLS CA generated, it will change on restart, set --tls-ca-dir to keep it")
+
+		return nil
+	}
+
+	if err := c.saveCA(directory); err != nil {
+		return fmt.Errorf("error while saving the TLS CA: %v", err)
+	}
+
+	log.Info().
+		Str("directory", directory).
+		Msg("TLS CA generated and saved")
+
+	return nil
+}
+
+func (c *CertificateService) generateCA() error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		return err
+	}
+
+	template, err := newCertificateTemplate("Go Mock Server CA", caValidity)
+
+	if err != nil {
+		return err
+	}
+
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+
+	if err != nil {
+		return err
+	}
+
+	ca, err := x509.ParseCertificate(der)
+
+	if err != nil {
+		return err
+	}
+
+	c.ca = ca
+	c.caKey = key
+	c.caPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	return nil
+}
+
+func (c *CertificateService) loadCA(directory string) (bool, error) {
+	certPath := filepath.Join(directory, caCertFileName)
+	keyPath := filepath.Join(directory, caKeyFileName)
+
+	if _, err := os.Stat(certPath); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	keyPair, err := tls.LoadX509KeyPair(certPath, keyPath)
+
+	if err != nil {
+		return false, fmt.Errorf("error while loading the TLS CA: %v", err)
+	}
+
+	ca, err := x509.ParseCertificate(keyPair.Certificate[0])
+
+	if err != nil {
+		return false, fmt.Errorf("error while loading the TLS CA: %v", err)
+	}
+
+	caKey, ok := keyPair.PrivateKey.(crypto.Signer)
+
+	if !ok || !ca.IsCA {
+		return false, fmt.Errorf("error while loading the TLS CA: %s is not a CA", certPath)
+	}
+
+	c.ca = ca
+	c.caKey = caKey
+	c.caPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})
+
+	log.Info().
+		Str("directory", directory).
+		Msg("TLS CA loaded")
+
+	return true, nil
+}
+
+func (c *CertificateService) saveCA(directory string) error {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return err
+	}
+
+	keyDer, err := x509.MarshalPKCS8PrivateKey(c.caKey)
+
+	if err != nil {
+		return err
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer})
+
+	if err := os.WriteFile(filepath.Join(directory, caKeyFileName), keyPEM, 0600); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(directory, caCertFileName), c.caPEM, 0644)
+}
+
+func (c *CertificateService) localAddress(hello *tls.ClientHelloInfo) string {
+	if hello.Conn == nil {
+		return defaultServerName
+	}
+
+	host, _, err := net.SplitHostPort(hello.Conn.LocalAddr().String())
+
+	if err != nil || net.ParseIP(host) == nil {
+		return defaultServerName
+	}
+
+	return host
+}
+
+func newCertificateTemplate(commonName string, validity time.Duration) (*x509.Certificate, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	return &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: []string{"Go Mock Server"},
+		},
+		// allowing for some clock skew between the server and its clients
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(validity),
+	}, nil
+}
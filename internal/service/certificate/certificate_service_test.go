@@ -0,0 +1,230 @@
+package certificate
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Caik/go-mock-server/internal/config"
+)
+
+func newCAPool(t *testing.T, service *CertificateService) *x509.CertPool {
+	t.Helper()
+
+	caPEM, exists := service.CACertificatePEM()
+
+	if !exists {
+		t.Fatal("expected a CA certificate")
+	}
+
+	pool := x509.NewCertPool()
+
+	if !pool.AppendCertsFromPEM(caPEM) {
+		t.Fatal("expected a valid PEM encoded CA certificate")
+	}
+
+	return pool
+}
+
+func verifyLeaf(t *testing.T, pool *x509.CertPool, certificate *tls.Certificate, serverName string) {
+	t.Helper()
+
+	if _, err := certificate.Leaf.Verify(x509.VerifyOptions{DNSName: serverName, Roots: pool}); err != nil {
+		t.Errorf("expected certificate to be valid for %s: %v", serverName, err)
+	}
+}
+
+func TestNewCertificateService(t *testing.T) {
+	t.Run("returns nil when TLS is disabled", func(t *testing.T) {
+		service, err := NewCertificateService(&config.AppArguments{})
+
+		if err != nil || service != nil {
+			t.Errorf("expected nil service and error, got %v, %v", service, err)
+		}
+	})
+
+	t.Run("requires both the certificate and the key", func(t *testing.T) {
+		if _, err := NewCertificateService(&config.AppArguments{TLS: true, TLSCertFile: "cert.pem"}); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("fails on an invalid certificate", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile := filepath.Join(dir, "cert.pem")
+		os.WriteFile(certFile, []byte("invalid"), 0644)
+
+		if _, err := NewCertificateService(&config.AppArguments{TLS: true, TLSCertFile: certFile, TLSKeyFile: certFile}); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("serves the supplied certificate", func(t *testing.T) {
+		dir := t.TempDir()
+		generated, _ := NewCertificateService(&config.AppArguments{TLS: true})
+		leaf, _ := generated.GetCertificate(&tls.ClientHelloInfo{ServerName: "api.example.com"})
+
+		certFile := filepath.Join(dir, "cert.pem")
+		keyFile := filepath.Join(dir, "key.pem")
+		keyDer, _ := x509.MarshalPKCS8PrivateKey(leaf.PrivateKey)
+		os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Certificate[0]}), 0644)
+		os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0600)
+
+		service, err := NewCertificateService(&config.AppArguments{AdminTLS: true, TLSCertFile: certFile, TLSKeyFile: keyFile})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		certificate, err := service.GetCertificate(&tls.ClientHelloInfo{ServerName: "other.example.com"})
+
+		if err != nil || string(certificate.Certificate[0]) != string(leaf.Certificate[0]) {
+			t.Errorf("expected the supplied certificate, got error %v", err)
+		}
+
+		if _, exists := service.CACertificatePEM(); exists {
+			t.Error("expected no CA when using the supplied certificate")
+		}
+	})
+}
+
+func TestCertificateService_GetCertificate(t *testing.T) {
+	service, err := NewCertificateService(&config.AppArguments{TLS: true})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pool := newCAPool(t, service)
+
+	t.Run("signs a certificate per SNI host", func(t *testing.T) {
+		first, err := service.GetCertificate(&tls.ClientHelloInfo{ServerName: "api.example.com"})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		second, _ := service.GetCertificate(&tls.ClientHelloInfo{ServerName: "API.partner.com."})
+
+		verifyLeaf(t, pool, first, "api.example.com")
+		verifyLeaf(t, pool, second, "api.partner.com")
+	})
+
+	t.Run("reuses the certificate of a host", func(t *testing.T) {
+		first, _ := service.GetCertificate(&tls.ClientHelloInfo{ServerName: "api.example.com"})
+		second, _ := service.GetCertificate(&tls.ClientHelloInfo{ServerName: "api.example.com"})
+
+		if first != second {
+			t.Error("expected the same certificate")
+		}
+	})
+
+	t.Run("uses the local address without SNI", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		defer listener.Close()
+
+		go func() {
+			if conn, err := listener.Accept(); err == nil {
+				conn.Close()
+			}
+		}()
+
+		conn, err := net.Dial("tcp", listener.Addr().String())
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		defer conn.Close()
+
+		certificate, err := service.GetCertificate(&tls.ClientHelloInfo{Conn: conn})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(certificate.Leaf.IPAddresses) != 1 || !certificate.Leaf.IPAddresses[0].Equal(net.ParseIP("127.0.0.1")) {
+			t.Errorf("expected a certificate for 127.0.0.1, got %v", certificate.Leaf.IPAddresses)
+		}
+	})
+
+	t.Run("serves TLS clients trusting the CA", func(t *testing.T) {
+		listener, err := tls.Listen("tcp", "127.0.0.1:0", service.TLSConfig())
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.TLS.ServerName))
+		})}
+
+		go server.Serve(listener)
+		defer server.Close()
+
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool, ServerName: "api.partner.com"},
+		}}
+
+		response, err := client.Get("https://" + listener.Addr().String())
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		response.Body.Close()
+	})
+}
+
+func TestCertificateService_CADirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "ca")
+
+	first, err := NewCertificateService(&config.AppArguments{TLS: true, TLSCADirectory: dir})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info, err := os.Stat(filepath.Join(dir, caKeyFileName)); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected the CA key to be saved privately, got %v", err)
+	}
+
+	second, err := NewCertificateService(&config.AppArguments{TLS: true, TLSCADirectory: dir})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	firstPEM, _ := first.CACertificatePEM()
+	secondPEM, _ := second.CACertificatePEM()
+
+	if string(firstPEM) != string(secondPEM) {
+		t.Error("expected the saved CA to be reused")
+	}
+
+	leaf, err := second.GetCertificate(&tls.ClientHelloInfo{ServerName: "api.example.com"})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	verifyLeaf(t, newCAPool(t, first), leaf, "api.example.com")
+}
+
+func TestCertificateService_CACertificatePEM(t *testing.T) {
+	var service *CertificateService
+
+	if _, exists := service.CACertificatePEM(); exists {
+		t.Error("expected no CA for a nil service")
+	}
+}
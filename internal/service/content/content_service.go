@@ -1,20 +1,57 @@
 package content
 
+import "github.com/Caik/go-mock-server/internal/service/matcher"
+
 type ContentService interface {
 	GetContent(host, uri, method, uuid string, statusCode int) (*ContentResult, error)
+	MatchContent(host, uri, method, uuid string, statusCode int, request matcher.Request) (*ContentResult, error)
 	SetContent(host, uri, method, uuid string, statusCode int, data *[]byte) error
+	SetContentMeta(host, uri, method, uuid string, statusCode int, meta ContentMeta) error
 	DeleteContent(host, uri, method, uuid string, statusCode int) error
 	ListContents(uuid string) (*[]ContentData, error)
 	ListDefaultContents(uuid string) (*[]ContentData, error)
 	Subscribe(subscriberId string, eventTypes ...ContentEventType) <-chan ContentEvent
 	Unsubscribe(subscriberId string)
+	SubscriberCount() int
 }
 
-// ContentResult contains the result of a GetContent call
+// ContentResult contains the result of a GetContent or MatchContent call
 type ContentResult struct {
-	Data   *[]byte
-	Source string // e.g., "filesystem", "s3", "redis" - implementation-defined
-	Path   string // filesystem path, S3 key, etc.
+	Data    *[]byte
+	Source  string            // e.g., "filesystem", "s3", "redis" - implementation-defined
+	Path    string            // filesystem path, S3 key, etc.
+	Headers map[string]string // response headers declared alongside the mock, if any
+
+	// StatusCode is the status declared alongside the mock, served instead of the one of its file name, if set
+	StatusCode int
+
+	// Template tells that the data is a text/template, to be rendered for each request
+	Template bool
+
+	// Scenario is the scenario step of the mock, if it's tied to one
+	Scenario *ScenarioStep
+
+	// PathParams holds the values captured by the path parameters of a pattern mock (e.g. {id} in users/{id})
+	PathParams map[string]string
+
+	// Conditional tells that the result depends on the headers, query or body of the request, so it can't be reused
+	// for other requests to the same host, uri, method and status
+	Conditional bool
+}
+
+// ContentMeta is the response declared alongside a mock: the status served instead of the one of its file name (if
+// not zero) and the response headers
+type ContentMeta struct {
+	StatusCode int
+	Headers    map[string]string
+}
+
+// ScenarioStep ties a mock to a scenario: the mock is only chosen while the scenario is in State (any state when
+// empty), and it moves the scenario to Next (if set) once served
+type ScenarioStep struct {
+	Name  string `json:"name"`
+	State string `json:"state,omitempty"`
+	Next  string `json:"next,omitempty"`
 }
 
 type ContentEvent struct {
@@ -29,6 +66,9 @@ type ContentData struct {
 	StatusCode int
 }
 
+// DefaultUri is the uri of the content data of a _default file, the fallback of a host for a method and status
+const DefaultUri = "/_default"
+
 type ContentEventType int
 
 const (
@@ -1,15 +1,19 @@
 package content
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/matcher"
 	"github.com/Caik/go-mock-server/internal/util"
 	"github.com/fsnotify/fsnotify"
 	"github.com/google/uuid"
@@ -19,13 +23,60 @@ import (
 const (
 	pathSeparator = string(os.PathSeparator)
 	rootToken     = "root"
+	metaSuffix    = ".meta.json"
 )
 
+// variantRegex validates the name of a variant, the optional last part of a mock file name (e.g. users.post.200.alice)
+var variantRegex = regexp.MustCompile(`^[a-zA-Z][\w-]*$`)
+
 type FilesystemContentService struct {
 	mocksDirConfig *config.MocksDirectoryConfig
 	broadcaster    *util.Broadcaster[ContentEvent]
 }
 
+// mockMeta holds the optional settings of a mock, read from a sidecar file named after the mock file
+// plus the ".meta.json" suffix (e.g. users.get.200.meta.json)
+type mockMeta struct {
+	Status   int               `json:"status"`
+	Headers  map[string]string `json:"headers"`
+	Match    *matcher.Rules    `json:"match"`
+	Template bool              `json:"template"`
+	Scenario *ScenarioStep     `json:"scenario"`
+}
+
+// applyTo sets the settings of the mock on the result of its lookup
+func (m *mockMeta) applyTo(result *ContentResult) {
+	if m == nil {
+		return
+	}
+
+	result.Headers = m.Headers
+	result.StatusCode = m.Status
+	result.Template = m.Template
+	result.Scenario = m.Scenario
+}
+
+func (m *mockMeta) validate() error {
+	if m.Status != 0 && (m.Status < 100 || m.Status > 599) {
+		return errors.New("status should be between 100 and 599")
+	}
+
+	if m.Scenario != nil && len(strings.TrimSpace(m.Scenario.Name)) == 0 {
+		return errors.New("scenario name should not be empty")
+	}
+
+	return nil
+}
+
+// candidateLookup is a path under which MatchContent looks for a mock file and its variants
+type candidateLookup struct {
+	path string
+	// only mocks declaring query rules are considered, as the path isn't the exact one requested
+	queryRulesOnly bool
+	// values captured by the path parameters of a pattern path
+	pathParams map[string]string
+}
+
 func (f *FilesystemContentService) GetContent(host, uri, method, uuid string, statusCode int) (*ContentResult, error) {
 	absolutePath, err := f.getFinalFilePath(host, uri, method, statusCode)
 
@@ -40,11 +91,7 @@ func (f *FilesystemContentService) GetContent(host, uri, method, uuid string, st
 	}
 
 	if err == nil {
-		return &ContentResult{
-			Data:   &data,
-			Source: "filesystem",
-			Path:   absolutePath,
-		}, nil
+		return f.newContentResult(absolutePath, &data, uuid), nil
 	}
 
 	// file not found — try _default.<method>.<statusCode> fallback
@@ -58,11 +105,7 @@ func (f *FilesystemContentService) GetContent(host, uri, method, uuid string, st
 		}
 
 		if defReadErr == nil {
-			return &ContentResult{
-				Data:   &defaultData,
-				Source: "filesystem",
-				Path:   defaultPath,
-			}, nil
+			return f.newContentResult(defaultPath, &defaultData, uuid), nil
 		}
 	}
 
@@ -81,6 +124,69 @@ func (f *FilesystemContentService) GetContent(host, uri, method, uuid string, st
 	}, nil
 }
 
+// MatchContent looks up the mock the same way GetContent does, but considering the match rules declared in the meta
+// files. The variants of a mock file (e.g. users.post.200.alice) are tried first, in alphabetical order, then the mock
+// file itself, and the first one whose rules the request meets is chosen. When the uri has a query string, mocks
+// declaring query rules are looked up under the path without it as well. Then the mock files with path parameters or
+// wildcards (e.g. users/{id}.get.200) are tried, the most specific first, before falling back to _default.
+func (f *FilesystemContentService) MatchContent(host, uri, method, uuid string, statusCode int, request matcher.Request) (*ContentResult, error) {
+	absolutePath, err := f.getFinalFilePath(host, uri, method, statusCode)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lookups := []candidateLookup{{path: absolutePath}}
+	uriPath, _, hasQuery := strings.Cut(uri, "?")
+
+	if hasQuery {
+		if path, err := f.getFinalFilePath(host, uriPath, method, statusCode); err == nil {
+			lookups = append(lookups, candidateLookup{path: path, queryRulesOnly: true})
+		}
+	}
+
+	for _, patternLookup := range f.findPatternLookups(host, uriPath, method, statusCode) {
+		patternLookup.queryRulesOnly = hasQuery
+		lookups = append(lookups, patternLookup)
+	}
+
+	if defaultPath, err := f.getDefaultFilePath(host, method, statusCode); err == nil {
+		lookups = append(lookups, candidateLookup{path: defaultPath})
+	}
+
+	conditional := false
+
+	for _, lookup := range lookups {
+		result, lookupConditional, err := f.matchCandidates(lookup, request, uuid)
+		conditional = conditional || lookupConditional
+
+		if err != nil {
+			return nil, err
+		}
+
+		if result != nil {
+			result.Conditional = conditional
+			result.PathParams = lookup.pathParams
+
+			return result, nil
+		}
+	}
+
+	log.Info().
+		Str("uuid", uuid).
+		Str("path", absolutePath).
+		Msg("mock not found")
+
+	empty := []byte("")
+
+	return &ContentResult{
+		Data:        &empty,
+		Source:      "filesystem",
+		Path:        "",
+		Conditional: conditional,
+	}, nil
+}
+
 func (f *FilesystemContentService) SetContent(host, uri, method, uuid string, statusCode int, data *[]byte) error {
 	absolutePath, err := f.getFinalFilePath(host, uri, method, statusCode)
 
@@ -122,6 +228,77 @@ func (f *FilesystemContentService) SetContent(host, uri, method, uuid string, st
 	return nil
 }
 
+// SetContentMeta writes the response declared alongside a mock to its sidecar file. The other settings of the
+// sidecar file are kept, and the file is removed when nothing is left in it.
+func (f *FilesystemContentService) SetContentMeta(host, uri, method, uuid string, statusCode int, meta ContentMeta) error {
+	absolutePath, err := f.getFinalFilePath(host, uri, method, statusCode)
+
+	if err != nil {
+		return err
+	}
+
+	metaPath := absolutePath + metaSuffix
+	settings := make(map[string]json.RawMessage)
+	data, err := os.ReadFile(metaPath)
+
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		msg := fmt.Sprintf("error while reading mock meta file: %v", err)
+
+		log.Err(err).
+			Stack().
+			Str("uuid", uuid).
+			Str("path", metaPath).
+			Msg("error while reading mock meta file")
+
+		return errors.New(msg)
+	}
+
+	if err == nil {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return fmt.Errorf("error while parsing mock meta file: %v", err)
+		}
+	}
+
+	delete(settings, "status")
+	delete(settings, "headers")
+
+	if meta.StatusCode != 0 {
+		settings["status"], _ = json.Marshal(meta.StatusCode)
+	}
+
+	if len(meta.Headers) > 0 {
+		settings["headers"], _ = json.Marshal(meta.Headers)
+	}
+
+	if len(settings) == 0 {
+		if err := os.Remove(metaPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("error while removing mock meta file: %v", err)
+		}
+
+		return nil
+	}
+
+	data, err = json.MarshalIndent(settings, "", "  ")
+
+	if err != nil {
+		return fmt.Errorf("error while serializing mock meta file: %v", err)
+	}
+
+	if err := os.WriteFile(metaPath, data, 0644); err != nil {
+		msg := fmt.Sprintf("error while writing mock meta file: %v", err)
+
+		log.Err(err).
+			Stack().
+			Str("uuid", uuid).
+			Str("path", metaPath).
+			Msg("error while writing mock meta file")
+
+		return errors.New(msg)
+	}
+
+	return nil
+}
+
 func (f *FilesystemContentService) DeleteContent(host, uri, method, uuid string, statusCode int) error {
 	absolutePath, err := f.getFinalFilePath(host, uri, method, statusCode)
 
@@ -141,6 +318,14 @@ func (f *FilesystemContentService) DeleteContent(host, uri, method, uuid string,
 		return errors.New(msg)
 	}
 
+	// the sidecar file has no meaning without its mock, so it goes away together with it
+	if err := os.Remove(absolutePath + metaSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warn().
+			Str("uuid", uuid).
+			Str("path", absolutePath+metaSuffix).
+			Msgf("error while removing mock meta file: %v", err)
+	}
+
 	return nil
 }
 
@@ -217,6 +402,11 @@ func (f *FilesystemContentService) Unsubscribe(subscriberId string) {
 	f.broadcaster.Unsubscribe(subscriberId)
 }
 
+// SubscriberCount returns the number of current subscribers
+func (f *FilesystemContentService) SubscriberCount() int {
+	return f.broadcaster.SubscriberCount()
+}
+
 func (f *FilesystemContentService) getFinalFilePath(host, uri, method string, statusCode int) (string, error) {
 	// Validate inputs before using them in a path expression.
 	// The regexes allow only safe characters (no ".." or path separators in host,
@@ -233,7 +423,7 @@ func (f *FilesystemContentService) getFinalFilePath(host, uri, method string, st
 	uriPath := parts[0]
 
 	// Root path "/" is valid but won't match UriRegex, so handle it explicitly.
-	if uriPath != "/" && !util.UriRegex.MatchString(uriPath) {
+	if uriPath != "/" && !util.UriRegex.MatchString(uriPath) && !util.UriPatternRegex.MatchString(uriPath) {
 		return "", errors.New("invalid uri")
 	}
 
@@ -267,8 +457,269 @@ func (f *FilesystemContentService) getFinalFilePath(host, uri, method string, st
 	return filepath.Join(mocksDir, rel), nil
 }
 
+// newContentResult builds the result for a mock file found on disk, along with the settings of its sidecar file
+func (f *FilesystemContentService) newContentResult(path string, data *[]byte, uuid string) *ContentResult {
+	result := &ContentResult{
+		Data:   data,
+		Source: "filesystem",
+		Path:   path,
+	}
+
+	meta, err := f.readMockMeta(path)
+
+	if err != nil {
+		log.Warn().
+			Str("uuid", uuid).
+			Str("path", path+metaSuffix).
+			Msgf("ignoring invalid mock meta file: %v", err)
+
+		return result
+	}
+
+	meta.applyTo(result)
+
+	return result
+}
+
+// matchCandidates returns the first mock file of the lookup whose match rules the request meets, or nil when there's
+// none. It also tells whether any rules were evaluated along the way.
+func (f *FilesystemContentService) matchCandidates(lookup candidateLookup, request matcher.Request, uuid string) (*ContentResult, bool, error) {
+	variants := f.listVariants(lookup.path)
+	conditional := len(variants) > 0
+
+	for _, candidate := range append(variants, lookup.path) {
+		meta, err := f.readMockMeta(candidate)
+
+		if err != nil {
+			log.Warn().
+				Str("uuid", uuid).
+				Str("path", candidate+metaSuffix).
+				Msgf("ignoring invalid mock meta file: %v", err)
+
+			// the rules of a variant are unknown, so it can't be chosen
+			if candidate != lookup.path {
+				continue
+			}
+
+			meta = nil
+		}
+
+		var rules *matcher.Rules
+
+		if meta != nil {
+			rules = meta.Match
+		}
+
+		if lookup.queryRulesOnly && (rules == nil || !rules.HasQuery()) {
+			continue
+		}
+
+		if rules != nil {
+			conditional = true
+
+			if !rules.Matches(request) {
+				continue
+			}
+		}
+
+		if meta != nil && meta.Scenario != nil {
+			// the response depends on the scenario state, and serving it may change that state
+			conditional = true
+
+			if !f.isScenarioStepActive(meta.Scenario, request) {
+				continue
+			}
+		}
+
+		data, err := os.ReadFile(candidate)
+
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, conditional, err // real I/O error — propagate it
+		}
+
+		result := &ContentResult{
+			Data:   &data,
+			Source: "filesystem",
+			Path:   candidate,
+		}
+
+		meta.applyTo(result)
+
+		return result, conditional, nil
+	}
+
+	return nil, conditional, nil
+}
+
+// findPatternLookups returns the pattern paths of the host matching the uri, the most specific first. Both
+// directories and file names may be path parameters (e.g. {id}) or wildcards (*).
+func (f *FilesystemContentService) findPatternLookups(host, uriPath, method string, statusCode int) []candidateLookup {
+	segments := splitUriPath(uriPath)
+
+	// the root path and paths ending with a slash are mapped to the root token, which can't be a pattern
+	if len(segments) == 0 || strings.HasSuffix(uriPath, "/") {
+		return nil
+	}
+
+	hostDir := filepath.Join(filepath.Clean(f.mocksDirConfig.Path), host)
+	fileSuffix := "." + strings.ToLower(method) + "." + strconv.Itoa(statusCode)
+	lookups := make([]candidateLookup, 0)
+
+	f.walkPatterns(hostDir, segments, fileSuffix, make(map[string]string), false, &lookups)
+
+	return lookups
+}
+
+// walkPatterns goes down the directories matching the segments, trying literal names first, then path parameters
+// and then wildcards, so the lookups are found from the most to the least specific
+func (f *FilesystemContentService) walkPatterns(dir string, segments []string, fileSuffix string, params map[string]string, isPattern bool, lookups *[]candidateLookup) {
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return
+	}
+
+	isLast := len(segments) == 1
+	names := make(map[string]bool)
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if isLast {
+			if entry.IsDir() || !strings.Contains(name, fileSuffix) {
+				continue
+			}
+
+			// a mock file, its variants or its meta files
+			name = name[:strings.Index(name, fileSuffix)]
+		} else if !entry.IsDir() {
+			continue
+		}
+
+		if name == segments[0] || segmentRank(name) > 0 {
+			names[name] = true
+		}
+	}
+
+	ordered := make([]string, 0, len(names))
+
+	for name := range names {
+		ordered = append(ordered, name)
+	}
+
+	sort.Slice(ordered, func(i, j int) bool {
+		if segmentRank(ordered[i]) != segmentRank(ordered[j]) {
+			return segmentRank(ordered[i]) < segmentRank(ordered[j])
+		}
+
+		return ordered[i] < ordered[j]
+	})
+
+	for _, name := range ordered {
+		nextParams := params
+		rank := segmentRank(name)
+
+		if rank == 1 {
+			nextParams = make(map[string]string, len(params)+1)
+
+			for key, value := range params {
+				nextParams[key] = value
+			}
+
+			nextParams[paramSegmentRegex.FindStringSubmatch(name)[1]] = segments[0]
+		}
+
+		if !isLast {
+			f.walkPatterns(filepath.Join(dir, name), segments[1:], fileSuffix, nextParams, isPattern || rank > 0, lookups)
+			continue
+		}
+
+		// the literal path has already been looked up as is
+		if isPattern || rank > 0 {
+			*lookups = append(*lookups, candidateLookup{path: filepath.Join(dir, name+fileSuffix), pathParams: nextParams})
+		}
+	}
+}
+
+func (f *FilesystemContentService) isScenarioStepActive(step *ScenarioStep, request matcher.Request) bool {
+	if len(step.State) == 0 {
+		return true
+	}
+
+	return request.ScenarioState != nil && request.ScenarioState(step.Name) == step.State
+}
+
+// listVariants returns the paths of the variants of the given mock file, sorted by name
+func (f *FilesystemContentService) listVariants(path string) []string {
+	dir, base := filepath.Split(path)
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return nil
+	}
+
+	variants := make([]string, 0)
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() || !strings.HasPrefix(name, base+".") {
+			continue
+		}
+
+		if variantRegex.MatchString(strings.TrimPrefix(name, base+".")) {
+			variants = append(variants, filepath.Join(dir, name))
+		}
+	}
+
+	return variants
+}
+
+// mockFilePathOf returns the path of the mock file a sidecar file or a variant belongs to. The path is returned
+// as-is for any other file.
+func (f *FilesystemContentService) mockFilePathOf(path string) string {
+	path = strings.TrimSuffix(path, metaSuffix)
+	variant := strings.TrimPrefix(filepath.Ext(path), ".")
+	status := strings.TrimPrefix(filepath.Ext(strings.TrimSuffix(path, "."+variant)), ".")
+
+	if _, err := strconv.Atoi(status); err == nil && variantRegex.MatchString(variant) {
+		return strings.TrimSuffix(path, "."+variant)
+	}
+
+	return path
+}
+
+// readMockMeta reads the sidecar file of the given mock file. It returns nil when there's no sidecar file.
+func (f *FilesystemContentService) readMockMeta(path string) (*mockMeta, error) {
+	data, err := os.ReadFile(path + metaSuffix)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var meta mockMeta
+
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+
+	if err := meta.validate(); err != nil {
+		return nil, err
+	}
+
+	return &meta, nil
+}
+
 func (f *FilesystemContentService) getDefaultFilePath(host, method string, statusCode int) (string, error) {
-	return f.getFinalFilePath(host, "/_default", method, statusCode)
+	return f.getFinalFilePath(host, DefaultUri, method, statusCode)
 }
 
 func (f *FilesystemContentService) filePathToContentData(path string) (*ContentData, error) {
@@ -277,6 +728,11 @@ func (f *FilesystemContentService) filePathToContentData(path string) (*ContentD
 
 	firstSlashIndex := strings.Index(relativePath, pathSeparator)
 
+	// Skip sidecar files — these hold settings of a mock, not mocks themselves
+	if strings.HasSuffix(relativePath, metaSuffix) {
+		return nil, fmt.Errorf("skipping mock meta file: %s", path)
+	}
+
 	// Skip _default.* files — these are fallbacks, not real mocks
 	if firstSlashIndex != -1 {
 		fileName := relativePath[firstSlashIndex+1:]
@@ -316,7 +772,7 @@ func (f *FilesystemContentService) filePathToContentData(path string) (*ContentD
 	}
 
 	// validating URI — skip regex for root path
-	if uri != "/" && !util.UriRegex.MatchString(uri) {
+	if uri != "/" && !util.UriRegex.MatchString(uri) && !util.UriPatternRegex.MatchString(uri) {
 		return nil, fmt.Errorf("invalid uri: %s", uri)
 	}
 
@@ -355,6 +811,10 @@ func (f *FilesystemContentService) defaultFilePathToContentData(path string) (*C
 		return nil, fmt.Errorf("not a default file: %s", path)
 	}
 
+	if strings.HasSuffix(fileName, metaSuffix) {
+		return nil, fmt.Errorf("skipping mock meta file: %s", path)
+	}
+
 	host := relativePath[:firstSlashIndex]
 
 	// Parse _default.<method>.<status>
@@ -383,12 +843,21 @@ func (f *FilesystemContentService) defaultFilePathToContentData(path string) (*C
 
 	return &ContentData{
 		Host:       host,
-		Uri:        "/_default",
+		Uri:        DefaultUri,
 		Method:     method,
 		StatusCode: statusCode,
 	}, nil
 }
 
+// anyFilePathToContentData converts the path of either a mock or a _default file to its content data
+func (f *FilesystemContentService) anyFilePathToContentData(path string) (*ContentData, error) {
+	if data, err := f.defaultFilePathToContentData(path); err == nil {
+		return data, nil
+	}
+
+	return f.filePathToContentData(path)
+}
+
 func (f *FilesystemContentService) startContentWatcher() {
 	watcher, err := fsnotify.NewWatcher()
 
@@ -505,7 +974,7 @@ func (f *FilesystemContentService) handleFilesystemEvent(event fsnotify.Event, w
 					return nil
 				}
 
-				if data, err := f.filePathToContentData(path); err == nil {
+				if data, err := f.anyFilePathToContentData(path); err == nil {
 					f.broadcaster.Publish(ContentEvent{Type: eventType, Data: *data}, uuid)
 				}
 
@@ -526,7 +995,16 @@ func (f *FilesystemContentService) handleFilesystemEvent(event fsnotify.Event, w
 
 	}
 
-	data, err := f.filePathToContentData(event.Name)
+	// a change on a sidecar file or on a variant is a change on the mock it belongs to
+	if mockFilePath := f.mockFilePathOf(event.Name); mockFilePath != event.Name {
+		if data, err := f.anyFilePathToContentData(mockFilePath); err == nil {
+			f.broadcaster.Publish(ContentEvent{Type: Updated, Data: *data}, uuid)
+		}
+
+		return
+	}
+
+	data, err := f.anyFilePathToContentData(event.Name)
 
 	if err != nil {
 		// for remove events it might happen that the object deleted was a dir, and in that case an error would be thrown
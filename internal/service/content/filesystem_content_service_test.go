@@ -1,13 +1,16 @@
 package content
 
 import (
+	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/matcher"
 	"github.com/Caik/go-mock-server/internal/util"
 	"github.com/fsnotify/fsnotify"
 )
@@ -1492,6 +1495,49 @@ func TestFilesystemContentService_handleFilesystemEvent(t *testing.T) {
 
 		service.Unsubscribe(subscriberId)
 	})
+
+	t.Run("broadcasts changes of _default files", func(t *testing.T) {
+		tempDir := t.TempDir()
+		hostDir := filepath.Join(tempDir, "example.com")
+		os.MkdirAll(hostDir, 0755)
+		defaultFile := filepath.Join(hostDir, "_default.get.404")
+		os.WriteFile(defaultFile, []byte("not found"), 0644)
+		os.WriteFile(defaultFile+metaSuffix, []byte(`{}`), 0644)
+
+		service := &FilesystemContentService{
+			broadcaster:    &util.Broadcaster[ContentEvent]{},
+			mocksDirConfig: &config.MocksDirectoryConfig{Path: tempDir},
+		}
+
+		eventChan := service.Subscribe("default-test")
+		defer service.Unsubscribe("default-test")
+
+		watcher, err := fsnotify.NewWatcher()
+
+		if err != nil {
+			t.Fatalf("failed to create watcher: %v", err)
+		}
+
+		defer watcher.Close()
+
+		expected := ContentData{Host: "example.com", Uri: DefaultUri, Method: "GET", StatusCode: 404}
+
+		for _, event := range []fsnotify.Event{
+			{Name: defaultFile, Op: fsnotify.Write},
+			{Name: defaultFile + metaSuffix, Op: fsnotify.Create},
+		} {
+			go service.handleFilesystemEvent(event, watcher)
+
+			select {
+			case received := <-eventChan:
+				if received.Type != Updated || received.Data != expected {
+					t.Errorf("expected update of %+v, got %+v", expected, received)
+				}
+			case <-time.After(time.Second):
+				t.Errorf("expected an event to be broadcast for %s", event.Name)
+			}
+		}
+	})
 }
 
 func TestFilesystemContentService_startContentWatcher(t *testing.T) {
@@ -1707,3 +1753,570 @@ func TestFilesystemContentService_GetContent_StatusSuffix(t *testing.T) {
 		}
 	})
 }
+
+func TestFilesystemContentService_GetContent_MetaFile(t *testing.T) {
+	t.Run("loads headers from the meta file of the mock", func(t *testing.T) {
+		dir := t.TempDir()
+		apiDir := filepath.Join(dir, "example.com", "api")
+		os.MkdirAll(apiDir, 0755)
+		os.WriteFile(filepath.Join(apiDir, "login.post.302"), []byte(""), 0644)
+		os.WriteFile(filepath.Join(apiDir, "login.post.302.meta.json"), []byte(`{"headers":{"Location":"/home","Set-Cookie":"session=abc"}}`), 0644)
+
+		svc := NewFilesystemContentService(&config.MocksDirectoryConfig{Path: dir})
+		result, err := svc.GetContent("example.com", "/api/login", "POST", "test", 302)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Headers["Location"] != "/home" || result.Headers["Set-Cookie"] != "session=abc" {
+			t.Errorf("unexpected headers: %v", result.Headers)
+		}
+	})
+
+	t.Run("loads headers from the meta file of the _default fallback", func(t *testing.T) {
+		dir := t.TempDir()
+		hostDir := filepath.Join(dir, "example.com")
+		os.MkdirAll(hostDir, 0755)
+		os.WriteFile(filepath.Join(hostDir, "_default.get.429"), []byte("slow down"), 0644)
+		os.WriteFile(filepath.Join(hostDir, "_default.get.429.meta.json"), []byte(`{"headers":{"Retry-After":"30"}}`), 0644)
+
+		svc := NewFilesystemContentService(&config.MocksDirectoryConfig{Path: dir})
+		result, err := svc.GetContent("example.com", "/api/users", "GET", "test", 429)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Headers["Retry-After"] != "30" {
+			t.Errorf("unexpected headers: %v", result.Headers)
+		}
+	})
+
+	t.Run("flags templates declared in the meta file", func(t *testing.T) {
+		dir := t.TempDir()
+		apiDir := filepath.Join(dir, "example.com", "api")
+		os.MkdirAll(apiDir, 0755)
+		os.WriteFile(filepath.Join(apiDir, "users.post.201"), []byte(`{"id":"{{uuid}}"}`), 0644)
+		os.WriteFile(filepath.Join(apiDir, "users.post.201.meta.json"), []byte(`{"template":true}`), 0644)
+
+		svc := NewFilesystemContentService(&config.MocksDirectoryConfig{Path: dir})
+		result, err := svc.MatchContent("example.com", "/api/users", "POST", "test", 201, matcher.Request{})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Template {
+			t.Error("expected result to be flagged as template")
+		}
+	})
+
+	t.Run("ignores an invalid meta file", func(t *testing.T) {
+		dir := t.TempDir()
+		apiDir := filepath.Join(dir, "example.com", "api")
+		os.MkdirAll(apiDir, 0755)
+		os.WriteFile(filepath.Join(apiDir, "users.get.200"), []byte("ok"), 0644)
+		os.WriteFile(filepath.Join(apiDir, "users.get.200.meta.json"), []byte(`{invalid`), 0644)
+
+		svc := NewFilesystemContentService(&config.MocksDirectoryConfig{Path: dir})
+		result, err := svc.GetContent("example.com", "/api/users", "GET", "test", 200)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(*result.Data) != "ok" {
+			t.Errorf("expected 'ok', got %q", string(*result.Data))
+		}
+		if result.Headers != nil {
+			t.Errorf("expected no headers, got %v", result.Headers)
+		}
+	})
+
+	t.Run("removes the meta file together with the mock", func(t *testing.T) {
+		dir := t.TempDir()
+		apiDir := filepath.Join(dir, "example.com", "api")
+		os.MkdirAll(apiDir, 0755)
+		os.WriteFile(filepath.Join(apiDir, "users.get.200"), []byte("ok"), 0644)
+		os.WriteFile(filepath.Join(apiDir, "users.get.200.meta.json"), []byte(`{}`), 0644)
+
+		svc := NewFilesystemContentService(&config.MocksDirectoryConfig{Path: dir})
+
+		if err := svc.DeleteContent("example.com", "/api/users", "GET", "test", 200); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(apiDir, "users.get.200.meta.json")); !os.IsNotExist(err) {
+			t.Error("expected meta file to be removed")
+		}
+	})
+
+	t.Run("does not list meta files as mocks", func(t *testing.T) {
+		dir := t.TempDir()
+		hostDir := filepath.Join(dir, "example.com")
+		os.MkdirAll(hostDir, 0755)
+		os.WriteFile(filepath.Join(hostDir, "users.get.200"), []byte("ok"), 0644)
+		os.WriteFile(filepath.Join(hostDir, "users.get.200.meta.json"), []byte(`{}`), 0644)
+		os.WriteFile(filepath.Join(hostDir, "_default.get.500"), []byte("error"), 0644)
+		os.WriteFile(filepath.Join(hostDir, "_default.get.500.meta.json"), []byte(`{}`), 0644)
+
+		svc := NewFilesystemContentService(&config.MocksDirectoryConfig{Path: dir})
+		contents, err := svc.ListContents("test")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*contents) != 1 {
+			t.Errorf("expected 1 mock, got %d: %v", len(*contents), *contents)
+		}
+
+		defaults, err := svc.ListDefaultContents("test")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*defaults) != 1 {
+			t.Errorf("expected 1 default mock, got %d: %v", len(*defaults), *defaults)
+		}
+	})
+
+	t.Run("broadcasts a meta file change as an update of its mock", func(t *testing.T) {
+		dir := t.TempDir()
+		apiDir := filepath.Join(dir, "example.com", "api")
+		os.MkdirAll(apiDir, 0755)
+		metaFile := filepath.Join(apiDir, "users.get.200.meta.json")
+		os.WriteFile(metaFile, []byte(`{}`), 0644)
+
+		service := &FilesystemContentService{
+			broadcaster:    &util.Broadcaster[ContentEvent]{},
+			mocksDirConfig: &config.MocksDirectoryConfig{Path: dir},
+		}
+
+		eventChan := service.Subscribe("meta-test")
+		defer service.Unsubscribe("meta-test")
+
+		watcher, err := fsnotify.NewWatcher()
+
+		if err != nil {
+			t.Fatalf("failed to create watcher: %v", err)
+		}
+
+		defer watcher.Close()
+
+		go service.handleFilesystemEvent(fsnotify.Event{Name: metaFile, Op: fsnotify.Create}, watcher)
+
+		select {
+		case event := <-eventChan:
+			if event.Type != Updated {
+				t.Errorf("expected Updated event, got %v", event.Type)
+			}
+			if event.Data.Uri != "/api/users" || event.Data.Method != "GET" {
+				t.Errorf("unexpected event data: %+v", event.Data)
+			}
+		case <-time.After(time.Second):
+			t.Error("expected an event to be broadcast")
+		}
+	})
+}
+
+func TestFilesystemContentService_SetContentMeta(t *testing.T) {
+	t.Run("writes the status and headers served with the mock", func(t *testing.T) {
+		dir := t.TempDir()
+		svc := NewFilesystemContentService(&config.MocksDirectoryConfig{Path: dir})
+		data := []byte(`{"id":1}`)
+
+		if err := svc.SetContent("example.com", "/api/users", "POST", "test", 200, &data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		meta := ContentMeta{StatusCode: 201, Headers: map[string]string{"Content-Type": "application/json"}}
+
+		if err := svc.SetContentMeta("example.com", "/api/users", "POST", "test", 200, meta); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		result, err := svc.GetContent("example.com", "/api/users", "POST", "test", 200)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.StatusCode != 201 {
+			t.Errorf("expected status 201, got %d", result.StatusCode)
+		}
+		if result.Headers["Content-Type"] != "application/json" {
+			t.Errorf("expected Content-Type header, got %v", result.Headers)
+		}
+	})
+
+	t.Run("keeps the other settings of the meta file", func(t *testing.T) {
+		dir := t.TempDir()
+		apiDir := filepath.Join(dir, "example.com", "api")
+		os.MkdirAll(apiDir, 0755)
+		os.WriteFile(filepath.Join(apiDir, "users.get.200"), []byte("ok"), 0644)
+		os.WriteFile(filepath.Join(apiDir, "users.get.200.meta.json"), []byte(`{"template":true,"status":404}`), 0644)
+
+		svc := NewFilesystemContentService(&config.MocksDirectoryConfig{Path: dir})
+		meta := ContentMeta{Headers: map[string]string{"X-Test": "yes"}}
+
+		if err := svc.SetContentMeta("example.com", "/api/users", "GET", "test", 200, meta); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		result, err := svc.GetContent("example.com", "/api/users", "GET", "test", 200)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Template {
+			t.Error("expected the template setting to be kept")
+		}
+		if result.StatusCode != 0 {
+			t.Errorf("expected the status to be cleared, got %d", result.StatusCode)
+		}
+		if result.Headers["X-Test"] != "yes" {
+			t.Errorf("expected X-Test header, got %v", result.Headers)
+		}
+	})
+
+	t.Run("removes the meta file when left empty", func(t *testing.T) {
+		dir := t.TempDir()
+		apiDir := filepath.Join(dir, "example.com", "api")
+		os.MkdirAll(apiDir, 0755)
+		os.WriteFile(filepath.Join(apiDir, "users.get.200"), []byte("ok"), 0644)
+		metaFile := filepath.Join(apiDir, "users.get.200.meta.json")
+		os.WriteFile(metaFile, []byte(`{"headers":{"X-Test":"yes"}}`), 0644)
+
+		svc := NewFilesystemContentService(&config.MocksDirectoryConfig{Path: dir})
+
+		if err := svc.SetContentMeta("example.com", "/api/users", "GET", "test", 200, ContentMeta{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Stat(metaFile); !os.IsNotExist(err) {
+			t.Errorf("expected the meta file to be removed, got %v", err)
+		}
+	})
+}
+
+func TestFilesystemContentService_MatchContent(t *testing.T) {
+	newService := func(t *testing.T, files map[string]string) *FilesystemContentService {
+		dir := t.TempDir()
+
+		for name, data := range files {
+			path := filepath.Join(dir, "example.com", name)
+			os.MkdirAll(filepath.Dir(path), 0755)
+			os.WriteFile(path, []byte(data), 0644)
+		}
+
+		return NewFilesystemContentService(&config.MocksDirectoryConfig{Path: dir})
+	}
+
+	t.Run("chooses the first variant whose rules match", func(t *testing.T) {
+		svc := newService(t, map[string]string{
+			"api/users.post.200":                 "base",
+			"api/users.post.200.alice":           "alice",
+			"api/users.post.200.alice.meta.json": `{"match":{"json_body":{"$.name":"alice"}}}`,
+			"api/users.post.200.bob":             "bob",
+			"api/users.post.200.bob.meta.json":   `{"match":{"json_body":{"$.name":"bob"}}}`,
+		})
+
+		result, err := svc.MatchContent("example.com", "/api/users", "POST", "test", 200, matcher.NewRequest(http.Header{}, "", []byte(`{"name":"bob"}`)))
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(*result.Data) != "bob" {
+			t.Errorf("expected 'bob', got %q", string(*result.Data))
+		}
+		if !result.Conditional {
+			t.Error("expected result to be conditional")
+		}
+	})
+
+	t.Run("falls back to the mock file when no variant matches", func(t *testing.T) {
+		svc := newService(t, map[string]string{
+			"api/users.post.200":                 "base",
+			"api/users.post.200.alice":           "alice",
+			"api/users.post.200.alice.meta.json": `{"match":{"json_body":{"$.name":"alice"}}}`,
+		})
+
+		result, err := svc.MatchContent("example.com", "/api/users", "POST", "test", 200, matcher.NewRequest(http.Header{}, "", []byte(`{"name":"carol"}`)))
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(*result.Data) != "base" {
+			t.Errorf("expected 'base', got %q", string(*result.Data))
+		}
+		if !result.Conditional {
+			t.Error("expected result to be conditional")
+		}
+	})
+
+	t.Run("chooses the variant tied to the current scenario state", func(t *testing.T) {
+		svc := newService(t, map[string]string{
+			"orders/1.get.200":                   "delivered",
+			"orders/1.get.200.meta.json":         `{"scenario":{"name":"order","state":"delivered"}}`,
+			"orders/1.get.200.pending":           "pending",
+			"orders/1.get.200.pending.meta.json": `{"scenario":{"name":"order","state":"started","next":"shipped"}}`,
+			"orders/1.get.200.shipped":           "shipped",
+			"orders/1.get.200.shipped.meta.json": `{"scenario":{"name":"order","state":"shipped","next":"delivered"}}`,
+			"orders/1.get.200.untied":            "untied",
+			"orders/1.get.200.untied.meta.json":  `{"scenario":{"name":""}}`,
+		})
+
+		for _, state := range []string{"started", "shipped", "delivered"} {
+			request := matcher.NewRequest(http.Header{}, "", nil)
+			request.ScenarioState = func(name string) string { return state }
+
+			result, err := svc.MatchContent("example.com", "/orders/1", "GET", "test", 200, request)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expected := map[string]string{"started": "pending", "shipped": "shipped", "delivered": "delivered"}[state]
+
+			if string(*result.Data) != expected {
+				t.Errorf("state %s: expected %q, got %q", state, expected, string(*result.Data))
+			}
+			if result.Scenario == nil || result.Scenario.Name != "order" {
+				t.Errorf("state %s: expected the scenario step, got %v", state, result.Scenario)
+			}
+			if !result.Conditional {
+				t.Errorf("state %s: expected result to be conditional", state)
+			}
+		}
+	})
+
+	t.Run("skips scenario variants when no scenario state is available", func(t *testing.T) {
+		svc := newService(t, map[string]string{
+			"orders/1.get.200":                   "base",
+			"orders/1.get.200.pending":           "pending",
+			"orders/1.get.200.pending.meta.json": `{"scenario":{"name":"order","state":"started"}}`,
+		})
+
+		result, err := svc.MatchContent("example.com", "/orders/1", "GET", "test", 200, matcher.NewRequest(http.Header{}, "", nil))
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(*result.Data) != "base" {
+			t.Errorf("expected 'base', got %q", string(*result.Data))
+		}
+	})
+
+	t.Run("skips the mock file when its rules don't match", func(t *testing.T) {
+		svc := newService(t, map[string]string{
+			"api/users.get.200":           "tenant",
+			"api/users.get.200.meta.json": `{"match":{"headers":{"X-Tenant":"acme"}}}`,
+			"_default.get.200":            "default",
+		})
+
+		result, err := svc.MatchContent("example.com", "/api/users", "GET", "test", 200, matcher.NewRequest(http.Header{"X-Tenant": []string{"other"}}, "", nil))
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(*result.Data) != "default" {
+			t.Errorf("expected 'default', got %q", string(*result.Data))
+		}
+	})
+
+	t.Run("skips a variant with an invalid meta file", func(t *testing.T) {
+		svc := newService(t, map[string]string{
+			"api/users.get.200":                  "base",
+			"api/users.get.200.broken":           "broken",
+			"api/users.get.200.broken.meta.json": `{"match":{"headers":{"X-Tenant":{"matches":"("}}}}`,
+		})
+
+		result, err := svc.MatchContent("example.com", "/api/users", "GET", "test", 200, matcher.NewRequest(http.Header{}, "", nil))
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(*result.Data) != "base" {
+			t.Errorf("expected 'base', got %q", string(*result.Data))
+		}
+	})
+
+	t.Run("looks up mocks declaring query rules under the path without the query string", func(t *testing.T) {
+		svc := newService(t, map[string]string{
+			"api/users.get.200":                 "plain",
+			"api/users.get.200.page2":           "page 2",
+			"api/users.get.200.page2.meta.json": `{"match":{"query":{"page":"2"}}}`,
+		})
+
+		result, err := svc.MatchContent("example.com", "/api/users?page=2", "GET", "test", 200, matcher.NewRequest(http.Header{}, "page=2", nil))
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(*result.Data) != "page 2" {
+			t.Errorf("expected 'page 2', got %q", string(*result.Data))
+		}
+
+		// the plain mock declares no query rules, so it doesn't match other query strings
+		result, err = svc.MatchContent("example.com", "/api/users?page=3", "GET", "test", 200, matcher.NewRequest(http.Header{}, "page=3", nil))
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Path != "" {
+			t.Errorf("expected no mock, got %q", result.Path)
+		}
+	})
+
+	t.Run("behaves like GetContent for mocks without rules", func(t *testing.T) {
+		svc := newService(t, map[string]string{
+			"api/users.get.200":           "ok",
+			"api/users.get.200.meta.json": `{"headers":{"ETag":"v1"}}`,
+		})
+
+		result, err := svc.MatchContent("example.com", "/api/users", "GET", "test", 200, matcher.NewRequest(http.Header{}, "", nil))
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(*result.Data) != "ok" || result.Headers["ETag"] != "v1" {
+			t.Errorf("unexpected result: %q %v", string(*result.Data), result.Headers)
+		}
+		if result.Conditional {
+			t.Error("expected result not to be conditional")
+		}
+	})
+}
+
+func TestFilesystemContentService_mockFilePathOf(t *testing.T) {
+	service := &FilesystemContentService{mocksDirConfig: &config.MocksDirectoryConfig{Path: "/mocks"}}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"mock file", "/mocks/example.com/users.get.200", "/mocks/example.com/users.get.200"},
+		{"meta file", "/mocks/example.com/users.get.200.meta.json", "/mocks/example.com/users.get.200"},
+		{"variant", "/mocks/example.com/users.post.200.alice", "/mocks/example.com/users.post.200"},
+		{"variant meta file", "/mocks/example.com/users.post.200.alice.meta.json", "/mocks/example.com/users.post.200"},
+		{"host directory", "/mocks/example.com", "/mocks/example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := service.mockFilePathOf(tt.path); result != tt.expected {
+				t.Errorf("mockFilePathOf(%q) = %q, expected %q", tt.path, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilesystemContentService_MatchContent_Patterns(t *testing.T) {
+	newService := func(t *testing.T, files map[string]string) *FilesystemContentService {
+		dir := t.TempDir()
+
+		for name, data := range files {
+			path := filepath.Join(dir, "example.com", name)
+			os.MkdirAll(filepath.Dir(path), 0755)
+			os.WriteFile(path, []byte(data), 0644)
+		}
+
+		return NewFilesystemContentService(&config.MocksDirectoryConfig{Path: dir})
+	}
+
+	match := func(t *testing.T, svc *FilesystemContentService, uri string) *ContentResult {
+		_, rawQuery, _ := strings.Cut(uri, "?")
+		result, err := svc.MatchContent("example.com", uri, "GET", "test", 200, matcher.NewRequest(http.Header{}, rawQuery, nil))
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return result
+	}
+
+	files := map[string]string{
+		"users/me.get.200":              "me",
+		"users/{id}.get.200":            "user",
+		"users/*.get.200":               "any",
+		"users/{id}/orders.get.200":     "orders",
+		"users/{id}/*.get.200":          "user any",
+		"users/{id}/orders/{o}.get.200": "order",
+		"_default.get.200":              "default",
+	}
+
+	tests := []struct {
+		name     string
+		uri      string
+		expected string
+		params   map[string]string
+	}{
+		{"literal file wins", "/users/me", "me", nil},
+		{"path parameter file", "/users/42", "user", map[string]string{"id": "42"}},
+		{"path parameter directory", "/users/42/orders", "orders", map[string]string{"id": "42"}},
+		{"wildcard after parameter", "/users/42/invoices", "user any", map[string]string{"id": "42"}},
+		{"several parameters", "/users/42/orders/7", "order", map[string]string{"id": "42", "o": "7"}},
+		{"falls back to _default", "/products/1", "default", nil},
+	}
+
+	svc := newService(t, files)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := match(t, svc, tt.uri)
+
+			if string(*result.Data) != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, string(*result.Data))
+			}
+
+			if len(tt.params) > 0 && !reflect.DeepEqual(result.PathParams, tt.params) {
+				t.Errorf("expected params %v, got %v", tt.params, result.PathParams)
+			}
+		})
+	}
+
+	t.Run("less specific pattern is used when the rules of a more specific one don't match", func(t *testing.T) {
+		svc := newService(t, map[string]string{
+			"users/{id}.get.200":           "user",
+			"users/{id}.get.200.meta.json": `{"match":{"headers":{"X-Tenant":"acme"}}}`,
+			"users/*.get.200":              "any",
+		})
+
+		if result := match(t, svc, "/users/42"); string(*result.Data) != "any" {
+			t.Errorf("expected 'any', got %q", string(*result.Data))
+		}
+	})
+
+	t.Run("patterns don't match requests with a query string unless declaring query rules", func(t *testing.T) {
+		svc := newService(t, map[string]string{
+			"users/{id}.get.200":                 "user",
+			"users/{id}.get.200.paged":           "paged",
+			"users/{id}.get.200.paged.meta.json": `{"match":{"query":{"page":{}}}}`,
+		})
+
+		if result := match(t, svc, "/users/42?page=1"); string(*result.Data) != "paged" {
+			t.Errorf("expected 'paged', got %q", string(*result.Data))
+		}
+
+		if result := match(t, svc, "/users/42?sort=name"); result.Path != "" {
+			t.Errorf("expected no mock, got %q", result.Path)
+		}
+	})
+
+	t.Run("lists and reads pattern mocks", func(t *testing.T) {
+		svc := newService(t, map[string]string{"users/{id}.get.200": "user"})
+
+		contents, err := svc.ListContents("test")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*contents) != 1 || (*contents)[0].Uri != "/users/{id}" {
+			t.Fatalf("expected the pattern mock to be listed, got %v", *contents)
+		}
+
+		result, err := svc.GetContent("example.com", "/users/{id}", "GET", "test", 200)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(*result.Data) != "user" {
+			t.Errorf("expected 'user', got %q", string(*result.Data))
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package content
+
+import (
+	"regexp"
+	"strings"
+)
+
+const wildcardSegment = "*"
+
+// paramSegmentRegex validates a path parameter segment, such as {id}
+var paramSegmentRegex = regexp.MustCompile(`^\{([a-zA-Z_]\w*)\}$`)
+
+// IsUriPattern tells whether the uri has any path parameter or wildcard segment
+func IsUriPattern(uri string) bool {
+	for _, segment := range splitUriPath(uri) {
+		if segmentRank(segment) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchUriPattern tells whether the uri matches the pattern, returning the values captured by its path parameters.
+// A wildcard segment matches any single segment without capturing it. The query string of the uri is ignored.
+func MatchUriPattern(pattern, uri string) (map[string]string, bool) {
+	uriPath, _, _ := strings.Cut(uri, "?")
+	patternSegments := splitUriPath(pattern)
+	uriSegments := splitUriPath(uriPath)
+
+	if len(patternSegments) != len(uriSegments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+
+	for i, segment := range patternSegments {
+		switch segmentRank(segment) {
+		case 0:
+			if segment != uriSegments[i] {
+				return nil, false
+			}
+		case 1:
+			params[paramSegmentRegex.FindStringSubmatch(segment)[1]] = uriSegments[i]
+		}
+	}
+
+	return params, true
+}
+
+// MoreSpecificUriPattern tells whether pattern a is more specific than pattern b. Segments are compared from left to
+// right, a literal segment being more specific than a path parameter, which is more specific than a wildcard.
+func MoreSpecificUriPattern(a, b string) bool {
+	aSegments := splitUriPath(a)
+	bSegments := splitUriPath(b)
+
+	for i := 0; i < len(aSegments) && i < len(bSegments); i++ {
+		aRank := segmentRank(aSegments[i])
+		bRank := segmentRank(bSegments[i])
+
+		if aRank != bRank {
+			return aRank < bRank
+		}
+	}
+
+	return len(aSegments) > len(bSegments)
+}
+
+// segmentRank returns 0 for a literal segment, 1 for a path parameter and 2 for a wildcard
+func segmentRank(segment string) int {
+	if segment == wildcardSegment {
+		return 2
+	}
+
+	if paramSegmentRegex.MatchString(segment) {
+		return 1
+	}
+
+	return 0
+}
+
+func splitUriPath(uri string) []string {
+	trimmed := strings.Trim(uri, "/")
+
+	if len(trimmed) == 0 {
+		return []string{}
+	}
+
+	return strings.Split(trimmed, "/")
+}
@@ -0,0 +1,82 @@
+package content
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsUriPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		uri      string
+		expected bool
+	}{
+		{"literal path", "/api/users", false},
+		{"root path", "/", false},
+		{"path parameter", "/api/users/{id}", true},
+		{"wildcard", "/api/*/users", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := IsUriPattern(tt.uri); result != tt.expected {
+				t.Errorf("IsUriPattern(%q) = %v, expected %v", tt.uri, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchUriPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		uri      string
+		params   map[string]string
+		expected bool
+	}{
+		{"path parameter", "/users/{id}", "/users/42", map[string]string{"id": "42"}, true},
+		{"several parameters", "/users/{id}/orders/{orderId}", "/users/1/orders/2", map[string]string{"id": "1", "orderId": "2"}, true},
+		{"wildcard", "/files/*", "/files/report", map[string]string{}, true},
+		{"query string ignored", "/users/{id}", "/users/42?expand=true", map[string]string{"id": "42"}, true},
+		{"literal mismatch", "/users/{id}", "/orders/42", nil, false},
+		{"fewer segments", "/users/{id}", "/users", nil, false},
+		{"more segments", "/users/{id}", "/users/42/orders", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, matches := MatchUriPattern(tt.pattern, tt.uri)
+
+			if matches != tt.expected {
+				t.Fatalf("MatchUriPattern(%q, %q) = %v, expected %v", tt.pattern, tt.uri, matches, tt.expected)
+			}
+
+			if matches && !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("expected params %v, got %v", tt.params, params)
+			}
+		})
+	}
+}
+
+func TestMoreSpecificUriPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected bool
+	}{
+		{"literal over parameter", "/users/me", "/users/{id}", true},
+		{"parameter over wildcard", "/users/{id}", "/users/*", true},
+		{"wildcard under parameter", "/users/*", "/users/{id}", false},
+		{"leftmost segment decides", "/users/{id}/*", "/{type}/{id}/orders", true},
+		{"equally specific", "/users/{id}", "/users/{name}", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := MoreSpecificUriPattern(tt.a, tt.b); result != tt.expected {
+				t.Errorf("MoreSpecificUriPattern(%q, %q) = %v, expected %v", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
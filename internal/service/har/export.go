@@ -0,0 +1,133 @@
+package har
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/service/traffic"
+)
+
+const (
+	creatorName = "go-mock-server"
+	httpVersion = "HTTP/1.1"
+)
+
+// FromTraffic builds an archive from the traffic log entries, in the same order
+func FromTraffic(entries []traffic.TrafficEntry, version string) *HAR {
+	archive := &HAR{
+		Log: Log{
+			Version: Version,
+			Creator: Creator{Name: creatorName, Version: version},
+			Entries: make([]Entry, 0, len(entries)),
+		},
+	}
+
+	for _, entry := range entries {
+		archive.Log.Entries = append(archive.Log.Entries, fromTrafficEntry(entry))
+	}
+
+	return archive
+}
+
+func fromTrafficEntry(entry traffic.TrafficEntry) Entry {
+	latency := float64(entry.Response.LatencyMs)
+
+	return Entry{
+		StartedDateTime: entry.Timestamp.Format(time.RFC3339Nano),
+		Time:            latency,
+		Request:         fromTrafficRequest(entry.Request),
+		Response:        fromTrafficResponse(entry.Response),
+		Timings:         Timings{Wait: latency},
+		UUID:            entry.UUID,
+		Metadata:        entry.Metadata,
+	}
+}
+
+func fromTrafficRequest(request traffic.TrafficRequest) Request {
+	scheme := request.Scheme
+
+	if len(scheme) == 0 {
+		scheme = "http"
+	}
+
+	requestURL := url.URL{Scheme: scheme, Host: request.Host, Path: request.Path, RawQuery: request.Query}
+	query, _ := url.ParseQuery(request.Query)
+
+	harRequest := Request{
+		Method:      request.Method,
+		URL:         requestURL.String(),
+		HTTPVersion: httpVersion,
+		Cookies:     make([]Cookie, 0),
+		Headers:     toNameValues(request.Headers),
+		QueryString: toNameValues(query),
+		HeadersSize: -1,
+		BodySize:    request.BodySize,
+	}
+
+	if request.BodySize > 0 {
+		// the post data has no encoding field, so a binary body is kept base64 encoded
+		harRequest.PostData = &PostData{
+			MimeType:  headerValue(request.Headers, "Content-Type"),
+			Text:      request.Body,
+			Truncated: request.BodyTruncated,
+		}
+	}
+
+	return harRequest
+}
+
+func fromTrafficResponse(response traffic.TrafficResponse) Response {
+	return Response{
+		Status:      response.StatusCode,
+		StatusText:  http.StatusText(response.StatusCode),
+		HTTPVersion: httpVersion,
+		Cookies:     make([]Cookie, 0),
+		Headers:     toNameValues(response.Headers),
+		Content: Content{
+			Size:      response.BodySize,
+			MimeType:  response.ContentType,
+			Text:      response.Body,
+			Encoding:  response.BodyEncoding,
+			Truncated: response.BodyTruncated,
+		},
+		RedirectURL: headerValue(response.Headers, "Location"),
+		HeadersSize: -1,
+		BodySize:    response.BodySize,
+	}
+}
+
+// toNameValues converts either flattened headers or parsed query values to a list sorted by name, so the archive
+// is the same for the same entries
+func toNameValues[V string | []string](values map[string]V) []NameValue {
+	nameValues := make([]NameValue, 0, len(values))
+
+	for name, value := range values {
+		switch typed := any(value).(type) {
+		case string:
+			nameValues = append(nameValues, NameValue{Name: name, Value: typed})
+		case []string:
+			for _, item := range typed {
+				nameValues = append(nameValues, NameValue{Name: name, Value: item})
+			}
+		}
+	}
+
+	sort.SliceStable(nameValues, func(i, j int) bool {
+		return nameValues[i].Name < nameValues[j].Name
+	})
+
+	return nameValues
+}
+
+func headerValue(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+
+	return ""
+}
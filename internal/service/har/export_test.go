@@ -0,0 +1,152 @@
+package har
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/service/traffic"
+)
+
+func TestFromTraffic(t *testing.T) {
+	timestamp := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	entries := []traffic.TrafficEntry{
+		{
+			UUID:      "first",
+			Timestamp: timestamp,
+			Request: traffic.TrafficRequest{
+				Method:   "POST",
+				Scheme:   "https",
+				Host:     "api.example.com",
+				Path:     "/users",
+				Query:    "tag=a&tag=b",
+				Headers:  map[string]string{"Content-Type": "application/json", "Accept": "*/*"},
+				Body:     `{"name": "john"}`,
+				BodySize: 16,
+			},
+			Response: traffic.TrafficResponse{
+				StatusCode:   302,
+				ContentType:  "application/octet-stream",
+				Headers:      map[string]string{"Location": "/users/1"},
+				Body:         "iVBORw==",
+				BodyEncoding: "base64",
+				BodySize:     4,
+				LatencyMs:    12,
+			},
+			Metadata: map[string]string{"Matched": "true"},
+		},
+		{
+			UUID:      "second",
+			Timestamp: timestamp,
+			Request:   traffic.TrafficRequest{Method: "GET", Host: "api.example.com", Path: "/health"},
+			Response:  traffic.TrafficResponse{StatusCode: 204},
+		},
+	}
+
+	archive := FromTraffic(entries, "1.0.0")
+
+	if archive.Log.Version != Version || archive.Log.Creator != (Creator{Name: "go-mock-server", Version: "1.0.0"}) {
+		t.Errorf("unexpected log %+v", archive.Log)
+	}
+
+	if len(archive.Log.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(archive.Log.Entries))
+	}
+
+	first := archive.Log.Entries[0]
+
+	if first.StartedDateTime != "2024-05-01T10:30:00Z" || first.Time != 12 || first.Timings.Wait != 12 || first.UUID != "first" {
+		t.Errorf("unexpected entry %+v", first)
+	}
+
+	if first.Request.URL != "https://api.example.com/users?tag=a&tag=b" {
+		t.Errorf("unexpected url %s", first.Request.URL)
+	}
+
+	wantQuery := []NameValue{{Name: "tag", Value: "a"}, {Name: "tag", Value: "b"}}
+
+	if len(first.Request.QueryString) != 2 || first.Request.QueryString[0] != wantQuery[0] || first.Request.QueryString[1] != wantQuery[1] {
+		t.Errorf("unexpected query string %+v", first.Request.QueryString)
+	}
+
+	if first.Request.Headers[0].Name != "Accept" || first.Request.Headers[1].Name != "Content-Type" {
+		t.Errorf("expected the headers sorted by name, got %+v", first.Request.Headers)
+	}
+
+	if first.Request.PostData == nil || first.Request.PostData.MimeType != "application/json" || first.Request.PostData.Text != `{"name": "john"}` {
+		t.Errorf("unexpected post data %+v", first.Request.PostData)
+	}
+
+	response := first.Response
+
+	if response.StatusText != "Found" || response.RedirectURL != "/users/1" || response.HeadersSize != -1 {
+		t.Errorf("unexpected response %+v", response)
+	}
+
+	if response.Content != (Content{Size: 4, MimeType: "application/octet-stream", Text: "iVBORw==", Encoding: "base64"}) {
+		t.Errorf("unexpected content %+v", response.Content)
+	}
+
+	second := archive.Log.Entries[1]
+
+	if second.Request.URL != "http://api.example.com/health" || second.Request.PostData != nil {
+		t.Errorf("unexpected request %+v", second.Request)
+	}
+
+	t.Run("can be imported back", func(t *testing.T) {
+		data, err := json.Marshal(archive)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		parsed, err := Parse(data)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		mocks, skipped := parsed.Mocks(nil)
+
+		if len(mocks) != 2 || len(skipped) != 0 {
+			t.Fatalf("unexpected mocks %+v, skipped %+v", mocks, skipped)
+		}
+
+		if mocks[0].URI != "/users?tag=a&tag=b" || string(mocks[0].Data) != "\x89PNG" {
+			t.Errorf("unexpected mock %+v", mocks[0])
+		}
+	})
+
+	t.Run("flags the bodies cut off by the traffic log", func(t *testing.T) {
+		request := traffic.TrafficRequest{Method: "POST", Host: "api.example.com", Path: "/upload"}
+		request.SetBody([]byte("a large upload"), 7)
+		response := traffic.TrafficResponse{StatusCode: 200}
+		response.SetBody([]byte("a large response"), 16, 7)
+
+		archive := FromTraffic([]traffic.TrafficEntry{{Request: request, Response: response}}, "1.0.0")
+		data, err := json.Marshal(archive)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		parsed, err := Parse(data)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		entry := parsed.Log.Entries[0]
+
+		if !entry.Request.PostData.Truncated || !entry.Response.Content.Truncated || entry.Response.Content.Size != 16 {
+			t.Errorf("expected the bodies to be flagged as cut off, got %+v, %+v", entry.Request.PostData, entry.Response.Content)
+		}
+
+		// importing it back gives no mock replaying part of the response
+		mocks, skipped := parsed.Mocks(nil)
+
+		if len(mocks) != 0 || len(skipped) != 1 || skipped[0].Reason != "the response body was only recorded in part (7 of 16 bytes)" {
+			t.Errorf("unexpected mocks %+v, skipped %+v", mocks, skipped)
+		}
+	})
+}
@@ -0,0 +1,294 @@
+package har
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Caik/go-mock-server/internal/service/traffic"
+	"github.com/Caik/go-mock-server/internal/util"
+)
+
+// Version is the version of the HAR format written and read
+const Version = "1.2"
+
+// ErrInvalidHAR tells that the document isn't a valid HAR file
+var ErrInvalidHAR = errors.New("invalid HAR file")
+
+// HAR is an HTTP Archive, as described by http://www.softwareishard.com/blog/har-12-spec/
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Entries []Entry `json:"entries"`
+}
+
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type Entry struct {
+	StartedDateTime string            `json:"startedDateTime"`
+	Time            float64           `json:"time"`
+	Request         Request           `json:"request"`
+	Response        Response          `json:"response"`
+	Cache           struct{}          `json:"cache"`
+	Timings         Timings           `json:"timings"`
+	UUID            string            `json:"_uuid,omitempty"`
+	Metadata        map[string]string `json:"_metadata,omitempty"`
+}
+
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type Cookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PostData is the body of a request. Truncated tells that Text holds only the start of it.
+type PostData struct {
+	MimeType  string `json:"mimeType"`
+	Text      string `json:"text"`
+	Truncated bool   `json:"_truncated,omitempty"`
+}
+
+// Content is the body of a response. Truncated tells that Text holds only the start of it, Size being the size of
+// the whole body.
+type Content struct {
+	Size      int    `json:"size"`
+	MimeType  string `json:"mimeType"`
+	Text      string `json:"text,omitempty"`
+	Encoding  string `json:"encoding,omitempty"`
+	Truncated bool   `json:"_truncated,omitempty"`
+}
+
+type Timings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// Mock is a mock generated from an entry of the archive
+type Mock struct {
+	URL         string // the url of the entry the mock was generated from
+	Host        string
+	URI         string
+	Method      string
+	StatusCode  int
+	ContentType string
+	Headers     map[string]string // the response headers to replay, other than Content-Type
+	Data        []byte
+}
+
+// skippedHeaders are the response headers not replayed, as they describe the recorded connection or body encoding
+// rather than the response
+var skippedHeaders = map[string]bool{
+	"Connection":        true,
+	"Content-Encoding":  true,
+	"Content-Length":    true,
+	"Content-Type":      true,
+	"Date":              true,
+	"Keep-Alive":        true,
+	"Transfer-Encoding": true,
+}
+
+// SkippedEntry is an entry of the archive no mock was generated for
+type SkippedEntry struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Status int    `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// Parse parses a HAR file
+func Parse(data []byte) (*HAR, error) {
+	var archive HAR
+
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHAR, err)
+	}
+
+	if archive.Log.Entries == nil {
+		return nil, fmt.Errorf("%w: log entries are missing", ErrInvalidHAR)
+	}
+
+	return &archive, nil
+}
+
+// Mocks generates a mock for every entry of the archive whose host is one of the given hosts, or for every entry
+// when no host is given. When several entries are sent to the same mock, the last one wins, as it is the latest
+// state of the session.
+func (h *HAR) Mocks(hosts []string) ([]Mock, []SkippedEntry) {
+	mocks := make([]Mock, 0)
+	skipped := make([]SkippedEntry, 0)
+	indexes := make(map[string]int)
+
+	for _, entry := range h.Log.Entries {
+		skip := func(reason string) {
+			skipped = append(skipped, SkippedEntry{
+				Method: entry.Request.Method,
+				URL:    entry.Request.URL,
+				Status: entry.Response.Status,
+				Reason: reason,
+			})
+		}
+
+		parsedURL, err := url.Parse(entry.Request.URL)
+
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+			skip("not an http or https url")
+			continue
+		}
+
+		host := strings.ToLower(parsedURL.Hostname())
+
+		if len(hosts) > 0 && !containsHost(hosts, host) {
+			continue
+		}
+
+		if !util.HostRegex.MatchString(host) && !util.IpAddressRegex.MatchString(host) {
+			skip(fmt.Sprintf("invalid host %q", host))
+			continue
+		}
+
+		uri := parsedURL.RequestURI()
+
+		if path, _, _ := strings.Cut(uri, "?"); !isMockablePath(path) {
+			skip(fmt.Sprintf("unsupported path %q: its segments can only have letters, digits, _ and -", path))
+			continue
+		}
+
+		if entry.Response.Status < 100 || entry.Response.Status > 599 {
+			skip("the request got no response")
+			continue
+		}
+
+		data, err := entry.Response.Content.body()
+
+		if err != nil {
+			skip(fmt.Sprintf("invalid response content: %v", err))
+			continue
+		}
+
+		// a mock replaying part of the body would be worse than no mock
+		if entry.Response.Content.Truncated || len(data) < entry.Response.Content.Size {
+			skip(fmt.Sprintf("the response body was only recorded in part (%d of %d bytes)", len(data), entry.Response.Content.Size))
+			continue
+		}
+
+		mock := Mock{
+			URL:         entry.Request.URL,
+			Host:        host,
+			URI:         uri,
+			Method:      strings.ToUpper(entry.Request.Method),
+			StatusCode:  entry.Response.Status,
+			ContentType: entry.Response.contentType(),
+			Headers:     entry.Response.replayedHeaders(),
+			Data:        data,
+		}
+
+		key := fmt.Sprintf("%s|%s|%s|%d", mock.Host, mock.URI, mock.Method, mock.StatusCode)
+
+		if index, exists := indexes[key]; exists {
+			mocks[index] = mock
+			continue
+		}
+
+		indexes[key] = len(mocks)
+		mocks = append(mocks, mock)
+	}
+
+	return mocks, skipped
+}
+
+// contentType returns the media type of the response, declared by its content or else by its headers
+func (r Response) contentType() string {
+	if len(r.Content.MimeType) > 0 {
+		return r.Content.MimeType
+	}
+
+	for _, header := range r.Headers {
+		if strings.EqualFold(header.Name, "Content-Type") {
+			return header.Value
+		}
+	}
+
+	return ""
+}
+
+// replayedHeaders returns the headers of the response to send along with its mock. The values redacted by the
+// traffic log are left out, and the last value wins when a header is repeated.
+func (r Response) replayedHeaders() map[string]string {
+	headers := make(map[string]string)
+
+	for _, header := range r.Headers {
+		name := http.CanonicalHeaderKey(header.Name)
+
+		if skippedHeaders[name] || strings.HasPrefix(name, ":") || header.Value == traffic.RedactedValue {
+			continue
+		}
+
+		headers[name] = header.Value
+	}
+
+	return headers
+}
+
+// body returns the raw bytes of the content
+func (c Content) body() ([]byte, error) {
+	if c.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(c.Text)
+	}
+
+	return []byte(c.Text), nil
+}
+
+// isMockablePath tells whether a mock file can be written for the path, following the rules of the content service
+func isMockablePath(path string) bool {
+	return path == "/" || util.UriRegex.MatchString(path) || util.UriPatternRegex.MatchString(path)
+}
+
+func containsHost(hosts []string, host string) bool {
+	for _, candidate := range hosts {
+		if strings.EqualFold(strings.TrimSpace(candidate), host) {
+			return true
+		}
+	}
+
+	return false
+}
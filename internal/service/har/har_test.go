@@ -0,0 +1,173 @@
+package har
+
+import (
+	"errors"
+	"testing"
+)
+
+func newEntry(method, rawURL string, status int, content Content) Entry {
+	return Entry{
+		Request:  Request{Method: method, URL: rawURL},
+		Response: Response{Status: status, Content: content},
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "valid file", data: `{"log": {"version": "1.2", "entries": []}}`},
+		{name: "not json", data: "not a file", wantErr: true},
+		{name: "no entries", data: `{"log": {"version": "1.2"}}`, wantErr: true},
+		{name: "not a har file", data: `{"openapi": "3.0.0"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			if err != nil && !errors.Is(err, ErrInvalidHAR) {
+				t.Errorf("expected ErrInvalidHAR, got %v", err)
+			}
+		})
+	}
+}
+
+func TestHAR_Mocks(t *testing.T) {
+	archive := &HAR{Log: Log{Entries: []Entry{
+		newEntry("get", "https://API.example.com/users?page=1", 200, Content{Text: `{"page": 1}`}),
+		newEntry("POST", "http://api.example.com/users", 201, Content{Text: `{"id": 1}`}),
+		newEntry("GET", "http://10.0.0.1:8080/health", 204, Content{}),
+		newEntry("GET", "https://cdn.example.com/logo", 200, Content{Text: "iVBORw==", Encoding: "base64"}),
+		newEntry("GET", "https://api.example.com/users?page=1", 200, Content{Text: `{"page": "last"}`}),
+		newEntry("GET", "wss://api.example.com/socket", 101, Content{}),
+		newEntry("GET", "http://localhost/users", 200, Content{}),
+		newEntry("GET", "https://api.example.com/aborted", 0, Content{}),
+		newEntry("GET", "https://cdn.example.com/broken", 200, Content{Text: "%%%", Encoding: "base64"}),
+	}}}
+
+	t.Run("generates a mock per response", func(t *testing.T) {
+		mocks, skipped := archive.Mocks(nil)
+
+		want := []Mock{
+			{Host: "api.example.com", URI: "/users?page=1", Method: "GET", StatusCode: 200, Data: []byte(`{"page": "last"}`)},
+			{Host: "api.example.com", URI: "/users", Method: "POST", StatusCode: 201, Data: []byte(`{"id": 1}`)},
+			{Host: "10.0.0.1", URI: "/health", Method: "GET", StatusCode: 204, Data: []byte{}},
+			{Host: "cdn.example.com", URI: "/logo", Method: "GET", StatusCode: 200, Data: []byte("\x89PNG")},
+		}
+
+		if len(mocks) != len(want) {
+			t.Fatalf("expected %d mocks, got %+v", len(want), mocks)
+		}
+
+		for i := range want {
+			if mocks[i].Host != want[i].Host || mocks[i].URI != want[i].URI || mocks[i].Method != want[i].Method ||
+				mocks[i].StatusCode != want[i].StatusCode || string(mocks[i].Data) != string(want[i].Data) {
+				t.Errorf("mock %d: expected %+v, got %+v", i, want[i], mocks[i])
+			}
+		}
+
+		wantReasons := []string{
+			"not an http or https url",
+			`invalid host "localhost"`,
+			"the request got no response",
+		}
+
+		if len(skipped) != len(wantReasons)+1 {
+			t.Fatalf("unexpected skipped entries %+v", skipped)
+		}
+
+		for i, reason := range wantReasons {
+			if skipped[i].Reason != reason {
+				t.Errorf("skipped %d: expected reason %q, got %q", i, reason, skipped[i].Reason)
+			}
+		}
+
+		if skipped[3].URL != "https://cdn.example.com/broken" {
+			t.Errorf("expected the invalid content to be skipped, got %+v", skipped[3])
+		}
+	})
+
+	t.Run("keeps the media type and headers of the responses", func(t *testing.T) {
+		entry := newEntry("GET", "https://api.example.com/login", 302, Content{MimeType: "text/html"})
+		entry.Response.Headers = []NameValue{
+			{Name: "location", Value: "/home"},
+			{Name: "Content-Length", Value: "0"},
+			{Name: "Content-Type", Value: "text/plain"},
+			{Name: "Set-Cookie", Value: "[REDACTED]"},
+		}
+		withoutMimeType := newEntry("GET", "https://api.example.com/users", 200, Content{Text: "[]"})
+		withoutMimeType.Response.Headers = []NameValue{{Name: "content-type", Value: "application/json"}}
+
+		mocks, _ := (&HAR{Log: Log{Entries: []Entry{entry, withoutMimeType}}}).Mocks(nil)
+
+		if len(mocks) != 2 {
+			t.Fatalf("expected 2 mocks, got %+v", mocks)
+		}
+
+		if mocks[0].ContentType != "text/html" {
+			t.Errorf("expected the media type of the content, got %q", mocks[0].ContentType)
+		}
+
+		if len(mocks[0].Headers) != 1 || mocks[0].Headers["Location"] != "/home" {
+			t.Errorf("expected only the Location header to be replayed, got %v", mocks[0].Headers)
+		}
+
+		if mocks[1].ContentType != "application/json" {
+			t.Errorf("expected the media type of the headers, got %q", mocks[1].ContentType)
+		}
+	})
+
+	t.Run("skips the paths no mock file can be written for", func(t *testing.T) {
+		mocks, skipped := (&HAR{Log: Log{Entries: []Entry{
+			newEntry("GET", "https://app.example.com/static/main.js", 200, Content{Text: "main()"}),
+			newEntry("GET", "https://app.example.com/favicon.ico", 200, Content{}),
+			newEntry("GET", "https://app.example.com/v1.0/users?page=1.5", 200, Content{}),
+			newEntry("GET", "https://app.example.com/api/users?page=1.5", 200, Content{Text: "[]"}),
+			newEntry("GET", "https://app.example.com/", 200, Content{Text: "<html>"}),
+		}}}).Mocks(nil)
+
+		if len(mocks) != 2 || mocks[0].URI != "/api/users?page=1.5" || mocks[1].URI != "/" {
+			t.Errorf("unexpected mocks %+v", mocks)
+		}
+
+		if len(skipped) != 3 || skipped[0].Reason != `unsupported path "/static/main.js": its segments can only have letters, digits, _ and -` {
+			t.Errorf("unexpected skipped entries %+v", skipped)
+		}
+	})
+
+	t.Run("skips the responses recorded in part", func(t *testing.T) {
+		mocks, skipped := (&HAR{Log: Log{Entries: []Entry{
+			newEntry("GET", "https://api.example.com/flagged", 200, Content{Text: "[1, 2", Size: 5, Truncated: true}),
+			newEntry("GET", "https://api.example.com/short", 200, Content{Text: "[1, 2", Size: 10}),
+			newEntry("GET", "https://api.example.com/whole", 200, Content{Text: "[1, 2]", Size: 6}),
+		}}}).Mocks(nil)
+
+		if len(mocks) != 1 || mocks[0].URI != "/whole" {
+			t.Errorf("unexpected mocks %+v", mocks)
+		}
+
+		if len(skipped) != 2 || skipped[1].Reason != "the response body was only recorded in part (5 of 10 bytes)" {
+			t.Errorf("unexpected skipped entries %+v", skipped)
+		}
+	})
+
+	t.Run("keeps only the given hosts", func(t *testing.T) {
+		mocks, skipped := archive.Mocks([]string{"CDN.example.com"})
+
+		if len(mocks) != 1 || mocks[0].URI != "/logo" {
+			t.Errorf("unexpected mocks %+v", mocks)
+		}
+
+		// the entries sent to the other hosts are left out, not skipped
+		if len(skipped) != 2 {
+			t.Errorf("unexpected skipped entries %+v", skipped)
+		}
+	})
+}
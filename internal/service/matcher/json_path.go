@@ -0,0 +1,115 @@
+package matcher
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// jsonPathToken is a single step of a JSONPath expression: either an object key or an array index
+type jsonPathToken struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseJsonPath parses the subset of JSONPath made of a root and child steps, e.g. $.user.name, $.items[0].id or
+// $['first name']
+func parseJsonPath(path string) ([]jsonPathToken, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, errors.New("path should start with $")
+	}
+
+	tokens := make([]jsonPathToken, 0)
+	rest := path[1:]
+
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+
+			if end == -1 {
+				end = len(rest) - 1
+			}
+
+			key := rest[1 : end+1]
+
+			if len(key) == 0 {
+				return nil, errors.New("empty key")
+			}
+
+			tokens = append(tokens, jsonPathToken{key: key})
+			rest = rest[end+1:]
+
+		case '[':
+			end := strings.Index(rest, "]")
+
+			if end == -1 {
+				return nil, errors.New("unclosed bracket")
+			}
+
+			content := rest[1:end]
+
+			if len(content) >= 2 && content[0] == '\'' && content[len(content)-1] == '\'' {
+				tokens = append(tokens, jsonPathToken{key: content[1 : len(content)-1]})
+			} else {
+				index, err := strconv.Atoi(content)
+
+				if err != nil || index < 0 {
+					return nil, errors.New("bracket should hold either a quoted key or a non-negative index")
+				}
+
+				tokens = append(tokens, jsonPathToken{index: index, isIndex: true})
+			}
+
+			rest = rest[end+1:]
+
+		default:
+			return nil, errors.New("unexpected character after " + strings.TrimSuffix(path, rest))
+		}
+	}
+
+	return tokens, nil
+}
+
+// LookupJsonPath returns the value found at the given path of a decoded JSON document. Paths are made of keys and
+// array indexes, e.g. $.items[0].id
+func LookupJsonPath(document any, path string) (any, bool) {
+	tokens, err := parseJsonPath(path)
+
+	if err != nil {
+		return nil, false
+	}
+
+	current := document
+
+	for _, token := range tokens {
+		if token.isIndex {
+			array, ok := current.([]any)
+
+			if !ok || token.index >= len(array) {
+				return nil, false
+			}
+
+			current = array[token.index]
+
+			continue
+		}
+
+		object, ok := current.(map[string]any)
+
+		if !ok {
+			return nil, false
+		}
+
+		value, exists := object[token.key]
+
+		if !exists {
+			return nil, false
+		}
+
+		current = value
+	}
+
+	return current, true
+}
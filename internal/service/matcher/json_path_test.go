@@ -0,0 +1,78 @@
+package matcher
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseJsonPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected []jsonPathToken
+		wantErr  bool
+	}{
+		{"root", "$", []jsonPathToken{}, false},
+		{"dot keys", "$.user.name", []jsonPathToken{{key: "user"}, {key: "name"}}, false},
+		{"array index", "$.items[2].id", []jsonPathToken{{key: "items"}, {index: 2, isIndex: true}, {key: "id"}}, false},
+		{"quoted key", "$['first name']", []jsonPathToken{{key: "first name"}}, false},
+		{"missing root", "user.name", nil, true},
+		{"empty key", "$..name", nil, true},
+		{"unclosed bracket", "$.items[0", nil, true},
+		{"negative index", "$.items[-1]", nil, true},
+		{"unexpected character", "$user", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := parseJsonPath(tt.path)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseJsonPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if len(tokens) != len(tt.expected) {
+				t.Fatalf("parseJsonPath(%q) = %v, expected %v", tt.path, tokens, tt.expected)
+			}
+
+			for i := range tokens {
+				if tokens[i] != tt.expected[i] {
+					t.Errorf("token %d = %v, expected %v", i, tokens[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLookupJsonPath(t *testing.T) {
+	var document any
+	_ = json.Unmarshal([]byte(`{"user":{"name":"alice"},"items":[{"id":"a"},{"id":"b"}]}`), &document)
+
+	tests := []struct {
+		name     string
+		path     string
+		expected any
+		found    bool
+	}{
+		{"nested key", "$.user.name", "alice", true},
+		{"array element", "$.items[1].id", "b", true},
+		{"index out of range", "$.items[5].id", nil, false},
+		{"missing key", "$.user.email", nil, false},
+		{"key on array", "$.items.id", nil, false},
+		{"index on object", "$.user[0]", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, found := LookupJsonPath(document, tt.path)
+
+			if found != tt.found || value != tt.expected {
+				t.Errorf("LookupJsonPath(%q) = (%v, %v), expected (%v, %v)", tt.path, value, found, tt.expected, tt.found)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package matcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Request holds the parts of an incoming request that rules can be evaluated against
+type Request struct {
+	Headers http.Header
+	Query   url.Values
+	Body    []byte
+
+	// ScenarioState returns the current state of a scenario. When nil, no mock tied to a scenario state is chosen.
+	ScenarioState func(name string) string
+}
+
+// Rules are the conditions a request has to meet for a mock to be chosen. All the conditions declared must hold.
+type Rules struct {
+	Headers  map[string]Condition `json:"headers,omitempty"`
+	Query    map[string]Condition `json:"query,omitempty"`
+	JsonBody map[string]Condition `json:"json_body,omitempty"` // keyed by JSONPath expression (e.g. $.user.name)
+	Body     *Condition           `json:"body,omitempty"`      // evaluated against the raw body
+}
+
+// Condition is evaluated against a single value of the request. It can be declared either as a plain string, which
+// must be equal to the value, or as an object combining the operators below. An empty object only requires the value
+// to be present.
+type Condition struct {
+	Equals   *string `json:"equals,omitempty"`
+	Contains string  `json:"contains,omitempty"`
+	Matches  string  `json:"matches,omitempty"`
+	Absent   bool    `json:"absent,omitempty"`
+
+	regex *regexp.Regexp
+}
+
+// NewRequest builds a Request out of the headers, raw query and body of an incoming request
+func NewRequest(headers http.Header, rawQuery string, body []byte) Request {
+	query, _ := url.ParseQuery(rawQuery)
+
+	return Request{
+		Headers: headers,
+		Query:   query,
+		Body:    body,
+	}
+}
+
+// Matches tells whether the request meets all the conditions declared
+func (r *Rules) Matches(request Request) bool {
+	for name, condition := range r.Headers {
+		if !condition.matchesAny(request.Headers.Values(name)) {
+			return false
+		}
+	}
+
+	for name, condition := range r.Query {
+		if !condition.matchesAny(request.Query[name]) {
+			return false
+		}
+	}
+
+	if len(r.JsonBody) > 0 {
+		var body any
+		decoder := json.NewDecoder(bytes.NewReader(request.Body))
+		decoder.UseNumber()
+
+		// a body that isn't JSON has none of the paths
+		validBody := decoder.Decode(&body) == nil
+
+		for path, condition := range r.JsonBody {
+			value, found := LookupJsonPath(body, path)
+
+			if !validBody || !found {
+				if !condition.Absent {
+					return false
+				}
+
+				continue
+			}
+
+			if !condition.matchesAny([]string{jsonValueToString(value)}) {
+				return false
+			}
+		}
+	}
+
+	if r.Body != nil && !r.Body.matchesAny([]string{string(request.Body)}) {
+		return false
+	}
+
+	return true
+}
+
+// HasQuery tells whether there are conditions on the query string
+func (r *Rules) HasQuery() bool {
+	return len(r.Query) > 0
+}
+
+func (r *Rules) validate() error {
+	for path := range r.JsonBody {
+		if _, err := parseJsonPath(path); err != nil {
+			return fmt.Errorf("invalid json_body path %q: %v", path, err)
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalJSON validates the rules while decoding them, so invalid rules are never evaluated
+func (r *Rules) UnmarshalJSON(data []byte) error {
+	type rules Rules
+	var decoded rules
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*r = Rules(decoded)
+
+	return r.validate()
+}
+
+// UnmarshalJSON accepts both the plain string and the object notations, compiling the regex when there's one
+func (c *Condition) UnmarshalJSON(data []byte) error {
+	var equals string
+
+	if err := json.Unmarshal(data, &equals); err == nil {
+		*c = Condition{Equals: &equals}
+
+		return nil
+	}
+
+	type condition Condition
+	var decoded condition
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return errors.New("condition should be either a string or an object")
+	}
+
+	*c = Condition(decoded)
+
+	if len(c.Matches) > 0 {
+		regex, err := regexp.Compile(c.Matches)
+
+		if err != nil {
+			return fmt.Errorf("invalid regex %q: %v", c.Matches, err)
+		}
+
+		c.regex = regex
+	}
+
+	return nil
+}
+
+// matchesAny tells whether any of the values meets the condition. When there's no value at all, only a condition
+// requiring it to be absent holds.
+func (c *Condition) matchesAny(values []string) bool {
+	if len(values) == 0 {
+		return c.Absent
+	}
+
+	if c.Absent {
+		return false
+	}
+
+	for _, value := range values {
+		if c.matches(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Condition) matches(value string) bool {
+	if c.Equals != nil && *c.Equals != value {
+		return false
+	}
+
+	if len(c.Contains) > 0 && !strings.Contains(value, c.Contains) {
+		return false
+	}
+
+	if c.regex != nil && !c.regex.MatchString(value) {
+		return false
+	}
+
+	return true
+}
+
+func jsonValueToString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return "null"
+	default:
+		data, err := json.Marshal(v)
+
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+
+		return string(data)
+	}
+}
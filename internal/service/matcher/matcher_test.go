@@ -0,0 +1,106 @@
+package matcher
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func newRules(t *testing.T, data string) *Rules {
+	t.Helper()
+
+	var rules Rules
+
+	if err := json.Unmarshal([]byte(data), &rules); err != nil {
+		t.Fatalf("unexpected error decoding rules: %v", err)
+	}
+
+	return &rules
+}
+
+func TestRules_Matches(t *testing.T) {
+	request := NewRequest(
+		http.Header{"X-Tenant": []string{"acme"}, "Authorization": []string{"Bearer abc123"}},
+		"page=2&sort=name",
+		[]byte(`{"user":{"name":"alice","age":30,"admin":true},"items":[{"id":7}]}`),
+	)
+
+	tests := []struct {
+		name     string
+		rules    string
+		expected bool
+	}{
+		{"no conditions", `{}`, true},
+		{"header equals", `{"headers":{"x-tenant":"acme"}}`, true},
+		{"header differs", `{"headers":{"X-Tenant":"other"}}`, false},
+		{"header contains", `{"headers":{"Authorization":{"contains":"Bearer"}}}`, true},
+		{"header matches regex", `{"headers":{"Authorization":{"matches":"^Bearer [a-z0-9]+$"}}}`, true},
+		{"header present", `{"headers":{"Authorization":{}}}`, true},
+		{"header missing", `{"headers":{"X-Missing":{}}}`, false},
+		{"header absent", `{"headers":{"X-Missing":{"absent":true}}}`, true},
+		{"header not absent", `{"headers":{"X-Tenant":{"absent":true}}}`, false},
+		{"query equals", `{"query":{"page":"2"}}`, true},
+		{"query differs", `{"query":{"page":"3"}}`, false},
+		{"json body string", `{"json_body":{"$.user.name":"alice"}}`, true},
+		{"json body number", `{"json_body":{"$.user.age":"30"}}`, true},
+		{"json body boolean", `{"json_body":{"$.user.admin":"true"}}`, true},
+		{"json body array index", `{"json_body":{"$.items[0].id":"7"}}`, true},
+		{"json body differs", `{"json_body":{"$.user.name":"bob"}}`, false},
+		{"json body missing path", `{"json_body":{"$.user.email":{}}}`, false},
+		{"json body absent path", `{"json_body":{"$.user.email":{"absent":true}}}`, true},
+		{"raw body matches regex", `{"body":{"matches":"\"name\":\"al"}}`, true},
+		{"raw body contains", `{"body":{"contains":"bob"}}`, false},
+		{"all conditions hold", `{"headers":{"X-Tenant":"acme"},"query":{"sort":"name"},"json_body":{"$.user.name":"alice"}}`, true},
+		{"one condition fails", `{"headers":{"X-Tenant":"acme"},"query":{"sort":"age"}}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := newRules(t, tt.rules).Matches(request); result != tt.expected {
+				t.Errorf("Matches() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+
+	t.Run("json body conditions fail on a body that isn't JSON", func(t *testing.T) {
+		rules := newRules(t, `{"json_body":{"$":{}}}`)
+
+		if rules.Matches(NewRequest(http.Header{}, "", []byte("plain text"))) {
+			t.Error("expected no match for a non-JSON body")
+		}
+	})
+}
+
+func TestRules_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   string
+		wantErr bool
+	}{
+		{"valid rules", `{"headers":{"X-Tenant":"acme"},"json_body":{"$.a[0]['b c']":"1"}}`, false},
+		{"invalid regex", `{"headers":{"X-Tenant":{"matches":"("}}}`, true},
+		{"invalid condition type", `{"query":{"page":2}}`, true},
+		{"invalid json path", `{"json_body":{"user.name":"alice"}}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rules Rules
+			err := json.Unmarshal([]byte(tt.rules), &rules)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRules_HasQuery(t *testing.T) {
+	if newRules(t, `{"headers":{"X-Tenant":"acme"}}`).HasQuery() {
+		t.Error("expected no query conditions")
+	}
+
+	if !newRules(t, `{"query":{"page":"2"}}`).HasQuery() {
+		t.Error("expected query conditions")
+	}
+}
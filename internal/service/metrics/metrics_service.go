@@ -0,0 +1,281 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/audit"
+	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/traffic"
+	"github.com/Caik/go-mock-server/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/expfmt"
+	"github.com/rs/zerolog/log"
+)
+
+// otherLabel is the value of the host and method labels of the requests to unknown hosts or with non-standard methods
+const otherLabel = "other"
+
+var (
+	// processingBuckets are the upper bounds, in seconds, of the time taken to build a response
+	processingBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+	// simulatedLatencyBuckets are the upper bounds, in seconds, of the simulated latencies
+	simulatedLatencyBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+)
+
+// MetricsService counts what the mock server does, and exposes it in the Prometheus text format along with the
+// runtime and process metrics. A nil MetricsService ignores every observation, so callers don't need to check if
+// metrics are enabled.
+type MetricsService struct {
+	registry          *prometheus.Registry
+	requests          *prometheus.CounterVec
+	processingTime    *prometheus.HistogramVec
+	simulatedLatency  *prometheus.HistogramVec
+	cacheLookups      *prometheus.CounterVec
+	simulatedStatuses *prometheus.CounterVec
+	simulatedFaults   *prometheus.CounterVec
+
+	// the host label is only kept for the hosts configured or having mocks, as the clients choose the host they send
+	hostsConfig *config.HostsConfig
+	mockedHosts map[string]struct{}
+	mu          sync.RWMutex
+}
+
+// NewMetricsService creates a new MetricsService from AppArguments.
+// Returns nil if metrics are disabled.
+func NewMetricsService(args *config.AppArguments, trafficLogService *traffic.TrafficLogService, hostsConfigWatcher *config.HostsConfigWatcher,
+	auditService *audit.AuditService, hostsConfig *config.HostsConfig, contentService content.ContentService) *MetricsService {
+	if args.DisableMetrics {
+		return nil
+	}
+
+	m := &MetricsService{
+		registry:    prometheus.NewRegistry(),
+		hostsConfig: hostsConfig,
+		mockedHosts: make(map[string]struct{}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mock_server_requests_total",
+			Help: "Requests answered by the mock server.",
+		}, []string{"host", "method", "status", "matched"}),
+		processingTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "mock_server_request_processing_seconds",
+			Help:    "Time taken to answer the requests, without the simulated latency.",
+			Buckets: processingBuckets,
+		}, []string{"host"}),
+		simulatedLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "mock_server_request_simulated_latency_seconds",
+			Help:    "Latency added to the requests by the latency simulation.",
+			Buckets: simulatedLatencyBuckets,
+		}, []string{"host"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mock_server_cache_lookups_total",
+			Help: "Lookups of mock responses in the cache.",
+		}, []string{"result"}),
+		simulatedStatuses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mock_server_simulated_statuses_total",
+			Help: "Statuses injected by the status simulation.",
+		}, []string{"host", "status"}),
+		simulatedFaults: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mock_server_simulated_faults_total",
+			Help: "Faults injected by the fault simulation.",
+		}, []string{"host", "fault"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.processingTime,
+		m.simulatedLatency,
+		m.cacheLookups,
+		m.simulatedStatuses,
+		m.simulatedFaults,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "mock_server_build_info",
+			Help:        "Version of the mock server, always 1.",
+			ConstLabels: prometheus.Labels{"version": config.GetVersion()},
+		}, func() float64 { return 1 }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "mock_server_traffic_log_entries",
+			Help: "Entries in the in-memory traffic log.",
+		}, func() float64 { return float64(trafficLogService.Size()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "mock_server_traffic_log_capacity",
+			Help: "Maximum number of entries in the in-memory traffic log.",
+		}, func() float64 { return float64(trafficLogService.Capacity()) }),
+	)
+
+	subscriberCounts := map[string]func() int{
+		"audit":        auditService.SubscriberCount,
+		"hosts_config": hostsConfigWatcher.SubscriberCount,
+		"traffic":      trafficLogService.SubscriberCount,
+		"content": func() int {
+			if contentService == nil {
+				return 0
+			}
+
+			return contentService.SubscriberCount()
+		},
+	}
+
+	for broadcaster, count := range subscriberCounts {
+		m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "mock_server_broadcaster_subscribers",
+			Help:        "Current subscribers of the event broadcasters.",
+			ConstLabels: prometheus.Labels{"broadcaster": broadcaster},
+		}, func() float64 { return float64(count()) }))
+	}
+
+	m.watchMockedHosts(contentService)
+
+	return m
+}
+
+// watchMockedHosts keeps track of the hosts having mocks. Hosts are never forgotten, so the removal of their last
+// mock doesn't move their series to the other host label.
+func (m *MetricsService) watchMockedHosts(contentService content.ContentService) {
+	if contentService == nil {
+		return
+	}
+
+	// subscribing before listing, so no mock created in between is missed
+	channel := contentService.Subscribe("metrics_service", content.Created, content.Updated)
+
+	for _, list := range []func(uuid string) (*[]content.ContentData, error){contentService.ListContents, contentService.ListDefaultContents} {
+		data, err := list("")
+
+		if err != nil {
+			log.Err(err).
+				Stack().
+				Msg("error while trying to list the mocked hosts")
+
+			continue
+		}
+
+		for _, item := range *data {
+			m.addMockedHost(item.Host)
+		}
+	}
+
+	go func() {
+		for event := range channel {
+			m.addMockedHost(event.Data.Host)
+		}
+	}()
+}
+
+func (m *MetricsService) addMockedHost(host string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mockedHosts[host] = struct{}{}
+}
+
+// hostLabel returns the host as a label value if it's configured or has mocks, and other otherwise
+func (m *MetricsService) hostLabel(host string) string {
+	if m.hostsConfig != nil && m.hostsConfig.GetHostConfig(host) != nil {
+		return host
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.mockedHosts[host]; ok {
+		return host
+	}
+
+	return otherLabel
+}
+
+// methodLabel returns the method as a label value if it's a standard HTTP method, and other otherwise
+func methodLabel(method string) string {
+	if util.HttpMethodRegex.MatchString(method) {
+		return method
+	}
+
+	return otherLabel
+}
+
+// ObserveRequest counts an answered request. The duration is the whole time taken to answer it, of which
+// simulatedLatency was added by the latency simulation.
+func (m *MetricsService) ObserveRequest(host, method string, statusCode int, matched bool, duration, simulatedLatency time.Duration) {
+	if m == nil {
+		return
+	}
+
+	host = m.hostLabel(host)
+
+	m.requests.WithLabelValues(host, methodLabel(method), strconv.Itoa(statusCode), strconv.FormatBool(matched)).Inc()
+	m.processingTime.WithLabelValues(host).Observe(max(duration-simulatedLatency, 0).Seconds())
+
+	if simulatedLatency > 0 {
+		m.simulatedLatency.WithLabelValues(host).Observe(simulatedLatency.Seconds())
+	}
+}
+
+// ObserveCacheLookup counts a lookup of a mock response in the cache
+func (m *MetricsService) ObserveCacheLookup(hit bool) {
+	if m == nil {
+		return
+	}
+
+	result := "miss"
+
+	if hit {
+		result = "hit"
+	}
+
+	m.cacheLookups.WithLabelValues(result).Inc()
+}
+
+// ObserveSimulatedStatus counts a status injected by the status simulation
+func (m *MetricsService) ObserveSimulatedStatus(host string, statusCode int) {
+	if m == nil {
+		return
+	}
+
+	m.simulatedStatuses.WithLabelValues(m.hostLabel(host), strconv.Itoa(statusCode)).Inc()
+}
+
+// ObserveSimulatedFault counts a fault injected by the fault simulation
+func (m *MetricsService) ObserveSimulatedFault(host, fault string) {
+	if m == nil {
+		return
+	}
+
+	m.simulatedFaults.WithLabelValues(m.hostLabel(host), fault).Inc()
+}
+
+// Handler returns the handler serving the metrics to Prometheus
+func (m *MetricsService) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Write writes every metric in the Prometheus text format
+func (m *MetricsService) Write(w io.Writer) error {
+	if m == nil {
+		return nil
+	}
+
+	families, err := m.registry.Gather()
+
+	if err != nil {
+		return err
+	}
+
+	encoder := expfmt.NewEncoder(w, expfmt.NewFormat(expfmt.TypeTextPlain))
+
+	for _, family := range families {
+		if err := encoder.Encode(family); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
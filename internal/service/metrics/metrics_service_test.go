@@ -0,0 +1,174 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/audit"
+	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/traffic"
+)
+
+func writeMetrics(t *testing.T, m *MetricsService) string {
+	t.Helper()
+
+	var builder strings.Builder
+
+	if err := m.Write(&builder); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return builder.String()
+}
+
+func TestNewMetricsService(t *testing.T) {
+	t.Run("returns nil when disabled", func(t *testing.T) {
+		if m := NewMetricsService(&config.AppArguments{DisableMetrics: true}, nil, nil, nil, nil, nil); m != nil {
+			t.Error("expected nil service")
+		}
+	})
+
+	t.Run("exposes the traffic log, broadcaster and runtime gauges", func(t *testing.T) {
+		trafficLogService := traffic.NewTrafficLogService(&config.AppArguments{TrafficLogBufferSize: 10})
+		trafficLogService.Capture(traffic.TrafficEntry{UUID: "a"})
+		trafficLogService.Subscribe("subscriber", nil)
+		defer trafficLogService.Unsubscribe("subscriber")
+
+		auditService, err := audit.NewAuditService(&config.AppArguments{AuditLogBufferSize: 10})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		auditService.Subscribe("subscriber", audit.AuditFilters{})
+		defer auditService.Unsubscribe("subscriber")
+
+		contentService := content.NewFilesystemContentService(&config.MocksDirectoryConfig{Path: t.TempDir()})
+		contentService.Subscribe("subscriber")
+		defer contentService.Unsubscribe("subscriber")
+
+		m := NewMetricsService(&config.AppArguments{}, trafficLogService, nil, auditService, nil, contentService)
+		defer contentService.Unsubscribe("metrics_service")
+
+		output := writeMetrics(t, m)
+
+		for _, line := range []string{
+			"mock_server_traffic_log_entries 1\n",
+			"mock_server_traffic_log_capacity 10\n",
+			`mock_server_broadcaster_subscribers{broadcaster="traffic"} 1` + "\n",
+			`mock_server_broadcaster_subscribers{broadcaster="hosts_config"} 0` + "\n",
+			`mock_server_broadcaster_subscribers{broadcaster="audit"} 1` + "\n",
+			// the metrics service listens to the content events too
+			`mock_server_broadcaster_subscribers{broadcaster="content"} 2` + "\n",
+			`mock_server_build_info{version="dev"} 1` + "\n",
+			"# TYPE go_goroutines gauge\n",
+			"# TYPE process_start_time_seconds gauge\n",
+		} {
+			if !strings.Contains(output, line) {
+				t.Errorf("expected %q in\n%s", line, output)
+			}
+		}
+	})
+}
+
+func TestMetricsService_Observe(t *testing.T) {
+	hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{"example.com": {}})
+	m := NewMetricsService(&config.AppArguments{}, nil, nil, nil, hostsConfig, nil)
+
+	m.ObserveRequest("example.com", "GET", 200, true, 300*time.Millisecond, 200*time.Millisecond)
+	m.ObserveRequest("example.com", "GET", 200, true, 2*time.Millisecond, 0)
+	m.ObserveRequest("example.com", "POST", 404, false, time.Millisecond, 0)
+	m.ObserveCacheLookup(true)
+	m.ObserveCacheLookup(false)
+	m.ObserveCacheLookup(false)
+	m.ObserveSimulatedStatus("example.com", 503)
+	m.ObserveSimulatedFault("example.com", "connection_reset")
+
+	output := writeMetrics(t, m)
+
+	for _, line := range []string{
+		`mock_server_requests_total{host="example.com",matched="true",method="GET",status="200"} 2`,
+		`mock_server_requests_total{host="example.com",matched="false",method="POST",status="404"} 1`,
+		`mock_server_request_processing_seconds_bucket{host="example.com",le="0.1"} 3`,
+		`mock_server_request_processing_seconds_count{host="example.com"} 3`,
+		`mock_server_request_simulated_latency_seconds_bucket{host="example.com",le="0.1"} 0`,
+		`mock_server_request_simulated_latency_seconds_bucket{host="example.com",le="0.25"} 1`,
+		`mock_server_request_simulated_latency_seconds_count{host="example.com"} 1`,
+		`mock_server_cache_lookups_total{result="hit"} 1`,
+		`mock_server_cache_lookups_total{result="miss"} 2`,
+		`mock_server_simulated_statuses_total{host="example.com",status="503"} 1`,
+		`mock_server_simulated_faults_total{fault="connection_reset",host="example.com"} 1`,
+	} {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("expected %q in\n%s", line, output)
+		}
+	}
+}
+
+func TestMetricsService_labels(t *testing.T) {
+	hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{"configured.com": {}})
+	contentService := content.NewFilesystemContentService(&config.MocksDirectoryConfig{Path: t.TempDir()})
+	data := []byte("hello")
+
+	if err := contentService.SetContent("mocked.com", "/users", "GET", "test-uuid", 200, &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m := NewMetricsService(&config.AppArguments{}, nil, nil, nil, hostsConfig, contentService)
+	defer contentService.Unsubscribe("metrics_service")
+
+	if err := contentService.SetContent("created.com", "/users", "GET", "test-uuid", 200, &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the mocks created after the service are known through the content events
+	for deadline := time.Now().Add(time.Second); m.hostLabel("created.com") == otherLabel && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	m.ObserveRequest("configured.com", "GET", 200, true, time.Millisecond, 0)
+	m.ObserveRequest("mocked.com", "GET", 200, true, time.Millisecond, 0)
+	m.ObserveRequest("created.com", "GET", 200, true, time.Millisecond, 0)
+	m.ObserveRequest("random-1.com", "GET", 404, false, time.Millisecond, 0)
+	m.ObserveRequest("random-2.com", "GET", 404, false, time.Millisecond, 0)
+	m.ObserveRequest("configured.com", "FOO", 404, false, time.Millisecond, 0)
+	m.ObserveSimulatedStatus("random-1.com", 503)
+	m.ObserveSimulatedFault("random-1.com", "connection_reset")
+
+	output := writeMetrics(t, m)
+
+	for _, line := range []string{
+		`mock_server_requests_total{host="configured.com",matched="true",method="GET",status="200"} 1`,
+		`mock_server_requests_total{host="mocked.com",matched="true",method="GET",status="200"} 1`,
+		`mock_server_requests_total{host="created.com",matched="true",method="GET",status="200"} 1`,
+		`mock_server_requests_total{host="other",matched="false",method="GET",status="404"} 2`,
+		`mock_server_requests_total{host="configured.com",matched="false",method="other",status="404"} 1`,
+		`mock_server_request_processing_seconds_count{host="other"} 2`,
+		`mock_server_simulated_statuses_total{host="other",status="503"} 1`,
+		`mock_server_simulated_faults_total{fault="connection_reset",host="other"} 1`,
+	} {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("expected %q in\n%s", line, output)
+		}
+	}
+
+	if strings.Contains(output, "random-") {
+		t.Errorf("expected no label for the unknown hosts, got\n%s", output)
+	}
+}
+
+func TestMetricsService_nil(t *testing.T) {
+	var m *MetricsService
+
+	// none of these should panic
+	m.ObserveRequest("example.com", "GET", 200, true, time.Second, 0)
+	m.ObserveCacheLookup(true)
+	m.ObserveSimulatedStatus("example.com", 500)
+	m.ObserveSimulatedFault("example.com", "garbage")
+
+	if output := writeMetrics(t, m); output != "" {
+		t.Errorf("expected no output, got %s", output)
+	}
+}
@@ -2,14 +2,23 @@ package mock
 
 import (
 	"encoding/json"
+	"strconv"
+	"strings"
 
 	"github.com/Caik/go-mock-server/internal/service/cache"
+	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/metrics"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type cacheMockService struct {
-	next         mockService
-	cacheService cache.CacheService
+	next           mockService
+	cacheService   cache.CacheService
+	contentService content.ContentService
+	metricsService *metrics.MetricsService
 }
 
 func (c *cacheMockService) getMockResponse(mockRequest MockRequest) *MockResponse {
@@ -17,6 +26,7 @@ func (c *cacheMockService) getMockResponse(mockRequest MockRequest) *MockRespons
 	data, exists := c.cacheService.Get(cacheKey, mockRequest.Uuid)
 
 	if !exists {
+		c.observeLookup(mockRequest, false)
 		return c.refreshCache(mockRequest, cacheKey)
 	}
 
@@ -29,16 +39,16 @@ func (c *cacheMockService) getMockResponse(mockRequest MockRequest) *MockRespons
 			Str("uuid", mockRequest.Uuid).
 			Msg("error while deserializing data from cache")
 
+		c.observeLookup(mockRequest, false)
 		return c.nextOrNil(mockRequest)
 	}
 
+	c.observeLookup(mockRequest, true)
+
 	log.Info().
 		Str("uuid", mockRequest.Uuid).
 		Msg("found mock response on cache")
 
-	// background cache refresh
-	go c.refreshCache(mockRequest, cacheKey)
-
 	// Update metadata to indicate this was served from cache
 	mockResponse.AddMetadata(MetadataSource, "cache")
 	mockResponse.AddMetadata(MetadataPath, cacheKey)
@@ -46,6 +56,12 @@ func (c *cacheMockService) getMockResponse(mockRequest MockRequest) *MockRespons
 	return &mockResponse
 }
 
+// observeLookup counts the lookup, and tells the span of the request whether it was a hit
+func (c *cacheMockService) observeLookup(mockRequest MockRequest, hit bool) {
+	c.metricsService.ObserveCacheLookup(hit)
+	trace.SpanFromContext(mockRequest.ctx()).SetAttributes(attribute.Bool("mock.cache.hit", hit))
+}
+
 func (c *cacheMockService) setNext(next mockService) {
 	c.next = next
 }
@@ -77,6 +93,11 @@ func (c *cacheMockService) refreshCache(mockRequest MockRequest, cacheKey string
 		return nil
 	}
 
+	// the cache key doesn't hold the headers, query or body of the request
+	if freshResponse.conditional {
+		return freshResponse
+	}
+
 	log.Info().
 		Str("host", mockRequest.Host).
 		Str("uri", mockRequest.URI).
@@ -99,9 +120,77 @@ func (c *cacheMockService) refreshCache(mockRequest MockRequest, cacheKey string
 	return freshResponse
 }
 
-func newCacheMockService(cacheService cache.CacheService) *cacheMockService {
-	// TODO listen to content changes to remove stuff from cache
-	return &cacheMockService{
-		cacheService: cacheService,
+// listenToContentChanges evicts the cached responses of every mock that is created, updated or removed
+func (c *cacheMockService) listenToContentChanges() {
+	uuid := uuid.NewString()
+	channel := c.contentService.Subscribe("cache_mock_service")
+
+	go func() {
+		log.Info().
+			Str("uuid", uuid).
+			Msg("starting to listen for content changes")
+
+		for event := range channel {
+			evicted := c.cacheService.DeleteFunc(func(cacheKey string) bool {
+				return cacheKeyMatchesContent(cacheKey, event.Data)
+			}, uuid)
+
+			log.Info().
+				Str("uuid", uuid).
+				Str("event", event.Type.String()).
+				Str("host", event.Data.Host).
+				Str("uri", event.Data.Uri).
+				Str("method", event.Data.Method).
+				Int("evicted", evicted).
+				Msg("content changed, evicting cached responses")
+		}
+
+		log.Info().
+			Str("uuid", uuid).
+			Msg("stopping to listen for content changes")
+	}()
+}
+
+// cacheKeyMatchesContent tells whether the cached response may have been served from the given mock. The uri of a
+// cache key holds the query of the request, and the mock uri may be a pattern. A _default file may have been served
+// for any uri of its host, method and status.
+func cacheKeyMatchesContent(cacheKey string, data content.ContentData) bool {
+	prefix := strings.Join([]string{data.Host, data.Method, ""}, ":")
+	statusIndex := strings.LastIndex(cacheKey, ":")
+
+	if !strings.HasPrefix(cacheKey, prefix) || statusIndex < len(prefix) {
+		return false
+	}
+
+	if cacheKey[statusIndex+1:] != strconv.Itoa(data.StatusCode) {
+		return false
+	}
+
+	if data.Uri == content.DefaultUri {
+		return true
+	}
+
+	uriPath, _, _ := strings.Cut(cacheKey[len(prefix):statusIndex], "?")
+	mockPath, _, _ := strings.Cut(data.Uri, "?")
+
+	if content.IsUriPattern(mockPath) {
+		_, matches := content.MatchUriPattern(mockPath, uriPath)
+		return matches
+	}
+
+	return uriPath == mockPath
+}
+
+func newCacheMockService(cacheService cache.CacheService, contentService content.ContentService, metricsService *metrics.MetricsService) *cacheMockService {
+	service := &cacheMockService{
+		cacheService:   cacheService,
+		contentService: contentService,
+		metricsService: metricsService,
 	}
+
+	if contentService != nil {
+		service.listenToContentChanges()
+	}
+
+	return service
 }
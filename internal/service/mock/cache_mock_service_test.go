@@ -2,10 +2,13 @@ package mock
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
+	"github.com/Caik/go-mock-server/internal/config"
 	"github.com/Caik/go-mock-server/internal/service/cache"
 	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/metrics"
 )
 
 // inMemoryCacheService is a simple in-memory cache for testing
@@ -31,12 +34,43 @@ func (c *inMemoryCacheService) Set(key string, data *[]byte, uuid string) {
 	c.data[key] = *data
 }
 
+func (c *inMemoryCacheService) Delete(key, uuid string) bool {
+	_, exists := c.data[key]
+	delete(c.data, key)
+
+	return exists
+}
+
+func (c *inMemoryCacheService) DeleteFunc(match func(key string) bool, uuid string) int {
+	removed := 0
+
+	for key := range c.data {
+		if match(key) {
+			delete(c.data, key)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+func (c *inMemoryCacheService) Clear(uuid string) int {
+	removed := len(c.data)
+	c.data = make(map[string][]byte)
+
+	return removed
+}
+
+func (c *inMemoryCacheService) Stats() cache.CacheStats {
+	return cache.CacheStats{Entries: len(c.data)}
+}
+
 var _ cache.CacheService = (*inMemoryCacheService)(nil)
 
 func TestCacheMockService_getMockResponse_cacheMiss(t *testing.T) {
 	t.Run("cache miss fetches from next service and caches result", func(t *testing.T) {
 		cacheStore := &inMemoryCacheService{data: make(map[string][]byte)}
-		svc := newCacheMockService(cacheStore)
+		svc := newCacheMockService(cacheStore, nil, nil)
 
 		data := []byte(`{"key":"value"}`)
 		nextSvc := &contentMockService{
@@ -69,6 +103,53 @@ func TestCacheMockService_getMockResponse_cacheMiss(t *testing.T) {
 	})
 }
 
+func TestCacheMockService_metrics(t *testing.T) {
+	cacheStore := &inMemoryCacheService{data: make(map[string][]byte)}
+	metricsService := metrics.NewMetricsService(&config.AppArguments{}, nil, nil, nil, nil, nil)
+	svc := newCacheMockService(cacheStore, nil, metricsService)
+
+	data := []byte("hello")
+	svc.setNext(&mockMockService{response: &MockResponse{StatusCode: 200, Data: &data}})
+
+	req := MockRequest{Host: "example.com", URI: "/api/test", Method: "GET", Uuid: "test-uuid"}
+
+	for range 3 {
+		svc.getMockResponse(req)
+	}
+
+	var output strings.Builder
+	metricsService.Write(&output)
+
+	for _, line := range []string{
+		`mock_server_cache_lookups_total{result="hit"} 2`,
+		`mock_server_cache_lookups_total{result="miss"} 1`,
+	} {
+		if !strings.Contains(output.String(), line) {
+			t.Errorf("expected %q in\n%s", line, output.String())
+		}
+	}
+}
+
+func TestCacheMockService_getMockResponse_conditional(t *testing.T) {
+	t.Run("responses chosen by match rules are not cached", func(t *testing.T) {
+		cacheStore := &inMemoryCacheService{data: make(map[string][]byte)}
+		svc := newCacheMockService(cacheStore, nil, nil)
+
+		data := []byte("alice")
+		svc.setNext(&mockMockService{response: &MockResponse{StatusCode: 200, Data: &data, conditional: true}})
+
+		resp := svc.getMockResponse(MockRequest{Host: "example.com", URI: "/api/users", Method: "POST", Uuid: "test-uuid"})
+
+		if resp == nil || string(*resp.Data) != "alice" {
+			t.Fatal("expected the response of the next service")
+		}
+
+		if len(cacheStore.data) != 0 {
+			t.Errorf("expected nothing to be cached, got %d entries", len(cacheStore.data))
+		}
+	})
+}
+
 func TestCacheMockService_getMockResponse_cacheHit(t *testing.T) {
 	t.Run("cache hit returns cached response with source=cache", func(t *testing.T) {
 		// Pre-populate cache with a serialized response
@@ -94,7 +175,7 @@ func TestCacheMockService_getMockResponse_cacheHit(t *testing.T) {
 		cacheStore := &inMemoryCacheService{
 			data: map[string][]byte{cacheKey: serialized},
 		}
-		svc := newCacheMockService(cacheStore)
+		svc := newCacheMockService(cacheStore, nil, nil)
 
 		// next service returns something different - should NOT be used for cache hit
 		emptyData := []byte("should not be returned")
@@ -144,7 +225,7 @@ func TestCacheMockService_getMockResponse_cacheHitBadJSON(t *testing.T) {
 			data: map[string][]byte{cacheKey: []byte("not-valid-json")},
 		}
 
-		svc := newCacheMockService(cacheStore)
+		svc := newCacheMockService(cacheStore, nil, nil)
 
 		data := []byte(`{"fallback":true}`)
 		nextSvc := &contentMockService{
@@ -201,3 +282,87 @@ func TestCacheMockService_deserialize(t *testing.T) {
 		}
 	})
 }
+
+func TestCacheMockService_getMockResponse_cacheHitSkipsNext(t *testing.T) {
+	t.Run("cache hit doesn't reach the next service", func(t *testing.T) {
+		cacheStore := &inMemoryCacheService{data: make(map[string][]byte)}
+		svc := newCacheMockService(cacheStore, nil, nil)
+
+		data := []byte("cached")
+		next := &mockMockService{response: &MockResponse{StatusCode: 200, Data: &data}}
+		svc.setNext(next)
+
+		req := MockRequest{Host: "example.com", URI: "/api/test", Method: "GET", Uuid: "first"}
+		svc.getMockResponse(req)
+
+		req.Uuid = "second"
+		svc.getMockResponse(req)
+
+		if next.lastRequest.Uuid != "first" {
+			t.Errorf("expected only the first request to reach the next service, got %q", next.lastRequest.Uuid)
+		}
+	})
+}
+
+func TestCacheMockService_contentChanges(t *testing.T) {
+	t.Run("evicts the responses of a changed mock", func(t *testing.T) {
+		events := make(chan content.ContentEvent)
+		cacheStore := cache.NewInMemoryCacheService(&config.AppArguments{})
+		svc := newCacheMockService(cacheStore, &mockContentService{events: events}, nil)
+
+		data := []byte("v1")
+		svc.setNext(&mockMockService{response: &MockResponse{StatusCode: 200, Data: &data}})
+
+		svc.getMockResponse(MockRequest{Host: "example.com", URI: "/api/users?page=2", Method: "GET", StatusCode: 200})
+		svc.getMockResponse(MockRequest{Host: "example.com", URI: "/api/orders", Method: "GET", StatusCode: 200})
+
+		events <- content.ContentEvent{
+			Type: content.Updated,
+			Data: content.ContentData{Host: "example.com", Uri: "/api/users", Method: "GET", StatusCode: 200},
+		}
+
+		// the events are handled one at a time, so the first one is done once the second is received
+		events <- content.ContentEvent{Type: content.Updated, Data: content.ContentData{Host: "other.com"}}
+
+		if _, exists := cacheStore.Get("example.com:GET:/api/users?page=2:200", "test-uuid"); exists {
+			t.Error("expected the response of the changed mock to be evicted")
+		}
+
+		if _, exists := cacheStore.Get("example.com:GET:/api/orders:200", "test-uuid"); !exists {
+			t.Error("expected the response of another mock to be kept")
+		}
+	})
+}
+
+func TestCacheKeyMatchesContent(t *testing.T) {
+	users := content.ContentData{Host: "example.com", Uri: "/api/users", Method: "GET", StatusCode: 200}
+	pattern := content.ContentData{Host: "example.com", Uri: "/api/users/{id}", Method: "GET", StatusCode: 200}
+	fallback := content.ContentData{Host: "example.com", Uri: content.DefaultUri, Method: "GET", StatusCode: 404}
+
+	tests := []struct {
+		name     string
+		cacheKey string
+		data     content.ContentData
+		expected bool
+	}{
+		{"same uri", "example.com:GET:/api/users:200", users, true},
+		{"same uri with query", "example.com:GET:/api/users?page=2:200", users, true},
+		{"other uri", "example.com:GET:/api/users/1:200", users, false},
+		{"other host", "other.com:GET:/api/users:200", users, false},
+		{"other method", "example.com:POST:/api/users:200", users, false},
+		{"other status", "example.com:GET:/api/users:500", users, false},
+		{"uri matching the pattern", "example.com:GET:/api/users/1:200", pattern, true},
+		{"uri not matching the pattern", "example.com:GET:/api/orders/1:200", pattern, false},
+		{"any uri of the default", "example.com:GET:/api/orders/1?page=2:404", fallback, true},
+		{"other status than the default", "example.com:GET:/api/orders/1:200", fallback, false},
+		{"other method than the default", "example.com:POST:/api/orders/1:404", fallback, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cacheKeyMatchesContent(tt.cacheKey, tt.data); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
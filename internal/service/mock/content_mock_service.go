@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Caik/go-mock-server/internal/rest"
 	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/matcher"
 	"github.com/gin-gonic/gin"
 )
 
@@ -20,12 +22,15 @@ type contentMockService struct {
 }
 
 func (c *contentMockService) getMockResponse(mockRequest MockRequest) *MockResponse {
-	result, err := c.contentService.GetContent(
+	_, rawQuery, _ := strings.Cut(mockRequest.URI, "?")
+
+	result, err := c.contentService.MatchContent(
 		mockRequest.Host,
 		mockRequest.URI,
 		mockRequest.Method,
 		mockRequest.Uuid,
 		mockRequest.StatusCode,
+		c.newMatcherRequest(mockRequest, rawQuery),
 	)
 
 	if err != nil {
@@ -52,11 +57,36 @@ func (c *contentMockService) getMockResponse(mockRequest MockRequest) *MockRespo
 		statusCode = 200
 	}
 
+	// no mock nor default found: the empty result has no path
+	if len(result.Path) == 0 {
+		resp := &MockResponse{
+			StatusCode:  statusCode,
+			Data:        result.Data,
+			conditional: result.Conditional,
+		}
+
+		resp.AddMetadata(MetadataMatched, "false")
+		resp.AddMetadata(MetadataSource, result.Source)
+
+		return resp
+	}
+
+	// a status declared alongside the mock takes precedence over the one of its file name
+	if result.StatusCode != 0 {
+		statusCode = result.StatusCode
+	}
+
 	resp := &MockResponse{
-		StatusCode: statusCode,
-		Data:       result.Data,
+		StatusCode:  statusCode,
+		Data:        result.Data,
+		PathParams:  result.PathParams,
+		Template:    result.Template,
+		conditional: result.Conditional,
+		scenario:    result.Scenario,
 	}
 
+	c.addContentHeaders(resp, result.Headers)
+
 	resp.AddMetadata(MetadataMatched, "true")
 	resp.AddMetadata(MetadataSource, result.Source)
 	resp.AddMetadata(MetadataPath, result.Path)
@@ -66,6 +96,34 @@ func (c *contentMockService) getMockResponse(mockRequest MockRequest) *MockRespo
 
 func (c *contentMockService) setNext(next mockService) {}
 
+func (c *contentMockService) newMatcherRequest(mockRequest MockRequest, rawQuery string) matcher.Request {
+	request := matcher.NewRequest(mockRequest.Headers, rawQuery, mockRequest.Body)
+	request.ScenarioState = mockRequest.scenarioState
+
+	return request
+}
+
+// addContentHeaders sets the headers declared alongside the mock file. Content-Type is kept apart, as it is
+// what the content type link relies on to decide whether a default should be applied.
+func (c *contentMockService) addContentHeaders(resp *MockResponse, headers map[string]string) {
+	if len(headers) == 0 {
+		return
+	}
+
+	others := make(map[string]string, len(headers))
+
+	for key, value := range headers {
+		if strings.EqualFold(key, "Content-Type") {
+			resp.ContentType = value
+			continue
+		}
+
+		others[key] = value
+	}
+
+	resp.AddHeaders(others)
+}
+
 func (c *contentMockService) new404Response(err error) *MockResponse {
 	msg := err.Error()
 
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/matcher"
 )
 
 // errContentService returns errContentServiceNotFound to trigger the 500 path
@@ -15,10 +16,18 @@ func (e *errContentService) GetContent(host, uri, method, uuid string, statusCod
 	return nil, errContentServiceNotFound
 }
 
+func (e *errContentService) MatchContent(host, uri, method, uuid string, statusCode int, request matcher.Request) (*content.ContentResult, error) {
+	return e.GetContent(host, uri, method, uuid, statusCode)
+}
+
 func (e *errContentService) SetContent(host, uri, method, uuid string, statusCode int, data *[]byte) error {
 	return nil
 }
 
+func (e *errContentService) SetContentMeta(host, uri, method, uuid string, statusCode int, meta content.ContentMeta) error {
+	return errors.New("error")
+}
+
 func (e *errContentService) DeleteContent(host, uri, method, uuid string, statusCode int) error {
 	return nil
 }
@@ -37,6 +46,25 @@ func (e *errContentService) Subscribe(subscriberId string, eventTypes ...content
 
 func (e *errContentService) Unsubscribe(subscriberId string) {}
 
+func (e *errContentService) SubscriberCount() int { return 0 }
+
+// resultContentService returns a fixed result for any lookup, keeping the request it was matched against
+type resultContentService struct {
+	errContentService
+	result  *content.ContentResult
+	request matcher.Request
+}
+
+func (r *resultContentService) GetContent(host, uri, method, uuid string, statusCode int) (*content.ContentResult, error) {
+	return r.result, nil
+}
+
+func (r *resultContentService) MatchContent(host, uri, method, uuid string, statusCode int, request matcher.Request) (*content.ContentResult, error) {
+	r.request = request
+
+	return r.GetContent(host, uri, method, uuid, statusCode)
+}
+
 func TestContentMockService_new500Response(t *testing.T) {
 	t.Run("returns 500 when content service returns errContentServiceNotFound", func(t *testing.T) {
 		svc := &contentMockService{contentService: &errContentService{}}
@@ -137,6 +165,164 @@ func TestContentMockService_getMockResponse_found(t *testing.T) {
 	})
 }
 
+func TestContentMockService_getMockResponse_headers(t *testing.T) {
+	t.Run("sets headers declared alongside the mock file", func(t *testing.T) {
+		data := []byte("")
+		svc := &contentMockService{
+			contentService: &resultContentService{
+				result: &content.ContentResult{
+					Data:   &data,
+					Source: "filesystem",
+					Path:   "/mocks/example.com/login.post.302",
+					Headers: map[string]string{
+						"Location":     "/home",
+						"content-type": "text/plain",
+					},
+				},
+			},
+		}
+
+		resp := svc.getMockResponse(MockRequest{Host: "example.com", URI: "/login", Method: "POST", StatusCode: 302})
+
+		if resp.Headers["Location"] != "/home" {
+			t.Errorf("expected Location header, got %v", resp.Headers)
+		}
+
+		if resp.ContentType != "text/plain" {
+			t.Errorf("expected content type text/plain, got %q", resp.ContentType)
+		}
+
+		if _, exists := resp.Headers["content-type"]; exists {
+			t.Error("content type should not be duplicated in headers")
+		}
+	})
+
+	t.Run("leaves headers empty when none are declared", func(t *testing.T) {
+		data := []byte("{}")
+		svc := &contentMockService{
+			contentService: &resultContentService{
+				result: &content.ContentResult{Data: &data, Source: "filesystem", Path: "/mocks/example.com/api.get.200"},
+			},
+		}
+
+		resp := svc.getMockResponse(MockRequest{Host: "example.com", URI: "/api", Method: "GET", StatusCode: 200})
+
+		if resp.Headers != nil || resp.ContentType != "" {
+			t.Errorf("expected no headers and no content type, got %v and %q", resp.Headers, resp.ContentType)
+		}
+	})
+}
+
+func TestContentMockService_getMockResponse_matching(t *testing.T) {
+	t.Run("matches content against the headers, query and body of the request", func(t *testing.T) {
+		data := []byte("alice")
+		contentService := &resultContentService{
+			result: &content.ContentResult{Data: &data, Source: "filesystem", Path: "/mocks/example.com/users.post.200.alice", Conditional: true},
+		}
+		svc := &contentMockService{contentService: contentService}
+
+		resp := svc.getMockResponse(MockRequest{
+			Host:       "example.com",
+			URI:        "/users?tenant=acme",
+			Method:     "POST",
+			StatusCode: 200,
+			Headers:    http.Header{"X-Request-Id": []string{"1"}},
+			Body:       []byte(`{"name":"alice"}`),
+		})
+
+		if contentService.request.Query.Get("tenant") != "acme" {
+			t.Errorf("expected query to be passed, got %v", contentService.request.Query)
+		}
+
+		if contentService.request.Headers.Get("X-Request-Id") != "1" {
+			t.Errorf("expected headers to be passed, got %v", contentService.request.Headers)
+		}
+
+		if string(contentService.request.Body) != `{"name":"alice"}` {
+			t.Errorf("expected body to be passed, got %q", string(contentService.request.Body))
+		}
+
+		if !resp.conditional {
+			t.Error("expected response to be flagged as conditional")
+		}
+	})
+}
+
+func TestContentMockService_getMockResponse_scenario(t *testing.T) {
+	t.Run("passes the scenario state lookup and exposes the scenario step", func(t *testing.T) {
+		data := []byte("shipped")
+		step := &content.ScenarioStep{Name: "order", State: "shipped", Next: "delivered"}
+		contentService := &resultContentService{
+			result: &content.ContentResult{Data: &data, Source: "filesystem", Path: "/mocks/example.com/orders/1.get.200.shipped", Scenario: step},
+		}
+		svc := &contentMockService{contentService: contentService}
+
+		resp := svc.getMockResponse(MockRequest{
+			Host:          "example.com",
+			URI:           "/orders/1",
+			Method:        "GET",
+			StatusCode:    200,
+			scenarioState: func(name string) string { return "shipped" },
+		})
+
+		if contentService.request.ScenarioState == nil || contentService.request.ScenarioState("order") != "shipped" {
+			t.Error("expected scenario state lookup to be passed")
+		}
+
+		if resp.scenario != step {
+			t.Errorf("expected scenario step to be exposed, got %v", resp.scenario)
+		}
+	})
+}
+
+func TestContentMockService_getMockResponse_pathParams(t *testing.T) {
+	t.Run("exposes the values captured by the path parameters and the template flag", func(t *testing.T) {
+		data := []byte("user")
+		svc := &contentMockService{
+			contentService: &resultContentService{
+				result: &content.ContentResult{
+					Data:       &data,
+					Source:     "filesystem",
+					Path:       "/mocks/example.com/users/{id}.get.200",
+					PathParams: map[string]string{"id": "42"},
+					Template:   true,
+				},
+			},
+		}
+
+		resp := svc.getMockResponse(MockRequest{Host: "example.com", URI: "/users/42", Method: "GET", StatusCode: 200})
+
+		if resp.PathParams["id"] != "42" {
+			t.Errorf("expected path param id=42, got %v", resp.PathParams)
+		}
+
+		if !resp.Template {
+			t.Error("expected response to be flagged as template")
+		}
+	})
+}
+
+func TestContentMockService_getMockResponse_notFound(t *testing.T) {
+	t.Run("returns Matched=false when the content service finds no mock", func(t *testing.T) {
+		empty := []byte("")
+		svc := &contentMockService{
+			contentService: &resultContentService{
+				result: &content.ContentResult{Data: &empty, Source: "filesystem", Path: ""},
+			},
+		}
+
+		resp := svc.getMockResponse(MockRequest{Host: "example.com", URI: "/missing", Method: "GET", StatusCode: 200})
+
+		if resp.Metadata[MetadataMatched] != "false" {
+			t.Errorf("expected Matched=false, got %q", resp.Metadata[MetadataMatched])
+		}
+
+		if resp.StatusCode != http.StatusOK || len(*resp.Data) != 0 {
+			t.Errorf("expected empty 200 response, got %d %q", resp.StatusCode, string(*resp.Data))
+		}
+	})
+}
+
 // Verify errContentServiceNotFound is distinct from regular errors
 func TestErrContentServiceNotFound(t *testing.T) {
 	err := errContentServiceNotFound
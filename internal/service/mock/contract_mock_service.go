@@ -0,0 +1,227 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/rest"
+	"github.com/Caik/go-mock-server/internal/service/openapi"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// contractDocument is an OpenAPI document loaded from disk, along with what's needed to tell when it changed
+type contractDocument struct {
+	modTime  time.Time
+	size     int64
+	document *openapi.Document
+	err      error
+}
+
+type contractMockService struct {
+	next        mockService
+	hostsConfig *config.HostsConfig
+	documentsMu sync.Mutex
+	documents   map[string]*contractDocument
+}
+
+func (c *contractMockService) getMockResponse(mockRequest MockRequest) *MockResponse {
+	contractConfig := c.hostsConfig.GetHostContractConfig(mockRequest.Host)
+
+	if contractConfig == nil {
+		return c.nextOrNil(mockRequest)
+	}
+
+	document, err := c.loadDocument(contractConfig.Spec)
+
+	if err != nil {
+		return c.nextOrNil(mockRequest)
+	}
+
+	request := c.newContractRequest(mockRequest)
+	violations := document.ValidateRequest(request)
+
+	if len(violations) > 0 {
+		log.Warn().
+			Str("uuid", mockRequest.Uuid).
+			Str("spec", contractConfig.Spec).
+			Strs("violations", violations).
+			Msg("request violates the contract")
+
+		if contractConfig.Mode == config.ContractModeReject {
+			return c.newViolationResponse(http.StatusBadRequest, rest.Fail, "request violates the contract", violations)
+		}
+	}
+
+	mockResponse := c.nextOrNil(mockRequest)
+
+	// only the mocks are checked, not the upstream responses nor the not found ones
+	if mockResponse == nil || mockResponse.Stream != nil || mockResponse.Metadata[MetadataMatched] != "true" {
+		c.addContractMetadata(mockResponse, violations)
+		return mockResponse
+	}
+
+	var body []byte
+
+	if mockResponse.Data != nil {
+		body = *mockResponse.Data
+	}
+
+	responseViolations := document.ValidateResponse(request, openapi.Response{
+		StatusCode:  mockResponse.StatusCode,
+		ContentType: mockResponse.ContentType,
+		Body:        body,
+	})
+
+	if len(responseViolations) > 0 {
+		log.Warn().
+			Str("uuid", mockRequest.Uuid).
+			Str("spec", contractConfig.Spec).
+			Str("path", mockResponse.Metadata[MetadataPath]).
+			Strs("violations", responseViolations).
+			Msg("mock response violates the contract")
+
+		if contractConfig.Mode == config.ContractModeReject {
+			return c.newViolationResponse(http.StatusInternalServerError, rest.Error, "mock response violates the contract", responseViolations)
+		}
+	}
+
+	c.addContractMetadata(mockResponse, append(violations, responseViolations...))
+
+	return mockResponse
+}
+
+func (c *contractMockService) setNext(next mockService) {
+	c.next = next
+}
+
+func (c *contractMockService) nextOrNil(mockRequest MockRequest) *MockResponse {
+	if c.next == nil {
+		return nil
+	}
+
+	return c.next.getMockResponse(mockRequest)
+}
+
+// loadDocument returns the parsed document at the path, parsing it again whenever the file changes
+func (c *contractMockService) loadDocument(path string) (*openapi.Document, error) {
+	info, err := os.Stat(path)
+
+	c.documentsMu.Lock()
+	defer c.documentsMu.Unlock()
+
+	loaded, exists := c.documents[path]
+
+	if err == nil && exists && loaded.modTime.Equal(info.ModTime()) && loaded.size == info.Size() {
+		return loaded.document, loaded.err
+	}
+
+	if err != nil {
+		// logging only the first failure, as it would otherwise be logged on every request
+		if !exists || loaded.err == nil {
+			log.Warn().
+				Str("spec", path).
+				Msgf("error while loading the contract: %v", err)
+		}
+
+		c.documents[path] = &contractDocument{err: err}
+
+		return nil, err
+	}
+
+	loaded = &contractDocument{modTime: info.ModTime(), size: info.Size()}
+
+	if data, readErr := os.ReadFile(path); readErr != nil {
+		loaded.err = readErr
+	} else {
+		loaded.document, loaded.err = openapi.Parse(data)
+	}
+
+	c.documents[path] = loaded
+
+	if loaded.err != nil {
+		log.Warn().
+			Str("spec", path).
+			Msgf("error while loading the contract: %v", loaded.err)
+
+		return nil, loaded.err
+	}
+
+	log.Info().
+		Str("spec", path).
+		Msg("contract loaded")
+
+	return loaded.document, nil
+}
+
+func (c *contractMockService) newContractRequest(mockRequest MockRequest) openapi.Request {
+	path, rawQuery, _ := strings.Cut(mockRequest.URI, "?")
+	query, _ := url.ParseQuery(rawQuery)
+
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		path = unescaped
+	}
+
+	return openapi.Request{
+		Method:  mockRequest.Method,
+		Path:    path,
+		Query:   query,
+		Headers: mockRequest.Headers,
+		Body:    mockRequest.Body,
+	}
+}
+
+func (c *contractMockService) addContractMetadata(mockResponse *MockResponse, violations []string) {
+	if mockResponse == nil {
+		return
+	}
+
+	if len(violations) == 0 {
+		mockResponse.AddMetadata(MetadataContract, "valid")
+		return
+	}
+
+	mockResponse.AddMetadata(MetadataContract, "violated")
+	mockResponse.AddMetadata(MetadataContractViolations, strings.Join(violations, "; "))
+}
+
+func (c *contractMockService) newViolationResponse(statusCode int, status rest.Status, message string, violations []string) *MockResponse {
+	res := rest.Response{
+		Status:  status,
+		Message: message,
+		Data:    violations,
+	}
+
+	data, err := json.Marshal(res)
+
+	if err != nil {
+		data = []byte(fmt.Sprintf("{%q:%q,%q:%q}", "status", res.Status, "message", res.Message))
+	}
+
+	resp := &MockResponse{
+		StatusCode:  statusCode,
+		Data:        &data,
+		ContentType: gin.MIMEJSON,
+	}
+
+	resp.AddMetadata(MetadataMatched, "false")
+	resp.AddMetadata(MetadataSource, "contract")
+	resp.AddMetadata(MetadataContract, "violated")
+	resp.AddMetadata(MetadataContractViolations, strings.Join(violations, "; "))
+
+	return resp
+}
+
+func newContractMockService(hostsConfig *config.HostsConfig) *contractMockService {
+	return &contractMockService{
+		hostsConfig: hostsConfig,
+		documents:   make(map[string]*contractDocument),
+	}
+}
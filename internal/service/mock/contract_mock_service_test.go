@@ -0,0 +1,207 @@
+package mock
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
+)
+
+const contractSpec = `{
+  "openapi": "3.0.0",
+  "paths": {
+    "/users": {
+      "post": {
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+        },
+        "responses": {
+          "201": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}}
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "User": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}
+    }
+  }
+}`
+
+func writeContractSpec(t *testing.T, spec string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "openapi.json")
+
+	if err := os.WriteFile(path, []byte(spec), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return path
+}
+
+func newContractHostsConfig(spec, mode string) *config.HostsConfig {
+	return config.NewHostsConfigFrom(map[string]config.HostConfig{
+		"example.com": {
+			ContractConfig: &config.ContractConfig{Spec: spec, Mode: mode},
+		},
+	})
+}
+
+func newMatchedResponse(statusCode int, body string) *MockResponse {
+	data := []byte(body)
+	resp := &MockResponse{
+		StatusCode:  statusCode,
+		Data:        &data,
+		ContentType: "application/json",
+	}
+	resp.AddMetadata(MetadataMatched, "true")
+
+	return resp
+}
+
+func newContractRequest(body string) MockRequest {
+	return MockRequest{
+		Host:       "example.com",
+		Method:     "POST",
+		URI:        "/users?source=test",
+		Uuid:       "test-uuid",
+		StatusCode: 200,
+		Headers:    http.Header{"Content-Type": []string{"application/json"}},
+		Body:       []byte(body),
+	}
+}
+
+func TestContractMockService_getMockResponse(t *testing.T) {
+	spec := writeContractSpec(t, contractSpec)
+
+	t.Run("passes through hosts without contract", func(t *testing.T) {
+		next := &mockMockService{response: newMatchedResponse(201, `{}`)}
+		service := newContractMockService(config.NewHostsConfigFrom(nil))
+		service.setNext(next)
+
+		response := service.getMockResponse(newContractRequest(`{}`))
+
+		if response != next.response || response.Metadata[MetadataContract] != "" {
+			t.Errorf("expected the response untouched, got %+v", response)
+		}
+	})
+
+	t.Run("marks valid exchanges", func(t *testing.T) {
+		service := newContractMockService(newContractHostsConfig(spec, ""))
+		service.setNext(&mockMockService{response: newMatchedResponse(201, `{"name": "john"}`)})
+
+		response := service.getMockResponse(newContractRequest(`{"name": "john"}`))
+
+		if response.Metadata[MetadataContract] != "valid" {
+			t.Errorf("expected a valid contract, got %v", response.Metadata)
+		}
+	})
+
+	t.Run("logs the violations into the metadata", func(t *testing.T) {
+		service := newContractMockService(newContractHostsConfig(spec, config.ContractModeLog))
+		service.setNext(&mockMockService{response: newMatchedResponse(201, `{"name": 1}`)})
+
+		response := service.getMockResponse(newContractRequest(`{}`))
+
+		if response.StatusCode != 201 || response.Metadata[MetadataContract] != "violated" {
+			t.Fatalf("expected the mock with a violated contract, got %+v", response)
+		}
+
+		want := "request body.name: is required; response body.name: expected string, got number"
+
+		if response.Metadata[MetadataContractViolations] != want {
+			t.Errorf("unexpected violations %q", response.Metadata[MetadataContractViolations])
+		}
+	})
+
+	t.Run("rejects the requests violating the contract", func(t *testing.T) {
+		next := &mockMockService{response: newMatchedResponse(201, `{"name": "john"}`)}
+		service := newContractMockService(newContractHostsConfig(spec, config.ContractModeReject))
+		service.setNext(next)
+
+		response := service.getMockResponse(newContractRequest(`{"name": true}`))
+
+		if response.StatusCode != http.StatusBadRequest || next.lastRequest.Uuid != "" {
+			t.Fatalf("expected the request to be rejected, got %+v", response)
+		}
+
+		if body := string(*response.Data); !strings.Contains(body, "request body.name: expected string, got boolean") {
+			t.Errorf("expected the violations in the body, got %s", body)
+		}
+
+		if response.Metadata[MetadataSource] != "contract" || response.Metadata[MetadataMatched] != "false" {
+			t.Errorf("unexpected metadata %v", response.Metadata)
+		}
+	})
+
+	t.Run("rejects the mock responses violating the contract", func(t *testing.T) {
+		service := newContractMockService(newContractHostsConfig(spec, config.ContractModeReject))
+		service.setNext(&mockMockService{response: newMatchedResponse(418, `{}`)})
+
+		response := service.getMockResponse(newContractRequest(`{"name": "john"}`))
+
+		if response.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("expected the response to be rejected, got %+v", response)
+		}
+
+		if response.Metadata[MetadataContractViolations] != "status 418 is not declared" {
+			t.Errorf("unexpected violations %q", response.Metadata[MetadataContractViolations])
+		}
+	})
+
+	t.Run("doesn't check unmatched responses", func(t *testing.T) {
+		service := newContractMockService(newContractHostsConfig(spec, config.ContractModeReject))
+		service.setNext(&mockMockService{response: newUnmatchedResponse()})
+
+		response := service.getMockResponse(newContractRequest(`{"name": "john"}`))
+
+		if response.StatusCode != 200 || response.Metadata[MetadataContract] != "valid" {
+			t.Errorf("expected the unmatched response untouched, got %+v", response)
+		}
+	})
+
+	t.Run("serves the mocks when the contract can't be loaded", func(t *testing.T) {
+		next := &mockMockService{response: newMatchedResponse(201, `{}`)}
+		service := newContractMockService(newContractHostsConfig(filepath.Join(t.TempDir(), "missing.json"), config.ContractModeReject))
+		service.setNext(next)
+
+		if response := service.getMockResponse(newContractRequest(`{}`)); response != next.response {
+			t.Errorf("expected the mock, got %+v", response)
+		}
+	})
+}
+
+func TestContractMockService_loadDocument(t *testing.T) {
+	spec := writeContractSpec(t, `{"openapi": "3.0.0"}`)
+	service := newContractMockService(config.NewHostsConfigFrom(nil))
+
+	if _, err := service.loadDocument(spec); err == nil {
+		t.Fatal("expected an error for a document without paths")
+	}
+
+	if err := os.WriteFile(spec, []byte(contractSpec), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// making sure the change is seen even on filesystems with a coarse modification time
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(spec, later, later)
+
+	first, err := service.loadDocument(spec)
+
+	if err != nil {
+		t.Fatalf("expected the changed document to be loaded, got %v", err)
+	}
+
+	second, _ := service.loadDocument(spec)
+
+	if first != second {
+		t.Error("expected the unchanged document to be reused")
+	}
+}
@@ -0,0 +1,96 @@
+package mock
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/metrics"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type faultSimulationMockService struct {
+	next           mockService
+	hostsConfig    *config.HostsConfig
+	metricsService *metrics.MetricsService
+}
+
+func (f *faultSimulationMockService) getMockResponse(mockRequest MockRequest) *MockResponse {
+	faultsConfig, scope := f.hostsConfig.GetAppropriateFaultsConfig(mockRequest.Host, mockRequest.URI)
+
+	// the response is still built as usual, since most of the faults send a part of it
+	resp := f.nextOrNil(mockRequest)
+
+	if resp == nil || faultsConfig == nil {
+		return resp
+	}
+
+	fault := f.drawFault(faultsConfig)
+
+	if fault == nil {
+		return resp
+	}
+
+	log.Info().
+		Str("uuid", mockRequest.Uuid).
+		Str("fault", fault.Type).
+		Msg("simulating fault")
+
+	trace.SpanFromContext(mockRequest.ctx()).SetAttributes(
+		attribute.String("mock.fault.simulated", fault.Type),
+		attribute.String("mock.fault.rule_scope", scope),
+	)
+
+	resp.Fault = fault
+	resp.AddMetadata(MetadataSimulatedFault, fault.Type)
+	resp.AddMetadata(MetadataFaultRuleScope, scope)
+	f.metricsService.ObserveSimulatedFault(mockRequest.Host, fault.Type)
+
+	return resp
+}
+
+func (f *faultSimulationMockService) setNext(next mockService) {
+	f.next = next
+}
+
+func (f *faultSimulationMockService) nextOrNil(mockRequest MockRequest) *MockResponse {
+	if f.next == nil {
+		return nil
+	}
+
+	return f.next.getMockResponse(mockRequest)
+}
+
+// drawFault randomly selects a fault based on the percentages, or none at all if the draw exceeds their sum
+func (f *faultSimulationMockService) drawFault(faultsConfig map[string]config.FaultConfig) *Fault {
+	// Using 1-100 range, the same way as the status simulation, so that a 0% fault rate truly never fires
+	draw := rand.Intn(100) + 1
+	sumFaultPercentage := 0
+
+	for faultType, faultConfig := range faultsConfig {
+		sumFaultPercentage += *faultConfig.Percentage
+
+		if draw > sumFaultPercentage {
+			continue
+		}
+
+		fault := Fault{Type: faultType}
+
+		if faultConfig.Duration != nil {
+			fault.Duration = time.Duration(*faultConfig.Duration) * time.Millisecond
+		}
+
+		return &fault
+	}
+
+	return nil
+}
+
+func newFaultSimulationMockService(hostsConfig *config.HostsConfig, metricsService *metrics.MetricsService) *faultSimulationMockService {
+	return &faultSimulationMockService{
+		hostsConfig:    hostsConfig,
+		metricsService: metricsService,
+	}
+}
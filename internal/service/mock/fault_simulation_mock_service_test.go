@@ -0,0 +1,125 @@
+package mock
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/metrics"
+)
+
+func TestFaultSimulationMockService_getMockResponse(t *testing.T) {
+	hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+		"example.com": {
+			FaultsConfig: map[string]config.FaultConfig{
+				config.FaultConnectionReset: {Percentage: intPtr(100)},
+			},
+			UrisConfig: map[string]config.UriConfig{
+				"/api/slow": {
+					FaultsConfig: map[string]config.FaultConfig{
+						config.FaultHang: {Percentage: intPtr(100), Duration: intPtr(1500)},
+					},
+				},
+			},
+		},
+		"never.com": {
+			FaultsConfig: map[string]config.FaultConfig{
+				config.FaultGarbage: {Percentage: intPtr(0)},
+			},
+		},
+	})
+
+	request := MockRequest{Host: "example.com", Method: "GET", URI: "/api/test", Uuid: "test-uuid"}
+
+	t.Run("injects the fault drawn", func(t *testing.T) {
+		service := newFaultSimulationMockService(hostsConfig, nil)
+		service.setNext(&mockMockService{response: &MockResponse{StatusCode: 200}})
+
+		response := service.getMockResponse(request)
+
+		if response.Fault == nil || response.Fault.Type != config.FaultConnectionReset || response.Fault.Duration != 0 {
+			t.Fatalf("expected a connection reset, got %+v", response.Fault)
+		}
+
+		if response.Metadata[MetadataSimulatedFault] != config.FaultConnectionReset ||
+			response.Metadata[MetadataFaultRuleScope] != "Host Default" {
+			t.Errorf("unexpected metadata %v", response.Metadata)
+		}
+	})
+
+	t.Run("uses the faults of the URI, with the duration of the hang", func(t *testing.T) {
+		service := newFaultSimulationMockService(hostsConfig, nil)
+		service.setNext(&mockMockService{response: &MockResponse{StatusCode: 200}})
+
+		uriRequest := request
+		uriRequest.URI = "/api/slow"
+		response := service.getMockResponse(uriRequest)
+
+		if response.Fault == nil || response.Fault.Type != config.FaultHang || response.Fault.Duration != 1500*time.Millisecond {
+			t.Fatalf("expected a hang of 1.5s, got %+v", response.Fault)
+		}
+
+		if response.Metadata[MetadataFaultRuleScope] != "URI Override" {
+			t.Errorf("unexpected metadata %v", response.Metadata)
+		}
+	})
+
+	t.Run("doesn't inject a fault with a 0% rate", func(t *testing.T) {
+		service := newFaultSimulationMockService(hostsConfig, nil)
+		service.setNext(&mockMockService{response: &MockResponse{StatusCode: 200}})
+
+		neverRequest := request
+		neverRequest.Host = "never.com"
+
+		for range 100 {
+			if response := service.getMockResponse(neverRequest); response.Fault != nil {
+				t.Fatalf("expected no fault, got %+v", response.Fault)
+			}
+		}
+	})
+
+	t.Run("doesn't inject a fault for hosts without faults", func(t *testing.T) {
+		service := newFaultSimulationMockService(hostsConfig, nil)
+		service.setNext(&mockMockService{response: &MockResponse{StatusCode: 200}})
+
+		otherRequest := request
+		otherRequest.Host = "other.com"
+
+		if response := service.getMockResponse(otherRequest); response.Fault != nil || response.Metadata != nil {
+			t.Errorf("expected no fault, got %+v", response)
+		}
+	})
+
+	t.Run("returns nil when the next service returns nil", func(t *testing.T) {
+		service := newFaultSimulationMockService(hostsConfig, nil)
+		service.setNext(&mockMockService{})
+
+		if response := service.getMockResponse(request); response != nil {
+			t.Errorf("expected nil response, got %+v", response)
+		}
+	})
+}
+
+func TestFaultSimulationMockService_metrics(t *testing.T) {
+	hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+		"example.com": {
+			FaultsConfig: map[string]config.FaultConfig{
+				config.FaultTruncatedBody: {Percentage: intPtr(100)},
+			},
+		},
+	})
+
+	metricsService := metrics.NewMetricsService(&config.AppArguments{}, nil, nil, nil, hostsConfig, nil)
+	service := newFaultSimulationMockService(hostsConfig, metricsService)
+	service.setNext(&mockMockService{response: &MockResponse{StatusCode: 200}})
+
+	service.getMockResponse(MockRequest{Host: "example.com", Method: "GET", URI: "/api/test", Uuid: "test-uuid"})
+
+	var output strings.Builder
+	metricsService.Write(&output)
+
+	if !strings.Contains(output.String(), `mock_server_simulated_faults_total{fault="truncated_body",host="example.com"} 1`) {
+		t.Errorf("expected the injected fault to be counted, got\n%s", output.String())
+	}
+}
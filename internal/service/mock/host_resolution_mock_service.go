@@ -15,7 +15,9 @@ type hostResolutionMockService struct {
 	next           mockService
 	once           sync.Once
 	contentService content.ContentService
+	mu             sync.RWMutex // guards pathHosts and patternHosts, updated by the content events listener
 	pathHosts      map[string]string
+	patternHosts   map[string]string // same as pathHosts, for the mocks with path parameters or wildcards
 }
 
 func (h *hostResolutionMockService) getMockResponse(mockRequest MockRequest) *MockResponse {
@@ -30,8 +32,15 @@ func (h *hostResolutionMockService) evaluate(request MockRequest) MockRequest {
 		return request
 	}
 
+	h.mu.RLock()
 	host, exists := h.pathHosts[h.generateKey(request.Method, request.URI)]
 
+	if !exists {
+		host, exists = h.resolvePatternHost(request.Method, request.URI)
+	}
+
+	h.mu.RUnlock()
+
 	if !exists {
 		return request
 	}
@@ -44,12 +53,9 @@ func (h *hostResolutionMockService) evaluate(request MockRequest) MockRequest {
 		Str("new_host", host).
 		Msg("host resolved for request")
 
-	return MockRequest{
-		Host:   host,
-		Method: request.Method,
-		URI:    request.URI,
-		Uuid:   request.Uuid,
-	}
+	request.Host = host
+
+	return request
 }
 
 func (h *hostResolutionMockService) setNext(next mockService) {
@@ -57,12 +63,9 @@ func (h *hostResolutionMockService) setNext(next mockService) {
 }
 
 func (h *hostResolutionMockService) ensureInit(uuid string) error {
-	if h.pathHosts != nil {
-		return nil
-	}
-
 	h.once.Do(func() {
 		h.pathHosts = make(map[string]string)
+		h.patternHosts = make(map[string]string)
 		data, err := h.contentService.ListContents(uuid)
 
 		if err != nil {
@@ -75,7 +78,7 @@ func (h *hostResolutionMockService) ensureInit(uuid string) error {
 		}
 
 		for _, item := range *data {
-			h.pathHosts[h.generateKey(item.Method, item.Uri)] = item.Host
+			h.hostsFor(item.Uri)[h.generateKey(item.Method, item.Uri)] = item.Host
 		}
 
 		channel := h.contentService.Subscribe("host_resolution_mock_service")
@@ -87,13 +90,23 @@ func (h *hostResolutionMockService) ensureInit(uuid string) error {
 
 			// listening to content change events
 			for event := range channel {
+				// _default files are fallbacks of their host, they don't tell which host serves a uri
+				if event.Data.Uri == content.DefaultUri {
+					continue
+				}
+
 				key := h.generateKey(event.Data.Method, event.Data.Uri)
 
+				h.mu.Lock()
+				hosts := h.hostsFor(event.Data.Uri)
+
 				if event.Type == content.Removed {
-					delete(h.pathHosts, key)
+					delete(hosts, key)
 				} else {
-					h.pathHosts[key] = event.Data.Host
+					hosts[key] = event.Data.Host
 				}
+
+				h.mu.Unlock()
 			}
 
 			log.Info().
@@ -105,6 +118,42 @@ func (h *hostResolutionMockService) ensureInit(uuid string) error {
 	return nil
 }
 
+// hostsFor returns the map keeping the host of the given uri, depending on whether it is a pattern
+func (h *hostResolutionMockService) hostsFor(uri string) map[string]string {
+	if content.IsUriPattern(uri) {
+		return h.patternHosts
+	}
+
+	return h.pathHosts
+}
+
+// resolvePatternHost returns the host of the most specific pattern matching the uri. The caller must hold the read lock.
+func (h *hostResolutionMockService) resolvePatternHost(method, uri string) (string, bool) {
+	var bestPattern, bestHost string
+
+	for key, host := range h.patternHosts {
+		keyMethod, pattern, _ := strings.Cut(key, ":")
+
+		if keyMethod != method {
+			continue
+		}
+
+		if _, matches := content.MatchUriPattern(pattern, uri); !matches {
+			continue
+		}
+
+		// equally specific patterns are ordered by name, so the result doesn't depend on the map ordering
+		isTied := !content.MoreSpecificUriPattern(bestPattern, pattern) && pattern < bestPattern
+
+		if len(bestPattern) == 0 || content.MoreSpecificUriPattern(pattern, bestPattern) || isTied {
+			bestPattern = pattern
+			bestHost = host
+		}
+	}
+
+	return bestHost, len(bestPattern) > 0
+}
+
 func (h *hostResolutionMockService) generateKey(method, uri string) string {
 	return strings.Join([]string{method, uri}, ":")
 }
@@ -5,11 +5,13 @@ import (
 	"testing"
 
 	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/matcher"
 )
 
 // Mock content service for testing
 type mockContentService struct {
 	contents map[string][]byte
+	metas    map[string]content.ContentMeta
 	events   chan content.ContentEvent
 }
 
@@ -25,12 +27,25 @@ func (m *mockContentService) GetContent(host, uri, method, uuid string, statusCo
 	return nil, errors.New("not found")
 }
 
+func (m *mockContentService) MatchContent(host, uri, method, uuid string, statusCode int, request matcher.Request) (*content.ContentResult, error) {
+	return m.GetContent(host, uri, method, uuid, statusCode)
+}
+
 func (m *mockContentService) SetContent(host, uri, method, uuid string, statusCode int, data *[]byte) error {
 	key := host + ":" + uri + ":" + method
 	m.contents[key] = *data
 	return nil
 }
 
+func (m *mockContentService) SetContentMeta(host, uri, method, uuid string, statusCode int, meta content.ContentMeta) error {
+	if m.metas == nil {
+		m.metas = make(map[string]content.ContentMeta)
+	}
+
+	m.metas[host+":"+uri+":"+method] = meta
+	return nil
+}
+
 func (m *mockContentService) DeleteContent(host, uri, method, uuid string, statusCode int) error {
 	key := host + ":" + uri + ":" + method
 	delete(m.contents, key)
@@ -61,6 +76,10 @@ func (m *mockContentService) Unsubscribe(subscriberId string) {
 	// Mock implementation
 }
 
+func (m *mockContentService) SubscriberCount() int {
+	return 0
+}
+
 func TestHostResolutionMockService_getMockResponse(t *testing.T) {
 	t.Run("passes through request when no host resolution needed", func(t *testing.T) {
 		contentService := &mockContentService{
@@ -228,6 +247,104 @@ func TestHostResolutionMockService_getMockResponse(t *testing.T) {
 	})
 }
 
+// listedContentService lists the given contents
+type listedContentService struct {
+	mockContentService
+	listed []content.ContentData
+}
+
+func (l *listedContentService) ListContents(uuid string) (*[]content.ContentData, error) {
+	return &l.listed, nil
+}
+
+func TestHostResolutionMockService_patterns(t *testing.T) {
+	t.Run("resolves host of the most specific pattern matching the uri", func(t *testing.T) {
+		contentService := &listedContentService{
+			mockContentService: mockContentService{events: make(chan content.ContentEvent)},
+			listed: []content.ContentData{
+				{Host: "users.example.com", Uri: "/api/users/{id}", Method: "GET"},
+				{Host: "any.example.com", Uri: "/api/*/{id}", Method: "GET"},
+				{Host: "post.example.com", Uri: "/api/users/{id}", Method: "POST"},
+			},
+		}
+
+		service, err := newHostResolutionMockService(contentService)
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+
+		mockNext := &mockMockService{response: &MockResponse{StatusCode: 200}}
+		service.setNext(mockNext)
+
+		service.getMockResponse(MockRequest{Host: "localhost", Method: "GET", URI: "/api/users/42?expand=true"})
+
+		if mockNext.lastRequest.Host != "users.example.com" {
+			t.Errorf("expected host 'users.example.com', got '%s'", mockNext.lastRequest.Host)
+		}
+
+		service.getMockResponse(MockRequest{Host: "localhost", Method: "GET", URI: "/api/orders/42"})
+
+		if mockNext.lastRequest.Host != "any.example.com" {
+			t.Errorf("expected host 'any.example.com', got '%s'", mockNext.lastRequest.Host)
+		}
+
+		service.getMockResponse(MockRequest{Host: "localhost", Method: "DELETE", URI: "/api/users/42"})
+
+		if mockNext.lastRequest.Host != "localhost" {
+			t.Errorf("expected host to be kept, got '%s'", mockNext.lastRequest.Host)
+		}
+	})
+}
+
+func TestHostResolutionMockService_events(t *testing.T) {
+	t.Run("resolves hosts while content events are applied", func(t *testing.T) {
+		events := make(chan content.ContentEvent)
+		contentService := &listedContentService{mockContentService: mockContentService{events: events}}
+
+		service, err := newHostResolutionMockService(contentService)
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+
+		mockNext := &mockMockService{response: &MockResponse{StatusCode: 200}}
+		service.setNext(mockNext)
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			for i := 0; i < 100; i++ {
+				events <- content.ContentEvent{Type: content.Created, Data: content.ContentData{Host: "users.example.com", Uri: "/api/users", Method: "GET"}}
+				events <- content.ContentEvent{Type: content.Created, Data: content.ContentData{Host: "users.example.com", Uri: "/api/users/{id}", Method: "GET"}}
+			}
+		}()
+
+		for i := 0; i < 100; i++ {
+			service.getMockResponse(MockRequest{Host: "localhost", Method: "GET", URI: "/api/users/42"})
+		}
+
+		<-done
+
+		events <- content.ContentEvent{Type: content.Created, Data: content.ContentData{Host: "fallback.example.com", Uri: content.DefaultUri, Method: "GET"}}
+
+		// the listener handled the previous events once it receives this one
+		events <- content.ContentEvent{Type: content.Removed, Data: content.ContentData{Host: "users.example.com", Uri: "/api/orders", Method: "GET"}}
+
+		service.getMockResponse(MockRequest{Host: "localhost", Method: "GET", URI: "/api/users"})
+
+		if mockNext.lastRequest.Host != "users.example.com" {
+			t.Errorf("expected host 'users.example.com', got '%s'", mockNext.lastRequest.Host)
+		}
+
+		service.getMockResponse(MockRequest{Host: "localhost", Method: "GET", URI: content.DefaultUri})
+
+		if mockNext.lastRequest.Host != "localhost" {
+			t.Errorf("expected _default files not to resolve hosts, got '%s'", mockNext.lastRequest.Host)
+		}
+	})
+}
+
 // Note: evaluate method is private, so we test it indirectly through getMockResponse
 
 func TestHostResolutionMockService_setNext(t *testing.T) {
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/Caik/go-mock-server/internal/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/rs/zerolog/log"
 )
@@ -37,6 +39,11 @@ func (l *latencyMockService) getMockResponse(mockRequest MockRequest) *MockRespo
 	mockResponse.activeLatencyConfig = latencyConfig
 	drawnLatency := l.drawLatency(latencyConfig)
 
+	trace.SpanFromContext(mockRequest.ctx()).SetAttributes(
+		attribute.Int("mock.latency.drawn_ms", drawnLatency),
+		attribute.String("mock.latency.rule_scope", scope),
+	)
+
 	// simulating the latency
 	log.Info().
 		Str("uuid", mockRequest.Uuid).
@@ -45,7 +52,8 @@ func (l *latencyMockService) getMockResponse(mockRequest MockRequest) *MockRespo
 		Msg("simulating latency")
 
 	targetLatencyTime := startTime.Add(time.Duration(drawnLatency * int(time.Millisecond)))
-	<-time.NewTimer(time.Until(targetLatencyTime)).C
+	mockResponse.simulatedLatency = max(time.Until(targetLatencyTime), 0)
+	<-time.NewTimer(mockResponse.simulatedLatency).C
 
 	mockResponse.AddMetadata(MetadataSimulatedLatency, "true")
 	mockResponse.AddMetadata(MetadataLatencyRuleScope, scope)
@@ -10,16 +10,14 @@ import (
 
 func TestLatencyMockService_getMockResponse(t *testing.T) {
 	t.Run("applies latency when config exists", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {
-					LatencyConfig: &config.LatencyConfig{
-						Min: intPtr(10),
-						Max: intPtr(20),
-					},
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				LatencyConfig: &config.LatencyConfig{
+					Min: intPtr(10),
+					Max: intPtr(20),
 				},
 			},
-		}
+		})
 
 		service := newLatencyMockService(hostsConfig)
 
@@ -51,12 +49,14 @@ func TestLatencyMockService_getMockResponse(t *testing.T) {
 		if duration < 10*time.Millisecond {
 			t.Errorf("expected latency of at least 10ms, got %v", duration)
 		}
+
+		if response.SimulatedLatency() <= 0 || response.SimulatedLatency() > 20*time.Millisecond {
+			t.Errorf("expected a simulated latency of at most 20ms, got %v", response.SimulatedLatency())
+		}
 	})
 
 	t.Run("skips latency when no config", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
 		service := newLatencyMockService(hostsConfig)
 
@@ -91,18 +91,16 @@ func TestLatencyMockService_getMockResponse(t *testing.T) {
 	})
 
 	t.Run("handles P95 with nil P99 using Max as upper bound", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {
-					LatencyConfig: &config.LatencyConfig{
-						Min: intPtr(10),
-						Max: intPtr(200), // Max should be used as upper bound when P99 is nil
-						P95: intPtr(100), // P95 is set
-						P99: nil,         // P99 is nil - should use Max instead
-					},
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				LatencyConfig: &config.LatencyConfig{
+					Min: intPtr(10),
+					Max: intPtr(200), // Max should be used as upper bound when P99 is nil
+					P95: intPtr(100), // P95 is set
+					P99: nil,         // P99 is nil - should use Max instead
 				},
 			},
-		}
+		})
 
 		service := newLatencyMockService(hostsConfig)
 
@@ -295,9 +293,7 @@ func TestLatencyMockService_drawLatency(t *testing.T) {
 
 func TestLatencyMockService_setNext(t *testing.T) {
 	t.Run("sets next service correctly", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
 		service := newLatencyMockService(hostsConfig)
 		mockNext := &mockMockService{}
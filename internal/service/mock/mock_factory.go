@@ -6,6 +6,9 @@ import (
 	"github.com/Caik/go-mock-server/internal/config"
 	"github.com/Caik/go-mock-server/internal/service/cache"
 	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/metrics"
+	"github.com/Caik/go-mock-server/internal/service/scenario"
+	"github.com/Caik/go-mock-server/internal/service/tracing"
 	"github.com/rs/zerolog/log"
 )
 
@@ -21,6 +24,9 @@ func (m *MockServiceFactory) GetMockResponse(mockRequest MockRequest) *MockRespo
 func (m *MockServiceFactory) initServiceChain(
 	contentService content.ContentService,
 	cacheService cache.CacheService,
+	scenarioService *scenario.ScenarioService,
+	metricsService *metrics.MetricsService,
+	tracingService *tracing.TracingService,
 	disableLatency,
 	disableCache,
 	disableCors bool,
@@ -35,7 +41,12 @@ func (m *MockServiceFactory) initServiceChain(
 		var first mockService
 		var last mockService
 
-		addNextFn := func(next mockService) {
+		addNextFn := func(name string, next mockService) {
+			// each link gets its own span, only when tracing is enabled
+			if tracingService != nil {
+				next = newTracingMockService(name, next, tracingService)
+			}
+
 			if first == nil {
 				first = next
 			}
@@ -55,31 +66,49 @@ func (m *MockServiceFactory) initServiceChain(
 				Msgf("error while starting HostResolutionMockService: %v", err)
 		}
 
-		addNextFn(hostResolutionMockService)
+		addNextFn("host_resolution", hostResolutionMockService)
 
 		// cors
 		if !disableCors {
-			addNextFn(newCorsMockService())
+			addNextFn("cors", newCorsMockService())
 		}
 
+		// contract (placed before the status simulation and the latency, so the requests violating the contract are
+		// rejected right away, while the responses are checked as served)
+		addNextFn("contract", newContractMockService(hostsConfig))
+
 		// latency
 		if !disableLatency {
-			addNextFn(newLatencyMockService(hostsConfig))
+			addNextFn("latency", newLatencyMockService(hostsConfig))
 		}
 
 		// status simulation
-		addNextFn(newStatusSimulationMockService(hostsConfig))
+		addNextFn("status_simulation", newStatusSimulationMockService(hostsConfig, metricsService))
+
+		// fault simulation (placed after the status simulation, so a fault can break a simulated status too)
+		addNextFn("fault_simulation", newFaultSimulationMockService(hostsConfig, metricsService))
 
 		// content type
-		addNextFn(newContentTypeMockService(MockServiceParams{defaultContentType: defaultContentType}))
+		addNextFn("content_type", newContentTypeMockService(MockServiceParams{defaultContentType: defaultContentType}))
+
+		// proxy and record (placed before the cache, so upstream responses are never cached and an unmatched
+		// cached response still reaches the upstream)
+		addNextFn("proxy", newProxyMockService(hostsConfig, tracingService))
+		addNextFn("record", newRecordMockService(hostsConfig, contentService, tracingService))
+
+		// template (placed before the cache, so the raw template is what gets cached)
+		addNextFn("template", newTemplateMockService())
+
+		// scenario (placed before the cache, as the response served depends on the scenario state)
+		addNextFn("scenario", newScenarioMockService(scenarioService))
 
 		// cache
 		if !disableCache {
-			addNextFn(newCacheMockService(cacheService))
+			addNextFn("cache", newCacheMockService(cacheService, contentService, metricsService))
 		}
 
 		// content
-		addNextFn(newContentMockService(contentService))
+		addNextFn("content", newContentMockService(contentService))
 
 		// setting the chain
 		m.mockServiceChain = first
@@ -89,11 +118,14 @@ func (m *MockServiceFactory) initServiceChain(
 func NewMockServiceFactory(
 	contentService content.ContentService,
 	cacheService cache.CacheService,
+	scenarioService *scenario.ScenarioService,
+	metricsService *metrics.MetricsService,
+	tracingService *tracing.TracingService,
 	arguments *config.AppArguments,
 	hostsConfig *config.HostsConfig,
 ) *MockServiceFactory {
 	factory := MockServiceFactory{}
-	factory.initServiceChain(contentService, cacheService, arguments.DisableLatency, arguments.DisableCache, arguments.DisableCors, arguments.DefaultContentType, hostsConfig)
+	factory.initServiceChain(contentService, cacheService, scenarioService, metricsService, tracingService, arguments.DisableLatency, arguments.DisableCache, arguments.DisableCors, arguments.DefaultContentType, hostsConfig)
 
 	return &factory
 }
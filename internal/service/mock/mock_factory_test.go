@@ -6,6 +6,7 @@ import (
 	"github.com/Caik/go-mock-server/internal/config"
 	"github.com/Caik/go-mock-server/internal/service/cache"
 	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/scenario"
 	"github.com/gin-gonic/gin"
 )
 
@@ -32,6 +33,33 @@ func (m *mockCacheService) Set(key string, data *[]byte, uuid string) {
 	m.cache[key] = *data
 }
 
+func (m *mockCacheService) Delete(key, uuid string) bool {
+	_, exists := m.cache[key]
+	delete(m.cache, key)
+	return exists
+}
+
+func (m *mockCacheService) DeleteFunc(match func(key string) bool, uuid string) int {
+	removed := 0
+	for key := range m.cache {
+		if match(key) {
+			delete(m.cache, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (m *mockCacheService) Clear(uuid string) int {
+	removed := len(m.cache)
+	m.cache = make(map[string][]byte)
+	return removed
+}
+
+func (m *mockCacheService) Stats() cache.CacheStats {
+	return cache.CacheStats{Entries: len(m.cache)}
+}
+
 func TestNewMockServiceFactory(t *testing.T) {
 	t.Run("creates factory with all services enabled", func(t *testing.T) {
 		contentService := &mockContentService{
@@ -41,16 +69,14 @@ func TestNewMockServiceFactory(t *testing.T) {
 		cacheService := &mockCacheService{
 			cache: make(map[string][]byte),
 		}
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 		appArgs := &config.AppArguments{
 			DisableLatency: false,
 			DisableCache:   false,
 			DisableCors:    false,
 		}
 
-		factory := NewMockServiceFactory(contentService, cacheService, appArgs, hostsConfig)
+		factory := NewMockServiceFactory(contentService, cacheService, scenario.NewScenarioService(), nil, nil, appArgs, hostsConfig)
 
 		if factory == nil {
 			t.Fatal("NewMockServiceFactory should return non-nil factory")
@@ -69,16 +95,14 @@ func TestNewMockServiceFactory(t *testing.T) {
 		cacheService := &mockCacheService{
 			cache: make(map[string][]byte),
 		}
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 		appArgs := &config.AppArguments{
 			DisableLatency: true, // Disabled
 			DisableCache:   false,
 			DisableCors:    false,
 		}
 
-		factory := NewMockServiceFactory(contentService, cacheService, appArgs, hostsConfig)
+		factory := NewMockServiceFactory(contentService, cacheService, scenario.NewScenarioService(), nil, nil, appArgs, hostsConfig)
 
 		if factory == nil {
 			t.Fatal("NewMockServiceFactory should return non-nil factory")
@@ -107,9 +131,7 @@ func TestNewMockServiceFactory(t *testing.T) {
 		cacheService := &mockCacheService{
 			cache: make(map[string][]byte),
 		}
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
 		// Test case 1: DisableLatency=true should disable latency service
 		appArgs1 := &config.AppArguments{
@@ -118,7 +140,7 @@ func TestNewMockServiceFactory(t *testing.T) {
 			DisableCors:    false,
 		}
 
-		factory1 := NewMockServiceFactory(contentService, cacheService, appArgs1, hostsConfig)
+		factory1 := NewMockServiceFactory(contentService, cacheService, scenario.NewScenarioService(), nil, nil, appArgs1, hostsConfig)
 
 		// Test case 2: DisableCache=true should disable cache service
 		appArgs2 := &config.AppArguments{
@@ -127,7 +149,7 @@ func TestNewMockServiceFactory(t *testing.T) {
 			DisableCors:    false,
 		}
 
-		factory2 := NewMockServiceFactory(contentService, cacheService, appArgs2, hostsConfig)
+		factory2 := NewMockServiceFactory(contentService, cacheService, scenario.NewScenarioService(), nil, nil, appArgs2, hostsConfig)
 
 		// Test case 3: Both disabled
 		appArgs3 := &config.AppArguments{
@@ -136,7 +158,7 @@ func TestNewMockServiceFactory(t *testing.T) {
 			DisableCors:    false,
 		}
 
-		factory3 := NewMockServiceFactory(contentService, cacheService, appArgs3, hostsConfig)
+		factory3 := NewMockServiceFactory(contentService, cacheService, scenario.NewScenarioService(), nil, nil, appArgs3, hostsConfig)
 
 		// Test case 4: DisableCors=true should disable CORS service
 		appArgs4 := &config.AppArguments{
@@ -145,7 +167,7 @@ func TestNewMockServiceFactory(t *testing.T) {
 			DisableCors:    true,
 		}
 
-		factory4 := NewMockServiceFactory(contentService, cacheService, appArgs4, hostsConfig)
+		factory4 := NewMockServiceFactory(contentService, cacheService, scenario.NewScenarioService(), nil, nil, appArgs4, hostsConfig)
 
 		// All factories should be created successfully
 		if factory1 == nil || factory2 == nil || factory3 == nil || factory4 == nil {
@@ -166,7 +188,7 @@ func TestMockServiceFactory_DisableCorsFlag(t *testing.T) {
 		cacheService := &mockCacheService{
 			cache: make(map[string][]byte),
 		}
-		hostsConfig := &config.HostsConfig{}
+		hostsConfig := config.NewHostsConfigFrom(nil)
 
 		// Add some test content so the service chain doesn't fail
 		testData := []byte(`{"message": "test"}`)
@@ -179,7 +201,7 @@ func TestMockServiceFactory_DisableCorsFlag(t *testing.T) {
 			DisableCors:    false, // CORS enabled
 		}
 
-		factoryEnabled := NewMockServiceFactory(contentService, cacheService, appArgsEnabled, hostsConfig)
+		factoryEnabled := NewMockServiceFactory(contentService, cacheService, scenario.NewScenarioService(), nil, nil, appArgsEnabled, hostsConfig)
 
 		// Test case 2: CORS disabled
 		appArgsDisabled := &config.AppArguments{
@@ -188,7 +210,7 @@ func TestMockServiceFactory_DisableCorsFlag(t *testing.T) {
 			DisableCors:    true, // CORS disabled
 		}
 
-		factoryDisabled := NewMockServiceFactory(contentService, cacheService, appArgsDisabled, hostsConfig)
+		factoryDisabled := NewMockServiceFactory(contentService, cacheService, scenario.NewScenarioService(), nil, nil, appArgsDisabled, hostsConfig)
 
 		// Create a test request
 		testRequest := MockRequest{
@@ -256,16 +278,14 @@ func TestMockServiceFactory_GetMockResponse(t *testing.T) {
 		cacheService := &mockCacheService{
 			cache: make(map[string][]byte),
 		}
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 		appArgs := &config.AppArguments{
 			DisableLatency: false,
 			DisableCache:   false,
 			DisableCors:    false,
 		}
 
-		factory := NewMockServiceFactory(contentService, cacheService, appArgs, hostsConfig)
+		factory := NewMockServiceFactory(contentService, cacheService, scenario.NewScenarioService(), nil, nil, appArgs, hostsConfig)
 
 		request := MockRequest{
 			Host:   "example.com",
@@ -297,16 +317,14 @@ func TestMockServiceFactory_GetMockResponse(t *testing.T) {
 		cacheService := &mockCacheService{
 			cache: make(map[string][]byte),
 		}
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 		appArgs := &config.AppArguments{
 			DisableLatency: true,
 			DisableCache:   true, // Disable cache to test filesystem source
 			DisableCors:    true,
 		}
 
-		factory := NewMockServiceFactory(contentService, cacheService, appArgs, hostsConfig)
+		factory := NewMockServiceFactory(contentService, cacheService, scenario.NewScenarioService(), nil, nil, appArgs, hostsConfig)
 
 		request := MockRequest{
 			Host:   "example.com",
@@ -347,16 +365,14 @@ func TestMockServiceFactory_GetMockResponse(t *testing.T) {
 		cacheService := &mockCacheService{
 			cache: make(map[string][]byte),
 		}
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 		appArgs := &config.AppArguments{
 			DisableLatency: true,
 			DisableCache:   true,
 			DisableCors:    true,
 		}
 
-		factory := NewMockServiceFactory(contentService, cacheService, appArgs, hostsConfig)
+		factory := NewMockServiceFactory(contentService, cacheService, scenario.NewScenarioService(), nil, nil, appArgs, hostsConfig)
 
 		request := MockRequest{
 			Host:       "example.com",
@@ -396,17 +412,15 @@ func TestMockServiceFactory_initServiceChain(t *testing.T) {
 		cacheService := &mockCacheService{
 			cache: make(map[string][]byte),
 		}
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
 		factory := &MockServiceFactory{}
 
 		// Call initServiceChain multiple times
-		factory.initServiceChain(contentService, cacheService, false, false, false, gin.MIMEPlain, hostsConfig)
+		factory.initServiceChain(contentService, cacheService, scenario.NewScenarioService(), nil, nil, false, false, false, gin.MIMEPlain, hostsConfig)
 		firstChain := factory.mockServiceChain
 
-		factory.initServiceChain(contentService, cacheService, false, false, false, gin.MIMEPlain, hostsConfig)
+		factory.initServiceChain(contentService, cacheService, scenario.NewScenarioService(), nil, nil, false, false, false, gin.MIMEPlain, hostsConfig)
 		secondChain := factory.mockServiceChain
 
 		// Should be the same instance (sync.Once behavior)
@@ -1,10 +1,15 @@
 package mock
 
 import (
+	"context"
+	"io"
+	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/content"
 )
 
 type mockService interface {
@@ -19,6 +24,11 @@ type MockRequest struct {
 	Accept     string
 	Uuid       string
 	StatusCode int
+	Headers    http.Header
+	Body       []byte
+	Context    context.Context // carries the span of the request, when tracing is enabled
+
+	scenarioState func(name string) string // looks up the current state of a scenario, set by the scenario link
 }
 
 type MockResponse struct {
@@ -27,8 +37,35 @@ type MockResponse struct {
 	ContentType         string
 	Headers             map[string]string
 	Metadata            map[string]string
+	PathParams          map[string]string // values captured by the path parameters of a pattern mock
+	Template            bool              // Data and Headers are text/templates, yet to be rendered for the request
+	Stream              io.ReadCloser     `json:"-"` // when set, relayed to the client instead of Data
+	Fault               *Fault            `json:"-"` // when set, the response is broken at the connection level as the fault says
 	activeStatusConfig  *config.StatusConfig
 	activeLatencyConfig *config.LatencyConfig
+	conditional         bool                  // chosen by match rules, so it can't be reused for other requests
+	scenario            *content.ScenarioStep // the scenario step of the mock served, if any
+	simulatedLatency    time.Duration         // the time waited by the latency simulation
+}
+
+// Fault is a failure injected at the connection level, applied by the server while writing the response
+type Fault struct {
+	Type     string        // one of the config.Fault* fault types
+	Duration time.Duration // how long a hang lasts, zero to hang until the client gives up
+}
+
+// ctx returns the context of the request, or an empty one if none was given
+func (m MockRequest) ctx() context.Context {
+	if m.Context == nil {
+		return context.Background()
+	}
+
+	return m.Context
+}
+
+// SimulatedLatency returns the time the response was delayed by the latency simulation
+func (m *MockResponse) SimulatedLatency() time.Duration {
+	return m.simulatedLatency
 }
 
 // AddMetadata adds a key-value pair to the response's Metadata map
@@ -0,0 +1,86 @@
+package mock
+
+import (
+	"net/http"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/tracing"
+	"github.com/rs/zerolog/log"
+)
+
+type proxyMockService struct {
+	next        mockService
+	hostsConfig *config.HostsConfig
+	upstream    *upstreamClient
+}
+
+func (p *proxyMockService) getMockResponse(mockRequest MockRequest) *MockResponse {
+	mockResponse := p.nextOrNil(mockRequest)
+
+	if mockResponse == nil || mockResponse.Metadata[MetadataMatched] != "false" {
+		return mockResponse
+	}
+
+	// a simulated status should be served as-is, only the regular flow is proxied
+	if mockRequest.StatusCode != http.StatusOK {
+		return mockResponse
+	}
+
+	proxyConfig := p.hostsConfig.GetHostProxyConfig(mockRequest.Host)
+
+	if proxyConfig == nil {
+		return mockResponse
+	}
+
+	upstreamResponse, err := p.upstream.stream(proxyConfig.Upstream, mockRequest)
+
+	if err != nil {
+		log.Warn().
+			Str("uuid", mockRequest.Uuid).
+			Str("upstream", proxyConfig.Upstream).
+			Msgf("error while proxying request to upstream: %v", err)
+
+		return mockResponse
+	}
+
+	log.Info().
+		Str("uuid", mockRequest.Uuid).
+		Str("upstream_url", upstreamResponse.url).
+		Int("status_code", upstreamResponse.statusCode).
+		Msg("proxying request to upstream")
+
+	empty := []byte("")
+
+	proxiedResponse := &MockResponse{
+		StatusCode:  upstreamResponse.statusCode,
+		Data:        &empty,
+		ContentType: upstreamResponse.contentType,
+		Headers:     upstreamResponse.headers,
+		Stream:      upstreamResponse.stream,
+	}
+
+	proxiedResponse.AddMetadata(MetadataMatched, "false")
+	proxiedResponse.AddMetadata(MetadataSource, "proxy")
+	proxiedResponse.AddMetadata(MetadataUpstream, upstreamResponse.url)
+
+	return proxiedResponse
+}
+
+func (p *proxyMockService) setNext(next mockService) {
+	p.next = next
+}
+
+func (p *proxyMockService) nextOrNil(mockRequest MockRequest) *MockResponse {
+	if p.next == nil {
+		return nil
+	}
+
+	return p.next.getMockResponse(mockRequest)
+}
+
+func newProxyMockService(hostsConfig *config.HostsConfig, tracingService *tracing.TracingService) *proxyMockService {
+	return &proxyMockService{
+		hostsConfig: hostsConfig,
+		upstream:    newUpstreamClient(tracingService),
+	}
+}
@@ -0,0 +1,244 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/tracing"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newProxyHostsConfig(upstream string) *config.HostsConfig {
+	return config.NewHostsConfigFrom(map[string]config.HostConfig{
+		"example.com": {
+			ProxyConfig: &config.ProxyConfig{Upstream: upstream},
+		},
+	})
+}
+
+func TestProxyMockService_getMockResponse(t *testing.T) {
+	t.Run("streams unmatched request response from upstream", func(t *testing.T) {
+		var receivedMethod, receivedBody string
+
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedMethod = r.Method
+			body, _ := io.ReadAll(r.Body)
+			receivedBody = string(body)
+
+			w.Header().Set("Content-Type", "application/xml")
+			w.Header().Set("ETag", "abc")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("<missing/>"))
+		}))
+		defer upstream.Close()
+
+		service := newProxyMockService(newProxyHostsConfig(upstream.URL), nil)
+		service.setNext(&mockMockService{response: newUnmatchedResponse()})
+
+		response := service.getMockResponse(MockRequest{
+			Host:       "example.com",
+			Method:     "PUT",
+			URI:        "/api/items/1",
+			Uuid:       "test-uuid",
+			StatusCode: 200,
+			Body:       []byte("payload"),
+		})
+
+		if response == nil || response.Stream == nil {
+			t.Fatal("expected streamed response")
+		}
+
+		defer response.Stream.Close()
+
+		body, err := io.ReadAll(response.Stream)
+
+		if err != nil {
+			t.Fatalf("unexpected error reading stream: %v", err)
+		}
+
+		if string(body) != "<missing/>" {
+			t.Errorf("expected upstream body, got %s", string(body))
+		}
+
+		if response.StatusCode != http.StatusNotFound {
+			t.Errorf("expected upstream status 404, got %d", response.StatusCode)
+		}
+
+		if response.ContentType != "application/xml" || response.Headers["Etag"] != "abc" {
+			t.Errorf("expected upstream headers, got content type %q and %v", response.ContentType, response.Headers)
+		}
+
+		if response.Metadata[MetadataSource] != "proxy" || response.Metadata[MetadataMatched] != "false" {
+			t.Errorf("unexpected metadata: %v", response.Metadata)
+		}
+
+		if response.Metadata[MetadataUpstream] != upstream.URL+"/api/items/1" {
+			t.Errorf("unexpected upstream metadata: %s", response.Metadata[MetadataUpstream])
+		}
+
+		if receivedMethod != "PUT" || receivedBody != "payload" {
+			t.Errorf("upstream received method=%q body=%q", receivedMethod, receivedBody)
+		}
+	})
+
+	t.Run("does not proxy matched responses", func(t *testing.T) {
+		called := false
+
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer upstream.Close()
+
+		data := []byte("mocked")
+		matched := &MockResponse{StatusCode: 200, Data: &data}
+		matched.AddMetadata(MetadataMatched, "true")
+
+		service := newProxyMockService(newProxyHostsConfig(upstream.URL), nil)
+		service.setNext(&mockMockService{response: matched})
+
+		response := service.getMockResponse(MockRequest{Host: "example.com", Method: "GET", URI: "/api", StatusCode: 200})
+
+		if response != matched || called {
+			t.Error("expected matched response to be returned without calling the upstream")
+		}
+	})
+
+	t.Run("does not proxy when a status is being simulated", func(t *testing.T) {
+		called := false
+
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer upstream.Close()
+
+		unmatched := newUnmatchedResponse()
+
+		service := newProxyMockService(newProxyHostsConfig(upstream.URL), nil)
+		service.setNext(&mockMockService{response: unmatched})
+
+		response := service.getMockResponse(MockRequest{Host: "example.com", Method: "GET", URI: "/api", StatusCode: 503})
+
+		if response != unmatched || called {
+			t.Error("expected simulated status response without calling the upstream")
+		}
+	})
+
+	t.Run("does not proxy when host has no proxy config", func(t *testing.T) {
+		unmatched := newUnmatchedResponse()
+
+		service := newProxyMockService(config.NewHostsConfigFrom(map[string]config.HostConfig{}), nil)
+		service.setNext(&mockMockService{response: unmatched})
+
+		response := service.getMockResponse(MockRequest{Host: "example.com", Method: "GET", URI: "/api", StatusCode: 200})
+
+		if response != unmatched {
+			t.Error("expected unmatched response to be returned untouched")
+		}
+	})
+
+	t.Run("returns unmatched response when upstream is unreachable", func(t *testing.T) {
+		unmatched := newUnmatchedResponse()
+
+		service := newProxyMockService(newProxyHostsConfig("http://127.0.0.1:1"), nil)
+		service.setNext(&mockMockService{response: unmatched})
+
+		response := service.getMockResponse(MockRequest{Host: "example.com", Method: "GET", URI: "/api", StatusCode: 200})
+
+		if response != unmatched {
+			t.Error("expected unmatched response when upstream fails")
+		}
+	})
+}
+
+func TestProxyMockService_upstreamContext(t *testing.T) {
+	t.Run("calls the upstream in a child span and passes the trace on", func(t *testing.T) {
+		var receivedTraceparent string
+
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedTraceparent = r.Header.Get("traceparent")
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer upstream.Close()
+
+		recorder := tracetest.NewSpanRecorder()
+		tracingService, err := tracing.NewTracingServiceFrom(recorder, "test")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		service := newProxyMockService(newProxyHostsConfig(upstream.URL), tracingService)
+		service.setNext(&mockMockService{response: newUnmatchedResponse()})
+
+		ctx, parent := tracingService.Start(context.Background(), "GET example.com")
+		response := service.getMockResponse(MockRequest{Host: "example.com", Method: "GET", URI: "/api", StatusCode: 200, Context: ctx,
+			// the trace of the client is replaced by the one of the mock server
+			Headers: http.Header{"Traceparent": []string{"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}}})
+		parent.End()
+
+		if response == nil || response.Stream == nil {
+			t.Fatal("expected streamed response")
+		}
+
+		response.Stream.Close()
+		spans := recorder.Ended()
+
+		if len(spans) != 2 {
+			t.Fatalf("expected 2 spans, got %d", len(spans))
+		}
+
+		upstreamSpan := spans[0]
+
+		if upstreamSpan.SpanKind() != trace.SpanKindClient || upstreamSpan.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("expected a client span child of the request span, got %s %v", upstreamSpan.Name(), upstreamSpan.Parent())
+		}
+
+		expected := fmt.Sprintf("00-%s-%s-01", upstreamSpan.SpanContext().TraceID(), upstreamSpan.SpanContext().SpanID())
+
+		if receivedTraceparent != expected {
+			t.Errorf("expected traceparent %q, got %q", expected, receivedTraceparent)
+		}
+	})
+
+	t.Run("cancels the upstream call when the client goes away", func(t *testing.T) {
+		started, canceled := make(chan struct{}), make(chan struct{})
+
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-r.Context().Done()
+			close(canceled)
+		}))
+		defer upstream.Close()
+
+		unmatched := newUnmatchedResponse()
+		service := newProxyMockService(newProxyHostsConfig(upstream.URL), nil)
+		service.setNext(&mockMockService{response: unmatched})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		responses := make(chan *MockResponse, 1)
+
+		go func() {
+			responses <- service.getMockResponse(MockRequest{Host: "example.com", Method: "GET", URI: "/api", StatusCode: 200, Context: ctx})
+		}()
+
+		<-started
+		cancel()
+
+		select {
+		case <-canceled:
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the upstream call to be canceled")
+		}
+
+		if response := <-responses; response != unmatched {
+			t.Error("expected unmatched response when the upstream call is canceled")
+		}
+	})
+}
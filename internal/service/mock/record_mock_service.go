@@ -0,0 +1,127 @@
+package mock
+
+import (
+	"net/http"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/tracing"
+	"github.com/rs/zerolog/log"
+)
+
+type recordMockService struct {
+	next           mockService
+	hostsConfig    *config.HostsConfig
+	contentService content.ContentService
+	upstream       *upstreamClient
+}
+
+func (r *recordMockService) getMockResponse(mockRequest MockRequest) *MockResponse {
+	mockResponse := r.nextOrNil(mockRequest)
+
+	if mockResponse == nil || mockResponse.Metadata[MetadataMatched] != "false" {
+		return mockResponse
+	}
+
+	// a simulated status should be served as-is, only the regular flow is recorded
+	if mockRequest.StatusCode != http.StatusOK {
+		return mockResponse
+	}
+
+	recordConfig := r.hostsConfig.GetHostRecordConfig(mockRequest.Host)
+
+	if recordConfig == nil {
+		return mockResponse
+	}
+
+	upstreamResponse, err := r.upstream.forward(recordConfig.Upstream, mockRequest)
+
+	if err != nil {
+		log.Warn().
+			Str("uuid", mockRequest.Uuid).
+			Str("upstream", recordConfig.Upstream).
+			Msgf("error while recording mock from upstream: %v", err)
+
+		return mockResponse
+	}
+
+	log.Info().
+		Str("uuid", mockRequest.Uuid).
+		Str("upstream_url", upstreamResponse.url).
+		Int("status_code", upstreamResponse.statusCode).
+		Msg("recording mock from upstream")
+
+	recorded := "true"
+
+	if err := r.save(mockRequest, upstreamResponse); err != nil {
+		recorded = "false"
+
+		log.Warn().
+			Str("uuid", mockRequest.Uuid).
+			Str("host", mockRequest.Host).
+			Str("uri", mockRequest.URI).
+			Str("method", mockRequest.Method).
+			Msgf("error while saving recorded mock: %v", err)
+	}
+
+	recordedResponse := &MockResponse{
+		StatusCode:  upstreamResponse.statusCode,
+		Data:        &upstreamResponse.body,
+		ContentType: upstreamResponse.contentType,
+		Headers:     upstreamResponse.headers,
+	}
+
+	recordedResponse.AddMetadata(MetadataMatched, "false")
+	recordedResponse.AddMetadata(MetadataSource, "record")
+	recordedResponse.AddMetadata(MetadataUpstream, upstreamResponse.url)
+	recordedResponse.AddMetadata(MetadataRecorded, recorded)
+
+	return recordedResponse
+}
+
+// save writes the upstream response as the mock of the request, under the status requested so it is the one found
+// next time. The upstream status, when different, and the headers go to the sidecar file, to be replayed as well.
+func (r *recordMockService) save(mockRequest MockRequest, upstreamResponse *upstreamResponse) error {
+	if err := r.contentService.SetContent(mockRequest.Host, mockRequest.URI, mockRequest.Method, mockRequest.Uuid, mockRequest.StatusCode, &upstreamResponse.body); err != nil {
+		return err
+	}
+
+	meta := content.ContentMeta{Headers: make(map[string]string, len(upstreamResponse.headers)+1)}
+
+	if upstreamResponse.statusCode != mockRequest.StatusCode {
+		meta.StatusCode = upstreamResponse.statusCode
+	}
+
+	for key, value := range upstreamResponse.headers {
+		// the date of the recording would be stale once replayed
+		if key != "Date" {
+			meta.Headers[key] = value
+		}
+	}
+
+	if len(upstreamResponse.contentType) > 0 {
+		meta.Headers["Content-Type"] = upstreamResponse.contentType
+	}
+
+	return r.contentService.SetContentMeta(mockRequest.Host, mockRequest.URI, mockRequest.Method, mockRequest.Uuid, mockRequest.StatusCode, meta)
+}
+
+func (r *recordMockService) setNext(next mockService) {
+	r.next = next
+}
+
+func (r *recordMockService) nextOrNil(mockRequest MockRequest) *MockResponse {
+	if r.next == nil {
+		return nil
+	}
+
+	return r.next.getMockResponse(mockRequest)
+}
+
+func newRecordMockService(hostsConfig *config.HostsConfig, contentService content.ContentService, tracingService *tracing.TracingService) *recordMockService {
+	return &recordMockService{
+		hostsConfig:    hostsConfig,
+		contentService: contentService,
+		upstream:       newUpstreamClient(tracingService),
+	}
+}
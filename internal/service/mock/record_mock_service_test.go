@@ -0,0 +1,277 @@
+package mock
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/content"
+)
+
+func newUnmatchedResponse() *MockResponse {
+	empty := []byte("")
+	resp := &MockResponse{
+		StatusCode: 200,
+		Data:       &empty,
+	}
+	resp.AddMetadata(MetadataMatched, "false")
+
+	return resp
+}
+
+func newRecordHostsConfig(upstream string) *config.HostsConfig {
+	return config.NewHostsConfigFrom(map[string]config.HostConfig{
+		"example.com": {
+			RecordConfig: &config.RecordConfig{Upstream: upstream},
+		},
+	})
+}
+
+func TestRecordMockService_getMockResponse(t *testing.T) {
+	t.Run("forwards unmatched request to upstream and saves the response", func(t *testing.T) {
+		var receivedPath, receivedBody, receivedHeader string
+
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedPath = r.URL.RequestURI()
+			receivedHeader = r.Header.Get("X-Custom")
+			body, _ := io.ReadAll(r.Body)
+			receivedBody = string(body)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Upstream", "yes")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":1}`))
+		}))
+		defer upstream.Close()
+
+		contentService := &mockContentService{
+			contents: make(map[string][]byte),
+			events:   make(chan content.ContentEvent),
+		}
+
+		service := newRecordMockService(newRecordHostsConfig(upstream.URL), contentService, nil)
+		service.setNext(&mockMockService{response: newUnmatchedResponse()})
+
+		response := service.getMockResponse(MockRequest{
+			Host:       "example.com",
+			Method:     "POST",
+			URI:        "/api/users?page=1",
+			Uuid:       "test-uuid",
+			StatusCode: 200,
+			Headers:    http.Header{"X-Custom": []string{"value"}},
+			Body:       []byte(`{"name":"alice"}`),
+		})
+
+		if response == nil {
+			t.Fatal("expected response, got nil")
+		}
+
+		if response.StatusCode != http.StatusCreated {
+			t.Errorf("expected status 201, got %d", response.StatusCode)
+		}
+
+		if string(*response.Data) != `{"id":1}` {
+			t.Errorf("expected upstream body, got %s", string(*response.Data))
+		}
+
+		if response.ContentType != "application/json" {
+			t.Errorf("expected upstream content type, got %s", response.ContentType)
+		}
+
+		if response.Headers["X-Upstream"] != "yes" {
+			t.Errorf("expected upstream headers to be relayed, got %v", response.Headers)
+		}
+
+		if response.Metadata[MetadataSource] != "record" || response.Metadata[MetadataRecorded] != "true" {
+			t.Errorf("unexpected metadata: %v", response.Metadata)
+		}
+
+		if receivedPath != "/api/users?page=1" || receivedBody != `{"name":"alice"}` || receivedHeader != "value" {
+			t.Errorf("upstream received path=%q body=%q header=%q", receivedPath, receivedBody, receivedHeader)
+		}
+
+		saved, exists := contentService.contents["example.com:/api/users?page=1:POST"]
+
+		if !exists {
+			t.Fatal("expected recorded content to be saved")
+		}
+
+		if string(saved) != `{"id":1}` {
+			t.Errorf("expected saved content to be upstream body, got %s", string(saved))
+		}
+
+		meta := contentService.metas["example.com:/api/users?page=1:POST"]
+
+		if meta.StatusCode != http.StatusCreated {
+			t.Errorf("expected upstream status to be saved, got %d", meta.StatusCode)
+		}
+
+		if meta.Headers["Content-Type"] != "application/json" || meta.Headers["X-Upstream"] != "yes" {
+			t.Errorf("expected upstream headers to be saved, got %v", meta.Headers)
+		}
+
+		if _, exists := meta.Headers["Date"]; exists {
+			t.Error("expected the Date header not to be saved")
+		}
+	})
+
+	t.Run("replays the recorded response from the mocks directory", func(t *testing.T) {
+		calls := 0
+
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Upstream", "yes")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":1}`))
+		}))
+		defer upstream.Close()
+
+		contentService := content.NewFilesystemContentService(&config.MocksDirectoryConfig{Path: t.TempDir()})
+
+		service := newRecordMockService(newRecordHostsConfig(upstream.URL), contentService, nil)
+		service.setNext(newContentMockService(contentService))
+
+		request := MockRequest{Host: "example.com", Method: "POST", URI: "/api/users", Uuid: "test-uuid", StatusCode: 200}
+
+		service.getMockResponse(request)
+		response := service.getMockResponse(request)
+
+		if calls != 1 {
+			t.Errorf("expected upstream to be called once, got %d", calls)
+		}
+
+		if response.Metadata[MetadataMatched] != "true" {
+			t.Fatalf("expected the recorded mock to be matched, got %v", response.Metadata)
+		}
+
+		if response.StatusCode != http.StatusCreated {
+			t.Errorf("expected upstream status to be replayed, got %d", response.StatusCode)
+		}
+
+		if response.ContentType != "application/json" || response.Headers["X-Upstream"] != "yes" {
+			t.Errorf("expected upstream headers to be replayed, got %q %v", response.ContentType, response.Headers)
+		}
+	})
+
+	t.Run("does not forward matched responses", func(t *testing.T) {
+		called := false
+
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer upstream.Close()
+
+		data := []byte("mocked")
+		matched := &MockResponse{StatusCode: 200, Data: &data}
+		matched.AddMetadata(MetadataMatched, "true")
+
+		service := newRecordMockService(newRecordHostsConfig(upstream.URL), &mockContentService{contents: make(map[string][]byte)}, nil)
+		service.setNext(&mockMockService{response: matched})
+
+		response := service.getMockResponse(MockRequest{Host: "example.com", Method: "GET", URI: "/api", StatusCode: 200})
+
+		if response != matched {
+			t.Error("expected matched response to be returned untouched")
+		}
+
+		if called {
+			t.Error("upstream should not be called for matched responses")
+		}
+	})
+
+	t.Run("does not forward when host has no record config", func(t *testing.T) {
+		unmatched := newUnmatchedResponse()
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{})
+
+		service := newRecordMockService(hostsConfig, &mockContentService{contents: make(map[string][]byte)}, nil)
+		service.setNext(&mockMockService{response: unmatched})
+
+		response := service.getMockResponse(MockRequest{Host: "example.com", Method: "GET", URI: "/api", StatusCode: 200})
+
+		if response != unmatched {
+			t.Error("expected unmatched response to be returned untouched")
+		}
+	})
+
+	t.Run("does not forward when a status is being simulated", func(t *testing.T) {
+		called := false
+
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer upstream.Close()
+
+		service := newRecordMockService(newRecordHostsConfig(upstream.URL), &mockContentService{contents: make(map[string][]byte)}, nil)
+		service.setNext(&mockMockService{response: newUnmatchedResponse()})
+
+		service.getMockResponse(MockRequest{Host: "example.com", Method: "GET", URI: "/api", StatusCode: 500})
+
+		if called {
+			t.Error("upstream should not be called when a status is simulated")
+		}
+	})
+
+	t.Run("returns unmatched response when upstream is unreachable", func(t *testing.T) {
+		unmatched := newUnmatchedResponse()
+
+		service := newRecordMockService(newRecordHostsConfig("http://127.0.0.1:1"), &mockContentService{contents: make(map[string][]byte)}, nil)
+		service.setNext(&mockMockService{response: unmatched})
+
+		response := service.getMockResponse(MockRequest{Host: "example.com", Method: "GET", URI: "/api", StatusCode: 200})
+
+		if response != unmatched {
+			t.Error("expected unmatched response when upstream fails")
+		}
+	})
+
+	t.Run("returns nil when next is nil", func(t *testing.T) {
+		service := newRecordMockService(newRecordHostsConfig("http://localhost"), nil, nil)
+
+		if response := service.getMockResponse(MockRequest{}); response != nil {
+			t.Error("expected nil response")
+		}
+	})
+}
+
+func TestUpstreamClient_forward(t *testing.T) {
+	t.Run("does not follow redirects and strips hop-by-hop headers", func(t *testing.T) {
+		var connectionHeader string
+
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			connectionHeader = r.Header.Get("Proxy-Authorization")
+			w.Header().Set("Location", "/elsewhere")
+			w.WriteHeader(http.StatusFound)
+		}))
+		defer upstream.Close()
+
+		client := newUpstreamClient(nil)
+		response, err := client.forward(upstream.URL+"/", MockRequest{
+			Method:  "GET",
+			URI:     "/path",
+			Headers: http.Header{"Proxy-Authorization": []string{"secret"}},
+		})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if response.statusCode != http.StatusFound {
+			t.Errorf("expected status 302, got %d", response.statusCode)
+		}
+
+		if response.headers["Location"] != "/elsewhere" {
+			t.Errorf("expected Location header, got %v", response.headers)
+		}
+
+		if response.url != upstream.URL+"/path" {
+			t.Errorf("unexpected upstream url %s", response.url)
+		}
+
+		if connectionHeader != "" {
+			t.Error("hop-by-hop headers should not be forwarded")
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package mock
+
+import (
+	"github.com/Caik/go-mock-server/internal/service/scenario"
+	"github.com/rs/zerolog/log"
+)
+
+type scenarioMockService struct {
+	next            mockService
+	scenarioService *scenario.ScenarioService
+}
+
+func (s *scenarioMockService) getMockResponse(mockRequest MockRequest) *MockResponse {
+	mockRequest.scenarioState = s.scenarioService.State
+
+	mockResponse := s.nextOrNil(mockRequest)
+
+	if mockResponse == nil || mockResponse.scenario == nil {
+		return mockResponse
+	}
+
+	step := mockResponse.scenario
+	mockResponse.AddMetadata(MetadataScenario, step.Name)
+
+	if len(step.Next) > 0 {
+		// the transition only happens if no other request moved the scenario in the meantime
+		if s.scenarioService.Transition(step.Name, step.State, step.Next) {
+			log.Info().
+				Str("uuid", mockRequest.Uuid).
+				Str("scenario", step.Name).
+				Msgf("scenario moved to state %s", step.Next)
+		}
+	}
+
+	mockResponse.AddMetadata(MetadataScenarioState, s.scenarioService.State(step.Name))
+
+	return mockResponse
+}
+
+func (s *scenarioMockService) setNext(next mockService) {
+	s.next = next
+}
+
+func (s *scenarioMockService) nextOrNil(mockRequest MockRequest) *MockResponse {
+	if s.next == nil {
+		return nil
+	}
+
+	return s.next.getMockResponse(mockRequest)
+}
+
+func newScenarioMockService(scenarioService *scenario.ScenarioService) *scenarioMockService {
+	return &scenarioMockService{
+		scenarioService: scenarioService,
+	}
+}
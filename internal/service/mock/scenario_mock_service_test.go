@@ -0,0 +1,100 @@
+package mock
+
+import (
+	"testing"
+
+	"github.com/Caik/go-mock-server/internal/service/content"
+	"github.com/Caik/go-mock-server/internal/service/scenario"
+)
+
+func TestScenarioMockService_getMockResponse(t *testing.T) {
+	request := MockRequest{Host: "example.com", Method: "GET", URI: "/orders/1", Uuid: "test-uuid"}
+
+	t.Run("passes the scenario state lookup to the next services", func(t *testing.T) {
+		scenarioService := scenario.NewScenarioService()
+		scenarioService.SetState("order", "shipped")
+
+		mockNext := &mockMockService{response: &MockResponse{StatusCode: 200}}
+		service := newScenarioMockService(scenarioService)
+		service.setNext(mockNext)
+
+		service.getMockResponse(request)
+
+		if mockNext.lastRequest.scenarioState == nil {
+			t.Fatal("expected scenario state lookup to be set")
+		}
+
+		if state := mockNext.lastRequest.scenarioState("order"); state != "shipped" {
+			t.Errorf("expected state shipped, got %q", state)
+		}
+	})
+
+	t.Run("moves the scenario to the next state once the mock is served", func(t *testing.T) {
+		scenarioService := scenario.NewScenarioService()
+		service := newScenarioMockService(scenarioService)
+		service.setNext(&mockMockService{response: &MockResponse{
+			StatusCode: 200,
+			scenario:   &content.ScenarioStep{Name: "order", State: scenario.StartState, Next: "shipped"},
+		}})
+
+		response := service.getMockResponse(request)
+
+		if state := scenarioService.State("order"); state != "shipped" {
+			t.Errorf("expected state shipped, got %q", state)
+		}
+
+		if response.Metadata[MetadataScenario] != "order" || response.Metadata[MetadataScenarioState] != "shipped" {
+			t.Errorf("unexpected metadata: %v", response.Metadata)
+		}
+	})
+
+	t.Run("doesn't move a scenario that changed state in the meantime", func(t *testing.T) {
+		scenarioService := scenario.NewScenarioService()
+		scenarioService.SetState("order", "delivered")
+
+		service := newScenarioMockService(scenarioService)
+		service.setNext(&mockMockService{response: &MockResponse{
+			StatusCode: 200,
+			scenario:   &content.ScenarioStep{Name: "order", State: scenario.StartState, Next: "shipped"},
+		}})
+
+		service.getMockResponse(request)
+
+		if state := scenarioService.State("order"); state != "delivered" {
+			t.Errorf("expected state delivered, got %q", state)
+		}
+	})
+
+	t.Run("keeps the state of a mock without next state", func(t *testing.T) {
+		scenarioService := scenario.NewScenarioService()
+		scenarioService.SetState("order", "delivered")
+
+		service := newScenarioMockService(scenarioService)
+		service.setNext(&mockMockService{response: &MockResponse{
+			StatusCode: 200,
+			scenario:   &content.ScenarioStep{Name: "order", State: "delivered"},
+		}})
+
+		response := service.getMockResponse(request)
+
+		if response.Metadata[MetadataScenarioState] != "delivered" {
+			t.Errorf("expected state delivered, got %q", response.Metadata[MetadataScenarioState])
+		}
+	})
+
+	t.Run("returns responses without scenario untouched", func(t *testing.T) {
+		plain := &MockResponse{StatusCode: 200}
+		service := newScenarioMockService(scenario.NewScenarioService())
+		service.setNext(&mockMockService{response: plain})
+
+		if response := service.getMockResponse(request); response != plain || response.Metadata != nil {
+			t.Error("expected response to be returned untouched")
+		}
+	})
+
+	t.Run("returns nil when next is nil", func(t *testing.T) {
+		if response := newScenarioMockService(scenario.NewScenarioService()).getMockResponse(request); response != nil {
+			t.Error("expected nil response")
+		}
+	})
+}
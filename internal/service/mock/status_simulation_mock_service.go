@@ -5,12 +5,16 @@ import (
 	"strconv"
 
 	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/metrics"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type statusSimulationMockService struct {
-	next        mockService
-	hostsConfig *config.HostsConfig
+	next           mockService
+	hostsConfig    *config.HostsConfig
+	metricsService *metrics.MetricsService
 }
 
 type statusPercentageWrapper struct {
@@ -37,6 +41,13 @@ func (e *statusSimulationMockService) getMockResponse(mockRequest MockRequest) *
 	// so ContentMockService can find the correct status-specific file.
 	mockRequest.StatusCode = statusCode
 
+	if drawnWrapper != nil {
+		trace.SpanFromContext(mockRequest.ctx()).SetAttributes(
+			attribute.Int("mock.status.simulated", statusCode),
+			attribute.String("mock.status.rule_scope", scope),
+		)
+	}
+
 	log.Info().
 		Str("uuid", mockRequest.Uuid).
 		Int("status_code", statusCode).
@@ -44,13 +55,17 @@ func (e *statusSimulationMockService) getMockResponse(mockRequest MockRequest) *
 
 	resp := e.nextOrNil(mockRequest)
 
+	// only a drawn status overrides the response status, so responses relayed from an upstream keep their own
 	if resp != nil {
-		resp.StatusCode = statusCode
+		if resp.StatusCode == 0 || drawnWrapper != nil {
+			resp.StatusCode = statusCode
+		}
 
 		if drawnWrapper != nil {
 			resp.activeStatusConfig = &drawnWrapper.originalStatusConfig
 			resp.AddMetadata(MetadataSimulatedStatus, "true")
 			resp.AddMetadata(MetadataStatusRuleScope, scope)
+			e.metricsService.ObserveSimulatedStatus(mockRequest.Host, statusCode)
 		}
 	}
 
@@ -100,8 +115,9 @@ func (e *statusSimulationMockService) drawStatus(statusesConfig *map[string]conf
 	return nil
 }
 
-func newStatusSimulationMockService(hostsConfig *config.HostsConfig) *statusSimulationMockService {
+func newStatusSimulationMockService(hostsConfig *config.HostsConfig, metricsService *metrics.MetricsService) *statusSimulationMockService {
 	return &statusSimulationMockService{
-		hostsConfig: hostsConfig,
+		hostsConfig:    hostsConfig,
+		metricsService: metricsService,
 	}
 }
@@ -1,9 +1,11 @@
 package mock
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/metrics"
 )
 
 // Helper function to create int pointers
@@ -11,21 +13,47 @@ func intPtr(i int) *int {
 	return &i
 }
 
+func TestStatusSimulationMockService_metrics(t *testing.T) {
+	hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+		"example.com": {
+			StatusesConfig: map[string]config.StatusConfig{
+				"503": {Percentage: intPtr(100)},
+			},
+		},
+	})
+
+	metricsService := metrics.NewMetricsService(&config.AppArguments{}, nil, nil, nil, hostsConfig, nil)
+	service := newStatusSimulationMockService(hostsConfig, metricsService)
+	service.setNext(&mockMockService{response: &MockResponse{StatusCode: 200}})
+
+	service.getMockResponse(MockRequest{Host: "example.com", Method: "GET", URI: "/api/test", Uuid: "test-uuid"})
+	service.getMockResponse(MockRequest{Host: "other.com", Method: "GET", URI: "/api/test", Uuid: "test-uuid"})
+
+	var output strings.Builder
+	metricsService.Write(&output)
+
+	if !strings.Contains(output.String(), `mock_server_simulated_statuses_total{host="example.com",status="503"} 1`) {
+		t.Errorf("expected the injected status to be counted, got\n%s", output.String())
+	}
+
+	if strings.Contains(output.String(), `host="other.com"`) {
+		t.Errorf("expected the status not injected not to be counted, got\n%s", output.String())
+	}
+}
+
 func TestStatusSimulationMockService_getMockResponse(t *testing.T) {
 	t.Run("returns status response and calls downstream when status is drawn", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {
-					StatusesConfig: map[string]config.StatusConfig{
-						"500": {
-							Percentage: intPtr(100), // Always return this status
-						},
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				StatusesConfig: map[string]config.StatusConfig{
+					"500": {
+						Percentage: intPtr(100), // Always return this status
 					},
 				},
 			},
-		}
+		})
 
-		service := newStatusSimulationMockService(hostsConfig)
+		service := newStatusSimulationMockService(hostsConfig, nil)
 
 		successData := []byte("success response")
 		mockNext := &mockMockService{
@@ -61,19 +89,17 @@ func TestStatusSimulationMockService_getMockResponse(t *testing.T) {
 	})
 
 	t.Run("passes through with status 200 when no status is drawn", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: map[string]config.HostConfig{
-				"example.com": {
-					StatusesConfig: map[string]config.StatusConfig{
-						"500": {
-							Percentage: intPtr(0), // Never return status
-						},
+		hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+			"example.com": {
+				StatusesConfig: map[string]config.StatusConfig{
+					"500": {
+						Percentage: intPtr(0), // Never return status
 					},
 				},
 			},
-		}
+		})
 
-		service := newStatusSimulationMockService(hostsConfig)
+		service := newStatusSimulationMockService(hostsConfig, nil)
 
 		successData2 := []byte("success response")
 		mockNext := &mockMockService{
@@ -109,11 +135,9 @@ func TestStatusSimulationMockService_getMockResponse(t *testing.T) {
 	})
 
 	t.Run("calls downstream when no status config", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := newStatusSimulationMockService(hostsConfig)
+		service := newStatusSimulationMockService(hostsConfig, nil)
 
 		successData3 := []byte("success response")
 		mockNext := &mockMockService{
@@ -310,13 +334,33 @@ func TestStatusSimulationMockService_drawStatus(t *testing.T) {
 	})
 }
 
+func TestStatusSimulationMockService_keepsDownstreamStatus(t *testing.T) {
+	t.Run("keeps downstream status when no status is drawn", func(t *testing.T) {
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+
+		service := newStatusSimulationMockService(hostsConfig, nil)
+
+		data := []byte("created")
+		service.setNext(&mockMockService{
+			response: &MockResponse{
+				StatusCode: 201,
+				Data:       &data,
+			},
+		})
+
+		response := service.getMockResponse(MockRequest{Host: "example.com", Method: "POST", URI: "/api/test"})
+
+		if response.StatusCode != 201 {
+			t.Errorf("expected downstream status 201 to be kept, got %d", response.StatusCode)
+		}
+	})
+}
+
 func TestStatusSimulationMockService_setNext(t *testing.T) {
 	t.Run("sets next service correctly", func(t *testing.T) {
-		hostsConfig := &config.HostsConfig{
-			Hosts: make(map[string]config.HostConfig),
-		}
+		hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
 
-		service := newStatusSimulationMockService(hostsConfig)
+		service := newStatusSimulationMockService(hostsConfig, nil)
 		mockNext := &mockMockService{}
 
 		service.setNext(mockNext)
@@ -0,0 +1,286 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/rest"
+	"github.com/Caik/go-mock-server/internal/service/matcher"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const randomStringLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+var (
+	fakeFirstNames = []string{"Alice", "Bob", "Carol", "David", "Emma", "Frank", "Grace", "Henry", "Isabel", "John"}
+	fakeLastNames  = []string{"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Silva", "Martin"}
+	fakeCities     = []string{"Lisbon", "Berlin", "Toronto", "Tokyo", "Sydney", "Madrid", "Chicago", "Dublin", "Oslo", "Lima"}
+	fakeCompanies  = []string{"Acme", "Globex", "Initech", "Umbrella", "Hooli", "Stark", "Wayne", "Wonka", "Cyberdyne", "Soylent"}
+)
+
+// templateRequest is the request data available to templates as .Request
+type templateRequest struct {
+	Host       string
+	Method     string
+	URI        string
+	Path       string
+	Uuid       string
+	PathParams map[string]string
+	Query      map[string]string
+	Headers    map[string]string
+	Body       string
+	Json       any
+}
+
+type templateData struct {
+	Request templateRequest
+}
+
+type templateMockService struct {
+	next       mockService
+	countersMu sync.Mutex
+	counters   map[string]int64
+}
+
+func (t *templateMockService) getMockResponse(mockRequest MockRequest) *MockResponse {
+	mockResponse := t.nextOrNil(mockRequest)
+
+	if mockResponse == nil || !mockResponse.Template {
+		return mockResponse
+	}
+
+	rendered, err := t.render(mockRequest, mockResponse)
+
+	if err != nil {
+		log.Warn().
+			Str("uuid", mockRequest.Uuid).
+			Str("path", mockResponse.Metadata[MetadataPath]).
+			Msgf("error while rendering mock template: %v", err)
+
+		return t.new500Response(err)
+	}
+
+	log.Info().
+		Str("uuid", mockRequest.Uuid).
+		Msg("mock template rendered")
+
+	return rendered
+}
+
+func (t *templateMockService) setNext(next mockService) {
+	t.next = next
+}
+
+func (t *templateMockService) nextOrNil(mockRequest MockRequest) *MockResponse {
+	if t.next == nil {
+		return nil
+	}
+
+	return t.next.getMockResponse(mockRequest)
+}
+
+// render returns a copy of the response with its body and headers rendered, the response itself may be held by the
+// cache and must keep the raw template
+func (t *templateMockService) render(mockRequest MockRequest, mockResponse *MockResponse) (*MockResponse, error) {
+	data := t.newTemplateData(mockRequest, mockResponse)
+	funcs := t.newFuncMap(data)
+	rendered := *mockResponse
+
+	if mockResponse.Data != nil {
+		body, err := t.execute("body", string(*mockResponse.Data), data, funcs)
+
+		if err != nil {
+			return nil, err
+		}
+
+		rendered.Data = &body
+	}
+
+	if len(mockResponse.Headers) > 0 {
+		rendered.Headers = make(map[string]string, len(mockResponse.Headers))
+
+		for key, value := range mockResponse.Headers {
+			header, err := t.execute(key, value, data, funcs)
+
+			if err != nil {
+				return nil, err
+			}
+
+			rendered.Headers[key] = string(header)
+		}
+	}
+
+	rendered.Template = false
+
+	return &rendered, nil
+}
+
+func (t *templateMockService) execute(name, text string, data templateData, funcs template.FuncMap) ([]byte, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=zero").Parse(text)
+
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing template: %v", err)
+	}
+
+	var buffer bytes.Buffer
+
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		return nil, fmt.Errorf("error while executing template: %v", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (t *templateMockService) newTemplateData(mockRequest MockRequest, mockResponse *MockResponse) templateData {
+	path, rawQuery, _ := strings.Cut(mockRequest.URI, "?")
+	request := matcher.NewRequest(mockRequest.Headers, rawQuery, mockRequest.Body)
+
+	query := make(map[string]string, len(request.Query))
+
+	for key, values := range request.Query {
+		query[key] = strings.Join(values, ",")
+	}
+
+	headers := make(map[string]string, len(mockRequest.Headers))
+
+	for key, values := range mockRequest.Headers {
+		headers[key] = strings.Join(values, ", ")
+	}
+
+	var body any
+	decoder := json.NewDecoder(bytes.NewReader(mockRequest.Body))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&body); err != nil {
+		body = nil
+	}
+
+	pathParams := mockResponse.PathParams
+
+	if pathParams == nil {
+		pathParams = make(map[string]string)
+	}
+
+	return templateData{
+		Request: templateRequest{
+			Host:       mockRequest.Host,
+			Method:     mockRequest.Method,
+			URI:        mockRequest.URI,
+			Path:       path,
+			Uuid:       mockRequest.Uuid,
+			PathParams: pathParams,
+			Query:      query,
+			Headers:    headers,
+			Body:       string(mockRequest.Body),
+			Json:       body,
+		},
+	}
+}
+
+func (t *templateMockService) newFuncMap(data templateData) template.FuncMap {
+	return template.FuncMap{
+		// request helpers
+		"pathParam": func(name string) string { return data.Request.PathParams[name] },
+		"query":     func(name string) string { return data.Request.Query[name] },
+		"header":    func(name string) string { return data.Request.Headers[http.CanonicalHeaderKey(name)] },
+		"jsonPath": func(path string) any {
+			value, _ := matcher.LookupJsonPath(data.Request.Json, path)
+			return value
+		},
+
+		// values
+		"now":          time.Now,
+		"timestamp":    func() int64 { return time.Now().Unix() },
+		"uuid":         uuid.NewString,
+		"randomInt":    func(min, max int) int { return min + rand.IntN(max-min+1) },
+		"randomString": t.randomString,
+		"counter":      t.nextCounter,
+
+		// faker values
+		"fakeFirstName": func() string { return randomItem(fakeFirstNames) },
+		"fakeLastName":  func() string { return randomItem(fakeLastNames) },
+		"fakeName":      func() string { return randomItem(fakeFirstNames) + " " + randomItem(fakeLastNames) },
+		"fakeEmail": func() string {
+			return strings.ToLower(randomItem(fakeFirstNames)+"."+randomItem(fakeLastNames)) + "@example.com"
+		},
+		"fakeCity":    func() string { return randomItem(fakeCities) },
+		"fakeCompany": func() string { return randomItem(fakeCompanies) },
+
+		// string helpers
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"json": func(value any) (string, error) {
+			data, err := json.Marshal(value)
+			return string(data), err
+		},
+		"default": func(fallback, value any) any {
+			if value == nil || value == "" {
+				return fallback
+			}
+
+			return value
+		},
+	}
+}
+
+// nextCounter increments the named counter and returns its new value. Counters are kept for as long as the server runs.
+func (t *templateMockService) nextCounter(name string) int64 {
+	t.countersMu.Lock()
+	defer t.countersMu.Unlock()
+
+	t.counters[name]++
+
+	return t.counters[name]
+}
+
+func (t *templateMockService) randomString(length int) string {
+	var builder strings.Builder
+
+	for i := 0; i < length; i++ {
+		builder.WriteByte(randomStringLetters[rand.IntN(len(randomStringLetters))])
+	}
+
+	return builder.String()
+}
+
+func (t *templateMockService) new500Response(err error) *MockResponse {
+	res := rest.Response{
+		Status:  rest.Error,
+		Message: err.Error(),
+	}
+
+	data, marshalErr := json.Marshal(res)
+
+	if marshalErr != nil {
+		data = []byte(fmt.Sprintf("{%q:%q,%q:%q}", "status", res.Status, "message", res.Message))
+	}
+
+	resp := &MockResponse{
+		StatusCode:  http.StatusInternalServerError,
+		Data:        &data,
+		ContentType: gin.MIMEJSON,
+	}
+
+	resp.AddMetadata(MetadataMatched, "false")
+
+	return resp
+}
+
+func randomItem(items []string) string {
+	return items[rand.IntN(len(items))]
+}
+
+func newTemplateMockService() *templateMockService {
+	return &templateMockService{
+		counters: make(map[string]int64),
+	}
+}
@@ -0,0 +1,133 @@
+package mock
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newTemplateResponse(body string, headers map[string]string) *MockResponse {
+	data := []byte(body)
+	resp := &MockResponse{
+		StatusCode: 201,
+		Data:       &data,
+		Headers:    headers,
+		Template:   true,
+		PathParams: map[string]string{"id": "42"},
+	}
+	resp.AddMetadata(MetadataMatched, "true")
+
+	return resp
+}
+
+func TestTemplateMockService_getMockResponse(t *testing.T) {
+	request := MockRequest{
+		Host:    "example.com",
+		Method:  "POST",
+		URI:     "/users/42/orders?page=2",
+		Uuid:    "test-uuid",
+		Headers: http.Header{"X-Tenant": []string{"acme"}},
+		Body:    []byte(`{"order":{"sku":"ABC-1","quantity":3}}`),
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"request fields", `{{.Request.Method}} {{.Request.Path}} {{.Request.Uuid}}`, "POST /users/42/orders test-uuid"},
+		{"path params", `{{.Request.PathParams.id}} {{pathParam "id"}}`, "42 42"},
+		{"query", `{{.Request.Query.page}} {{query "page"}}`, "2 2"},
+		{"headers", `{{header "x-tenant"}}`, "acme"},
+		{"json body", `{{.Request.Json.order.sku}} {{jsonPath "$.order.quantity"}}`, "ABC-1 3"},
+		{"raw body", `{{.Request.Body}}`, `{"order":{"sku":"ABC-1","quantity":3}}`},
+		{"string helpers", `{{upper "abc"}} {{lower "ABC"}} {{default "none" (query "missing")}}`, "ABC abc none"},
+		{"json helper", `{{json .Request.PathParams}}`, `{"id":"42"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTemplateMockService()
+			service.setNext(&mockMockService{response: newTemplateResponse(tt.template, nil)})
+
+			response := service.getMockResponse(request)
+
+			if string(*response.Data) != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, string(*response.Data))
+			}
+		})
+	}
+
+	t.Run("renders values that change on every request", func(t *testing.T) {
+		service := newTemplateMockService()
+		service.setNext(&mockMockService{response: newTemplateResponse(`{{uuid}}|{{counter "orders"}}|{{randomInt 5 5}}|{{len (randomString 8)}}|{{fakeEmail}}|{{timestamp}}|{{now.Year}}`, nil)})
+
+		first := strings.Split(string(*service.getMockResponse(request).Data), "|")
+		second := strings.Split(string(*service.getMockResponse(request).Data), "|")
+
+		if first[0] == second[0] || len(first[0]) != 36 {
+			t.Errorf("expected distinct uuids, got %q and %q", first[0], second[0])
+		}
+
+		if first[1] != "1" || second[1] != "2" {
+			t.Errorf("expected counter to increment, got %q and %q", first[1], second[1])
+		}
+
+		if first[2] != "5" || first[3] != "8" || !strings.HasSuffix(first[4], "@example.com") {
+			t.Errorf("unexpected values: %v", first)
+		}
+	})
+
+	t.Run("renders headers and keeps the original response untouched", func(t *testing.T) {
+		original := newTemplateResponse(`{"id":"{{pathParam "id"}}"}`, map[string]string{"Location": "/users/{{pathParam \"id\"}}"})
+		service := newTemplateMockService()
+		service.setNext(&mockMockService{response: original})
+
+		response := service.getMockResponse(request)
+
+		if response.Headers["Location"] != "/users/42" {
+			t.Errorf("expected rendered Location header, got %q", response.Headers["Location"])
+		}
+
+		if response.StatusCode != 201 || response.Template {
+			t.Errorf("unexpected response: status %d, template %v", response.StatusCode, response.Template)
+		}
+
+		if string(*original.Data) != `{"id":"{{pathParam "id"}}"}` || original.Headers["Location"] != `/users/{{pathParam "id"}}` {
+			t.Error("expected the original response to keep the raw template")
+		}
+	})
+
+	t.Run("returns 500 for an invalid template", func(t *testing.T) {
+		service := newTemplateMockService()
+		service.setNext(&mockMockService{response: newTemplateResponse(`{{.Request.Method`, nil)})
+
+		response := service.getMockResponse(request)
+
+		if response.StatusCode != http.StatusInternalServerError {
+			t.Errorf("expected status 500, got %d", response.StatusCode)
+		}
+
+		if !strings.Contains(string(*response.Data), "error while parsing template") {
+			t.Errorf("unexpected body: %s", string(*response.Data))
+		}
+	})
+
+	t.Run("does not render responses that aren't templates", func(t *testing.T) {
+		data := []byte(`{{.Request.Method}}`)
+		plain := &MockResponse{StatusCode: 200, Data: &data}
+
+		service := newTemplateMockService()
+		service.setNext(&mockMockService{response: plain})
+
+		if response := service.getMockResponse(request); response != plain {
+			t.Error("expected response to be returned untouched")
+		}
+	})
+
+	t.Run("returns nil when next is nil", func(t *testing.T) {
+		if response := newTemplateMockService().getMockResponse(request); response != nil {
+			t.Error("expected nil response")
+		}
+	})
+}
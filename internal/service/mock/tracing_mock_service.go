@@ -0,0 +1,40 @@
+package mock
+
+import (
+	"github.com/Caik/go-mock-server/internal/service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// tracingMockService wraps a link of the chain in a span, so each step shows up in the trace of the request.
+// The links below it find the span in the context of the request, and add their own attributes to it.
+type tracingMockService struct {
+	name           string
+	service        mockService
+	tracingService *tracing.TracingService
+}
+
+func (t *tracingMockService) getMockResponse(mockRequest MockRequest) *MockResponse {
+	ctx, span := t.tracingService.Start(mockRequest.ctx(), "mock."+t.name)
+	defer span.End()
+
+	mockRequest.Context = ctx
+	mockResponse := t.service.getMockResponse(mockRequest)
+
+	if mockResponse != nil {
+		span.SetAttributes(attribute.Int("mock.status_code", mockResponse.StatusCode))
+	}
+
+	return mockResponse
+}
+
+func (t *tracingMockService) setNext(next mockService) {
+	t.service.setNext(next)
+}
+
+func newTracingMockService(name string, service mockService, tracingService *tracing.TracingService) *tracingMockService {
+	return &tracingMockService{
+		name:           name,
+		service:        service,
+		tracingService: tracingService,
+	}
+}
@@ -0,0 +1,123 @@
+package mock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/Caik/go-mock-server/internal/service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key string) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value, true
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+func TestTracingMockService_getMockResponse(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracingService, err := tracing.NewTracingServiceFrom(recorder, "test")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hostsConfig := config.NewHostsConfigFrom(map[string]config.HostConfig{
+		"example.com": {
+			LatencyConfig: &config.LatencyConfig{Min: intPtr(0), Max: intPtr(0)},
+			StatusesConfig: map[string]config.StatusConfig{
+				"503": {Percentage: intPtr(100)},
+			},
+		},
+	})
+
+	// latency -> status simulation -> content, each link in its own span
+	last := &mockMockService{response: &MockResponse{StatusCode: 200}}
+	status := newTracingMockService("status_simulation", newStatusSimulationMockService(hostsConfig, nil), tracingService)
+	status.setNext(last)
+	latency := newTracingMockService("latency", newLatencyMockService(hostsConfig), tracingService)
+	latency.setNext(status)
+
+	ctx, parent := tracingService.Start(context.Background(), "GET example.com")
+	latency.getMockResponse(MockRequest{Host: "example.com", Method: "GET", URI: "/api/test", Uuid: "test-uuid", Context: ctx})
+	parent.End()
+
+	spans := recorder.Ended()
+
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+
+	statusSpan, latencySpan, parentSpan := spans[0], spans[1], spans[2]
+
+	if statusSpan.Name() != "mock.status_simulation" || latencySpan.Name() != "mock.latency" {
+		t.Fatalf("unexpected span names: %s, %s", statusSpan.Name(), latencySpan.Name())
+	}
+
+	if latencySpan.Parent().SpanID() != parentSpan.SpanContext().SpanID() {
+		t.Error("expected the latency span to be a child of the request span")
+	}
+
+	if statusSpan.Parent().SpanID() != latencySpan.SpanContext().SpanID() {
+		t.Error("expected the status simulation span to be a child of the latency span")
+	}
+
+	if value, ok := spanAttribute(statusSpan, "mock.status.simulated"); !ok || value.AsInt64() != 503 {
+		t.Errorf("expected the simulated status on the status simulation span, got %v", value.Emit())
+	}
+
+	if value, ok := spanAttribute(statusSpan, "mock.status_code"); !ok || value.AsInt64() != 503 {
+		t.Errorf("expected the status code on the status simulation span, got %v", value.Emit())
+	}
+
+	if value, ok := spanAttribute(latencySpan, "mock.latency.drawn_ms"); !ok || value.AsInt64() != 0 {
+		t.Errorf("expected the drawn latency on the latency span, got %v", value.Emit())
+	}
+
+	if _, ok := spanAttribute(latencySpan, "mock.status.simulated"); ok {
+		t.Error("expected the simulated status only on the status simulation span")
+	}
+
+	if last.lastRequest.Context == nil {
+		t.Error("expected the context to reach the end of the chain")
+	}
+}
+
+func TestMockServiceFactory_tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracingService, err := tracing.NewTracingServiceFrom(recorder, "test")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	contentService := &mockContentService{contents: make(map[string][]byte)}
+	hostsConfig := config.NewHostsConfigFrom(make(map[string]config.HostConfig))
+	appArgs := &config.AppArguments{DisableLatency: true, DisableCache: true}
+
+	factory := NewMockServiceFactory(contentService, &mockCacheService{}, nil, nil, tracingService, appArgs, hostsConfig)
+	factory.GetMockResponse(MockRequest{Host: "example.com", Method: "GET", URI: "/api/test", Uuid: "test-uuid"})
+
+	names := make(map[string]bool)
+
+	for _, span := range recorder.Ended() {
+		names[span.Name()] = true
+	}
+
+	for _, name := range []string{"mock.host_resolution", "mock.cors", "mock.status_simulation", "mock.content"} {
+		if !names[name] {
+			t.Errorf("expected a %s span, got %v", name, names)
+		}
+	}
+
+	if names["mock.latency"] || names["mock.cache"] {
+		t.Errorf("expected no span for the disabled links, got %v", names)
+	}
+}
@@ -0,0 +1,171 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const upstreamTimeout = 30 * time.Second
+
+// hop-by-hop headers are meaningful only for a single connection and must not be forwarded
+var hopByHopHeaders = map[string]bool{
+	"Connection":          true,
+	"Keep-Alive":          true,
+	"Proxy-Authenticate":  true,
+	"Proxy-Authorization": true,
+	"Te":                  true,
+	"Trailer":             true,
+	"Transfer-Encoding":   true,
+	"Upgrade":             true,
+}
+
+type upstreamResponse struct {
+	url         string
+	statusCode  int
+	contentType string
+	headers     map[string]string
+	body        []byte
+	stream      io.ReadCloser
+}
+
+type upstreamClient struct {
+	httpClient     *http.Client
+	tracingService *tracing.TracingService
+}
+
+// forward sends the request to the upstream and reads the whole response body
+func (u *upstreamClient) forward(upstream string, mockRequest MockRequest) (*upstreamResponse, error) {
+	ctx, cancel := context.WithTimeout(mockRequest.ctx(), upstreamTimeout)
+	defer cancel()
+
+	resp, targetUrl, err := u.do(ctx, upstream, mockRequest)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		return nil, fmt.Errorf("error while reading upstream response: %v", err)
+	}
+
+	response := u.newUpstreamResponse(targetUrl, resp)
+	response.body = body
+
+	return response, nil
+}
+
+// stream sends the request to the upstream and hands over the response body unread, so it can be
+// relayed to the client while it is still being received. The caller is responsible for closing it.
+// The body is tied to the context of the request, so it stops being received once the client goes away.
+func (u *upstreamClient) stream(upstream string, mockRequest MockRequest) (*upstreamResponse, error) {
+	resp, targetUrl, err := u.do(mockRequest.ctx(), upstream, mockRequest)
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := u.newUpstreamResponse(targetUrl, resp)
+	response.stream = resp.Body
+
+	return response, nil
+}
+
+// do sends the request to the upstream in a client span, child of the span of the request, whose trace is passed on
+// to the upstream
+func (u *upstreamClient) do(ctx context.Context, upstream string, mockRequest MockRequest) (*http.Response, string, error) {
+	targetUrl := strings.TrimSuffix(upstream, "/") + mockRequest.URI
+	ctx, span := u.tracingService.Start(ctx, mockRequest.Method+" upstream",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", mockRequest.Method),
+			attribute.String("url.full", targetUrl),
+		),
+	)
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, mockRequest.Method, targetUrl, bytes.NewReader(mockRequest.Body))
+
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, "", fmt.Errorf("error while creating upstream request: %v", err)
+	}
+
+	for key, values := range mockRequest.Headers {
+		if hopByHopHeaders[http.CanonicalHeaderKey(key)] {
+			continue
+		}
+
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	// letting the transport negotiate compression, so the body we get back is already decoded
+	req.Header.Del("Accept-Encoding")
+
+	// replacing the trace of the client, if any, with the span of this call
+	u.tracingService.Inject(ctx, req.Header)
+
+	resp, err := u.httpClient.Do(req)
+
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, "", fmt.Errorf("error while calling upstream: %v", err)
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
+	return resp, targetUrl, nil
+}
+
+func (u *upstreamClient) newUpstreamResponse(targetUrl string, resp *http.Response) *upstreamResponse {
+	headers := make(map[string]string)
+
+	for key, values := range resp.Header {
+		if hopByHopHeaders[key] || key == "Content-Length" || key == "Content-Encoding" || key == "Content-Type" {
+			continue
+		}
+
+		headers[key] = strings.Join(values, ", ")
+	}
+
+	return &upstreamResponse{
+		url:         targetUrl,
+		statusCode:  resp.StatusCode,
+		contentType: resp.Header.Get("Content-Type"),
+		headers:     headers,
+	}
+}
+
+func newUpstreamClient(tracingService *tracing.TracingService) *upstreamClient {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = upstreamTimeout
+
+	return &upstreamClient{
+		tracingService: tracingService,
+		httpClient: &http.Client{
+			// no overall timeout, as streamed bodies may take longer than that to be relayed
+			Transport: transport,
+			// redirects are part of the upstream behaviour, so they are returned as-is
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
@@ -0,0 +1,527 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// maxSchemaDepth bounds the nesting of the bodies synthesized from schemas
+const maxSchemaDepth = 8
+
+var (
+	// ErrInvalidDocument tells that the document isn't a valid OpenAPI 3 or Swagger 2 document
+	ErrInvalidDocument = errors.New("invalid OpenAPI document")
+
+	operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+	literalSegmentRegex = regexp.MustCompile(`^[\w-]+$`)
+	paramSegmentRegex   = regexp.MustCompile(`^\{([^{}]+)\}$`)
+	serverVariableRegex = regexp.MustCompile(`\{([^{}]+)\}`)
+	nonWordRegex        = regexp.MustCompile(`\W+`)
+)
+
+// Mock is a mock generated for a response of an operation
+type Mock struct {
+	URI         string
+	Method      string
+	StatusCode  int
+	ContentType string // the media type of the body, empty when the response has none
+	Data        []byte
+}
+
+// SkippedResponse is a response of the document no mock could be generated for
+type SkippedResponse struct {
+	Path   string `json:"path"`
+	Method string `json:"method"`
+	Status string `json:"status,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Document is a parsed OpenAPI 3 or Swagger 2 document
+type Document struct {
+	root    map[string]any
+	swagger bool
+}
+
+// Parse parses an OpenAPI 3 or Swagger 2 document, either in JSON or YAML
+func Parse(data []byte) (*Document, error) {
+	trimmed := bytes.TrimSpace(data)
+
+	if !bytes.HasPrefix(trimmed, []byte("{")) {
+		converted, err := yaml.YAMLToJSON(trimmed)
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+		}
+
+		trimmed = converted
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+
+	var root map[string]any
+
+	if err := decoder.Decode(&root); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+
+	document := &Document{root: root}
+
+	switch {
+	case strings.HasPrefix(stringOf(root["openapi"]), "3."):
+	case stringOf(root["swagger"]) == "2.0":
+		document.swagger = true
+	default:
+		return nil, fmt.Errorf("%w: only OpenAPI 3 and Swagger 2 documents are supported", ErrInvalidDocument)
+	}
+
+	if _, ok := root["paths"].(map[string]any); !ok {
+		return nil, fmt.Errorf("%w: paths are missing", ErrInvalidDocument)
+	}
+
+	return document, nil
+}
+
+// Host returns the host the document is served from, if it tells it
+func (d *Document) Host() string {
+	if d.swagger {
+		host, _, _ := strings.Cut(stringOf(d.root["host"]), ":")
+		return strings.ToLower(host)
+	}
+
+	serverURL, err := d.serverURL()
+
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(serverURL.Hostname())
+}
+
+// Mocks generates a mock for every response of every operation of the document
+func (d *Document) Mocks() ([]Mock, []SkippedResponse) {
+	paths := d.root["paths"].(map[string]any)
+	basePath := d.basePath()
+	mocks := make([]Mock, 0)
+	skipped := make([]SkippedResponse, 0)
+
+	for _, path := range sortedKeys(paths) {
+		pathItem, _ := d.resolve(paths[path]).(map[string]any)
+		uri, err := toMockUri(basePath + path)
+
+		for _, method := range operationMethods {
+			operation, ok := pathItem[method].(map[string]any)
+
+			if !ok {
+				continue
+			}
+
+			method = strings.ToUpper(method)
+
+			if err != nil {
+				skipped = append(skipped, SkippedResponse{Path: path, Method: method, Reason: err.Error()})
+				continue
+			}
+
+			responses, _ := operation["responses"].(map[string]any)
+
+			if len(responses) == 0 {
+				skipped = append(skipped, SkippedResponse{Path: path, Method: method, Reason: "no response declared"})
+				continue
+			}
+
+			for _, status := range sortedKeys(responses) {
+				statusCode, err := toStatusCode(status)
+
+				if err != nil {
+					skipped = append(skipped, SkippedResponse{Path: path, Method: method, Status: status, Reason: err.Error()})
+					continue
+				}
+
+				contentType, data, err := d.responseBody(d.resolve(responses[status]))
+
+				if err != nil {
+					skipped = append(skipped, SkippedResponse{Path: path, Method: method, Status: status, Reason: err.Error()})
+					continue
+				}
+
+				mocks = append(mocks, Mock{URI: uri, Method: method, StatusCode: statusCode, ContentType: contentType, Data: data})
+			}
+		}
+	}
+
+	return mocks, skipped
+}
+
+// responseBody returns the media type and the body of a response, taken from its examples or synthesized from its
+// schema. The media type is empty when the response has no body.
+func (d *Document) responseBody(response any) (string, []byte, error) {
+	responseObject, ok := response.(map[string]any)
+
+	if !ok {
+		return "", nil, errors.New("invalid response")
+	}
+
+	if d.swagger {
+		if examples, ok := responseObject["examples"].(map[string]any); ok && len(examples) > 0 {
+			mediaType := preferredMediaType(examples)
+			return encodeBody(mediaType, examples[mediaType])
+		}
+
+		if schema, ok := responseObject["schema"]; ok {
+			return encodeBody("application/json", d.exampleFromSchema(schema, 0, nil))
+		}
+
+		return "", []byte{}, nil
+	}
+
+	content, ok := responseObject["content"].(map[string]any)
+
+	if !ok || len(content) == 0 {
+		return "", []byte{}, nil
+	}
+
+	mediaType := preferredMediaType(content)
+	mediaTypeObject, _ := content[mediaType].(map[string]any)
+
+	if example, ok := mediaTypeObject["example"]; ok {
+		return encodeBody(mediaType, example)
+	}
+
+	if examples, ok := mediaTypeObject["examples"].(map[string]any); ok {
+		for _, name := range sortedKeys(examples) {
+			exampleObject, _ := d.resolve(examples[name]).(map[string]any)
+
+			if value, ok := exampleObject["value"]; ok {
+				return encodeBody(mediaType, value)
+			}
+		}
+	}
+
+	if schema, ok := mediaTypeObject["schema"]; ok {
+		return encodeBody(mediaType, d.exampleFromSchema(schema, 0, nil))
+	}
+
+	return "", []byte{}, nil
+}
+
+// exampleFromSchema synthesizes a value matching the schema, favouring the examples and defaults it declares
+func (d *Document) exampleFromSchema(schema any, depth int, refs []string) any {
+	if depth > maxSchemaDepth {
+		return nil
+	}
+
+	schemaObject, ok := schema.(map[string]any)
+
+	if !ok {
+		return nil
+	}
+
+	// following references, stopping on the ones already being followed, as schemas can be recursive
+	if ref, ok := schemaObject["$ref"].(string); ok {
+		for _, seen := range refs {
+			if seen == ref {
+				return nil
+			}
+		}
+
+		return d.exampleFromSchema(d.resolve(schemaObject), depth, append(refs[:len(refs):len(refs)], ref))
+	}
+
+	for _, key := range []string{"example", "default", "const"} {
+		if value, ok := schemaObject[key]; ok {
+			return value
+		}
+	}
+
+	if examples, ok := schemaObject["examples"].([]any); ok && len(examples) > 0 {
+		return examples[0]
+	}
+
+	if enum, ok := schemaObject["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	if allOf, ok := schemaObject["allOf"].([]any); ok && len(allOf) > 0 {
+		merged := make(map[string]any)
+
+		for _, part := range allOf {
+			if value, ok := d.exampleFromSchema(part, depth+1, refs).(map[string]any); ok {
+				for key, item := range value {
+					merged[key] = item
+				}
+			}
+		}
+
+		return merged
+	}
+
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if options, ok := schemaObject[key].([]any); ok && len(options) > 0 {
+			return d.exampleFromSchema(options[0], depth+1, refs)
+		}
+	}
+
+	switch schemaType(schemaObject) {
+	case "object":
+		value := make(map[string]any)
+		properties, _ := schemaObject["properties"].(map[string]any)
+
+		for name, property := range properties {
+			value[name] = d.exampleFromSchema(property, depth+1, refs)
+		}
+
+		return value
+	case "array":
+		items, ok := schemaObject["items"]
+
+		if !ok {
+			return []any{}
+		}
+
+		return []any{d.exampleFromSchema(items, depth+1, refs)}
+	case "string":
+		return exampleString(stringOf(schemaObject["format"]))
+	case "integer", "number":
+		if minimum, ok := schemaObject["minimum"]; ok {
+			return minimum
+		}
+
+		return 0
+	case "boolean":
+		return true
+	default:
+		return nil
+	}
+}
+
+// resolve follows the local reference of the node, if any
+func (d *Document) resolve(node any) any {
+	for i := 0; i < maxSchemaDepth; i++ {
+		object, ok := node.(map[string]any)
+
+		if !ok {
+			return node
+		}
+
+		ref, ok := object["$ref"].(string)
+
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return node
+		}
+
+		node = d.lookup(ref)
+	}
+
+	return node
+}
+
+// lookup returns the node of the document at the JSON pointer of a local reference, e.g. #/components/schemas/User
+func (d *Document) lookup(ref string) any {
+	var node any = d.root
+
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		object, ok := node.(map[string]any)
+
+		if !ok {
+			return nil
+		}
+
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		node = object[token]
+	}
+
+	return node
+}
+
+func (d *Document) basePath() string {
+	if d.swagger {
+		return strings.TrimSuffix(stringOf(d.root["basePath"]), "/")
+	}
+
+	serverURL, err := d.serverURL()
+
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSuffix(serverURL.Path, "/")
+}
+
+// serverURL returns the url of the first server, with its variables set to their default value
+func (d *Document) serverURL() (*url.URL, error) {
+	servers, _ := d.root["servers"].([]any)
+
+	if len(servers) == 0 {
+		return nil, errors.New("no server declared")
+	}
+
+	server, _ := servers[0].(map[string]any)
+	variables, _ := server["variables"].(map[string]any)
+
+	rawURL := serverVariableRegex.ReplaceAllStringFunc(stringOf(server["url"]), func(match string) string {
+		variable, _ := variables[strings.Trim(match, "{}")].(map[string]any)
+		return stringOf(variable["default"])
+	})
+
+	return url.Parse(rawURL)
+}
+
+// toMockUri converts an OpenAPI path to the uri of a mock file. Path templates become path parameters, whose name is
+// made a valid identifier if needed (e.g. /users/{user-id} becomes /users/{user_id}).
+func toMockUri(path string) (string, error) {
+	if path == "" || path == "/" {
+		return "/", nil
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i, segment := range segments {
+		if literalSegmentRegex.MatchString(segment) {
+			continue
+		}
+
+		match := paramSegmentRegex.FindStringSubmatch(segment)
+
+		if match == nil {
+			return "", fmt.Errorf("unsupported path segment %q", segment)
+		}
+
+		name := nonWordRegex.ReplaceAllString(match[1], "_")
+
+		if name[0] >= '0' && name[0] <= '9' {
+			name = "_" + name
+		}
+
+		segments[i] = "{" + name + "}"
+	}
+
+	return "/" + strings.Join(segments, "/"), nil
+}
+
+// toStatusCode converts the status of a response to a status code, a range such as 2XX becoming its first code
+func toStatusCode(status string) (int, error) {
+	if strings.EqualFold(status, "default") {
+		return 0, errors.New("default responses have no status code")
+	}
+
+	if len(status) == 3 && strings.EqualFold(status[1:], "XX") {
+		status = status[:1] + "00"
+	}
+
+	statusCode, err := strconv.Atoi(status)
+
+	if err != nil || statusCode < 100 || statusCode > 599 {
+		return 0, fmt.Errorf("invalid status %q", status)
+	}
+
+	return statusCode, nil
+}
+
+// preferredMediaType returns the JSON media type of the content, or its first one otherwise
+func preferredMediaType(content map[string]any) string {
+	mediaTypes := sortedKeys(content)
+
+	for _, mediaType := range mediaTypes {
+		if isJSONMediaType(mediaType) {
+			return mediaType
+		}
+	}
+
+	return mediaTypes[0]
+}
+
+func isJSONMediaType(mediaType string) bool {
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// encodeBody encodes the value as a body of the given media type, which it returns along with the body
+func encodeBody(mediaType string, value any) (string, []byte, error) {
+	if text, ok := value.(string); ok && !isJSONMediaType(mediaType) {
+		return mediaType, []byte(text), nil
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+
+	return mediaType, data, err
+}
+
+func schemaType(schema map[string]any) string {
+	switch value := schema["type"].(type) {
+	case string:
+		return value
+	case []any:
+		// OpenAPI 3.1 allows several types, e.g. ["string", "null"]
+		for _, item := range value {
+			if itemType := stringOf(item); itemType != "null" {
+				return itemType
+			}
+		}
+	}
+
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+
+	return ""
+}
+
+func exampleString(format string) string {
+	switch format {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "time":
+		return "00:00:00"
+	case "uuid":
+		return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	case "hostname":
+		return "example.com"
+	case "ipv4":
+		return "192.0.2.1"
+	case "ipv6":
+		return "2001:db8::1"
+	case "byte":
+		return "c3RyaW5n"
+	default:
+		return "string"
+	}
+}
+
+func stringOf(value any) string {
+	text, _ := value.(string)
+	return text
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,304 @@
+package openapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const petstoreYAML = `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: "1"
+servers:
+  - url: https://{region}.pets.com/v1
+    variables:
+      region:
+        default: API
+paths:
+  /pets:
+    get:
+      responses:
+        200:
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+        default:
+          $ref: '#/components/responses/Error'
+    post:
+      responses:
+        "201":
+          content:
+            application/json:
+              example:
+                id: 7
+                name: rex
+  /pets/{pet-id}:
+    get:
+      responses:
+        2XX:
+          content:
+            application/json:
+              examples:
+                cat:
+                  value:
+                    name: tom
+                dog:
+                  value:
+                    name: rex
+        "404":
+          description: not found
+  /pets/{petId}.json:
+    get:
+      responses:
+        "200":
+          description: ok
+components:
+  responses:
+    Error:
+      description: error
+  schemas:
+    Pet:
+      type: object
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+        tag:
+          type: string
+          enum: [dog, cat]
+        born:
+          type: string
+          format: date
+        parent:
+          $ref: '#/components/schemas/Pet'
+`
+
+const petstoreSwagger = `{
+  "swagger": "2.0",
+  "host": "legacy.pets.com:8443",
+  "basePath": "/api",
+  "paths": {
+    "/pets/{id}": {
+      "get": {
+        "produces": ["text/plain"],
+        "responses": {
+          "200": {"examples": {"text/plain": "rex"}},
+          "500": {"schema": {"$ref": "#/definitions/Error"}}
+        }
+      }
+    }
+  },
+  "definitions": {
+    "Error": {
+      "allOf": [
+        {"properties": {"code": {"type": "integer", "default": 500}}},
+        {"properties": {"message": {"type": "string", "example": "boom"}}}
+      ]
+    }
+  }
+}`
+
+func findMock(mocks []Mock, uri, method string, statusCode int) *Mock {
+	for i := range mocks {
+		if mocks[i].URI == uri && mocks[i].Method == method && mocks[i].StatusCode == statusCode {
+			return &mocks[i]
+		}
+	}
+
+	return nil
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		wantErr  bool
+	}{
+		{name: "parses OpenAPI 3 in YAML", document: petstoreYAML},
+		{name: "parses Swagger 2 in JSON", document: petstoreSwagger},
+		{name: "rejects invalid documents", document: "{not json", wantErr: true},
+		{name: "rejects other versions", document: `{"swagger": "1.2", "paths": {}}`, wantErr: true},
+		{name: "rejects documents without paths", document: `{"openapi": "3.1.0"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.document))
+
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			if err != nil && !errors.Is(err, ErrInvalidDocument) {
+				t.Errorf("expected ErrInvalidDocument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDocument_Host(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{name: "uses the server url and its variables", document: petstoreYAML, want: "api.pets.com"},
+		{name: "uses the swagger host", document: petstoreSwagger, want: "legacy.pets.com"},
+		{name: "is empty without servers", document: `{"openapi": "3.0.0", "paths": {}}`, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := Parse([]byte(tt.document))
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if host := document.Host(); host != tt.want {
+				t.Errorf("expected host %q, got %q", tt.want, host)
+			}
+		})
+	}
+}
+
+func TestDocument_Mocks(t *testing.T) {
+	t.Run("generates the mocks of an OpenAPI 3 document", func(t *testing.T) {
+		document, _ := Parse([]byte(petstoreYAML))
+		mocks, skipped := document.Mocks()
+
+		if len(mocks) != 4 {
+			t.Fatalf("expected 4 mocks, got %d: %+v", len(mocks), mocks)
+		}
+
+		list := findMock(mocks, "/v1/pets", "GET", 200)
+
+		if list == nil {
+			t.Fatal("expected the pets list mock")
+		}
+
+		wantList := `[
+  {
+    "born": "2024-01-01",
+    "id": 0,
+    "name": "string",
+    "parent": null,
+    "tag": "dog"
+  }
+]`
+
+		if string(list.Data) != wantList {
+			t.Errorf("unexpected body synthesized from the schema:\n%s", list.Data)
+		}
+
+		if list.ContentType != "application/json" {
+			t.Errorf("expected the media type of the response, got %q", list.ContentType)
+		}
+
+		if created := findMock(mocks, "/v1/pets", "POST", 201); created == nil || !strings.Contains(string(created.Data), `"id": 7`) {
+			t.Errorf("expected the example to be used, got %+v", created)
+		}
+
+		if found := findMock(mocks, "/v1/pets/{pet_id}", "GET", 200); found == nil || !strings.Contains(string(found.Data), `"tom"`) {
+			t.Errorf("expected the first named example on the 2XX mock, got %+v", found)
+		}
+
+		if notFound := findMock(mocks, "/v1/pets/{pet_id}", "GET", 404); notFound == nil || len(notFound.Data) != 0 || len(notFound.ContentType) != 0 {
+			t.Errorf("expected an empty 404 mock, got %+v", notFound)
+		}
+
+		if len(skipped) != 2 {
+			t.Fatalf("expected 2 skipped responses, got %+v", skipped)
+		}
+
+		for _, response := range skipped {
+			if response.Path != "/pets" && response.Path != "/pets/{petId}.json" {
+				t.Errorf("unexpected skipped response %+v", response)
+			}
+		}
+	})
+
+	t.Run("generates the mocks of a Swagger 2 document", func(t *testing.T) {
+		document, _ := Parse([]byte(petstoreSwagger))
+		mocks, skipped := document.Mocks()
+
+		if len(mocks) != 2 || len(skipped) != 0 {
+			t.Fatalf("expected 2 mocks and no skipped response, got %+v, %+v", mocks, skipped)
+		}
+
+		if found := findMock(mocks, "/api/pets/{id}", "GET", 200); found == nil || string(found.Data) != "rex" || found.ContentType != "text/plain" {
+			t.Errorf("expected the plain text example, got %+v", found)
+		}
+
+		failed := findMock(mocks, "/api/pets/{id}", "GET", 500)
+
+		if failed == nil || string(failed.Data) != "{\n  \"code\": 500,\n  \"message\": \"boom\"\n}" {
+			t.Errorf("expected the allOf schemas to be merged, got %+v", failed)
+		}
+	})
+}
+
+func TestToMockUri(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{path: "", want: "/"},
+		{path: "/users", want: "/users"},
+		{path: "/users/{id}/orders/", want: "/users/{id}/orders"},
+		{path: "/users/{user-id}", want: "/users/{user_id}"},
+		{path: "/users/{1st}", want: "/users/{_1st}"},
+		{path: "/users/{id}.json", wantErr: true},
+		{path: "/users/me;v=1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			uri, err := toMockUri(tt.path)
+
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			if uri != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, uri)
+			}
+		})
+	}
+}
+
+func TestToStatusCode(t *testing.T) {
+	tests := []struct {
+		status  string
+		want    int
+		wantErr bool
+	}{
+		{status: "200", want: 200},
+		{status: "4XX", want: 400},
+		{status: "5xx", want: 500},
+		{status: "default", wantErr: true},
+		{status: "600", wantErr: true},
+		{status: "ok", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			statusCode, err := toStatusCode(tt.status)
+
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			if statusCode != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, statusCode)
+			}
+		})
+	}
+}
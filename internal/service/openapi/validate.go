@@ -0,0 +1,876 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxValidationDepth bounds the nesting of the schemas followed while validating, as references can loop
+const maxValidationDepth = 64
+
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Request is a request to be checked against the document
+type Request struct {
+	Method  string
+	Path    string
+	Query   url.Values
+	Headers http.Header
+	Body    []byte
+}
+
+// Response is a response to be checked against the document
+type Response struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// operation is the operation of the document a request is sent to
+type operation struct {
+	path       string
+	node       map[string]any
+	parameters []map[string]any
+	pathParams map[string]string
+}
+
+// direction tells whether a schema is checked for a request or a response, as readOnly properties are only
+// expected in responses and writeOnly ones in requests
+type direction int
+
+const (
+	directionRequest direction = iota
+	directionResponse
+)
+
+// ValidateRequest checks the path, parameters and body of a request against the operation it is sent to, returning
+// the violations found
+func (d *Document) ValidateRequest(request Request) []string {
+	operation, violation := d.findOperation(request.Method, request.Path)
+
+	if operation == nil {
+		return []string{violation}
+	}
+
+	violations := make([]string, 0)
+
+	for _, parameter := range operation.parameters {
+		violations = append(violations, d.validateParameter(parameter, operation, request)...)
+	}
+
+	return append(violations, d.validateRequestBody(operation, request)...)
+}
+
+// ValidateResponse checks a response against the ones declared by the operation the request was sent to, returning
+// the violations found. Nothing is checked for the requests not matching any operation.
+func (d *Document) ValidateResponse(request Request, response Response) []string {
+	operation, _ := d.findOperation(request.Method, request.Path)
+
+	if operation == nil {
+		return nil
+	}
+
+	responses, _ := operation.node["responses"].(map[string]any)
+	declared, ok := d.declaredResponse(responses, response.StatusCode)
+
+	if !ok {
+		return []string{fmt.Sprintf("status %d is not declared", response.StatusCode)}
+	}
+
+	if d.swagger {
+		schema, ok := declared["schema"]
+
+		if !ok {
+			return nil
+		}
+
+		return d.validateBody("response body", swaggerMediaType(response.ContentType), response.Body, schema, true, directionResponse)
+	}
+
+	content, _ := declared["content"].(map[string]any)
+
+	if len(content) == 0 {
+		return nil
+	}
+
+	mediaType, mediaTypeObject, ok := matchMediaType(content, response.ContentType)
+
+	if !ok {
+		return []string{fmt.Sprintf("response content type %q is not declared", response.ContentType)}
+	}
+
+	return d.validateBody("response body", mediaType, response.Body, mediaTypeObject["schema"], true, directionResponse)
+}
+
+// findOperation returns the operation matching the method and path, or a violation telling why none does. The most
+// concrete paths are preferred, e.g. /users/me over /users/{id}.
+func (d *Document) findOperation(method, requestPath string) (*operation, string) {
+	paths, _ := d.root["paths"].(map[string]any)
+	basePath := d.basePath()
+
+	if relative, ok := strings.CutPrefix(requestPath, basePath); ok && len(basePath) > 0 &&
+		(len(relative) == 0 || relative[0] == '/') {
+		requestPath = relative
+	}
+
+	if len(requestPath) == 0 {
+		requestPath = "/"
+	}
+
+	var found *operation
+	pathMatched := false
+
+	for _, path := range sortedKeys(paths) {
+		pathParams, ok := matchPath(path, requestPath)
+
+		if !ok {
+			continue
+		}
+
+		pathMatched = true
+		pathItem, _ := d.resolve(paths[path]).(map[string]any)
+		node, ok := pathItem[strings.ToLower(method)].(map[string]any)
+
+		if !ok || (found != nil && !moreConcrete(path, pathParams, found)) {
+			continue
+		}
+
+		found = &operation{
+			path:       path,
+			node:       node,
+			parameters: d.mergeParameters(pathItem["parameters"], node["parameters"]),
+			pathParams: pathParams,
+		}
+	}
+
+	if found != nil {
+		return found, ""
+	}
+
+	if pathMatched {
+		return nil, fmt.Sprintf("method %s is not declared for path %s", method, requestPath)
+	}
+
+	return nil, fmt.Sprintf("path %s is not declared", requestPath)
+}
+
+// moreConcrete tells whether a path is more concrete than the one of the operation found, i.e. it has fewer
+// parameters, or more literal characters, e.g. /users/{id}.json over /users/{id}
+func moreConcrete(path string, pathParams map[string]string, found *operation) bool {
+	if len(pathParams) != len(found.pathParams) {
+		return len(pathParams) < len(found.pathParams)
+	}
+
+	literalLength := func(template string) int {
+		return len(serverVariableRegex.ReplaceAllString(template, ""))
+	}
+
+	return literalLength(path) > literalLength(found.path)
+}
+
+// mergeParameters returns the parameters of the path item, overridden by the ones of the operation
+func (d *Document) mergeParameters(pathParameters, operationParameters any) []map[string]any {
+	merged := make([]map[string]any, 0)
+	indexes := make(map[string]int)
+
+	for _, list := range []any{pathParameters, operationParameters} {
+		items, _ := list.([]any)
+
+		for _, item := range items {
+			parameter, ok := d.resolve(item).(map[string]any)
+
+			if !ok {
+				continue
+			}
+
+			key := stringOf(parameter["in"]) + ":" + stringOf(parameter["name"])
+
+			if index, exists := indexes[key]; exists {
+				merged[index] = parameter
+				continue
+			}
+
+			indexes[key] = len(merged)
+			merged = append(merged, parameter)
+		}
+	}
+
+	return merged
+}
+
+func (d *Document) validateParameter(parameter map[string]any, operation *operation, request Request) []string {
+	name := stringOf(parameter["name"])
+	in := stringOf(parameter["in"])
+	required, _ := parameter["required"].(bool)
+	location := fmt.Sprintf("%s parameter %q", in, name)
+
+	var values []string
+
+	switch in {
+	case "path":
+		if value, ok := operation.pathParams[name]; ok {
+			values = []string{value}
+		}
+
+		required = true
+	case "query":
+		values = request.Query[name]
+	case "header":
+		values = request.Headers.Values(name)
+	case "cookie":
+		if cookie, err := (&http.Request{Header: request.Headers}).Cookie(name); err == nil {
+			values = []string{cookie.Value}
+		}
+	default:
+		// Swagger 2 bodies and forms are checked with the request body
+		return nil
+	}
+
+	if len(values) == 0 {
+		if required {
+			return []string{location + ": is required"}
+		}
+
+		return nil
+	}
+
+	// Swagger 2 parameters are schemas themselves
+	schema := parameter["schema"]
+
+	if d.swagger {
+		schema = parameter
+	}
+
+	value, err := d.parseParameter(values, schema)
+
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", location, err)}
+	}
+
+	return d.validateSchema(value, schema, location, directionRequest, 0)
+}
+
+// parseParameter converts the raw values of a parameter to the type of its schema
+func (d *Document) parseParameter(values []string, schema any) (any, error) {
+	schemaObject, _ := d.resolve(schema).(map[string]any)
+
+	if schemaType(schemaObject) != "array" {
+		return parseScalar(values[0], schemaType(schemaObject))
+	}
+
+	// arrays are sent either as repeated parameters, or as comma separated values
+	if len(values) == 1 {
+		values = strings.Split(values[0], ",")
+	}
+
+	itemsSchema, _ := d.resolve(schemaObject["items"]).(map[string]any)
+	items := make([]any, 0, len(values))
+
+	for _, value := range values {
+		item, err := parseScalar(value, schemaType(itemsSchema))
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func parseScalar(value, valueType string) (any, error) {
+	switch valueType {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return nil, fmt.Errorf("expected %s, got %q", valueType, value)
+		}
+
+		return json.Number(value), nil
+	case "boolean":
+		parsed, err := strconv.ParseBool(value)
+
+		if err != nil {
+			return nil, fmt.Errorf("expected boolean, got %q", value)
+		}
+
+		return parsed, nil
+	default:
+		return value, nil
+	}
+}
+
+func (d *Document) validateRequestBody(operation *operation, request Request) []string {
+	contentType := request.Headers.Get("Content-Type")
+
+	if d.swagger {
+		for _, parameter := range operation.parameters {
+			if stringOf(parameter["in"]) == "body" {
+				required, _ := parameter["required"].(bool)
+				return d.validateBody("request body", swaggerMediaType(contentType), request.Body, parameter["schema"], required, directionRequest)
+			}
+		}
+
+		return nil
+	}
+
+	requestBody, ok := d.resolve(operation.node["requestBody"]).(map[string]any)
+
+	if !ok {
+		return nil
+	}
+
+	required, _ := requestBody["required"].(bool)
+
+	if len(request.Body) == 0 {
+		if required {
+			return []string{"request body: is required"}
+		}
+
+		return nil
+	}
+
+	content, _ := requestBody["content"].(map[string]any)
+
+	if len(content) == 0 {
+		return nil
+	}
+
+	mediaType, mediaTypeObject, ok := matchMediaType(content, contentType)
+
+	if !ok {
+		return []string{fmt.Sprintf("request content type %q is not declared", contentType)}
+	}
+
+	return d.validateBody("request body", mediaType, request.Body, mediaTypeObject["schema"], required, directionRequest)
+}
+
+// validateBody checks a JSON body against its schema. The bodies of other media types are not checked.
+func (d *Document) validateBody(location, mediaType string, body []byte, schema any, required bool, direction direction) []string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		if required && schema != nil {
+			return []string{location + ": is required"}
+		}
+
+		return nil
+	}
+
+	if schema == nil || !isJSONMediaType(mediaType) {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+
+	if err := decoder.Decode(&value); err != nil {
+		return []string{fmt.Sprintf("%s: invalid JSON: %v", location, err)}
+	}
+
+	return d.validateSchema(value, schema, location, direction, 0)
+}
+
+// declaredResponse returns the response declared for the status code, falling back to its range (e.g. 2XX) and to the
+// default response
+func (d *Document) declaredResponse(responses map[string]any, statusCode int) (map[string]any, bool) {
+	status := strconv.Itoa(statusCode)
+
+	for key, response := range responses {
+		if strings.EqualFold(key, status) {
+			declared, _ := d.resolve(response).(map[string]any)
+			return declared, true
+		}
+	}
+
+	for key, response := range responses {
+		if len(key) == 3 && key[0] == status[0] && strings.EqualFold(key[1:], "XX") {
+			declared, _ := d.resolve(response).(map[string]any)
+			return declared, true
+		}
+	}
+
+	for key, response := range responses {
+		if strings.EqualFold(key, "default") {
+			declared, _ := d.resolve(response).(map[string]any)
+			return declared, true
+		}
+	}
+
+	return nil, false
+}
+
+// validateSchema checks a value against a schema, returning the violations found
+func (d *Document) validateSchema(value any, schema any, location string, direction direction, depth int) []string {
+	if depth > maxValidationDepth {
+		return nil
+	}
+
+	schemaObject, ok := d.resolve(schema).(map[string]any)
+
+	if !ok || len(schemaObject) == 0 {
+		return nil
+	}
+
+	if value == nil && isNullable(schemaObject) {
+		return nil
+	}
+
+	violations := make([]string, 0)
+
+	if allOf, ok := schemaObject["allOf"].([]any); ok {
+		for _, part := range allOf {
+			violations = append(violations, d.validateSchema(value, part, location, direction, depth+1)...)
+		}
+	}
+
+	if anyOf, ok := schemaObject["anyOf"].([]any); ok && len(anyOf) > 0 && d.countMatches(value, anyOf, direction, depth) == 0 {
+		violations = append(violations, location+": doesn't match any of the anyOf schemas")
+	}
+
+	if oneOf, ok := schemaObject["oneOf"].([]any); ok && len(oneOf) > 0 {
+		if matches := d.countMatches(value, oneOf, direction, depth); matches != 1 {
+			violations = append(violations, fmt.Sprintf("%s: should match exactly one of the oneOf schemas, matches %d", location, matches))
+		}
+	}
+
+	if enum, ok := schemaObject["enum"].([]any); ok && !containsValue(enum, value) {
+		violations = append(violations, fmt.Sprintf("%s: %s is not one of the allowed values", location, describeValue(value)))
+	}
+
+	if constant, ok := schemaObject["const"]; ok && !equalValues(constant, value) {
+		violations = append(violations, fmt.Sprintf("%s: expected %s, got %s", location, describeValue(constant), describeValue(value)))
+	}
+
+	expectedType := schemaType(schemaObject)
+
+	if len(expectedType) > 0 && !matchesType(value, expectedType) {
+		return append(violations, fmt.Sprintf("%s: expected %s, got %s", location, expectedType, typeOf(value)))
+	}
+
+	switch typedValue := value.(type) {
+	case string:
+		violations = append(violations, validateString(typedValue, schemaObject, location)...)
+	case json.Number:
+		violations = append(violations, validateNumber(typedValue, schemaObject, location)...)
+	case []any:
+		violations = append(violations, d.validateArray(typedValue, schemaObject, location, direction, depth)...)
+	case map[string]any:
+		violations = append(violations, d.validateObject(typedValue, schemaObject, location, direction, depth)...)
+	}
+
+	return violations
+}
+
+func (d *Document) countMatches(value any, schemas []any, direction direction, depth int) int {
+	matches := 0
+
+	for _, schema := range schemas {
+		if len(d.validateSchema(value, schema, "", direction, depth+1)) == 0 {
+			matches++
+		}
+	}
+
+	return matches
+}
+
+func (d *Document) validateArray(value []any, schema map[string]any, location string, direction direction, depth int) []string {
+	violations := make([]string, 0)
+
+	if minItems, ok := numberOf(schema["minItems"]); ok && float64(len(value)) < minItems {
+		violations = append(violations, fmt.Sprintf("%s: expected at least %v items, got %d", location, minItems, len(value)))
+	}
+
+	if maxItems, ok := numberOf(schema["maxItems"]); ok && float64(len(value)) > maxItems {
+		violations = append(violations, fmt.Sprintf("%s: expected at most %v items, got %d", location, maxItems, len(value)))
+	}
+
+	if uniqueItems, _ := schema["uniqueItems"].(bool); uniqueItems {
+		for i := range value {
+			for j := i + 1; j < len(value); j++ {
+				if equalValues(value[i], value[j]) {
+					violations = append(violations, fmt.Sprintf("%s: items %d and %d are equal", location, i, j))
+				}
+			}
+		}
+	}
+
+	if items, ok := schema["items"]; ok {
+		for i, item := range value {
+			violations = append(violations, d.validateSchema(item, items, fmt.Sprintf("%s[%d]", location, i), direction, depth+1)...)
+		}
+	}
+
+	return violations
+}
+
+func (d *Document) validateObject(value map[string]any, schema map[string]any, location string, direction direction, depth int) []string {
+	violations := make([]string, 0)
+	properties, _ := schema["properties"].(map[string]any)
+
+	if required, ok := schema["required"].([]any); ok {
+		for _, item := range required {
+			name := stringOf(item)
+
+			if _, exists := value[name]; exists || !d.expectedIn(properties[name], direction) {
+				continue
+			}
+
+			violations = append(violations, fmt.Sprintf("%s.%s: is required", location, name))
+		}
+	}
+
+	if minProperties, ok := numberOf(schema["minProperties"]); ok && float64(len(value)) < minProperties {
+		violations = append(violations, fmt.Sprintf("%s: expected at least %v properties, got %d", location, minProperties, len(value)))
+	}
+
+	if maxProperties, ok := numberOf(schema["maxProperties"]); ok && float64(len(value)) > maxProperties {
+		violations = append(violations, fmt.Sprintf("%s: expected at most %v properties, got %d", location, maxProperties, len(value)))
+	}
+
+	additionalProperties, hasAdditionalProperties := schema["additionalProperties"]
+
+	for _, name := range sortedKeys(value) {
+		propertyLocation := location + "." + name
+
+		if property, ok := properties[name]; ok {
+			violations = append(violations, d.validateSchema(value[name], property, propertyLocation, direction, depth+1)...)
+			continue
+		}
+
+		if !hasAdditionalProperties {
+			continue
+		}
+
+		if allowed, ok := additionalProperties.(bool); ok {
+			if !allowed {
+				violations = append(violations, propertyLocation+": is not allowed")
+			}
+
+			continue
+		}
+
+		violations = append(violations, d.validateSchema(value[name], additionalProperties, propertyLocation, direction, depth+1)...)
+	}
+
+	return violations
+}
+
+// expectedIn tells whether a property is expected in the given direction, readOnly properties being left out of
+// requests and writeOnly ones out of responses
+func (d *Document) expectedIn(property any, direction direction) bool {
+	propertyObject, _ := d.resolve(property).(map[string]any)
+
+	if direction == directionRequest {
+		readOnly, _ := propertyObject["readOnly"].(bool)
+		return !readOnly
+	}
+
+	writeOnly, _ := propertyObject["writeOnly"].(bool)
+
+	return !writeOnly
+}
+
+func validateString(value string, schema map[string]any, location string) []string {
+	violations := make([]string, 0)
+	length := float64(len([]rune(value)))
+
+	if minLength, ok := numberOf(schema["minLength"]); ok && length < minLength {
+		violations = append(violations, fmt.Sprintf("%s: expected at least %v characters, got %v", location, minLength, length))
+	}
+
+	if maxLength, ok := numberOf(schema["maxLength"]); ok && length > maxLength {
+		violations = append(violations, fmt.Sprintf("%s: expected at most %v characters, got %v", location, maxLength, length))
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		// invalid patterns are ignored, as they aren't the fault of the value
+		if patternRegex, err := regexp.Compile(pattern); err == nil && !patternRegex.MatchString(value) {
+			violations = append(violations, fmt.Sprintf("%s: %q doesn't match the pattern %s", location, value, pattern))
+		}
+	}
+
+	if format := stringOf(schema["format"]); !matchesFormat(value, format) {
+		violations = append(violations, fmt.Sprintf("%s: %q is not a valid %s", location, value, format))
+	}
+
+	return violations
+}
+
+func validateNumber(value json.Number, schema map[string]any, location string) []string {
+	violations := make([]string, 0)
+	number, err := value.Float64()
+
+	if err != nil {
+		return append(violations, fmt.Sprintf("%s: invalid number %s", location, value))
+	}
+
+	// OpenAPI 3.0 declares the exclusive bounds as booleans, while OpenAPI 3.1 declares them as numbers
+	exclusiveMinimum, _ := schema["exclusiveMinimum"].(bool)
+	exclusiveMaximum, _ := schema["exclusiveMaximum"].(bool)
+
+	if minimum, ok := numberOf(schema["minimum"]); ok && (number < minimum || (exclusiveMinimum && number == minimum)) {
+		violations = append(violations, fmt.Sprintf("%s: %s is lesser than the minimum %v", location, value, minimum))
+	}
+
+	if maximum, ok := numberOf(schema["maximum"]); ok && (number > maximum || (exclusiveMaximum && number == maximum)) {
+		violations = append(violations, fmt.Sprintf("%s: %s is greater than the maximum %v", location, value, maximum))
+	}
+
+	if minimum, ok := numberOf(schema["exclusiveMinimum"]); ok && number <= minimum {
+		violations = append(violations, fmt.Sprintf("%s: %s should be greater than %v", location, value, minimum))
+	}
+
+	if maximum, ok := numberOf(schema["exclusiveMaximum"]); ok && number >= maximum {
+		violations = append(violations, fmt.Sprintf("%s: %s should be lesser than %v", location, value, maximum))
+	}
+
+	if multipleOf, ok := numberOf(schema["multipleOf"]); ok && multipleOf > 0 {
+		if quotient := number / multipleOf; math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			violations = append(violations, fmt.Sprintf("%s: %s is not a multiple of %v", location, value, multipleOf))
+		}
+	}
+
+	return violations
+}
+
+// matchMediaType returns the declared media type matching the content type, wildcards included (e.g. application/*)
+func matchMediaType(content map[string]any, contentType string) (string, map[string]any, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+
+	if err != nil {
+		mediaType = strings.TrimSpace(strings.ToLower(contentType))
+	}
+
+	mainType, _, _ := strings.Cut(mediaType, "/")
+
+	for _, candidate := range []string{mediaType, mainType + "/*", "*/*"} {
+		for _, declared := range sortedKeys(content) {
+			declaredType, _, _ := strings.Cut(strings.ToLower(declared), ";")
+
+			if strings.TrimSpace(declaredType) == candidate {
+				mediaTypeObject, _ := content[declared].(map[string]any)
+				return mediaType, mediaTypeObject, true
+			}
+		}
+	}
+
+	return "", nil, false
+}
+
+// swaggerMediaType returns the media type of a Swagger 2 body, which is JSON unless told otherwise
+func swaggerMediaType(contentType string) string {
+	if len(contentType) == 0 {
+		return "application/json"
+	}
+
+	return strings.ToLower(contentType)
+}
+
+// matchPath matches a request path against a path template, returning the values of its parameters
+func matchPath(template, requestPath string) (map[string]string, bool) {
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	requestSegments := strings.Split(strings.Trim(requestPath, "/"), "/")
+
+	if len(templateSegments) != len(requestSegments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+
+	for i, segment := range templateSegments {
+		if !strings.Contains(segment, "{") {
+			if segment != requestSegments[i] {
+				return nil, false
+			}
+
+			continue
+		}
+
+		// segments can mix literals and parameters, e.g. {id}.json
+		names := make([]string, 0)
+		pattern := ""
+		last := 0
+
+		for _, indexes := range serverVariableRegex.FindAllStringSubmatchIndex(segment, -1) {
+			pattern += regexp.QuoteMeta(segment[last:indexes[0]]) + "([^/]+?)"
+			names = append(names, segment[indexes[2]:indexes[3]])
+			last = indexes[1]
+		}
+
+		pattern += regexp.QuoteMeta(segment[last:])
+		match := regexp.MustCompile("^" + pattern + "$").FindStringSubmatch(requestSegments[i])
+
+		if match == nil {
+			return nil, false
+		}
+
+		for j, name := range names {
+			value, err := url.PathUnescape(match[j+1])
+
+			if err != nil {
+				value = match[j+1]
+			}
+
+			params[name] = value
+		}
+	}
+
+	return params, true
+}
+
+func isNullable(schema map[string]any) bool {
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		return true
+	}
+
+	if nullable, _ := schema["x-nullable"].(bool); nullable {
+		return true
+	}
+
+	if types, ok := schema["type"].([]any); ok {
+		for _, item := range types {
+			if stringOf(item) == "null" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func matchesType(value any, expectedType string) bool {
+	switch expectedType {
+	case "integer":
+		number, ok := value.(json.Number)
+
+		if !ok {
+			return false
+		}
+
+		float, err := number.Float64()
+
+		return err == nil && float == math.Trunc(float)
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return true
+	}
+}
+
+func matchesFormat(value, format string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, value)
+		return err == nil
+	case "uuid":
+		return uuidRegex.MatchString(value)
+	case "email":
+		_, err := mail.ParseAddress(value)
+		return err == nil
+	case "ipv4":
+		address, err := netip.ParseAddr(value)
+		return err == nil && address.Is4()
+	case "ipv6":
+		address, err := netip.ParseAddr(value)
+		return err == nil && address.Is6()
+	case "uri":
+		parsed, err := url.Parse(value)
+		return err == nil && parsed.IsAbs()
+	default:
+		return true
+	}
+}
+
+func typeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func describeValue(value any) string {
+	encoded, err := json.Marshal(value)
+
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(encoded)
+}
+
+func containsValue(values []any, value any) bool {
+	for _, item := range values {
+		if equalValues(item, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// equalValues compares two JSON values, numbers being compared by value (e.g. 1 equals 1.0)
+func equalValues(a, b any) bool {
+	aNumber, aIsNumber := numberOf(a)
+	bNumber, bIsNumber := numberOf(b)
+
+	if aIsNumber || bIsNumber {
+		return aIsNumber && bIsNumber && aNumber == bNumber
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func numberOf(value any) (float64, bool) {
+	number, ok := value.(json.Number)
+
+	if !ok {
+		return 0, false
+	}
+
+	float, err := number.Float64()
+
+	return float, err == nil
+}
@@ -0,0 +1,410 @@
+package openapi
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const ordersYAML = `
+openapi: 3.0.3
+info:
+  title: Orders
+  version: "1"
+servers:
+  - url: https://api.shop.com/v1
+paths:
+  /orders:
+    get:
+      parameters:
+        - name: page
+          in: query
+          schema:
+            type: integer
+            minimum: 1
+        - name: status
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+              enum: [open, closed]
+        - name: X-Tenant
+          in: header
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Order'
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Order'
+      responses:
+        "201":
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Order'
+        4XX:
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Error'
+  /orders/{orderId}:
+    parameters:
+      - name: orderId
+        in: path
+        required: true
+        schema:
+          type: string
+          format: uuid
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Order'
+        default:
+          content:
+            text/plain:
+              schema:
+                type: string
+  /orders/latest:
+    get:
+      responses:
+        "200":
+          description: ok
+  /orders/{orderId}.pdf:
+    get:
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Order:
+      type: object
+      additionalProperties: false
+      required: [id, total, items]
+      properties:
+        id:
+          type: integer
+          readOnly: true
+        total:
+          type: number
+          exclusiveMinimum: true
+          minimum: 0
+        coupon:
+          type: string
+          nullable: true
+          pattern: '^[A-Z]{4}$'
+        items:
+          type: array
+          minItems: 1
+          items:
+            $ref: '#/components/schemas/Item'
+        parent:
+          $ref: '#/components/schemas/Order'
+    Item:
+      oneOf:
+        - type: object
+          required: [sku]
+          properties:
+            sku:
+              type: string
+        - type: object
+          required: [bundle]
+          properties:
+            bundle:
+              type: integer
+    Error:
+      type: object
+      required: [message]
+      properties:
+        message:
+          type: string
+`
+
+const orderId = "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+
+func newOrdersDocument(t *testing.T) *Document {
+	t.Helper()
+
+	document, err := Parse([]byte(ordersYAML))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return document
+}
+
+func newRequest(method, rawURI, body string, headers map[string]string) Request {
+	path, rawQuery, _ := strings.Cut(rawURI, "?")
+	query, _ := url.ParseQuery(rawQuery)
+	header := http.Header{}
+
+	for key, value := range headers {
+		header.Set(key, value)
+	}
+
+	return Request{Method: method, Path: path, Query: query, Headers: header, Body: []byte(body)}
+}
+
+func TestDocument_ValidateRequest(t *testing.T) {
+	document := newOrdersDocument(t)
+	jsonHeaders := map[string]string{"Content-Type": "application/json; charset=utf-8"}
+	tenantHeaders := map[string]string{"X-Tenant": "acme"}
+
+	tests := []struct {
+		name    string
+		request Request
+		want    []string
+	}{
+		{
+			name:    "accepts a valid request",
+			request: newRequest("GET", "/v1/orders?page=2&status=open,closed", "", tenantHeaders),
+		},
+		{
+			name:    "accepts a request without the base path",
+			request: newRequest("GET", "/orders?status=open&status=closed", "", tenantHeaders),
+		},
+		{
+			name:    "accepts a valid body, readOnly properties aside",
+			request: newRequest("POST", "/v1/orders", `{"total": 10.5, "coupon": null, "items": [{"sku": "A1"}]}`, jsonHeaders),
+		},
+		{
+			name:    "prefers the concrete paths",
+			request: newRequest("GET", "/v1/orders/latest", "", nil),
+		},
+		{
+			name:    "matches the paths mixing literals and parameters",
+			request: newRequest("GET", "/v1/orders/"+orderId+".pdf", "", nil),
+		},
+		{
+			name:    "reports undeclared paths",
+			request: newRequest("GET", "/v1/invoices", "", nil),
+			want:    []string{"path /invoices is not declared"},
+		},
+		{
+			name:    "reports undeclared methods",
+			request: newRequest("DELETE", "/v1/orders", "", nil),
+			want:    []string{"method DELETE is not declared for path /orders"},
+		},
+		{
+			name:    "reports invalid parameters",
+			request: newRequest("GET", "/v1/orders?page=0&status=lost", "", nil),
+			want: []string{
+				`query parameter "page": 0 is lesser than the minimum 1`,
+				`query parameter "status"[0]: "lost" is not one of the allowed values`,
+				`header parameter "X-Tenant": is required`,
+			},
+		},
+		{
+			name:    "reports parameters of the wrong type",
+			request: newRequest("GET", "/v1/orders?page=first", "", tenantHeaders),
+			want:    []string{`query parameter "page": expected integer, got "first"`},
+		},
+		{
+			name:    "reports invalid path parameters",
+			request: newRequest("GET", "/v1/orders/42", "", nil),
+			want:    []string{`path parameter "orderId": "42" is not a valid uuid`},
+		},
+		{
+			name:    "reports a missing body",
+			request: newRequest("POST", "/v1/orders", "", jsonHeaders),
+			want:    []string{"request body: is required"},
+		},
+		{
+			name:    "reports an undeclared content type",
+			request: newRequest("POST", "/v1/orders", "total=1", map[string]string{"Content-Type": "application/x-www-form-urlencoded"}),
+			want:    []string{`request content type "application/x-www-form-urlencoded" is not declared`},
+		},
+		{
+			name:    "reports invalid JSON",
+			request: newRequest("POST", "/v1/orders", `{"total":`, jsonHeaders),
+			want:    []string{"request body: invalid JSON: unexpected EOF"},
+		},
+		{
+			name:    "reports the body violations",
+			request: newRequest("POST", "/v1/orders", `{"total": 0, "coupon": "abc", "items": [{"sku": "A1", "bundle": 2}, {}], "note": "x"}`, jsonHeaders),
+			want: []string{
+				`request body.coupon: "abc" doesn't match the pattern ^[A-Z]{4}$`,
+				"request body.items[0]: should match exactly one of the oneOf schemas, matches 2",
+				"request body.items[1]: should match exactly one of the oneOf schemas, matches 0",
+				"request body.note: is not allowed",
+				"request body.total: 0 is lesser than the minimum 0",
+			},
+		},
+		{
+			name:    "reports violations in nested references",
+			request: newRequest("POST", "/v1/orders", `{"total": 1, "items": [], "parent": {"total": "1", "items": [{"sku": 1}]}}`, jsonHeaders),
+			want: []string{
+				"request body.items: expected at least 1 items, got 0",
+				"request body.parent.items[0]: should match exactly one of the oneOf schemas, matches 0",
+				"request body.parent.total: expected number, got string",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := document.ValidateRequest(tt.request)
+
+			if strings.Join(violations, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("expected violations:\n%s\ngot:\n%s", strings.Join(tt.want, "\n"), strings.Join(violations, "\n"))
+			}
+		})
+	}
+}
+
+func TestDocument_ValidateResponse(t *testing.T) {
+	document := newOrdersDocument(t)
+
+	tests := []struct {
+		name     string
+		request  Request
+		response Response
+		want     []string
+	}{
+		{
+			name:     "accepts a valid response",
+			request:  newRequest("GET", "/v1/orders/"+orderId, "", nil),
+			response: Response{StatusCode: 200, ContentType: "application/json", Body: []byte(`{"id": 1, "total": 3, "items": [{"bundle": 2}]}`)},
+		},
+		{
+			name:     "uses the status ranges",
+			request:  newRequest("POST", "/v1/orders", "", nil),
+			response: Response{StatusCode: 422, ContentType: "application/json", Body: []byte(`{"message": "invalid"}`)},
+		},
+		{
+			name:     "uses the default response",
+			request:  newRequest("GET", "/v1/orders/"+orderId, "", nil),
+			response: Response{StatusCode: 404, ContentType: "text/plain", Body: []byte("not found")},
+		},
+		{
+			name:     "ignores the requests matching no operation",
+			request:  newRequest("GET", "/v1/invoices", "", nil),
+			response: Response{StatusCode: 200, ContentType: "application/json", Body: []byte(`{}`)},
+		},
+		{
+			name:     "reports undeclared statuses",
+			request:  newRequest("POST", "/v1/orders", "", nil),
+			response: Response{StatusCode: 500, ContentType: "application/json", Body: []byte(`{}`)},
+			want:     []string{"status 500 is not declared"},
+		},
+		{
+			name:     "reports undeclared content types",
+			request:  newRequest("POST", "/v1/orders", "", nil),
+			response: Response{StatusCode: 201, ContentType: "text/plain", Body: []byte("created")},
+			want:     []string{`response content type "text/plain" is not declared`},
+		},
+		{
+			name:     "reports a missing body",
+			request:  newRequest("POST", "/v1/orders", "", nil),
+			response: Response{StatusCode: 201, ContentType: "application/json"},
+			want:     []string{"response body: is required"},
+		},
+		{
+			name:     "reports the body violations",
+			request:  newRequest("GET", "/v1/orders?page=1", "", nil),
+			response: Response{StatusCode: 200, ContentType: "application/json", Body: []byte(`[{"id": 1.5, "total": 3, "items": [{"sku": "A"}]}, {"id": 2}]`)},
+			want: []string{
+				"response body[0].id: expected integer, got number",
+				"response body[1].total: is required",
+				"response body[1].items: is required",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := document.ValidateResponse(tt.request, tt.response)
+
+			if strings.Join(violations, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("expected violations:\n%s\ngot:\n%s", strings.Join(tt.want, "\n"), strings.Join(violations, "\n"))
+			}
+		})
+	}
+}
+
+func TestDocument_ValidateSwagger(t *testing.T) {
+	document, err := Parse([]byte(`{
+  "swagger": "2.0",
+  "basePath": "/api",
+  "paths": {
+    "/users/{id}": {
+      "put": {
+        "parameters": [
+          {"name": "id", "in": "path", "required": true, "type": "integer"},
+          {"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/User"}}
+        ],
+        "responses": {"200": {"schema": {"$ref": "#/definitions/User"}}}
+      }
+    }
+  },
+  "definitions": {
+    "User": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string", "maxLength": 3}}}
+  }
+}`))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	request := newRequest("PUT", "/api/users/abc", `{"name": "john"}`, nil)
+	violations := document.ValidateRequest(request)
+	want := `path parameter "id": expected integer, got "abc"` + "\n" + "request body.name: expected at most 3 characters, got 4"
+
+	if strings.Join(violations, "\n") != want {
+		t.Errorf("unexpected request violations: %v", violations)
+	}
+
+	violations = document.ValidateResponse(request, Response{StatusCode: 200, ContentType: "application/json", Body: []byte(`{}`)})
+
+	if len(violations) != 1 || violations[0] != "response body.name: is required" {
+		t.Errorf("unexpected response violations: %v", violations)
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		template string
+		path     string
+		want     map[string]string
+		matches  bool
+	}{
+		{template: "/users", path: "/users/", want: map[string]string{}, matches: true},
+		{template: "/users/{id}", path: "/users/42", want: map[string]string{"id": "42"}, matches: true},
+		{template: "/files/{name}.{ext}", path: "/files/report.final.pdf", want: map[string]string{"name": "report", "ext": "final.pdf"}, matches: true},
+		{template: "/users/{id}", path: "/users", matches: false},
+		{template: "/users/{id}.json", path: "/users/42.xml", matches: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template+" "+tt.path, func(t *testing.T) {
+			params, matches := matchPath(tt.template, tt.path)
+
+			if matches != tt.matches {
+				t.Fatalf("expected match %v, got %v", tt.matches, matches)
+			}
+
+			for name, value := range tt.want {
+				if params[name] != value {
+					t.Errorf("expected %s=%q, got %q", name, value, params[name])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package scenario
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// StartState is the state every scenario is in until a mock moves it elsewhere, or after it is reset
+const StartState = "started"
+
+// Scenario is the current state of a named scenario
+type Scenario struct {
+	Name      string    `json:"name"`
+	State     string    `json:"state"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ScenarioService keeps the state of the scenarios in memory. Scenarios are known from the first time their state is
+// looked up, which happens when a request reaches one of their mocks.
+type ScenarioService struct {
+	mu        sync.Mutex
+	scenarios map[string]*Scenario
+}
+
+// NewScenarioService creates a new ScenarioService with no scenario known
+func NewScenarioService() *ScenarioService {
+	return &ScenarioService{
+		scenarios: make(map[string]*Scenario),
+	}
+}
+
+// State returns the current state of the scenario
+func (s *ScenarioService) State(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.getOrCreate(name).State
+}
+
+// Transition moves the scenario to the given state, as long as it is still in the expected one. An empty expected
+// state means any state.
+func (s *ScenarioService) Transition(name, from, to string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scenario := s.getOrCreate(name)
+
+	if len(from) > 0 && scenario.State != from {
+		return false
+	}
+
+	scenario.State = to
+	scenario.UpdatedAt = time.Now()
+
+	return true
+}
+
+// SetState moves the scenario to the given state, whatever state it is in
+func (s *ScenarioService) SetState(name, state string) Scenario {
+	s.Transition(name, "", state)
+
+	scenario, _ := s.Get(name)
+
+	return scenario
+}
+
+// Get returns the scenario, if known
+func (s *ScenarioService) Get(name string) (Scenario, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scenario, exists := s.scenarios[name]
+
+	if !exists {
+		return Scenario{}, false
+	}
+
+	return *scenario, true
+}
+
+// List returns all the known scenarios, sorted by name
+func (s *ScenarioService) List() []Scenario {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scenarios := make([]Scenario, 0, len(s.scenarios))
+
+	for _, scenario := range s.scenarios {
+		scenarios = append(scenarios, *scenario)
+	}
+
+	sort.Slice(scenarios, func(i, j int) bool {
+		return scenarios[i].Name < scenarios[j].Name
+	})
+
+	return scenarios
+}
+
+// Reset moves the scenario back to its start state. It returns false if the scenario isn't known.
+func (s *ScenarioService) Reset(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scenario, exists := s.scenarios[name]
+
+	if !exists {
+		return false
+	}
+
+	scenario.State = StartState
+	scenario.UpdatedAt = time.Now()
+
+	return true
+}
+
+// ResetAll moves every known scenario back to its start state
+func (s *ScenarioService) ResetAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	for _, scenario := range s.scenarios {
+		scenario.State = StartState
+		scenario.UpdatedAt = now
+	}
+}
+
+func (s *ScenarioService) getOrCreate(name string) *Scenario {
+	scenario, exists := s.scenarios[name]
+
+	if !exists {
+		scenario = &Scenario{
+			Name:      name,
+			State:     StartState,
+			UpdatedAt: time.Now(),
+		}
+
+		s.scenarios[name] = scenario
+	}
+
+	return scenario
+}
@@ -0,0 +1,138 @@
+package scenario
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestScenarioService_State(t *testing.T) {
+	t.Run("starts unknown scenarios in the start state", func(t *testing.T) {
+		service := NewScenarioService()
+
+		if state := service.State("order"); state != StartState {
+			t.Errorf("expected state %q, got %q", StartState, state)
+		}
+
+		if _, exists := service.Get("order"); !exists {
+			t.Error("expected scenario to be known after its state is looked up")
+		}
+	})
+}
+
+func TestScenarioService_Transition(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		expected bool
+		state    string
+	}{
+		{"from the current state", StartState, true, "paid"},
+		{"from any state", "", true, "paid"},
+		{"from another state", "created", false, StartState},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewScenarioService()
+
+			if result := service.Transition("order", tt.from, "paid"); result != tt.expected {
+				t.Errorf("Transition() = %v, expected %v", result, tt.expected)
+			}
+
+			if state := service.State("order"); state != tt.state {
+				t.Errorf("expected state %q, got %q", tt.state, state)
+			}
+		})
+	}
+
+	t.Run("moves the scenario only once on concurrent transitions", func(t *testing.T) {
+		service := NewScenarioService()
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		moved := 0
+
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				if service.Transition("order", StartState, "shipped") {
+					mu.Lock()
+					moved++
+					mu.Unlock()
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		if moved != 1 {
+			t.Errorf("expected a single transition, got %d", moved)
+		}
+	})
+}
+
+func TestScenarioService_SetState(t *testing.T) {
+	service := NewScenarioService()
+
+	scenario := service.SetState("order", "shipped")
+
+	if scenario.Name != "order" || scenario.State != "shipped" || scenario.UpdatedAt.IsZero() {
+		t.Errorf("unexpected scenario: %+v", scenario)
+	}
+}
+
+func TestScenarioService_List(t *testing.T) {
+	service := NewScenarioService()
+	service.SetState("payment", "paid")
+	service.State("order")
+
+	scenarios := service.List()
+
+	if len(scenarios) != 2 || scenarios[0].Name != "order" || scenarios[1].Name != "payment" {
+		t.Errorf("expected scenarios sorted by name, got %+v", scenarios)
+	}
+}
+
+func TestScenarioService_Reset(t *testing.T) {
+	t.Run("moves a known scenario back to the start state", func(t *testing.T) {
+		service := NewScenarioService()
+		service.SetState("order", "shipped")
+
+		if !service.Reset("order") {
+			t.Fatal("expected scenario to be reset")
+		}
+
+		if state := service.State("order"); state != StartState {
+			t.Errorf("expected state %q, got %q", StartState, state)
+		}
+	})
+
+	t.Run("returns false for an unknown scenario", func(t *testing.T) {
+		service := NewScenarioService()
+
+		if service.Reset("order") {
+			t.Error("expected unknown scenario not to be reset")
+		}
+
+		if _, exists := service.Get("order"); exists {
+			t.Error("expected reset not to register the scenario")
+		}
+	})
+
+	t.Run("resets all the scenarios", func(t *testing.T) {
+		service := NewScenarioService()
+		service.SetState("order", "shipped")
+		service.SetState("payment", "paid")
+
+		service.ResetAll()
+
+		for _, scenario := range service.List() {
+			if scenario.State != StartState {
+				t.Errorf("expected scenario %s in state %q, got %q", scenario.Name, StartState, scenario.State)
+			}
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	// ExporterOTLP exports the traces to an OTLP/HTTP endpoint
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes the traces to the standard output
+	ExporterStdout = "stdout"
+
+	instrumentationName = "github.com/Caik/go-mock-server"
+)
+
+// TracingService traces the mock requests with OpenTelemetry, continuing the traces of the clients.
+// A nil TracingService starts no-op spans, so callers don't need to check if tracing is enabled.
+type TracingService struct {
+	provider   *sdktrace.TracerProvider
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// NewTracingService creates a new TracingService from AppArguments.
+// Returns nil if tracing is disabled.
+func NewTracingService(args *config.AppArguments) (*TracingService, error) {
+	if len(args.TracingExporter) == 0 {
+		return nil, nil
+	}
+
+	exporter, err := newExporter(args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("exporter", args.TracingExporter).
+		Str("endpoint", args.TracingEndpoint).
+		Msg("tracing enabled")
+
+	return NewTracingServiceFrom(sdktrace.NewBatchSpanProcessor(exporter), args.TracingServiceName)
+}
+
+// NewTracingServiceFrom creates a new TracingService handing the spans over to the given processor
+func NewTracingServiceFrom(processor sdktrace.SpanProcessor, serviceName string) (*TracingService, error) {
+	serviceResource, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", serviceName),
+		attribute.String("service.version", config.GetVersion()),
+	))
+
+	if err != nil {
+		return nil, fmt.Errorf("error while creating the tracing resource: %v", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(serviceResource),
+		// the clients decide whether their traces are sampled
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.AlwaysSample())),
+	)
+
+	return &TracingService{
+		provider:   provider,
+		tracer:     provider.Tracer(instrumentationName),
+		propagator: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}, nil
+}
+
+func newExporter(args *config.AppArguments) (sdktrace.SpanExporter, error) {
+	switch args.TracingExporter {
+	case ExporterOTLP:
+		var options []otlptracehttp.Option
+
+		// without an endpoint, the OTEL_EXPORTER_OTLP_* environment variables apply
+		if len(args.TracingEndpoint) > 0 {
+			options = append(options, otlptracehttp.WithEndpointURL(args.TracingEndpoint))
+		}
+
+		exporter, err := otlptracehttp.New(context.Background(), options...)
+
+		if err != nil {
+			return nil, fmt.Errorf("error while creating the OTLP exporter: %v", err)
+		}
+
+		return exporter, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New()
+
+		if err != nil {
+			return nil, fmt.Errorf("error while creating the stdout exporter: %v", err)
+		}
+
+		return exporter, nil
+	default:
+		return nil, fmt.Errorf("invalid tracing exporter %q: must be %s or %s", args.TracingExporter, ExporterOTLP, ExporterStdout)
+	}
+}
+
+// Extract returns the context carrying the trace of the client, as sent in the traceparent header, if any
+func (t *TracingService) Extract(ctx context.Context, header http.Header) context.Context {
+	if t == nil {
+		return ctx
+	}
+
+	return t.propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Inject adds the trace of the span in the context to the headers of an outgoing request, as a traceparent header
+func (t *TracingService) Inject(ctx context.Context, header http.Header) {
+	if t == nil {
+		return
+	}
+
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Start starts a span, child of the span in the context if any
+func (t *TracingService) Start(ctx context.Context, name string, options ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if t == nil {
+		return ctx, noop.Span{}
+	}
+
+	return t.tracer.Start(ctx, name, options...)
+}
+
+// Shutdown exports the spans not exported yet, and stops the exporter
+func (t *TracingService) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	return t.provider.Shutdown(ctx)
+}
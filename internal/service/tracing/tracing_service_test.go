@@ -0,0 +1,125 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Caik/go-mock-server/internal/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newRecordedTracingService(t *testing.T) (*TracingService, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracingService, err := NewTracingServiceFrom(recorder, "test")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return tracingService, recorder
+}
+
+func TestNewTracingService(t *testing.T) {
+	t.Run("returns nil when disabled", func(t *testing.T) {
+		tracingService, err := NewTracingService(&config.AppArguments{})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if tracingService != nil {
+			t.Error("expected nil service")
+		}
+	})
+
+	t.Run("rejects an unknown exporter", func(t *testing.T) {
+		if _, err := NewTracingService(&config.AppArguments{TracingExporter: "zipkin"}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("creates the stdout and otlp exporters", func(t *testing.T) {
+		for _, args := range []*config.AppArguments{
+			{TracingExporter: ExporterStdout, TracingServiceName: "mock"},
+			{TracingExporter: ExporterOTLP, TracingServiceName: "mock", TracingEndpoint: "http://localhost:4318/v1/traces"},
+		} {
+			tracingService, err := NewTracingService(args)
+
+			if err != nil {
+				t.Fatalf("unexpected error for %s: %v", args.TracingExporter, err)
+			}
+
+			if tracingService == nil {
+				t.Fatalf("expected a service for %s", args.TracingExporter)
+			}
+		}
+	})
+}
+
+func TestTracingService_Start(t *testing.T) {
+	tracingService, recorder := newRecordedTracingService(t)
+
+	ctx, parent := tracingService.Start(context.Background(), "parent")
+	_, child := tracingService.Start(ctx, "child", trace.WithAttributes(attribute.String("key", "value")))
+	child.End()
+	parent.End()
+
+	spans := recorder.Ended()
+
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	if spans[0].Name() != "child" || spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Error("expected the child span to be a child of the parent span")
+	}
+
+	if !spans[1].Resource().Set().HasValue("service.name") {
+		t.Error("expected the service name in the resource")
+	}
+}
+
+func TestTracingService_Extract(t *testing.T) {
+	tracingService, recorder := newRecordedTracingService(t)
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	_, span := tracingService.Start(tracingService.Extract(context.Background(), header), "request")
+	span.End()
+
+	spans := recorder.Ended()
+
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if got := spans[0].SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected the trace of the client, got %s", got)
+	}
+
+	if got := spans[0].Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("expected the span of the client as parent, got %s", got)
+	}
+}
+
+func TestTracingService_nil(t *testing.T) {
+	var tracingService *TracingService
+
+	ctx := tracingService.Extract(context.Background(), http.Header{"Traceparent": {"invalid"}})
+	_, span := tracingService.Start(ctx, "request")
+	span.End()
+
+	if span.SpanContext().IsValid() {
+		t.Error("expected a no-op span")
+	}
+
+	if err := tracingService.Shutdown(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -1,24 +1,40 @@
 package traffic
 
 import (
+	"encoding/base64"
+	"net/http"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+// bodyEncodingBase64 tells that a captured body isn't valid UTF-8 text, and was base64 encoded
+const bodyEncodingBase64 = "base64"
+
 // TrafficRequest captures details about the incoming HTTP request
 type TrafficRequest struct {
-	Method  string            `json:"method"`
-	Host    string            `json:"host"`
-	Path    string            `json:"path"`
-	Query   string            `json:"query,omitempty"`
-	Headers map[string]string `json:"headers,omitempty"`
+	Method        string            `json:"method"`
+	Scheme        string            `json:"scheme,omitempty"`
+	Host          string            `json:"host"`
+	Path          string            `json:"path"`
+	Query         string            `json:"query,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	Body          string            `json:"body,omitempty"`
+	BodyEncoding  string            `json:"body_encoding,omitempty"`
+	BodySize      int               `json:"body_size,omitempty"`
+	BodyTruncated bool              `json:"body_truncated,omitempty"`
 }
 
 // TrafficResponse captures details about the mock response
 type TrafficResponse struct {
-	StatusCode  int    `json:"status_code"`
-	ContentType string `json:"content_type,omitempty"`
-	BodySize    int    `json:"body_size"`
-	LatencyMs   int64  `json:"latency_ms"`
+	StatusCode    int               `json:"status_code"`
+	ContentType   string            `json:"content_type,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	Body          string            `json:"body,omitempty"`
+	BodyEncoding  string            `json:"body_encoding,omitempty"`
+	BodySize      int               `json:"body_size"`
+	BodyTruncated bool              `json:"body_truncated,omitempty"`
+	LatencyMs     int64             `json:"latency_ms"`
 }
 
 // TrafficEntry represents a single traffic log entry
@@ -37,3 +53,64 @@ func NewTrafficEntry(uuid string) *TrafficEntry {
 		Timestamp: time.Now(),
 	}
 }
+
+// SetBody captures the request body, truncated to maxSize
+func (t *TrafficRequest) SetBody(data []byte, maxSize int) {
+	t.BodySize = len(data)
+	t.Body, t.BodyEncoding, t.BodyTruncated = encodeBody(data, maxSize)
+}
+
+// SetBody captures the response body, truncated to maxSize. The size is the one of the whole body, which may be
+// bigger than the data when only its beginning was kept, as for the streamed bodies.
+func (t *TrafficResponse) SetBody(data []byte, size, maxSize int) {
+	t.BodySize = size
+	t.Body, t.BodyEncoding, t.BodyTruncated = encodeBody(data, maxSize)
+	t.BodyTruncated = t.BodyTruncated || size > len(data)
+}
+
+// DecodeBody returns the raw bytes of a captured body
+func DecodeBody(body, encoding string) ([]byte, error) {
+	if encoding == bodyEncodingBase64 {
+		return base64.StdEncoding.DecodeString(body)
+	}
+
+	return []byte(body), nil
+}
+
+// FlattenHeaders converts the headers to a map, joining the values of the repeated ones
+func FlattenHeaders(headers http.Header) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	flattened := make(map[string]string, len(headers))
+
+	for key, values := range headers {
+		flattened[key] = strings.Join(values, ", ")
+	}
+
+	return flattened
+}
+
+// encodeBody returns the body as text, base64 encoded when it isn't valid UTF-8, and whether it was truncated
+func encodeBody(data []byte, maxSize int) (string, string, bool) {
+	maxSize = max(maxSize, 0)
+	truncated := len(data) > maxSize
+
+	if truncated {
+		// not cutting a multibyte character in half, which would make a text body look binary
+		for cut := maxSize; cut > maxSize-utf8.UTFMax; cut-- {
+			if utf8.Valid(data[:cut]) {
+				return string(data[:cut]), "", true
+			}
+		}
+
+		data = data[:maxSize]
+	}
+
+	if utf8.Valid(data) {
+		return string(data), "", truncated
+	}
+
+	return base64.StdEncoding.EncodeToString(data), bodyEncodingBase64, truncated
+}
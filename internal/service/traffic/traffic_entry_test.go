@@ -1,8 +1,11 @@
 package traffic
 
 import (
+	"bytes"
+	"net/http"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
 const metadataSource = "Source"
@@ -99,3 +102,74 @@ func TestTrafficEntry_JSONSerialization(t *testing.T) {
 		}
 	})
 }
+
+func TestTrafficRequest_SetBody(t *testing.T) {
+	t.Run("keeps text bodies as they are", func(t *testing.T) {
+		var req TrafficRequest
+		req.SetBody([]byte(`{"name":"john"}`), 1024)
+
+		if req.Body != `{"name":"john"}` || req.BodyEncoding != "" || req.BodySize != 15 || req.BodyTruncated {
+			t.Errorf("unexpected request %+v", req)
+		}
+	})
+
+	t.Run("base64 encodes binary bodies", func(t *testing.T) {
+		var req TrafficRequest
+		req.SetBody([]byte{0x89, 'P', 'N', 'G'}, 1024)
+
+		if req.Body != "iVBORw==" || req.BodyEncoding != "base64" {
+			t.Errorf("unexpected request %+v", req)
+		}
+
+		if data, err := DecodeBody(req.Body, req.BodyEncoding); err != nil || string(data) != "\x89PNG" {
+			t.Errorf("expected the body to be decoded, got %q, %v", data, err)
+		}
+	})
+
+	t.Run("truncates big bodies without cutting characters", func(t *testing.T) {
+		var req TrafficRequest
+		data := append(bytes.Repeat([]byte("a"), 1023), "é and more"...)
+		req.SetBody(data, 1024)
+
+		if !req.BodyTruncated || req.BodySize != len(data) || req.BodyEncoding != "" {
+			t.Fatalf("unexpected request size %d, encoding %q, truncated %v", req.BodySize, req.BodyEncoding, req.BodyTruncated)
+		}
+
+		if len(req.Body) != 1023 || !utf8.ValidString(req.Body) {
+			t.Errorf("expected the body cut before the multibyte character, got %d bytes", len(req.Body))
+		}
+	})
+
+	t.Run("keeps no body when the size is 0", func(t *testing.T) {
+		var req TrafficRequest
+		req.SetBody([]byte("data"), 0)
+
+		if req.Body != "" || req.BodySize != 4 || !req.BodyTruncated {
+			t.Errorf("unexpected request %+v", req)
+		}
+	})
+}
+
+func TestTrafficResponse_SetBody(t *testing.T) {
+	var resp TrafficResponse
+	resp.SetBody([]byte("partial"), 1024, 1024)
+
+	if resp.Body != "partial" || resp.BodySize != 1024 || !resp.BodyTruncated {
+		t.Errorf("expected the partial body to be flagged as truncated, got %+v", resp)
+	}
+}
+
+func TestFlattenHeaders(t *testing.T) {
+	if headers := FlattenHeaders(nil); headers != nil {
+		t.Errorf("expected nil for no headers, got %v", headers)
+	}
+
+	headers := FlattenHeaders(http.Header{
+		"Accept":       {"text/plain", "application/json"},
+		"Content-Type": {"application/json"},
+	})
+
+	if headers["Accept"] != "text/plain, application/json" || headers["Content-Type"] != "application/json" {
+		t.Errorf("unexpected headers %v", headers)
+	}
+}
@@ -2,7 +2,13 @@ package traffic
 
 import (
 	"fmt"
+	"net/http"
+	"path"
+	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/Caik/go-mock-server/internal/config"
 	"github.com/Caik/go-mock-server/internal/util"
@@ -11,11 +17,34 @@ import (
 
 const metadataMatched = "Matched"
 
+// RedactedValue replaces the values of the redacted headers
+const RedactedValue = "[REDACTED]"
+
+// persistQueueSize is the number of entries waiting to be written to the store, above which new entries are only
+// kept in memory, so a slow disk never slows the requests down
+const persistQueueSize = 1024
+
+// persistItem is an entry waiting to be written to the store, or a marker closing flushed once the entries queued
+// before it are written
+type persistItem struct {
+	entry   TrafficEntry
+	flushed chan struct{}
+}
+
 // TrafficLogService manages traffic logging with an in-memory ring buffer
 // and broadcasts new entries to subscribers for real-time streaming.
 type TrafficLogService struct {
-	ringBuffer  *util.RingBuffer[TrafficEntry]
-	broadcaster *util.Broadcaster[TrafficEntry]
+	ringBuffer      *util.RingBuffer[TrafficEntry]
+	broadcaster     *util.Broadcaster[TrafficEntry]
+	store           TrafficStore
+	storeFailing    atomic.Bool
+	queueFull       atomic.Bool
+	queue           chan persistItem
+	queueMu         sync.RWMutex // guards queueClosed, so nothing is queued once the store is closed
+	queueClosed     bool
+	writerDone      chan struct{}
+	maxBodySize     int
+	redactedHeaders map[string]bool
 }
 
 // NewTrafficLogService creates a new TrafficLogService from AppArguments.
@@ -38,22 +67,130 @@ func NewTrafficLogService(args *config.AppArguments) *TrafficLogService {
 		return nil
 	}
 
-	return &TrafficLogService{
-		ringBuffer:  ringBuffer,
-		broadcaster: &util.Broadcaster[TrafficEntry]{},
+	redactedHeaders := make(map[string]bool)
+
+	for _, header := range strings.Split(args.TrafficLogRedactHeaders, ",") {
+		if header = strings.TrimSpace(header); len(header) > 0 {
+			redactedHeaders[http.CanonicalHeaderKey(header)] = true
+		}
+	}
+
+	service := &TrafficLogService{
+		ringBuffer:      ringBuffer,
+		broadcaster:     &util.Broadcaster[TrafficEntry]{},
+		maxBodySize:     max(args.TrafficLogMaxBodySize, 0),
+		redactedHeaders: redactedHeaders,
 	}
+
+	if len(args.TrafficLogDirectory) > 0 {
+		store, err := NewFileTrafficStore(args.TrafficLogDirectory, args.TrafficLogSegmentSize, args.TrafficLogMaxSegments)
+
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("directory", args.TrafficLogDirectory).
+				Msg("error while opening the traffic log store, keeping the traffic log in memory only")
+		} else {
+			service.store = store
+			service.queue = make(chan persistItem, persistQueueSize)
+			service.writerDone = make(chan struct{})
+
+			go service.writeQueued()
+		}
+	}
+
+	return service
+}
+
+// MaxBodySize returns the size above which the captured bodies are truncated.
+func (t *TrafficLogService) MaxBodySize() int {
+	if t == nil {
+		return 0
+	}
+
+	return t.maxBodySize
 }
 
 // Capture adds a traffic entry to the log and broadcasts it to subscribers.
+// The values of the redacted headers are hidden before the entry is kept.
 func (t *TrafficLogService) Capture(entry TrafficEntry) {
 	if t == nil {
 		return
 	}
 
+	entry.Request.Headers = t.redactHeaders(entry.Request.Headers)
+	entry.Response.Headers = t.redactHeaders(entry.Response.Headers)
+
 	t.ringBuffer.Add(entry)
+	t.persist(entry)
 	t.broadcaster.PublishAsync(entry, entry.UUID)
 }
 
+// persist queues the entry to be written to the store, if any. The entry is only kept in memory when the queue is
+// full, and only the first of consecutive drops is logged.
+func (t *TrafficLogService) persist(entry TrafficEntry) {
+	if t.store == nil {
+		return
+	}
+
+	t.queueMu.RLock()
+	defer t.queueMu.RUnlock()
+
+	if t.queueClosed {
+		return
+	}
+
+	select {
+	case t.queue <- persistItem{entry: entry}:
+		if t.queueFull.Swap(false) {
+			log.Info().
+				Str("uuid", entry.UUID).
+				Msg("traffic entries queued to be persisted again")
+		}
+	default:
+		if !t.queueFull.Swap(true) {
+			log.Warn().
+				Str("uuid", entry.UUID).
+				Msg("too many traffic entries waiting to be persisted, keeping the new ones in memory only")
+		}
+	}
+}
+
+// writeQueued writes the queued entries to the store, until the queue is closed
+func (t *TrafficLogService) writeQueued() {
+	defer close(t.writerDone)
+
+	for item := range t.queue {
+		if item.flushed != nil {
+			close(item.flushed)
+			continue
+		}
+
+		t.write(item.entry)
+	}
+}
+
+// write appends the entry to the store. Only the first of consecutive failures is logged, as a full disk would
+// otherwise be logged on every request.
+func (t *TrafficLogService) write(entry TrafficEntry) {
+	if err := t.store.Append(entry); err != nil {
+		if !t.storeFailing.Swap(true) {
+			log.Warn().
+				Err(err).
+				Str("uuid", entry.UUID).
+				Msg("error while persisting the traffic entry")
+		}
+
+		return
+	}
+
+	if t.storeFailing.Swap(false) {
+		log.Info().
+			Str("uuid", entry.UUID).
+			Msg("traffic entries persisted again")
+	}
+}
+
 // GetAll returns all entries in the buffer, ordered from oldest to newest.
 func (t *TrafficLogService) GetAll() []TrafficEntry {
 	if t == nil {
@@ -63,6 +200,24 @@ func (t *TrafficLogService) GetAll() []TrafficEntry {
 	return t.ringBuffer.GetAll()
 }
 
+// Get returns the entry with the given UUID, if still in the buffer.
+func (t *TrafficLogService) Get(uuid string) (TrafficEntry, bool) {
+	if t == nil {
+		return TrafficEntry{}, false
+	}
+
+	all := t.ringBuffer.GetAll()
+
+	// the most recent entries are the most likely to be looked up
+	for i := len(all) - 1; i >= 0; i-- {
+		if all[i].UUID == uuid {
+			return all[i], true
+		}
+	}
+
+	return TrafficEntry{}, false
+}
+
 // GetRecent returns the n most recent entries, ordered from oldest to newest.
 func (t *TrafficLogService) GetRecent(n int) []TrafficEntry {
 	if t == nil {
@@ -96,6 +251,35 @@ func (t *TrafficLogService) GetFiltered(filters *TrafficFilters) []TrafficEntry
 	return filtered
 }
 
+// Query returns a page of the entries matching the filters, from the most recent to the oldest. The entries come
+// from the store when there is one, so they may be older than the ones in the buffer, or even than the last start.
+func (t *TrafficLogService) Query(query TrafficQuery) (*TrafficPage, error) {
+	if t == nil {
+		return &TrafficPage{Entries: []TrafficEntry{}}, nil
+	}
+
+	var since, until *time.Time
+
+	if query.Filters != nil {
+		since, until = query.Filters.Since, query.Filters.Until
+	}
+
+	if t.store != nil {
+		t.flush()
+		return query.page(t.store.Entries(since, until))
+	}
+
+	all := t.ringBuffer.GetAll()
+
+	return query.page(func(yield func(TrafficEntry) bool) {
+		for i := len(all) - 1; i >= 0; i-- {
+			if !yield(all[i]) {
+				return
+			}
+		}
+	})
+}
+
 // Subscribe returns a channel that receives new traffic entries.
 // If filters is nil or empty, all entries are received.
 // Otherwise, only entries matching the filter are received.
@@ -143,11 +327,98 @@ func (t *TrafficLogService) Size() int {
 	return t.ringBuffer.Size()
 }
 
+// Capacity returns the maximum number of entries in the buffer.
+func (t *TrafficLogService) Capacity() int {
+	if t == nil {
+		return 0
+	}
+
+	return t.ringBuffer.Capacity()
+}
+
+// SubscriberCount returns the number of current subscribers.
+func (t *TrafficLogService) SubscriberCount() int {
+	if t == nil {
+		return 0
+	}
+
+	return t.broadcaster.SubscriberCount()
+}
+
+// flush waits for the entries queued so far to be written to the store
+func (t *TrafficLogService) flush() {
+	t.queueMu.RLock()
+
+	if t.queueClosed {
+		t.queueMu.RUnlock()
+		return
+	}
+
+	flushed := make(chan struct{})
+	t.queue <- persistItem{flushed: flushed}
+	t.queueMu.RUnlock()
+
+	<-flushed
+}
+
+// Close writes the entries still queued to the store, and closes it. Entries captured afterwards are only kept in
+// memory.
+func (t *TrafficLogService) Close() error {
+	if t == nil || t.store == nil {
+		return nil
+	}
+
+	t.queueMu.Lock()
+
+	if t.queueClosed {
+		t.queueMu.Unlock()
+		return nil
+	}
+
+	t.queueClosed = true
+	close(t.queue)
+	t.queueMu.Unlock()
+
+	<-t.writerDone
+
+	return t.store.Close()
+}
+
+// redactHeaders returns a copy of the headers with the values of the redacted ones hidden.
+func (t *TrafficLogService) redactHeaders(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return headers
+	}
+
+	redacted := make(map[string]string, len(headers))
+
+	for key, value := range headers {
+		if t.redactedHeaders[http.CanonicalHeaderKey(key)] {
+			value = RedactedValue
+		}
+
+		redacted[key] = value
+	}
+
+	return redacted
+}
+
 // TrafficFilters contains optional filters for querying traffic entries.
 type TrafficFilters struct {
-	Hosts       []string // Match any of these hosts (case-insensitive)
-	StatusCodes []int    // Match any of these status codes
-	Matched     *bool    // Match entries by matched status
+	Hosts         []string          // Match any of these hosts (case-insensitive)
+	StatusCodes   []int             // Match any of these status codes
+	Matched       *bool             // Match entries by matched status
+	Methods       []string          // Match any of these methods (case-insensitive)
+	PathPrefix    string            // Match paths starting with this prefix
+	PathGlob      string            // Match paths matching this glob, as in path.Match
+	PathRegex     *regexp.Regexp    // Match paths matching this regular expression
+	QueryContains string            // Match query strings containing this text
+	MinLatency    *time.Duration    // Match entries answered in at least this time
+	MaxLatency    *time.Duration    // Match entries answered in at most this time
+	Since         *time.Time        // Match entries received at or after this time
+	Until         *time.Time        // Match entries received before this time
+	Metadata      map[string]string // Match entries having all these metadata values (case-insensitive)
+	Text          string            // Match entries containing this text anywhere (case-insensitive)
 }
 
 // Validate checks if the filter values are valid.
@@ -171,16 +442,47 @@ func (f TrafficFilters) Validate() error {
 		}
 	}
 
+	for _, method := range f.Methods {
+		if strings.TrimSpace(method) == "" {
+			return fmt.Errorf("invalid method: empty or whitespace-only method not allowed")
+		}
+	}
+
+	if _, err := path.Match(f.PathGlob, ""); err != nil {
+		return fmt.Errorf("invalid path glob %q: %v", f.PathGlob, err)
+	}
+
+	if (f.MinLatency != nil && *f.MinLatency < 0) || (f.MaxLatency != nil && *f.MaxLatency < 0) {
+		return fmt.Errorf("invalid latency: must not be negative")
+	}
+
+	if f.MinLatency != nil && f.MaxLatency != nil && *f.MinLatency > *f.MaxLatency {
+		return fmt.Errorf("invalid latency range: min latency must not be above max latency")
+	}
+
+	if f.Since != nil && f.Until != nil && !f.Since.Before(*f.Until) {
+		return fmt.Errorf("invalid time range: since must be before until")
+	}
+
+	for key := range f.Metadata {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid metadata: empty or whitespace-only key not allowed")
+		}
+	}
+
 	return nil
 }
 
 // IsEmpty returns true if no filters are set.
 func (f TrafficFilters) IsEmpty() bool {
-	return len(f.Hosts) == 0 && len(f.StatusCodes) == 0 && f.Matched == nil
+	return len(f.Hosts) == 0 && len(f.StatusCodes) == 0 && f.Matched == nil &&
+		len(f.Methods) == 0 && f.PathPrefix == "" && f.PathGlob == "" && f.PathRegex == nil &&
+		f.QueryContains == "" && f.MinLatency == nil && f.MaxLatency == nil && f.Since == nil && f.Until == nil &&
+		len(f.Metadata) == 0 && f.Text == ""
 }
 
 // Matches returns true if the entry matches all non-empty filters.
-// For array filters (Hosts, StatusCodes), the entry must match ANY value in the array.
+// For array filters (Hosts, StatusCodes, Methods), the entry must match ANY value in the array.
 func (f TrafficFilters) Matches(entry TrafficEntry) bool {
 	if len(f.Hosts) > 0 && !f.matchesHost(entry.Request.Host) {
 		return false
@@ -194,9 +496,108 @@ func (f TrafficFilters) Matches(entry TrafficEntry) bool {
 		return false
 	}
 
+	if len(f.Methods) > 0 && !f.matchesMethod(entry.Request.Method) {
+		return false
+	}
+
+	if !f.matchesPath(entry.Request.Path) {
+		return false
+	}
+
+	if f.QueryContains != "" && !strings.Contains(entry.Request.Query, f.QueryContains) {
+		return false
+	}
+
+	latency := time.Duration(entry.Response.LatencyMs) * time.Millisecond
+
+	if (f.MinLatency != nil && latency < *f.MinLatency) || (f.MaxLatency != nil && latency > *f.MaxLatency) {
+		return false
+	}
+
+	if f.Since != nil && entry.Timestamp.Before(*f.Since) {
+		return false
+	}
+
+	if f.Until != nil && !entry.Timestamp.Before(*f.Until) {
+		return false
+	}
+
+	if len(f.Metadata) > 0 && !f.matchesMetadata(entry.Metadata) {
+		return false
+	}
+
+	if f.Text != "" && !f.matchesText(entry) {
+		return false
+	}
+
+	return true
+}
+
+// matchesPath returns true if the given path matches the prefix, glob and regular expression of the filter.
+func (f TrafficFilters) matchesPath(requestPath string) bool {
+	if f.PathPrefix != "" && !strings.HasPrefix(requestPath, f.PathPrefix) {
+		return false
+	}
+
+	if f.PathGlob != "" {
+		if matched, _ := path.Match(f.PathGlob, requestPath); !matched {
+			return false
+		}
+	}
+
+	return f.PathRegex == nil || f.PathRegex.MatchString(requestPath)
+}
+
+// matchesMetadata returns true if the given metadata has all the values of the filter.
+func (f TrafficFilters) matchesMetadata(metadata map[string]string) bool {
+	for key, value := range f.Metadata {
+		if actual, ok := metadata[key]; !ok || !strings.EqualFold(actual, value) {
+			return false
+		}
+	}
 	return true
 }
 
+// matchesText returns true if the text of the filter is found in the request line, the headers, the text bodies
+// or the metadata of the given entry.
+func (f TrafficFilters) matchesText(entry TrafficEntry) bool {
+	text := strings.ToLower(f.Text)
+	contains := func(value string) bool {
+		return strings.Contains(strings.ToLower(value), text)
+	}
+
+	if contains(entry.Request.Method) || contains(entry.Request.Host) || contains(entry.Request.Path) ||
+		contains(entry.Request.Query) {
+		return true
+	}
+
+	// base64 encoded bodies are binary, so the text can't be searched in them
+	if (entry.Request.BodyEncoding == "" && contains(entry.Request.Body)) ||
+		(entry.Response.BodyEncoding == "" && contains(entry.Response.Body)) {
+		return true
+	}
+
+	for _, values := range []map[string]string{entry.Request.Headers, entry.Response.Headers, entry.Metadata} {
+		for key, value := range values {
+			if contains(key) || contains(value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchesMethod returns true if the given method matches any method in the filter.
+func (f TrafficFilters) matchesMethod(method string) bool {
+	for _, m := range f.Methods {
+		if strings.EqualFold(method, strings.TrimSpace(m)) {
+			return true
+		}
+	}
+	return false
+}
+
 // matchesHost returns true if the given host matches any host in the filter.
 func (f TrafficFilters) matchesHost(host string) bool {
 	for _, h := range f.Hosts {
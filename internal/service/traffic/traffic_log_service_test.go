@@ -1,6 +1,8 @@
 package traffic
 
 import (
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -69,6 +71,67 @@ func TestTrafficLogService_Capture(t *testing.T) {
 			t.Errorf("expected size 0 for disabled service, got %d", service.Size())
 		}
 	})
+
+	t.Run("redacts the configured headers", func(t *testing.T) {
+		service := NewTrafficLogService(&config.AppArguments{
+			TrafficLogBufferSize:    10,
+			TrafficLogRedactHeaders: "authorization, Set-Cookie",
+		})
+
+		requestHeaders := map[string]string{"Authorization": "Bearer secret", "Accept": "*/*"}
+
+		service.Capture(TrafficEntry{
+			UUID:     "test-uuid",
+			Request:  TrafficRequest{Headers: requestHeaders},
+			Response: TrafficResponse{Headers: map[string]string{"set-cookie": "session=1"}},
+		})
+
+		entry := service.GetAll()[0]
+
+		if entry.Request.Headers["Authorization"] != RedactedValue || entry.Request.Headers["Accept"] != "*/*" {
+			t.Errorf("unexpected request headers %v", entry.Request.Headers)
+		}
+
+		if entry.Response.Headers["set-cookie"] != RedactedValue {
+			t.Errorf("unexpected response headers %v", entry.Response.Headers)
+		}
+
+		if requestHeaders["Authorization"] != "Bearer secret" {
+			t.Error("expected the captured headers to be left untouched")
+		}
+	})
+}
+
+func TestTrafficLogService_Get(t *testing.T) {
+	service := newTestService(2)
+
+	for _, uuid := range []string{"a", "b", "c"} {
+		service.Capture(TrafficEntry{UUID: uuid})
+	}
+
+	if entry, exists := service.Get("c"); !exists || entry.UUID != "c" {
+		t.Errorf("expected entry c, got %+v", entry)
+	}
+
+	if _, exists := service.Get("a"); exists {
+		t.Error("expected the evicted entry not to be found")
+	}
+
+	if _, exists := newTestService(0).Get("a"); exists {
+		t.Error("expected no entry when disabled")
+	}
+}
+
+func TestTrafficLogService_MaxBodySize(t *testing.T) {
+	service := NewTrafficLogService(&config.AppArguments{TrafficLogBufferSize: 10, TrafficLogMaxBodySize: -1})
+
+	if size := service.MaxBodySize(); size != 0 {
+		t.Errorf("expected a negative size to keep no body, got %d", size)
+	}
+
+	if size := newTestService(0).MaxBodySize(); size != 0 {
+		t.Errorf("expected 0 when disabled, got %d", size)
+	}
 }
 
 func TestTrafficLogService_GetAll(t *testing.T) {
@@ -182,6 +245,17 @@ func TestTrafficLogService_GetFiltered(t *testing.T) {
 		}
 	})
 
+	t.Run("combines host and free text filters", func(t *testing.T) {
+		entries := service.GetFiltered(&TrafficFilters{
+			Hosts: []string{"example.com"},
+			Text:  "EXAMPLE",
+		})
+
+		if len(entries) != 2 {
+			t.Errorf("expected 2 entries matching example.com AND the text, got %d", len(entries))
+		}
+	})
+
 	t.Run("returns all when nil filter", func(t *testing.T) {
 		entries := service.GetFiltered(nil)
 
@@ -274,6 +348,33 @@ func TestTrafficLogService_Subscribe(t *testing.T) {
 		}
 	})
 
+	t.Run("subscriber with request filters skips other entries", func(t *testing.T) {
+		service := newTestService(10)
+
+		filter := &TrafficFilters{PathGlob: "/orders/*", Metadata: map[string]string{"Simulated Status": "true"}}
+		ch := service.Subscribe("request-subscriber", filter)
+		defer service.Unsubscribe("request-subscriber")
+
+		service.Capture(TrafficEntry{
+			UUID:    "not-simulated",
+			Request: TrafficRequest{Path: "/orders/1"},
+		})
+		service.Capture(TrafficEntry{
+			UUID:     "simulated",
+			Request:  TrafficRequest{Path: "/orders/2"},
+			Metadata: map[string]string{"Simulated Status": "true"},
+		})
+
+		select {
+		case received := <-ch:
+			if received.UUID != "simulated" {
+				t.Errorf("expected UUID 'simulated', got '%s'", received.UUID)
+			}
+		case <-time.After(100 * time.Millisecond):
+			t.Error("timeout waiting for matching entry")
+		}
+	})
+
 	t.Run("returns nil when disabled", func(t *testing.T) {
 		service := newTestService(0)
 
@@ -351,6 +452,189 @@ func TestTrafficFilters(t *testing.T) {
 			t.Error("expected IsEmpty to return false")
 		}
 	})
+
+	t.Run("IsEmpty returns false when path prefix is set", func(t *testing.T) {
+		filters := TrafficFilters{PathPrefix: "/api"}
+
+		if filters.IsEmpty() {
+			t.Error("expected IsEmpty to return false")
+		}
+	})
+}
+
+func TestTrafficFilters_Matches(t *testing.T) {
+	now := time.Now()
+	before := now.Add(-time.Minute)
+	after := now.Add(time.Minute)
+
+	fast := 100 * time.Millisecond
+	slow := time.Second
+
+	entry := TrafficEntry{
+		Timestamp: now,
+		Request: TrafficRequest{
+			Method:  "POST",
+			Host:    "example.com",
+			Path:    "/api/users",
+			Query:   "role=admin&page=2",
+			Headers: map[string]string{"X-Client": "billing-job"},
+			Body:    `{"name":"Ada"}`,
+		},
+		Response: TrafficResponse{StatusCode: 201, Body: `{"id":42}`, LatencyMs: 250},
+		Metadata: map[string]string{"Matched": "true", "Simulated Status": "true"},
+	}
+
+	tests := []struct {
+		name    string
+		filters TrafficFilters
+		want    bool
+	}{
+		{name: "methods", filters: TrafficFilters{Methods: []string{"get", "post"}}, want: true},
+		{name: "other methods", filters: TrafficFilters{Methods: []string{"GET"}}, want: false},
+		{name: "path prefix", filters: TrafficFilters{PathPrefix: "/api/"}, want: true},
+		{name: "other path prefix", filters: TrafficFilters{PathPrefix: "/admin"}, want: false},
+		{name: "time range", filters: TrafficFilters{Since: &before, Until: &after}, want: true},
+		{name: "since the entry", filters: TrafficFilters{Since: &now}, want: true},
+		{name: "until the entry", filters: TrafficFilters{Until: &now}, want: false},
+		{name: "after the entry", filters: TrafficFilters{Since: &after}, want: false},
+		{name: "path glob", filters: TrafficFilters{PathGlob: "/api/*"}, want: true},
+		{name: "other path glob", filters: TrafficFilters{PathGlob: "/*"}, want: false},
+		{name: "path regex", filters: TrafficFilters{PathRegex: regexp.MustCompile("^/api/(users|orders)$")}, want: true},
+		{name: "other path regex", filters: TrafficFilters{PathRegex: regexp.MustCompile("orders")}, want: false},
+		{name: "query", filters: TrafficFilters{QueryContains: "role=admin"}, want: true},
+		{name: "other query", filters: TrafficFilters{QueryContains: "role=user"}, want: false},
+		{name: "latency range", filters: TrafficFilters{MinLatency: &fast, MaxLatency: &slow}, want: true},
+		{name: "faster latency", filters: TrafficFilters{MaxLatency: &fast}, want: false},
+		{name: "slower latency", filters: TrafficFilters{MinLatency: &slow}, want: false},
+		{name: "metadata", filters: TrafficFilters{Metadata: map[string]string{"Simulated Status": "TRUE", "Matched": "true"}}, want: true},
+		{name: "other metadata", filters: TrafficFilters{Metadata: map[string]string{"Simulated Status": "false"}}, want: false},
+		{name: "missing metadata", filters: TrafficFilters{Metadata: map[string]string{"Recorded": "true"}}, want: false},
+		{name: "text in the path", filters: TrafficFilters{Text: "USERS"}, want: true},
+		{name: "text in a header", filters: TrafficFilters{Text: "billing"}, want: true},
+		{name: "text in the request body", filters: TrafficFilters{Text: "ada"}, want: true},
+		{name: "text in the response body", filters: TrafficFilters{Text: `"id":42`}, want: true},
+		{name: "text nowhere", filters: TrafficFilters{Text: "grace"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filters.Matches(entry); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTrafficLogService_Query(t *testing.T) {
+	capture := func(service *TrafficLogService, uuids ...string) {
+		for _, uuid := range uuids {
+			entry := NewTrafficEntry(uuid)
+			entry.Request = TrafficRequest{Method: "GET", Host: "example.com", Path: "/" + uuid}
+			service.Capture(*entry)
+		}
+	}
+
+	t.Run("pages through the buffer", func(t *testing.T) {
+		service := newTestService(10)
+		capture(service, "a", "b", "c")
+
+		page, err := service.Query(TrafficQuery{Limit: 2})
+
+		if err != nil || len(page.Entries) != 2 || page.Entries[0].UUID != "c" || page.Next != "b" {
+			t.Fatalf("unexpected page %+v, error %v", page, err)
+		}
+
+		page, err = service.Query(TrafficQuery{Before: page.Next, Limit: 2})
+
+		if err != nil || len(page.Entries) != 1 || page.Entries[0].UUID != "a" || page.Next != "" {
+			t.Errorf("unexpected page %+v, error %v", page, err)
+		}
+	})
+
+	t.Run("queries the store across restarts", func(t *testing.T) {
+		args := &config.AppArguments{
+			TrafficLogBufferSize:  2,
+			TrafficLogDirectory:   t.TempDir(),
+			TrafficLogSegmentSize: 1024,
+			TrafficLogMaxSegments: 10,
+		}
+
+		service := NewTrafficLogService(args)
+		capture(service, "a", "b", "c")
+		service.Close()
+
+		restarted := NewTrafficLogService(args)
+		defer restarted.Close()
+		capture(restarted, "d")
+
+		page, err := restarted.Query(TrafficQuery{Filters: &TrafficFilters{PathPrefix: "/"}})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var uuids []string
+
+		for _, entry := range page.Entries {
+			uuids = append(uuids, entry.UUID)
+		}
+
+		if strings.Join(uuids, ",") != "d,c,b,a" {
+			t.Errorf("expected the entries beyond the buffer and the restart, got %v", uuids)
+		}
+	})
+
+	t.Run("writes the queued entries when closed", func(t *testing.T) {
+		args := &config.AppArguments{
+			TrafficLogBufferSize:  10,
+			TrafficLogDirectory:   t.TempDir(),
+			TrafficLogSegmentSize: 1024 * 1024,
+		}
+
+		service := NewTrafficLogService(args)
+		capture(service, "a", "b", "c")
+
+		if err := service.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// closing again, or capturing once closed, is harmless
+		if err := service.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		capture(service, "d")
+
+		if service.Size() != 4 {
+			t.Errorf("expected the entries to be kept in memory, got %d", service.Size())
+		}
+
+		store, err := NewFileTrafficStore(args.TrafficLogDirectory, args.TrafficLogSegmentSize, 0)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		defer store.Close()
+
+		count := 0
+
+		for range store.Entries(nil, nil) {
+			count++
+		}
+
+		if count != 3 {
+			t.Errorf("expected the 3 entries captured before closing to be written, got %d", count)
+		}
+	})
+
+	t.Run("returns an empty page when disabled", func(t *testing.T) {
+		page, err := newTestService(0).Query(TrafficQuery{})
+
+		if err != nil || len(page.Entries) != 0 {
+			t.Errorf("unexpected page %+v, error %v", page, err)
+		}
+	})
 }
 
 func TestTrafficFilters_Validate(t *testing.T) {
@@ -438,4 +722,56 @@ func TestTrafficFilters_Validate(t *testing.T) {
 			t.Errorf("expected localhost to pass validation, got %v", err)
 		}
 	})
+
+	t.Run("empty method fails validation", func(t *testing.T) {
+		filters := TrafficFilters{Methods: []string{"GET", " "}}
+
+		if err := filters.Validate(); err == nil {
+			t.Error("expected error for empty method")
+		}
+	})
+
+	t.Run("invalid path glob fails validation", func(t *testing.T) {
+		filters := TrafficFilters{PathGlob: "/api/["}
+
+		if err := filters.Validate(); err == nil {
+			t.Error("expected error for invalid path glob")
+		}
+	})
+
+	t.Run("negative latency fails validation", func(t *testing.T) {
+		latency := -time.Second
+		filters := TrafficFilters{MinLatency: &latency}
+
+		if err := filters.Validate(); err == nil {
+			t.Error("expected error for negative latency")
+		}
+	})
+
+	t.Run("inverted latency range fails validation", func(t *testing.T) {
+		minLatency, maxLatency := time.Second, time.Millisecond
+		filters := TrafficFilters{MinLatency: &minLatency, MaxLatency: &maxLatency}
+
+		if err := filters.Validate(); err == nil {
+			t.Error("expected error for inverted latency range")
+		}
+	})
+
+	t.Run("empty metadata key fails validation", func(t *testing.T) {
+		filters := TrafficFilters{Metadata: map[string]string{" ": "true"}}
+
+		if err := filters.Validate(); err == nil {
+			t.Error("expected error for empty metadata key")
+		}
+	})
+
+	t.Run("inverted time range fails validation", func(t *testing.T) {
+		since := time.Now()
+		until := since.Add(-time.Minute)
+		filters := TrafficFilters{Since: &since, Until: &until}
+
+		if err := filters.Validate(); err == nil {
+			t.Error("expected error for inverted time range")
+		}
+	})
 }
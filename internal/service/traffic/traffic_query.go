@@ -0,0 +1,68 @@
+package traffic
+
+import (
+	"errors"
+	"iter"
+)
+
+const (
+	// DefaultQueryLimit is the number of entries in a page when no limit is given
+	DefaultQueryLimit = 100
+	// MaxQueryLimit is the maximum number of entries in a page
+	MaxQueryLimit = 1000
+)
+
+// ErrUnknownCursor tells that the entry a page should start after isn't in the log anymore
+var ErrUnknownCursor = errors.New("unknown cursor")
+
+// TrafficQuery selects a page of entries, from the most recent to the oldest
+type TrafficQuery struct {
+	Filters *TrafficFilters
+	// Before is the UUID of the last entry of the previous page, empty for the first page
+	Before string
+	Limit  int
+}
+
+// TrafficPage is a page of entries, along with the cursor of the next page, empty for the last one
+type TrafficPage struct {
+	Entries []TrafficEntry `json:"entries"`
+	Next    string         `json:"next,omitempty"`
+}
+
+// page collects the page of the query from the entries, given from the most recent to the oldest
+func (q TrafficQuery) page(entries iter.Seq[TrafficEntry]) (*TrafficPage, error) {
+	limit := q.Limit
+
+	if limit <= 0 {
+		limit = DefaultQueryLimit
+	}
+
+	limit = min(limit, MaxQueryLimit)
+	page := &TrafficPage{Entries: make([]TrafficEntry, 0)}
+	started := len(q.Before) == 0
+
+	for entry := range entries {
+		if !started {
+			started = entry.UUID == q.Before
+			continue
+		}
+
+		if q.Filters != nil && !q.Filters.Matches(entry) {
+			continue
+		}
+
+		// one more entry than the limit tells there is a next page
+		if len(page.Entries) == limit {
+			page.Next = page.Entries[limit-1].UUID
+			break
+		}
+
+		page.Entries = append(page.Entries, entry)
+	}
+
+	if !started {
+		return nil, ErrUnknownCursor
+	}
+
+	return page, nil
+}
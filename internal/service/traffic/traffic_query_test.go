@@ -0,0 +1,71 @@
+package traffic
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"testing"
+)
+
+func newQueryEntries(n int) iter.Seq[TrafficEntry] {
+	return func(yield func(TrafficEntry) bool) {
+		for i := n; i > 0; i-- {
+			entry := TrafficEntry{UUID: fmt.Sprintf("entry-%d", i)}
+			entry.Response.StatusCode = 200
+
+			if i%2 == 0 {
+				entry.Response.StatusCode = 404
+			}
+
+			if !yield(entry) {
+				return
+			}
+		}
+	}
+}
+
+func TestTrafficQuery_page(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     TrafficQuery
+		wantFirst string
+		wantLen   int
+		wantNext  string
+		wantErr   error
+	}{
+		{name: "first page", query: TrafficQuery{Limit: 3}, wantFirst: "entry-10", wantLen: 3, wantNext: "entry-8"},
+		{name: "next page", query: TrafficQuery{Before: "entry-8", Limit: 3}, wantFirst: "entry-7", wantLen: 3, wantNext: "entry-5"},
+		{name: "last page", query: TrafficQuery{Before: "entry-3", Limit: 3}, wantFirst: "entry-2", wantLen: 2},
+		{name: "exact last page", query: TrafficQuery{Before: "entry-4", Limit: 3}, wantFirst: "entry-3", wantLen: 3},
+		{name: "default limit", query: TrafficQuery{}, wantFirst: "entry-10", wantLen: 10},
+		{name: "filtered", query: TrafficQuery{Filters: &TrafficFilters{StatusCodes: []int{404}}, Limit: 2}, wantFirst: "entry-10", wantLen: 2, wantNext: "entry-8"},
+		{name: "filtered next page", query: TrafficQuery{Filters: &TrafficFilters{StatusCodes: []int{404}}, Before: "entry-8", Limit: 2}, wantFirst: "entry-6", wantLen: 2, wantNext: "entry-4"},
+		{name: "unknown cursor", query: TrafficQuery{Before: "entry-42"}, wantErr: ErrUnknownCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := tt.query.page(newQueryEntries(10))
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if len(page.Entries) != tt.wantLen || page.Entries[0].UUID != tt.wantFirst || page.Next != tt.wantNext {
+				t.Errorf("unexpected page %+v", page)
+			}
+		})
+	}
+
+	t.Run("caps the limit", func(t *testing.T) {
+		page, err := TrafficQuery{Limit: MaxQueryLimit + 1}.page(newQueryEntries(MaxQueryLimit + 5))
+
+		if err != nil || len(page.Entries) != MaxQueryLimit {
+			t.Errorf("expected %d entries, got %d, error %v", MaxQueryLimit, len(page.Entries), err)
+		}
+	})
+}
@@ -0,0 +1,278 @@
+package traffic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	segmentPrefix    = "traffic-"
+	segmentExtension = ".jsonl"
+)
+
+// TrafficStore keeps the traffic entries beyond the in-memory buffer, e.g. across restarts
+type TrafficStore interface {
+	// Append keeps the entry
+	Append(entry TrafficEntry) error
+	// Entries returns the entries kept whose time may be in the given range, from the most recent to the oldest.
+	// A nil bound leaves the range open on its side.
+	Entries(since, until *time.Time) iter.Seq[TrafficEntry]
+	// Close releases the resources held by the store
+	Close() error
+}
+
+// FileTrafficStore keeps the traffic entries in append-only JSONL segments. A new segment is started once the
+// current one reaches the segment size, and the oldest ones are deleted to keep at most maxSegments.
+type FileTrafficStore struct {
+	mu          sync.Mutex
+	directory   string
+	segmentSize int64
+	maxSegments int
+	current     *os.File
+	currentSeq  int
+	currentSize int64
+}
+
+// NewFileTrafficStore opens the store kept in the directory, appending to its most recent segment
+func NewFileTrafficStore(directory string, segmentSize int64, maxSegments int) (*FileTrafficStore, error) {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, fmt.Errorf("error while creating the traffic log directory: %v", err)
+	}
+
+	store := &FileTrafficStore{
+		directory:   directory,
+		segmentSize: segmentSize,
+		maxSegments: maxSegments,
+	}
+
+	segments, err := store.segments()
+
+	if err != nil {
+		return nil, err
+	}
+
+	seq := 1
+
+	if len(segments) > 0 {
+		seq = segments[len(segments)-1]
+	}
+
+	if err := store.openSegment(seq); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("directory", directory).
+		Int("segment", seq).
+		Msg("traffic log store opened")
+
+	return store, nil
+}
+
+// Append writes the entry at the end of the current segment, starting a new segment first when it is full
+func (f *FileTrafficStore) Append(entry TrafficEntry) error {
+	data, err := json.Marshal(entry)
+
+	if err != nil {
+		return fmt.Errorf("error while encoding the traffic entry: %v", err)
+	}
+
+	data = append(data, '\n')
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.current == nil {
+		return fmt.Errorf("the traffic log store is closed")
+	}
+
+	if f.segmentSize > 0 && f.currentSize > 0 && f.currentSize+int64(len(data)) > f.segmentSize {
+		if err := f.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := f.current.Write(data)
+	f.currentSize += int64(n)
+
+	if err != nil {
+		return fmt.Errorf("error while writing the traffic entry: %v", err)
+	}
+
+	return nil
+}
+
+// Entries reads the segments from the most recent to the oldest, skipping the ones last written before since, as
+// they can't hold entries received after it. The lines that can't be decoded, such as one partially written when
+// the server stopped, are skipped.
+func (f *FileTrafficStore) Entries(since, until *time.Time) iter.Seq[TrafficEntry] {
+	return func(yield func(TrafficEntry) bool) {
+		f.mu.Lock()
+		segments, err := f.segments()
+		f.mu.Unlock()
+
+		if err != nil {
+			log.Warn().
+				Str("directory", f.directory).
+				Msgf("error while listing the traffic log segments: %v", err)
+
+			return
+		}
+
+		for i := len(segments) - 1; i >= 0; i-- {
+			path := f.segmentPath(segments[i])
+
+			if since != nil {
+				if info, err := os.Stat(path); err == nil && info.ModTime().Before(*since) {
+					return
+				}
+			}
+
+			data, err := os.ReadFile(path)
+
+			if err != nil {
+				// the segment may have been deleted by a rotation in the meantime
+				continue
+			}
+
+			lines := bytes.Split(data, []byte{'\n'})
+
+			for j := len(lines) - 1; j >= 0; j-- {
+				if len(lines[j]) == 0 {
+					continue
+				}
+
+				var entry TrafficEntry
+
+				if err := json.Unmarshal(lines[j], &entry); err != nil {
+					continue
+				}
+
+				if until != nil && !entry.Timestamp.Before(*until) {
+					continue
+				}
+
+				if !yield(entry) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Close closes the current segment
+func (f *FileTrafficStore) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.current == nil {
+		return nil
+	}
+
+	err := f.current.Close()
+	f.current = nil
+
+	return err
+}
+
+func (f *FileTrafficStore) rotate() error {
+	if err := f.current.Close(); err != nil {
+		log.Warn().
+			Str("directory", f.directory).
+			Msgf("error while closing the traffic log segment: %v", err)
+	}
+
+	if err := f.openSegment(f.currentSeq + 1); err != nil {
+		f.current = nil
+		return err
+	}
+
+	if f.maxSegments <= 0 {
+		return nil
+	}
+
+	segments, err := f.segments()
+
+	if err != nil {
+		return err
+	}
+
+	for len(segments) > f.maxSegments {
+		if err := os.Remove(f.segmentPath(segments[0])); err != nil {
+			log.Warn().
+				Str("directory", f.directory).
+				Msgf("error while deleting the oldest traffic log segment: %v", err)
+		}
+
+		segments = segments[1:]
+	}
+
+	return nil
+}
+
+func (f *FileTrafficStore) openSegment(seq int) error {
+	file, err := os.OpenFile(f.segmentPath(seq), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+
+	if err != nil {
+		return fmt.Errorf("error while opening the traffic log segment: %v", err)
+	}
+
+	info, err := file.Stat()
+
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("error while opening the traffic log segment: %v", err)
+	}
+
+	f.current = file
+	f.currentSeq = seq
+	f.currentSize = info.Size()
+
+	return nil
+}
+
+// segments returns the sequence numbers of the segments in the directory, from the oldest to the most recent
+func (f *FileTrafficStore) segments() ([]int, error) {
+	dirEntries, err := os.ReadDir(f.directory)
+
+	if err != nil {
+		return nil, fmt.Errorf("error while listing the traffic log segments: %v", err)
+	}
+
+	var segments []int
+
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+
+		if dirEntry.IsDir() || !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentExtension) {
+			continue
+		}
+
+		seq, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentExtension))
+
+		if err != nil || seq <= 0 {
+			continue
+		}
+
+		segments = append(segments, seq)
+	}
+
+	sort.Ints(segments)
+
+	return segments, nil
+}
+
+func (f *FileTrafficStore) segmentPath(seq int) string {
+	return filepath.Join(f.directory, fmt.Sprintf("%s%06d%s", segmentPrefix, seq, segmentExtension))
+}
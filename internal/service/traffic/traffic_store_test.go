@@ -0,0 +1,171 @@
+package traffic
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func storedUUIDs(store TrafficStore, since, until *time.Time) string {
+	var uuids []string
+
+	for entry := range store.Entries(since, until) {
+		uuids = append(uuids, entry.UUID)
+	}
+
+	return strings.Join(uuids, ",")
+}
+
+func appendEntries(t *testing.T, store TrafficStore, timestamp time.Time, uuids ...string) {
+	t.Helper()
+
+	for i, uuid := range uuids {
+		entry := TrafficEntry{UUID: uuid, Timestamp: timestamp.Add(time.Duration(i) * time.Second)}
+
+		if err := store.Append(entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestFileTrafficStore(t *testing.T) {
+	now := time.Now()
+
+	t.Run("returns the entries from the most recent", func(t *testing.T) {
+		store, err := NewFileTrafficStore(t.TempDir(), 1<<20, 0)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		defer store.Close()
+		appendEntries(t, store, now, "a", "b", "c")
+
+		if got := storedUUIDs(store, nil, nil); got != "c,b,a" {
+			t.Errorf("expected c,b,a, got %s", got)
+		}
+	})
+
+	t.Run("rotates the segments and deletes the oldest", func(t *testing.T) {
+		directory := t.TempDir()
+		store, err := NewFileTrafficStore(directory, 200, 2)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		defer store.Close()
+		appendEntries(t, store, now, "a", "b", "c", "d", "e", "f")
+
+		segments, _ := filepath.Glob(filepath.Join(directory, "traffic-*.jsonl"))
+
+		if len(segments) != 2 {
+			t.Fatalf("expected 2 segments, got %v", segments)
+		}
+
+		got := storedUUIDs(store, nil, nil)
+
+		if !strings.HasPrefix(got, "f,e") || strings.HasSuffix(got, "a") {
+			t.Errorf("expected the most recent entries only, got %s", got)
+		}
+	})
+
+	t.Run("continues the last segment when reopened", func(t *testing.T) {
+		directory := t.TempDir()
+		store, err := NewFileTrafficStore(directory, 1<<20, 0)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		appendEntries(t, store, now, "a", "b")
+		store.Close()
+
+		if err := store.Append(TrafficEntry{UUID: "closed"}); err == nil {
+			t.Error("expected an error when appending to a closed store")
+		}
+
+		reopened, err := NewFileTrafficStore(directory, 1<<20, 0)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		defer reopened.Close()
+		appendEntries(t, reopened, now, "c")
+
+		segments, _ := filepath.Glob(filepath.Join(directory, "traffic-*.jsonl"))
+
+		if len(segments) != 1 {
+			t.Errorf("expected 1 segment, got %v", segments)
+		}
+
+		if got := storedUUIDs(reopened, nil, nil); got != "c,b,a" {
+			t.Errorf("expected c,b,a, got %s", got)
+		}
+	})
+
+	t.Run("skips the lines that can't be decoded", func(t *testing.T) {
+		directory := t.TempDir()
+		content := `{"uuid":"a"}` + "\nnot json\n" + `{"uuid":"b"}` + "\n" + `{"uuid":"c","times`
+
+		if err := os.WriteFile(filepath.Join(directory, "traffic-000001.jsonl"), []byte(content), 0644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		store, err := NewFileTrafficStore(directory, 1<<20, 0)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		defer store.Close()
+
+		if got := storedUUIDs(store, nil, nil); got != "b,a" {
+			t.Errorf("expected b,a, got %s", got)
+		}
+	})
+
+	t.Run("returns the entries before until", func(t *testing.T) {
+		store, err := NewFileTrafficStore(t.TempDir(), 1<<20, 0)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		defer store.Close()
+		appendEntries(t, store, now, "a", "b", "c")
+		until := now.Add(2 * time.Second)
+
+		if got := storedUUIDs(store, nil, &until); got != "b,a" {
+			t.Errorf("expected b,a, got %s", got)
+		}
+	})
+
+	t.Run("skips the segments last written before since", func(t *testing.T) {
+		directory := t.TempDir()
+		store, err := NewFileTrafficStore(directory, 100, 0)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		defer store.Close()
+		appendEntries(t, store, now.Add(-time.Hour), "a")
+		appendEntries(t, store, now, "b")
+
+		old := now.Add(-time.Hour)
+
+		if err := os.Chtimes(filepath.Join(directory, "traffic-000001.jsonl"), old, old); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		since := now.Add(-time.Minute)
+
+		if got := storedUUIDs(store, &since, nil); got != "b" {
+			t.Errorf("expected b, got %s", got)
+		}
+	})
+}
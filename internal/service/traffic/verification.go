@@ -0,0 +1,334 @@
+package traffic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// maxNearMisses is the number of closest mismatching entries returned along with a failed verification
+const maxNearMisses = 3
+
+// waitSubscriberSeq makes the subscriber IDs of concurrent waits unique
+var waitSubscriberSeq atomic.Uint64
+
+// RequestPattern describes the requests a test expects the mock server to have received. Empty fields match any
+// request.
+type RequestPattern struct {
+	Method       string            `json:"method,omitempty"`
+	Host         string            `json:"host,omitempty"`
+	Path         string            `json:"path,omitempty"`
+	PathRegex    string            `json:"path_regex,omitempty"`
+	Query        map[string]string `json:"query,omitempty"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	BodyContains string            `json:"body_contains,omitempty"`
+	StatusCode   int               `json:"status_code,omitempty"`
+
+	pathRegex *regexp.Regexp
+}
+
+// CountExpectation is how many requests matching a pattern a test expects. When no field is set, at least one
+// request is expected.
+type CountExpectation struct {
+	Exactly *int `json:"exactly,omitempty"`
+	AtLeast *int `json:"at_least,omitempty"`
+	AtMost  *int `json:"at_most,omitempty"`
+}
+
+// NearMiss is an entry that doesn't match a pattern, along with what differs. BodyTruncated tells that the body of
+// the request was truncated in the log, so its expected content may be in the part that wasn't kept.
+type NearMiss struct {
+	Entry         TrafficEntry `json:"entry"`
+	Differences   []string     `json:"differences"`
+	BodyTruncated bool         `json:"body_truncated,omitempty"`
+}
+
+// VerificationResult tells whether the requests received meet an expectation
+type VerificationResult struct {
+	Passed     bool       `json:"passed"`
+	Count      int        `json:"count"`
+	Expected   string     `json:"expected"`
+	NearMisses []NearMiss `json:"near_misses,omitempty"`
+}
+
+// Compile validates the pattern and prepares it for matching. It must be called before the pattern is used.
+func (p *RequestPattern) Compile() error {
+	if p.StatusCode != 0 && (p.StatusCode < 100 || p.StatusCode > 599) {
+		return fmt.Errorf("invalid status code %d: must be between 100 and 599", p.StatusCode)
+	}
+
+	if len(p.Path) > 0 && len(p.PathRegex) > 0 {
+		return fmt.Errorf("path and path_regex can't be used together")
+	}
+
+	if len(p.PathRegex) > 0 {
+		pathRegex, err := regexp.Compile(p.PathRegex)
+
+		if err != nil {
+			return fmt.Errorf("invalid path_regex: %v", err)
+		}
+
+		p.pathRegex = pathRegex
+	}
+
+	return nil
+}
+
+// Matches returns true if the entry matches every field of the pattern
+func (p *RequestPattern) Matches(entry TrafficEntry) bool {
+	return len(p.differences(entry)) == 0
+}
+
+// differences returns what differs between the pattern and the entry
+func (p *RequestPattern) differences(entry TrafficEntry) []string {
+	var differences []string
+
+	if len(p.Method) > 0 && !strings.EqualFold(p.Method, entry.Request.Method) {
+		differences = append(differences, fmt.Sprintf("method: expected %s, got %s", strings.ToUpper(p.Method), entry.Request.Method))
+	}
+
+	if len(p.Host) > 0 && !strings.EqualFold(p.Host, entry.Request.Host) {
+		differences = append(differences, fmt.Sprintf("host: expected %s, got %s", p.Host, entry.Request.Host))
+	}
+
+	if len(p.Path) > 0 && p.Path != entry.Request.Path {
+		differences = append(differences, fmt.Sprintf("path: expected %s, got %s", p.Path, entry.Request.Path))
+	}
+
+	if p.pathRegex != nil && !p.pathRegex.MatchString(entry.Request.Path) {
+		differences = append(differences, fmt.Sprintf("path: expected to match %s, got %s", p.PathRegex, entry.Request.Path))
+	}
+
+	if len(p.Query) > 0 {
+		query, _ := url.ParseQuery(entry.Request.Query)
+
+		for _, key := range sortedKeys(p.Query) {
+			if values, exists := query[key]; !exists || !contains(values, p.Query[key]) {
+				differences = append(differences, fmt.Sprintf("query parameter %q: expected %q, got %q", key, p.Query[key], strings.Join(values, ",")))
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(p.Headers) {
+		if value := headerValue(entry.Request.Headers, key); value != p.Headers[key] {
+			differences = append(differences, fmt.Sprintf("header %q: expected %q, got %q", key, p.Headers[key], value))
+		}
+	}
+
+	if len(p.BodyContains) > 0 {
+		body, err := DecodeBody(entry.Request.Body, entry.Request.BodyEncoding)
+
+		if err != nil || !strings.Contains(string(body), p.BodyContains) {
+			difference := fmt.Sprintf("body: expected to contain %q", p.BodyContains)
+
+			if entry.Request.BodyTruncated {
+				difference += ", not found in the part of the body kept in the log"
+			}
+
+			differences = append(differences, difference)
+		}
+	}
+
+	if p.StatusCode != 0 && p.StatusCode != entry.Response.StatusCode {
+		differences = append(differences, fmt.Sprintf("status code: expected %d, got %d", p.StatusCode, entry.Response.StatusCode))
+	}
+
+	return differences
+}
+
+// Validate checks that the bounds are consistent
+func (e CountExpectation) Validate() error {
+	for name, bound := range map[string]*int{"exactly": e.Exactly, "at_least": e.AtLeast, "at_most": e.AtMost} {
+		if bound != nil && *bound < 0 {
+			return fmt.Errorf("invalid %s: must not be negative", name)
+		}
+	}
+
+	if e.Exactly != nil && (e.AtLeast != nil || e.AtMost != nil) {
+		return fmt.Errorf("exactly can't be used along with at_least or at_most")
+	}
+
+	if e.AtLeast != nil && e.AtMost != nil && *e.AtLeast > *e.AtMost {
+		return fmt.Errorf("at_least can't be greater than at_most")
+	}
+
+	return nil
+}
+
+// Met returns true if the count meets the expectation
+func (e CountExpectation) Met(count int) bool {
+	if e.Exactly != nil {
+		return count == *e.Exactly
+	}
+
+	if e.AtLeast == nil && e.AtMost == nil {
+		return count >= 1
+	}
+
+	return (e.AtLeast == nil || count >= *e.AtLeast) && (e.AtMost == nil || count <= *e.AtMost)
+}
+
+// String describes the expectation, e.g. "exactly 2"
+func (e CountExpectation) String() string {
+	switch {
+	case e.Exactly != nil:
+		return fmt.Sprintf("exactly %d", *e.Exactly)
+	case e.AtLeast != nil && e.AtMost != nil:
+		return fmt.Sprintf("between %d and %d", *e.AtLeast, *e.AtMost)
+	case e.AtLeast != nil:
+		return fmt.Sprintf("at least %d", *e.AtLeast)
+	case e.AtMost != nil:
+		return fmt.Sprintf("at most %d", *e.AtMost)
+	default:
+		return "at least 1"
+	}
+}
+
+// CompilePattern compiles the pattern, rejecting the headers whose values are redacted in the log, as they could
+// never be matched.
+func (t *TrafficLogService) CompilePattern(pattern *RequestPattern) error {
+	if err := pattern.Compile(); err != nil {
+		return err
+	}
+
+	for _, key := range sortedKeys(pattern.Headers) {
+		if t.redactedHeaders[http.CanonicalHeaderKey(key)] {
+			return fmt.Errorf("header %q can't be matched, as its values are redacted in the traffic log", key)
+		}
+	}
+
+	return nil
+}
+
+// Count returns the number of entries in the buffer matching the pattern.
+func (t *TrafficLogService) Count(pattern *RequestPattern) int {
+	count := 0
+
+	for _, entry := range t.GetAll() {
+		if pattern.Matches(entry) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Verify checks that the number of entries in the buffer matching the pattern meets the expectation. When it
+// doesn't, the entries closest to the pattern are returned, to tell what the requests looked like instead.
+func (t *TrafficLogService) Verify(pattern *RequestPattern, expectation CountExpectation) VerificationResult {
+	var nearMisses []NearMiss
+	count := 0
+
+	for _, entry := range t.GetAll() {
+		if differences := pattern.differences(entry); len(differences) == 0 {
+			count++
+		} else {
+			nearMisses = append(nearMisses, NearMiss{
+				Entry:         entry,
+				Differences:   differences,
+				BodyTruncated: len(pattern.BodyContains) > 0 && entry.Request.BodyTruncated,
+			})
+		}
+	}
+
+	result := VerificationResult{
+		Passed:   expectation.Met(count),
+		Count:    count,
+		Expected: expectation.String(),
+	}
+
+	if !result.Passed {
+		result.NearMisses = closestNearMisses(nearMisses)
+	}
+
+	return result
+}
+
+// WaitFor waits until the buffer holds at least n entries matching the pattern, or the context is done. It returns
+// the number of matching entries, and whether they were enough.
+func (t *TrafficLogService) WaitFor(ctx context.Context, pattern *RequestPattern, n int) (int, bool) {
+	if t == nil {
+		return 0, n <= 0
+	}
+
+	// subscribing before looking at the buffer, so no entry is missed in between
+	subscriberID := fmt.Sprintf("wait-%d", waitSubscriberSeq.Add(1))
+	ch := t.Subscribe(subscriberID, nil)
+	defer t.Unsubscribe(subscriberID)
+
+	// an entry captured while subscribing may be both in the buffer and sent on the channel
+	seen := make(map[string]bool)
+
+	for _, entry := range t.GetAll() {
+		if pattern.Matches(entry) {
+			seen[entry.UUID] = true
+		}
+	}
+
+	for len(seen) < n {
+		select {
+		case <-ctx.Done():
+			return len(seen), false
+		case entry, ok := <-ch:
+			if !ok {
+				return len(seen), false
+			}
+
+			if pattern.Matches(entry) {
+				seen[entry.UUID] = true
+			}
+		}
+	}
+
+	return len(seen), true
+}
+
+// closestNearMisses returns the near misses with the fewest differences, the most recent first on a tie
+func closestNearMisses(nearMisses []NearMiss) []NearMiss {
+	for i, j := 0, len(nearMisses)-1; i < j; i, j = i+1, j-1 {
+		nearMisses[i], nearMisses[j] = nearMisses[j], nearMisses[i]
+	}
+
+	sort.SliceStable(nearMisses, func(i, j int) bool {
+		return len(nearMisses[i].Differences) < len(nearMisses[j].Differences)
+	})
+
+	return nearMisses[:min(len(nearMisses), maxNearMisses)]
+}
+
+func headerValue(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,290 @@
+package traffic
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Caik/go-mock-server/internal/config"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func newOrderEntry(uuid, method, path, body string, statusCode int) TrafficEntry {
+	return TrafficEntry{
+		UUID: uuid,
+		Request: TrafficRequest{
+			Method:  method,
+			Host:    "shop.example.com",
+			Path:    path,
+			Query:   "source=web&tag=a&tag=b",
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    body,
+		},
+		Response: TrafficResponse{StatusCode: statusCode},
+	}
+}
+
+func TestRequestPattern_Compile(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern RequestPattern
+		wantErr bool
+	}{
+		{name: "empty pattern", pattern: RequestPattern{}},
+		{name: "valid regex", pattern: RequestPattern{PathRegex: "^/orders/[0-9]+$"}},
+		{name: "invalid regex", pattern: RequestPattern{PathRegex: "("}, wantErr: true},
+		{name: "path and regex", pattern: RequestPattern{Path: "/orders", PathRegex: "/orders"}, wantErr: true},
+		{name: "invalid status code", pattern: RequestPattern{StatusCode: 42}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.pattern.Compile(); tt.wantErr != (err != nil) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRequestPattern_differences(t *testing.T) {
+	entry := newOrderEntry("a", "POST", "/orders/12", `{"sku":"book"}`, 201)
+
+	tests := []struct {
+		name    string
+		pattern RequestPattern
+		want    []string
+	}{
+		{
+			name:    "matches every field",
+			pattern: RequestPattern{Method: "post", Host: "SHOP.example.com", PathRegex: "^/orders/[0-9]+$", Query: map[string]string{"tag": "b"}, Headers: map[string]string{"content-type": "application/json"}, BodyContains: `"book"`, StatusCode: 201},
+		},
+		{
+			name:    "lists every difference",
+			pattern: RequestPattern{Method: "GET", Path: "/orders", Query: map[string]string{"source": "app", "page": "1"}, BodyContains: "pen", StatusCode: 200},
+			want: []string{
+				"method: expected GET, got POST",
+				"path: expected /orders, got /orders/12",
+				`query parameter "page": expected "1", got ""`,
+				`query parameter "source": expected "app", got "web"`,
+				`body: expected to contain "pen"`,
+				"status code: expected 200, got 201",
+			},
+		},
+		{
+			name:    "checks the headers",
+			pattern: RequestPattern{Headers: map[string]string{"Authorization": "Bearer token"}},
+			want:    []string{`header "Authorization": expected "Bearer token", got ""`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.pattern.Compile(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := tt.pattern.differences(entry)
+
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("expected differences %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("decodes binary bodies", func(t *testing.T) {
+		binary := entry
+		binary.Request.SetBody([]byte("\xffbook"), 1024)
+		pattern := RequestPattern{BodyContains: "book"}
+
+		if !pattern.Matches(binary) {
+			t.Error("expected the decoded body to match")
+		}
+	})
+}
+
+func TestCountExpectation(t *testing.T) {
+	tests := []struct {
+		name        string
+		expectation CountExpectation
+		wantErr     bool
+		wantString  string
+		met         []int
+		notMet      []int
+	}{
+		{name: "default", expectation: CountExpectation{}, wantString: "at least 1", met: []int{1, 5}, notMet: []int{0}},
+		{name: "exactly", expectation: CountExpectation{Exactly: intPtr(2)}, wantString: "exactly 2", met: []int{2}, notMet: []int{1, 3}},
+		{name: "never", expectation: CountExpectation{Exactly: intPtr(0)}, wantString: "exactly 0", met: []int{0}, notMet: []int{1}},
+		{name: "at most", expectation: CountExpectation{AtMost: intPtr(1)}, wantString: "at most 1", met: []int{0, 1}, notMet: []int{2}},
+		{name: "between", expectation: CountExpectation{AtLeast: intPtr(1), AtMost: intPtr(3)}, wantString: "between 1 and 3", met: []int{1, 3}, notMet: []int{0, 4}},
+		{name: "exactly with bounds", expectation: CountExpectation{Exactly: intPtr(1), AtLeast: intPtr(1)}, wantErr: true},
+		{name: "inverted bounds", expectation: CountExpectation{AtLeast: intPtr(3), AtMost: intPtr(1)}, wantErr: true},
+		{name: "negative", expectation: CountExpectation{AtMost: intPtr(-1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.expectation.Validate(); tt.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got := tt.expectation.String(); got != tt.wantString {
+				t.Errorf("expected %q, got %q", tt.wantString, got)
+			}
+
+			for _, count := range tt.met {
+				if !tt.expectation.Met(count) {
+					t.Errorf("expected %d to meet %s", count, tt.wantString)
+				}
+			}
+
+			for _, count := range tt.notMet {
+				if tt.expectation.Met(count) {
+					t.Errorf("expected %d not to meet %s", count, tt.wantString)
+				}
+			}
+		})
+	}
+}
+
+func TestTrafficLogService_Verify(t *testing.T) {
+	service := newTestService(10)
+	service.Capture(newOrderEntry("a", "POST", "/orders", `{"sku":"book"}`, 201))
+	service.Capture(newOrderEntry("b", "GET", "/orders", "", 200))
+	service.Capture(newOrderEntry("c", "POST", "/orders", `{"sku":"pen"}`, 201))
+	service.Capture(newOrderEntry("d", "GET", "/users", "", 200))
+
+	pattern := &RequestPattern{Method: "POST", Path: "/orders", BodyContains: "book"}
+
+	if err := pattern.Compile(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("passes when the count meets the expectation", func(t *testing.T) {
+		result := service.Verify(pattern, CountExpectation{Exactly: intPtr(1)})
+
+		if !result.Passed || result.Count != 1 || result.Expected != "exactly 1" || result.NearMisses != nil {
+			t.Errorf("unexpected result %+v", result)
+		}
+	})
+
+	t.Run("returns the closest mismatches on failure", func(t *testing.T) {
+		result := service.Verify(pattern, CountExpectation{Exactly: intPtr(2)})
+
+		if result.Passed || result.Count != 1 {
+			t.Fatalf("unexpected result %+v", result)
+		}
+
+		if len(result.NearMisses) != 3 {
+			t.Fatalf("expected 3 near misses, got %+v", result.NearMisses)
+		}
+
+		// c only differs by its body, b by its method and body, and d by its method, path and body
+		for i, uuid := range []string{"c", "b", "d"} {
+			if result.NearMisses[i].Entry.UUID != uuid {
+				t.Errorf("near miss %d: expected %s, got %s", i, uuid, result.NearMisses[i].Entry.UUID)
+			}
+		}
+	})
+
+	t.Run("counts the matching entries", func(t *testing.T) {
+		if count := service.Count(&RequestPattern{Method: "GET"}); count != 2 {
+			t.Errorf("expected 2 entries, got %d", count)
+		}
+	})
+
+	t.Run("flags the near misses whose body was truncated", func(t *testing.T) {
+		service := newTestService(10)
+		truncated := newOrderEntry("a", "POST", "/orders", `{"items":[`, 201)
+		truncated.Request.BodyTruncated = true
+		service.Capture(truncated)
+		service.Capture(newOrderEntry("b", "POST", "/orders", `{"sku":"pen"}`, 201))
+
+		result := service.Verify(pattern, CountExpectation{})
+
+		if result.Passed || len(result.NearMisses) != 2 {
+			t.Fatalf("unexpected result %+v", result)
+		}
+
+		for _, nearMiss := range result.NearMisses {
+			if nearMiss.BodyTruncated != (nearMiss.Entry.UUID == "a") {
+				t.Errorf("near miss %s: unexpected body_truncated %v", nearMiss.Entry.UUID, nearMiss.BodyTruncated)
+			}
+
+			if strings.Contains(nearMiss.Differences[0], "part of the body kept") != nearMiss.BodyTruncated {
+				t.Errorf("near miss %s: unexpected difference %q", nearMiss.Entry.UUID, nearMiss.Differences[0])
+			}
+		}
+	})
+}
+
+func TestTrafficLogService_CompilePattern(t *testing.T) {
+	service := NewTrafficLogService(&config.AppArguments{TrafficLogBufferSize: 10, TrafficLogRedactHeaders: "Authorization"})
+
+	t.Run("rejects the redacted headers", func(t *testing.T) {
+		err := service.CompilePattern(&RequestPattern{Headers: map[string]string{"authorization": "Bearer token"}})
+
+		if err == nil || !strings.Contains(err.Error(), "redacted") {
+			t.Errorf("expected the redacted header to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("compiles the other patterns", func(t *testing.T) {
+		pattern := &RequestPattern{PathRegex: "^/orders", Headers: map[string]string{"Accept": "*/*"}}
+
+		if err := service.CompilePattern(pattern); err != nil || pattern.pathRegex == nil {
+			t.Errorf("expected the pattern to be compiled, got %v", err)
+		}
+
+		if err := service.CompilePattern(&RequestPattern{PathRegex: "("}); err == nil {
+			t.Error("expected an invalid pattern to be rejected")
+		}
+	})
+}
+
+func TestTrafficLogService_WaitFor(t *testing.T) {
+	pattern := &RequestPattern{Method: "POST"}
+
+	t.Run("returns once enough entries arrived", func(t *testing.T) {
+		service := newTestService(10)
+		service.Capture(newOrderEntry("a", "POST", "/orders", "", 201))
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			service.Capture(newOrderEntry("b", "GET", "/orders", "", 200))
+			service.Capture(newOrderEntry("c", "POST", "/orders", "", 201))
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if count, reached := service.WaitFor(ctx, pattern, 2); !reached || count != 2 {
+			t.Errorf("expected 2 entries, got %d, reached %v", count, reached)
+		}
+	})
+
+	t.Run("returns what arrived on timeout", func(t *testing.T) {
+		service := newTestService(10)
+		service.Capture(newOrderEntry("a", "POST", "/orders", "", 201))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		if count, reached := service.WaitFor(ctx, pattern, 2); reached || count != 1 {
+			t.Errorf("expected to time out with 1 entry, got %d, reached %v", count, reached)
+		}
+	})
+
+	t.Run("doesn't wait when disabled", func(t *testing.T) {
+		if _, reached := newTestService(0).WaitFor(context.Background(), pattern, 1); reached {
+			t.Error("expected not to be reached")
+		}
+	})
+}
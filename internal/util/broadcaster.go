@@ -60,6 +60,14 @@ func (b *Broadcaster[T]) Unsubscribe(subscriberId string) {
 	close(sub.ch)
 }
 
+// SubscriberCount returns the number of current subscribers
+func (b *Broadcaster[T]) SubscriberCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return len(b.subscribers)
+}
+
 func (b *Broadcaster[T]) Publish(event T, uuid string) {
 	log.Info().
 		Str("uuid", uuid).
@@ -1,5 +1,6 @@
 package util
 
 const (
-	UuidKey = "uuid"
+	UuidKey     = "uuid"
+	IdentityKey = "identity"
 )
@@ -11,6 +11,7 @@ var (
 	HostRegex       = regexp.MustCompile(`^(?:[\w-]+\.)+\w+$`)
 	IpAddressRegex  = regexp.MustCompile(`^(?:[0-9]{1,3}\.){3}[0-9]{1,3}$`)
 	UriRegex        = regexp.MustCompile(`^/?(?:[\w-]+/)*[\w-]+/?(?:\?(?:[\w-]+=[\w-]+)(?:&[\w-]+=[\w-]+)*)?$`)
+	UriPatternRegex = regexp.MustCompile(`^/?(?:(?:[\w-]+|\{[a-zA-Z_]\w*\}|\*)/)*(?:[\w-]+|\{[a-zA-Z_]\w*\}|\*)/?$`)
 	HttpMethodRegex = regexp.MustCompile(fmt.Sprintf(`^(%s)$`, strings.Join([]string{
 		http.MethodGet,
 		http.MethodHead,
@@ -141,6 +141,41 @@ func TestUriRegex(t *testing.T) {
 	}
 }
 
+// Test UriPatternRegex
+func TestUriPatternRegex(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		// Valid patterns
+		{"literal path", "/api/users", true},
+		{"path parameter", "/api/users/{id}", true},
+		{"parameter directory", "/api/users/{id}/orders", true},
+		{"wildcard", "/api/files/*", true},
+		{"with trailing slash", "/api/users/{id}/", true},
+		{"parameter with underscore", "/api/{user_id}", true},
+
+		// Invalid patterns
+		{"root path", "/", false},
+		{"parameter starting with digit", "/api/{1id}", false},
+		{"empty parameter", "/api/{}", false},
+		{"partial parameter", "/api/user-{id}", false},
+		{"partial wildcard", "/api/file*", false},
+		{"with query params", "/api/{id}?page=1", false},
+		{"double slashes", "/api//{id}", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := UriPatternRegex.MatchString(tt.input)
+			if result != tt.expected {
+				t.Errorf("UriPatternRegex.MatchString(%q) = %v, expected %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
 // Test HttpMethodRegex
 func TestHttpMethodRegex(t *testing.T) {
 	// Test all valid HTTP methods
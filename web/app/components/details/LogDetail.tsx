@@ -1,7 +1,7 @@
 // LogDetail - Expanded content for traffic log entries
 import React, { useState } from 'react';
-import type { TrafficEntry } from '~/types';
-import { getStatusClass, formatHeaders } from '~/lib/formatters';
+import type { TrafficEntry, TrafficRequest, TrafficResponse } from '~/types';
+import { getStatusClass, formatHeaders, formatBody } from '~/lib/formatters';
 
 type DetailTab = 'request' | 'response';
 
@@ -9,6 +9,14 @@ interface LogDetailProps {
   entry: TrafficEntry;
 }
 
+// bodyNote tells when the captured body isn't the one sent as is
+function bodyNote(message: TrafficRequest | TrafficResponse): string {
+  const notes: string[] = [];
+  if (message.body_encoding === 'base64') notes.push('base64');
+  if (message.body_truncated) notes.push(`truncated, ${message.body_size} bytes`);
+  return notes.length > 0 ? ` (${notes.join(', ')})` : '';
+}
+
 export function LogDetail({ entry }: LogDetailProps) {
   const [activeTab, setActiveTab] = useState<DetailTab>('request');
 
@@ -71,6 +79,13 @@ export function LogDetail({ entry }: LogDetailProps) {
                 <pre className="code-block">{formatHeaders(entry.request.headers)}</pre>
               </div>
             )}
+
+            {entry.request.body && (
+              <div className="detail-section">
+                <h4>Request Body{bodyNote(entry.request)}</h4>
+                <pre className="code-block">{formatBody(entry.request.body)}</pre>
+              </div>
+            )}
           </>
         )}
 
@@ -102,6 +117,20 @@ export function LogDetail({ entry }: LogDetailProps) {
               </div>
             </div>
 
+            {entry.response.headers && Object.keys(entry.response.headers).length > 0 && (
+              <div className="detail-section">
+                <h4>Response Headers</h4>
+                <pre className="code-block">{formatHeaders(entry.response.headers)}</pre>
+              </div>
+            )}
+
+            {entry.response.body && (
+              <div className="detail-section">
+                <h4>Response Body{bodyNote(entry.response)}</h4>
+                <pre className="code-block">{formatBody(entry.response.body)}</pre>
+              </div>
+            )}
+
             {entry.metadata && Object.keys(entry.metadata).length > 0 && (
               <div className="detail-section">
                 <h4>Mock Info</h4>
@@ -0,0 +1,36 @@
+// Button setting the bearer token sent to the admin API, when it requires authentication
+import { useEffect, useState } from 'react';
+import { KeyRound } from 'lucide-react';
+import { getAdminToken, setAdminToken } from '~/lib/auth';
+
+export function AdminTokenButton() {
+  const [hasToken, setHasToken] = useState(false);
+
+  useEffect(() => {
+    setHasToken(Boolean(getAdminToken()));
+  }, []);
+
+  const handleClick = () => {
+    const token = window.prompt('Admin API token (leave empty to remove it)', getAdminToken() ?? '');
+
+    // cancelled
+    if (token === null) return;
+
+    setAdminToken(token.trim() || null);
+
+    // reconnecting the traffic stream and reloading the data with the new credentials
+    window.location.reload();
+  };
+
+  return (
+    <button
+      className="btn btn-secondary btn-sm"
+      onClick={handleClick}
+      title={hasToken ? 'Change the admin API token' : 'Set the admin API token'}
+      aria-label="Admin API token"
+    >
+      <KeyRound size={16} />
+      {hasToken ? 'Token set' : 'Token'}
+    </button>
+  );
+}
@@ -1,6 +1,7 @@
-// Header component with page title, optional status, action buttons, and theme toggle
+// Header component with page title, optional status, action buttons, admin token and theme toggle
 import type { ReactNode, CSSProperties } from 'react';
 import { ThemeToggle } from './ThemeToggle';
+import { AdminTokenButton } from './AdminTokenButton';
 
 interface HeaderProps {
   /** Page title */
@@ -32,6 +33,7 @@ export function Header({ title, subtitle, actions, pageAccent }: HeaderProps) {
             {actions}
           </div>
         )}
+        <AdminTokenButton />
         <ThemeToggle />
       </div>
     </header>
@@ -1,5 +1,6 @@
 export { Sidebar } from './Sidebar';
 export { ThemeToggle } from './ThemeToggle';
+export { AdminTokenButton } from './AdminTokenButton';
 export { Header } from './Header';
 export { PageLayout } from './PageLayout';
 
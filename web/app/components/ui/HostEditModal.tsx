@@ -371,13 +371,22 @@ export function HostEditModal({ isOpen, onClose, onSave, host, isLoading }: Host
     if (uris.length > 0) {
       payload.uris = {};
       for (const uri of uris) {
+        // faults aren't editable in this form, so the ones of the URI are kept as they are
+        const uriFaults = host?.uris?.[uri.pattern.trim()]?.faults;
         payload.uris[uri.pattern.trim()] = {
           ...(buildLatencyPayload(uri.latency) && { latency: buildLatencyPayload(uri.latency)! }),
           ...(buildErrorsPayload(uri.statusRows) && { statuses: buildErrorsPayload(uri.statusRows)! }),
+          ...(uriFaults && { faults: uriFaults }),
         };
       }
     }
 
+    // settings not editable in this form are kept as they are
+    if (host?.record) payload.record = host.record;
+    if (host?.proxy) payload.proxy = host.proxy;
+    if (host?.contract) payload.contract = host.contract;
+    if (host?.faults) payload.faults = host.faults;
+
     onSave(payload);
   };
 
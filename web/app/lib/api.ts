@@ -1,14 +1,15 @@
 // API client utilities
+import { authHeaders, withAccessToken } from './auth';
 
 const API_BASE = '/api/v1';
 
 export async function fetchApi<T>(endpoint: string, options?: RequestInit): Promise<T> {
   const response = await fetch(`${API_BASE}${endpoint}`, {
-    headers: {
-      'Content-Type': 'application/json',
-      ...options?.headers,
-    },
     ...options,
+    headers: authHeaders({
+      'Content-Type': 'application/json',
+      ...(options?.headers as Record<string, string> | undefined),
+    }),
   });
 
   if (!response.ok) {
@@ -27,7 +28,7 @@ export function connectTrafficStream(
   const params = new URLSearchParams(filters);
   const url = `${API_BASE}/traffic${params.toString() ? '?' + params.toString() : ''}`;
   
-  const eventSource = new EventSource(url);
+  const eventSource = new EventSource(withAccessToken(url));
   
   eventSource.onmessage = (event) => {
     try {
@@ -0,0 +1,50 @@
+import { describe, it, expect, beforeEach } from 'vitest';
+import { authHeaders, getAdminToken, setAdminToken, withAccessToken } from './auth';
+
+describe('admin token', () => {
+  beforeEach(() => {
+    localStorage.clear();
+  });
+
+  it('is stored and removed', () => {
+    setAdminToken('secret');
+    expect(getAdminToken()).toBe('secret');
+
+    setAdminToken(null);
+    expect(getAdminToken()).toBeNull();
+  });
+});
+
+describe('authHeaders', () => {
+  beforeEach(() => {
+    localStorage.clear();
+  });
+
+  it('returns the given headers without a token', () => {
+    expect(authHeaders({ 'Content-Type': 'application/json' })).toEqual({ 'Content-Type': 'application/json' });
+  });
+
+  it('adds the bearer token', () => {
+    setAdminToken('secret');
+    expect(authHeaders({ 'x-mock-host': 'example.com' })).toEqual({
+      'x-mock-host': 'example.com',
+      Authorization: 'Bearer secret',
+    });
+  });
+});
+
+describe('withAccessToken', () => {
+  beforeEach(() => {
+    localStorage.clear();
+  });
+
+  it('returns the URL as-is without a token', () => {
+    expect(withAccessToken('/api/v1/traffic')).toBe('/api/v1/traffic');
+  });
+
+  it('adds the token to the query', () => {
+    setAdminToken('a&b');
+    expect(withAccessToken('/api/v1/traffic')).toBe('/api/v1/traffic?access_token=a%26b');
+    expect(withAccessToken('/api/v1/traffic?methods=GET')).toBe('/api/v1/traffic?methods=GET&access_token=a%26b');
+  });
+});
@@ -0,0 +1,44 @@
+// Credentials of the admin API, kept in localStorage and sent along with every API call
+
+const TOKEN_KEY = 'admin-token';
+
+// Query parameter carrying the token of the EventSource connections, which can't send headers
+export const ACCESS_TOKEN_PARAM = 'access_token';
+
+export function getAdminToken(): string | null {
+  if (typeof window === 'undefined') return null;
+  return localStorage.getItem(TOKEN_KEY);
+}
+
+export function setAdminToken(token: string | null): void {
+  if (token) {
+    localStorage.setItem(TOKEN_KEY, token);
+  } else {
+    localStorage.removeItem(TOKEN_KEY);
+  }
+}
+
+/**
+ * Adds the bearer token, if any, to the given headers.
+ * Without a token, browsers still send the credentials of the Basic auth prompt and the client certificate.
+ */
+export function authHeaders(headers?: Record<string, string>): Record<string, string> {
+  const token = getAdminToken();
+
+  if (!token) return { ...headers };
+
+  return { ...headers, Authorization: `Bearer ${token}` };
+}
+
+/**
+ * Adds the bearer token, if any, to the query of the given URL
+ */
+export function withAccessToken(url: string): string {
+  const token = getAdminToken();
+
+  if (!token) return url;
+
+  const separator = url.includes('?') ? '&' : '?';
+
+  return `${url}${separator}${ACCESS_TOKEN_PARAM}=${encodeURIComponent(token)}`;
+}
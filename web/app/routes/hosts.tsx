@@ -4,7 +4,7 @@ import { Server } from 'lucide-react';
 import { PageLayout } from '~/components/layout';
 import { FilterChipGroup, ExpandableTable, ConfirmModal, ToastContainer, HostEditModal, MockEditModal, EmptyState, type Column, type MockFormData } from '~/components/ui';
 import { HostDetail } from '~/components/details';
-import { getHosts, saveHost, deleteHost, getDefaultMocks, createMock, updateMock, deleteMock, type HostSaveData } from '~/services';
+import { getHostsConfig, saveHost, deleteHost, getDefaultMocks, createMock, updateMock, deleteMock, type HostSaveData } from '~/services';
 import { useUrlHash, useToast } from '~/hooks';
 import { getStatusClass } from '~/lib/formatters';
 import type { HostConfig, MockDefinition } from '~/types';
@@ -23,6 +23,7 @@ function totalErrorPct(errors: Record<string, { percentage: number }>): number {
 
 export default function HostsPage() {
   const [hosts, setHosts] = useState<HostConfig[]>([]);
+  const [configVersion, setConfigVersion] = useState<number | null>(null);
   const [defaultMocks, setDefaultMocks] = useState<MockDefinition[]>([]);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
@@ -61,8 +62,9 @@ export default function HostsPage() {
     try {
       setLoading(true);
       setError(null);
-      const [loadedHosts, loadedDefaultMocks] = await Promise.all([getHosts(), getDefaultMocks()]);
-      setHosts(loadedHosts);
+      const [loadedConfig, loadedDefaultMocks] = await Promise.all([getHostsConfig(), getDefaultMocks()]);
+      setHosts(loadedConfig.hosts);
+      setConfigVersion(loadedConfig.version);
       setDefaultMocks(loadedDefaultMocks);
     } catch (err) {
       setError(err instanceof Error ? err.message : 'Failed to load data');
@@ -228,7 +230,10 @@ export default function HostsPage() {
   ];
 
   return (
-    <PageLayout title="Hosts Configuration" pageAccent="var(--page-accent-hosts)">
+    <PageLayout
+      title="Hosts Configuration"
+      subtitle={configVersion !== null ? `Version ${configVersion}` : undefined}
+      pageAccent="var(--page-accent-hosts)">
       <div style={{ display: 'flex', flexDirection: 'column', height: '100%' }}>
         <div className="filter-bar">
           <input
@@ -6,7 +6,7 @@ import { FilterChipGroup, ExpandableTable, EmptyState, type Column } from '~/com
 import { LogDetail } from '~/components/details';
 import { subscribeToTraffic } from '~/services';
 import { formatTime, getStatusClass } from '~/lib/formatters';
-import type { TrafficEntry, HttpMethod, StatusCategory } from '~/types';
+import type { TrafficEntry, HttpMethod, StatusCategory, HostsConfigEvent } from '~/types';
 import { ALL_HTTP_METHODS, ALL_STATUS_CATEGORIES } from '~/types';
 
 interface Filters {
@@ -45,6 +45,7 @@ export default function LogsPage() {
   const [selectedEntryId, setSelectedEntryId] = useState<string | null>(null);
   const [isPaused, setIsPaused] = useState(false);
   const [newEntryIds, setNewEntryIds] = useState<Set<string>>(new Set());
+  const [configEvent, setConfigEvent] = useState<HostsConfigEvent | null>(null);
   const [filters, setFilters] = useState<Filters>({
     methods: [],
     statuses: [],
@@ -84,7 +85,7 @@ export default function LogsPage() {
           }, 600);
         }
       }
-    });
+    }, setConfigEvent);
     return () => unsubscribe();
   }, [isPaused]);
 
@@ -135,6 +136,17 @@ export default function LogsPage() {
           <span className="cell-secondary" style={{ fontSize: '14px' }}>
             {isPaused ? 'Paused' : 'Live'}
           </span>
+          {configEvent && (
+            <span
+              className="cell-secondary"
+              style={{ fontSize: '13px', ...(configEvent.type === 'reload_failed' && { color: 'var(--error)' }) }}
+              title={configEvent.error ?? configEvent.path}
+            >
+              {configEvent.type === 'reloaded'
+                ? `Hosts config reloaded at ${formatTime(configEvent.timestamp)}`
+                : `Invalid hosts config ignored at ${formatTime(configEvent.timestamp)}`}
+            </span>
+          )}
           {bufferedEntries.length > 0 && (
             <button onClick={handleLoadBuffered} className="buffered-entries-badge">
               <span className="status-dot buffered" />
@@ -1,4 +1,5 @@
-import type { HostConfig, LatencyConfig, StatusConfig, UriConfig } from '~/types/host';
+import type { ContractConfig, FaultConfig, HostConfig, LatencyConfig, ProxyConfig, RecordConfig, StatusConfig, UriConfig } from '~/types/host';
+import { authHeaders } from '~/lib/auth';
 
 const API_BASE_URL = import.meta.env.DEV ? 'http://localhost:9090' : '';
 
@@ -16,18 +17,29 @@ interface ApiStatusConfig {
   latency?: ApiLatencyConfig | null;
 }
 
+interface ApiFaultConfig {
+  percentage: number;
+  duration?: number | null;
+}
+
 interface ApiUriConfig {
   latency?: ApiLatencyConfig | null;
   statuses?: Record<string, ApiStatusConfig> | null;
+  faults?: Record<string, ApiFaultConfig> | null;
 }
 
 interface ApiHostConfig {
   latency?: ApiLatencyConfig | null;
   statuses?: Record<string, ApiStatusConfig> | null;
   uris?: Record<string, ApiUriConfig> | null;
+  record?: RecordConfig | null;
+  proxy?: ProxyConfig | null;
+  contract?: ContractConfig | null;
+  faults?: Record<string, ApiFaultConfig> | null;
 }
 
 interface ApiHostsConfigData {
+  version: number;
   hosts: Record<string, ApiHostConfig>;
 }
 
@@ -58,10 +70,22 @@ function toStatusesConfig(api: Record<string, ApiStatusConfig>): Record<string,
   return result;
 }
 
+function toFaultsConfig(api: Record<string, ApiFaultConfig>): Record<string, FaultConfig> {
+  const result: Record<string, FaultConfig> = {};
+  for (const [type, cfg] of Object.entries(api)) {
+    result[type] = {
+      percentage: cfg.percentage,
+      ...(cfg.duration != null && { duration: cfg.duration }),
+    };
+  }
+  return result;
+}
+
 function toUriConfig(api: ApiUriConfig): UriConfig {
   return {
     ...(api.latency && { latency: toLatencyConfig(api.latency) }),
     ...(api.statuses && Object.keys(api.statuses).length > 0 && { statuses: toStatusesConfig(api.statuses) }),
+    ...(api.faults && Object.keys(api.faults).length > 0 && { faults: toFaultsConfig(api.faults) }),
   };
 }
 
@@ -75,13 +99,22 @@ function toHostConfig(hostname: string, api: ApiHostConfig): HostConfig {
         Object.entries(api.uris).map(([pattern, cfg]) => [pattern, toUriConfig(cfg)])
       ),
     }),
+    ...(api.record && { record: api.record }),
+    ...(api.proxy && { proxy: api.proxy }),
+    ...(api.contract && { contract: api.contract }),
+    ...(api.faults && Object.keys(api.faults).length > 0 && { faults: toFaultsConfig(api.faults) }),
   };
 }
 
 // --- Public API ---
 
-export async function getHosts(): Promise<HostConfig[]> {
-  const response = await fetch(`${API_BASE_URL}/api/v1/config/hosts`);
+export interface HostsConfigSnapshot {
+  version: number;
+  hosts: HostConfig[];
+}
+
+export async function getHostsConfig(): Promise<HostsConfigSnapshot> {
+  const response = await fetch(`${API_BASE_URL}/api/v1/config/hosts`, { headers: authHeaders() });
 
   if (!response.ok) {
     throw new Error(`Failed to fetch hosts: ${response.statusText}`);
@@ -89,7 +122,14 @@ export async function getHosts(): Promise<HostConfig[]> {
 
   const data: ApiResponse<ApiHostsConfigData> = await response.json();
 
-  return Object.entries(data.data.hosts).map(([hostname, cfg]) => toHostConfig(hostname, cfg));
+  return {
+    version: data.data.version,
+    hosts: Object.entries(data.data.hosts).map(([hostname, cfg]) => toHostConfig(hostname, cfg)),
+  };
+}
+
+export async function getHosts(): Promise<HostConfig[]> {
+  return (await getHostsConfig()).hosts;
 }
 
 interface LatencyPayload {
@@ -106,6 +146,7 @@ interface StatusPayload {
 interface UriPayload {
   latency?: LatencyPayload;
   statuses?: Record<string, StatusPayload>;
+  faults?: Record<string, FaultConfig>;
 }
 
 export interface HostSaveData {
@@ -113,12 +154,16 @@ export interface HostSaveData {
   latency?: LatencyPayload;
   statuses?: Record<string, StatusPayload>;
   uris?: Record<string, UriPayload>;
+  record?: RecordConfig;
+  proxy?: ProxyConfig;
+  contract?: ContractConfig;
+  faults?: Record<string, FaultConfig>;
 }
 
 export async function saveHost(payload: HostSaveData): Promise<void> {
   const response = await fetch(`${API_BASE_URL}/api/v1/config/hosts`, {
     method: 'POST',
-    headers: { 'Content-Type': 'application/json' },
+    headers: authHeaders({ 'Content-Type': 'application/json' }),
     body: JSON.stringify(payload),
   });
 
@@ -131,6 +176,7 @@ export async function saveHost(payload: HostSaveData): Promise<void> {
 export async function deleteHost(hostname: string): Promise<void> {
   const response = await fetch(`${API_BASE_URL}/api/v1/config/hosts/${encodeURIComponent(hostname)}`, {
     method: 'DELETE',
+    headers: authHeaders(),
   });
 
   if (!response.ok) {
@@ -1,4 +1,5 @@
 import type { MockDefinition } from '~/types/mock';
+import { authHeaders } from '~/lib/auth';
 
 const API_BASE_URL = import.meta.env.DEV ? 'http://localhost:9090' : '';
 
@@ -44,7 +45,7 @@ export async function getDefaultMocks(): Promise<MockDefinition[]> {
 }
 
 async function fetchMocks(query: string): Promise<MockDefinition[]> {
-  const response = await fetch(`${API_BASE_URL}/api/v1/mocks${query}`);
+  const response = await fetch(`${API_BASE_URL}/api/v1/mocks${query}`, { headers: authHeaders() });
 
   if (!response.ok) {
     throw new Error(`Failed to fetch mocks: ${response.statusText}`);
@@ -63,7 +64,9 @@ interface MockContentResponse {
  * Get mock content (response body) for a specific mock by ID
  */
 export async function getMockContent(id: string): Promise<string> {
-  const response = await fetch(`${API_BASE_URL}/api/v1/mocks/${encodeURIComponent(id)}/content`);
+  const response = await fetch(`${API_BASE_URL}/api/v1/mocks/${encodeURIComponent(id)}/content`, {
+    headers: authHeaders(),
+  });
 
   if (!response.ok) {
     throw new Error(`Failed to fetch mock content: ${response.statusText}`);
@@ -95,13 +98,13 @@ export interface MockData {
 export async function createMock(mock: MockData): Promise<void> {
   const response = await fetch(`${API_BASE_URL}/api/v1/mocks`, {
     method: 'POST',
-    headers: {
+    headers: authHeaders({
       'Content-Type': 'text/plain',
       'x-mock-host': mock.host,
       'x-mock-uri': mock.uri,
       'x-mock-method': mock.method,
       'x-mock-status': String(mock.statusCode),
-    },
+    }),
     body: mock.body,
   });
 
@@ -117,12 +120,12 @@ export async function createMock(mock: MockData): Promise<void> {
 export async function deleteMock(mock: MockDefinition): Promise<void> {
   const response = await fetch(`${API_BASE_URL}/api/v1/mocks`, {
     method: 'DELETE',
-    headers: {
+    headers: authHeaders({
       'x-mock-host': mock.host,
       'x-mock-uri': mock.endpoint,
       'x-mock-method': mock.method,
       'x-mock-status': String(mock.statusCode),
-    },
+    }),
   });
 
   if (!response.ok) {
@@ -137,13 +140,13 @@ export async function deleteMock(mock: MockDefinition): Promise<void> {
 export async function updateMock(id: string, mock: MockData): Promise<void> {
   const response = await fetch(`${API_BASE_URL}/api/v1/mocks/${encodeURIComponent(id)}`, {
     method: 'PUT',
-    headers: {
+    headers: authHeaders({
       'Content-Type': 'text/plain',
       'x-mock-host': mock.host,
       'x-mock-uri': mock.uri,
       'x-mock-method': mock.method,
       'x-mock-status': String(mock.statusCode),
-    },
+    }),
     body: mock.body,
   });
 
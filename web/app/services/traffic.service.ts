@@ -1,14 +1,31 @@
 import type { TrafficEntry } from '~/types/traffic';
+import type { HostsConfigEvent } from '~/types/host';
+import { withAccessToken } from '~/lib/auth';
 
 const API_BASE_URL = import.meta.env.DEV ? 'http://localhost:9090' : '';
 
 /**
  * Subscribe to real-time traffic entries via SSE.
  * The backend sends catch-up entries immediately on connect, then live entries.
+ * Reloads of the config file arrive on the same stream as `hosts-config` events.
+ * EventSource can't send headers, so the admin token goes in the query.
  * Returns an unsubscribe function that closes the EventSource.
  */
-export function subscribeToTraffic(callback: (entry: TrafficEntry) => void): () => void {
-  const es = new EventSource(`${API_BASE_URL}/api/v1/traffic`);
+export function subscribeToTraffic(
+  callback: (entry: TrafficEntry) => void,
+  onHostsConfigEvent?: (event: HostsConfigEvent) => void
+): () => void {
+  const es = new EventSource(withAccessToken(`${API_BASE_URL}/api/v1/traffic`));
+
+  if (onHostsConfigEvent) {
+    es.addEventListener('hosts-config', (event) => {
+      try {
+        onHostsConfigEvent(JSON.parse((event as MessageEvent).data) as HostsConfigEvent);
+      } catch {
+        // ignore parse errors
+      }
+    });
+  }
 
   es.onmessage = (event) => {
     try {
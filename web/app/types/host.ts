@@ -5,6 +5,23 @@ export interface HostConfig {
   latency?: LatencyConfig;
   statuses?: Record<string, StatusConfig>;
   uris?: Record<string, UriConfig>;
+  record?: RecordConfig;
+  proxy?: ProxyConfig;
+  contract?: ContractConfig;
+  faults?: Record<string, FaultConfig>;
+}
+
+export interface RecordConfig {
+  upstream: string;
+}
+
+export interface ProxyConfig {
+  upstream: string;
+}
+
+export interface ContractConfig {
+  spec: string;
+  mode?: 'log' | 'reject';
 }
 
 export interface LatencyConfig {
@@ -19,7 +36,23 @@ export interface StatusConfig {
   latency?: LatencyConfig;
 }
 
+export type FaultType = 'connection_reset' | 'hang' | 'truncated_body' | 'invalid_content_length' | 'garbage';
+
+export interface FaultConfig {
+  percentage: number;
+  duration?: number; // hang only, in milliseconds
+}
+
 export interface UriConfig {
   latency?: LatencyConfig;
   statuses?: Record<string, StatusConfig>;
+  faults?: Record<string, FaultConfig>;
+}
+
+// Sent along the traffic stream when the config file is reloaded
+export interface HostsConfigEvent {
+  type: 'reloaded' | 'reload_failed';
+  path: string;
+  error?: string;
+  timestamp: string;
 }
@@ -10,16 +10,25 @@ export interface TrafficEntry {
 
 export interface TrafficRequest {
   method: string;
+  scheme?: string;
   host: string;
   path: string;
   query?: string;
   headers?: Record<string, string>;
+  body?: string;
+  body_encoding?: "base64";
+  body_size?: number;
+  body_truncated?: boolean;
 }
 
 export interface TrafficResponse {
   status_code: number;
   content_type?: string;
+  headers?: Record<string, string>;
+  body?: string;
+  body_encoding?: "base64";
   body_size: number;
+  body_truncated?: boolean;
   latency_ms: number;
 }
 